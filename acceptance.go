@@ -0,0 +1,103 @@
+package generator
+
+// Acceptance block layout in millimeters
+const (
+	acceptanceHeight          float64 = 42
+	acceptanceFieldWidth      float64 = 90
+	acceptanceSignatureHeight float64 = 18
+	acceptanceAnchorFontSize  float64 = 4
+)
+
+// Acceptance define the block customers fill to accept a quote: name, date and signature
+type Acceptance struct {
+	Statement   string `json:"statement,omitempty"`    // Options.TextAcceptanceStatement when empty
+	AcceptedVia string `json:"accepted_via,omitempty"` // How the quote was accepted, ex customer portal, not printed when empty
+	SignerName  string `json:"signer_name,omitempty"`  // Prefilled signer name
+	Date        string `json:"date,omitempty"`         // Prefilled acceptance date
+
+	// Anchors e-signature provider anchor tags placed on fields
+	Anchors *SignatureAnchors `json:"anchors,omitempty"`
+}
+
+// SignatureAnchors define the anchor tags e-signature providers search in the PDF text to place their
+// fields, ex \s1\ or {{Sig_es_:signer1:signature}}. They are drawn in white, on the field line start.
+type SignatureAnchors struct {
+	Signature string `json:"signature,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Date      string `json:"date,omitempty"`
+}
+
+// drawAnchor draw an invisible e-signature anchor tag at x, y
+func (doc *Document) drawAnchor(x float64, y float64, anchor string) {
+	if len(anchor) == 0 {
+		return
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", acceptanceAnchorFontSize)
+	doc.pdf.SetTextColor(255, 255, 255)
+	doc.pdf.Text(x, y, doc.encodeString(anchor))
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
+
+// drawAcceptanceField draw a field label, its prefilled value and the line to write on
+func (doc *Document) drawAcceptanceField(x float64, y float64, label string, value string, anchor string) {
+	doc.pdf.SetXY(x, y)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(acceptanceFieldWidth, 4, doc.encodeString(label), "0", 2, "L", false, 0, "")
+	doc.pdf.SetX(x)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.CellFormat(acceptanceFieldWidth, 5, doc.encodeString(value), "B", 0, "L", false, 0, "")
+	doc.drawAnchor(x+1, y+8, anchor)
+}
+
+// appendAcceptance append the acceptance block of quotes: statement, name, date and signature box
+func (doc *Document) appendAcceptance() {
+	acceptance := doc.Acceptance
+	if acceptance == nil || doc.Type != Quotation {
+		return
+	}
+
+	statement := acceptance.Statement
+	if len(statement) == 0 {
+		statement = doc.Options.TextAcceptanceStatement
+	}
+	anchors := acceptance.Anchors
+	if anchors == nil {
+		anchors = &SignatureAnchors{}
+	}
+
+	doc.pdf.SetXY(BaseMargin, doc.blockY(acceptanceHeight))
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 5, doc.encodeString(doc.Options.TextAcceptanceTitle), "0", 2, "L", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(190, 4, doc.encodeString(statement), "0", "L", false)
+	y := doc.pdf.GetY() + 2
+
+	// Name and date on the left, signature box on the right
+	doc.drawAcceptanceField(BaseMargin, y, doc.Options.TextAcceptanceNameTitle, acceptance.SignerName, anchors.Name)
+	doc.drawAcceptanceField(BaseMargin, y+11, doc.Options.TextAcceptanceDateTitle, acceptance.Date, anchors.Date)
+
+	right := BaseMargin + 190 - acceptanceFieldWidth
+	doc.pdf.SetXY(right, y)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(acceptanceFieldWidth, 4, doc.encodeString(doc.Options.TextAcceptanceSignatureTitle), "0", 0, "L", false, 0, "")
+	doc.pdf.Rect(right, y+4, acceptanceFieldWidth, acceptanceSignatureHeight, "D")
+	doc.drawAnchor(right+2, y+4+acceptanceSignatureHeight-3, anchors.Signature)
+	y += 4 + acceptanceSignatureHeight
+
+	if len(acceptance.AcceptedVia) > 0 {
+		doc.pdf.SetXY(BaseMargin, y+2)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+		doc.pdf.CellFormat(190, 4, doc.encodeString(doc.Options.TextAcceptanceViaTitle+": "+acceptance.AcceptedVia), "0", 0, "L", false, 0, "")
+		doc.pdf.SetTextColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+		y += 6
+	}
+
+	doc.pdf.SetXY(BaseMargin, y+2)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
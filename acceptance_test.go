@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAcceptance(t *testing.T) {
+	doc := newQuote()
+	doc.Acceptance = &Acceptance{
+		AcceptedVia: "customer portal",
+		SignerName:  "Jane Buyer",
+		Anchors:     &SignatureAnchors{Signature: `\s1\`, Date: `\d1\`},
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"(Acceptance)",
+		"(Good for agreement, I accept this quotation and its terms.)",
+		"(Jane Buyer)",
+		"(Accepted via: customer portal)",
+		`(\\s1\\)`,
+		`(\\d1\\)`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Invoices have no acceptance block
+	doc = newQuote()
+	doc.Type = Invoice
+	doc.Acceptance = &Acceptance{}
+	pdf, err = doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("(Acceptance)")) {
+		t.Error("expected no acceptance block on invoices")
+	}
+}
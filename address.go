@@ -1,12 +1,15 @@
 package generator
 
+import "strings"
+
 // Address represent an address
 type Address struct {
 	Address    string `json:"address,omitempty" validate:"required"`
 	Address2   string `json:"address_2,omitempty"`
 	PostalCode string `json:"postal_code,omitempty"`
 	City       string `json:"city,omitempty"`
-	Country    string `json:"country,omitempty"`
+	State      string `json:"state,omitempty"`   // State, province or region, ex NSW
+	Country    string `json:"country,omitempty"` // ISO 3166 alpha-2 code or name
 }
 
 // ToString output address as string
@@ -31,6 +34,11 @@ func (a *Address) ToString() string {
 		addrString += a.City
 	}
 
+	if len(a.State) > 0 {
+		addrString += " "
+		addrString += a.State
+	}
+
 	if len(a.Country) > 0 {
 		addrString += "\n"
 		addrString += a.Country
@@ -38,3 +46,108 @@ func (a *Address) ToString() string {
 
 	return addrString
 }
+
+// AddressFormat define the postal conventions of a destination country, after libaddressinput:
+// Format lays out the locality lines with %Z postal code, %C city and %S state, %n breaking lines
+type AddressFormat struct {
+	Country string // ISO 3166 alpha-2 code
+	Name    string // Country line, ex FRANCE
+	Format  string
+	Upper   string // Fields in capitals, ex CZ
+}
+
+// defaultAddressFormat lay out addresses of unknown countries like ToString
+var defaultAddressFormat = &AddressFormat{Format: "%Z %C %S"}
+
+var addressFormats = map[string]*AddressFormat{
+	"AT": {Country: "AT", Name: "AUSTRIA", Format: "%Z %C"},
+	"AU": {Country: "AU", Name: "AUSTRALIA", Format: "%C %S %Z", Upper: "CS"},
+	"BE": {Country: "BE", Name: "BELGIUM", Format: "%Z %C"},
+	"BR": {Country: "BR", Name: "BRAZIL", Format: "%C-%S%n%Z", Upper: "CS"},
+	"CA": {Country: "CA", Name: "CANADA", Format: "%C %S %Z", Upper: "CSZ"},
+	"CH": {Country: "CH", Name: "SWITZERLAND", Format: "%Z %C"},
+	"DE": {Country: "DE", Name: "GERMANY", Format: "%Z %C"},
+	"ES": {Country: "ES", Name: "SPAIN", Format: "%Z %C %S", Upper: "CS"},
+	"FR": {Country: "FR", Name: "FRANCE", Format: "%Z %C", Upper: "C"},
+	"GB": {Country: "GB", Name: "UNITED KINGDOM", Format: "%C%n%Z", Upper: "CZ"},
+	"IE": {Country: "IE", Name: "IRELAND", Format: "%C%n%S %Z", Upper: "CS"},
+	"IT": {Country: "IT", Name: "ITALY", Format: "%Z %C %S", Upper: "CS"},
+	"NL": {Country: "NL", Name: "NETHERLANDS", Format: "%Z %C"},
+	"NZ": {Country: "NZ", Name: "NEW ZEALAND", Format: "%C %Z"},
+	"PT": {Country: "PT", Name: "PORTUGAL", Format: "%Z %C"},
+	"US": {Country: "US", Name: "UNITED STATES", Format: "%C, %S %Z", Upper: "CS"},
+}
+
+// RegisterAddressFormat add or replace the address format of a country
+func RegisterAddressFormat(format *AddressFormat) {
+	addressFormats[strings.ToUpper(format.Country)] = format
+}
+
+// GetAddressFormat return the address format of a country, by code or name, nil if none
+func GetAddressFormat(country string) *AddressFormat {
+	if format, ok := addressFormats[strings.ToUpper(country)]; ok {
+		return format
+	}
+
+	for _, format := range addressFormats {
+		if strings.EqualFold(format.Name, country) {
+			return format
+		}
+	}
+
+	return nil
+}
+
+// Format output address following the postal conventions of its country, see RegisterAddressFormat.
+// The country line, in capitals, is omitted for addresses in the from country, code or name.
+func (a *Address) Format(from string) string {
+	format := GetAddressFormat(a.Country)
+	if format == nil {
+		format = defaultAddressFormat
+	}
+
+	upper := func(field string, value string) string {
+		if strings.Contains(format.Upper, field) {
+			return strings.ToUpper(value)
+		}
+		return value
+	}
+
+	lines := []string{a.Address}
+	if len(a.Address2) > 0 {
+		lines = append(lines, a.Address2)
+	}
+
+	locality := strings.NewReplacer(
+		"%Z", upper("Z", a.PostalCode),
+		"%C", upper("C", a.City),
+		"%S", upper("S", a.State),
+	).Replace(format.Format)
+	for _, line := range strings.Split(locality, "%n") {
+		// Separators of missing fields
+		line = strings.Trim(strings.Join(strings.Fields(line), " "), " ,-")
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(a.Country) > 0 && !a.inCountry(from) {
+		country := strings.ToUpper(a.Country)
+		if len(format.Name) > 0 {
+			country = format.Name
+		}
+		lines = append(lines, country)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// inCountry return true when address is in country, given by code or name
+func (a *Address) inCountry(country string) bool {
+	if strings.EqualFold(a.Country, country) {
+		return true
+	}
+
+	format := GetAddressFormat(a.Country)
+	return format != nil && format == GetAddressFormat(country)
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAddressFormat(t *testing.T) {
+	for expected, formatted := range map[string]string{
+		"12 rue de la Paix\n75002 PARIS":                      (&Address{Address: "12 rue de la Paix", PostalCode: "75002", City: "Paris", Country: "FR"}).Format("FR"),
+		"12 rue de la Paix\n75002 PARIS\nFRANCE":              (&Address{Address: "12 rue de la Paix", PostalCode: "75002", City: "Paris", Country: "FR"}).Format("DE"),
+		"Hauptstraße 1\n10115 Berlin\nGERMANY":                (&Address{Address: "Hauptstraße 1", PostalCode: "10115", City: "Berlin", Country: "Germany"}).Format("France"),
+		"10 Downing Street\nLONDON\nSW1A 2AA\nUNITED KINGDOM": (&Address{Address: "10 Downing Street", PostalCode: "sw1a 2aa", City: "London", Country: "GB"}).Format("FR"),
+		"1600 Pennsylvania Ave NW\nWASHINGTON, DC 20500":      (&Address{Address: "1600 Pennsylvania Ave NW", PostalCode: "20500", City: "Washington", State: "DC", Country: "US"}).Format("United States"),
+		"Av. Paulista, 1000\nSÃO PAULO-SP\n01310-100\nBRAZIL": (&Address{Address: "Av. Paulista, 1000", PostalCode: "01310-100", City: "São Paulo", State: "SP", Country: "BR"}).Format("PT"),
+		"1 Main street\nSpringfield 12345\nNARNIA":            (&Address{Address: "1 Main street", PostalCode: "Springfield 12345", Country: "Narnia"}).Format("FR"),
+		"1 George St\nSuite 2\nSYDNEY NSW 2000":               (&Address{Address: "1 George St", Address2: "Suite 2", PostalCode: "2000", City: "Sydney", State: "nsw", Country: "AU"}).Format("AU"),
+		"Via Roma 1\n00184 ROMA RM":                           (&Address{Address: "Via Roma 1", PostalCode: "00184", City: "Roma", State: "rm", Country: "it"}).Format("IT"),
+		"1 Main St\nSPRINGFIELD, 62701\nUNITED STATES":        (&Address{Address: "1 Main St", PostalCode: "62701", City: "Springfield", Country: "US"}).Format("CA"),
+	} {
+		if formatted != expected {
+			t.Errorf("expected %q, got %q", expected, formatted)
+		}
+	}
+
+	RegisterAddressFormat(&AddressFormat{Country: "XX", Name: "TESTLAND", Format: "%C%n%Z"})
+	if formatted := (&Address{Address: "1 Test street", PostalCode: "T1", City: "Test", Country: "Testland"}).Format(""); formatted != "1 Test street\nTest\nT1\nTESTLAND" {
+		t.Errorf("unexpected registered format %q", formatted)
+	}
+}
+
+func TestPostalAddresses(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, PostalAddresses: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street", PostalCode: "10115", City: "Berlin", Country: "DE"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "12 rue de la Paix", PostalCode: "75002", City: "Paris", Country: "FR"}})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(75002 PARIS)", "(FRANCE)", "(10115 Berlin)", "(DE)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %s in output", expected)
+		}
+	}
+}
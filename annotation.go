@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidAnnotation when an annotation has neither text nor image, or targets a missing page
+var ErrInvalidAnnotation = errors.New("invalid annotation")
+
+// Annotation pages
+const (
+	AnnotationAllPages int = 0
+	AnnotationLastPage int = -1
+)
+
+// Annotation define a text or image positioned in millimeters on a page, added after layout
+type Annotation struct {
+	Page     int     `json:"page,omitempty" validate:"min=-1"` // One-based page, AnnotationAllPages or AnnotationLastPage
+	X        float64 `json:"x,omitempty"`
+	Y        float64 `json:"y,omitempty"`
+	Text     string  `json:"text,omitempty"`
+	FontSize float64 `json:"font_size,omitempty"` // SmallTextFontSize when empty
+	Image    []byte  `json:"image,omitempty"`
+	Width    float64 `json:"width,omitempty"`    // Image width, computed from height when empty
+	Height   float64 `json:"height,omitempty"`   // Image height, computed from width when empty
+	Rotation float64 `json:"rotation,omitempty"` // Degrees counter-clockwise around X, Y, ex 90 for a spine label
+	Vertical bool    `json:"vertical,omitempty"` // Text characters stacked top to bottom from X, Y, ex Japanese tategaki
+	Grid     bool    `json:"grid,omitempty"`     // X, Y are column and row and Width, Height spans of Options.Grid
+}
+
+// Page give access to a laid out page to annotate it, see Options.AnnotatePage
+type Page struct {
+	Number int // One-based page number
+	Count  int // Number of pages of document
+	Width  float64
+	Height float64
+
+	doc *Document
+}
+
+// Text write text at x, y (baseline) in millimeters, with size font size, SmallTextFontSize when 0
+func (p *Page) Text(x float64, y float64, text string, size float64) {
+	if size <= 0 {
+		size = SmallTextFontSize
+	}
+
+	p.doc.pdf.SetFont(p.doc.Options.Font, "", size)
+	p.doc.pdf.Text(x, y, p.doc.encodeString(text))
+}
+
+// RotatedText write text like Text, rotated of angle degrees counter-clockwise around x, y
+func (p *Page) RotatedText(x float64, y float64, text string, size float64, angle float64) {
+	p.doc.pdf.TransformBegin()
+	p.doc.pdf.TransformRotate(angle, x, y)
+	p.Text(x, y, text, size)
+	p.doc.pdf.TransformEnd()
+}
+
+// VerticalText write text characters stacked top to bottom, centered on x, the first baseline at y,
+// with size font size, SmallTextFontSize when 0. Characters the document font can't encode are drawn
+// with fallback fonts.
+func (p *Page) VerticalText(x float64, y float64, text string, size float64) {
+	if size <= 0 {
+		size = SmallTextFontSize
+	}
+
+	p.doc.pdf.SetFont(p.doc.Options.Font, "", size)
+	_, step := p.doc.pdf.GetFontSize()
+	step *= 1.2
+
+	for _, r := range text {
+		character := string(r)
+		if family := p.doc.fallbackFont(r); !p.doc.encodable(r) && len(family) > 0 {
+			p.doc.pdf.SetFont(family, "", size)
+			p.doc.substitute(r, GlyphFallback, family, "")
+		} else {
+			character = p.doc.encodeString(character)
+		}
+
+		p.doc.pdf.Text(x-p.doc.pdf.GetStringWidth(character)/2, y, character)
+		p.doc.pdf.SetFont(p.doc.Options.Font, "", size)
+		y += step
+	}
+}
+
+// Image draw image data at x, y in millimeters, width or height computed from
+// image ratio when 0
+func (p *Page) Image(x float64, y float64, width float64, height float64, data []byte) error {
+	if err := p.doc.checkImageLimits(data); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("annotation-%x", sha256.Sum256(data))
+	_, err := p.doc.drawImage(fmt.Sprintf("page %d image", p.Number), name, data, x, y, width, height)
+
+	return err
+}
+
+// Annotate add annotation to page when it targets it
+func (p *Page) Annotate(annotation *Annotation) error {
+	switch annotation.Page {
+	case AnnotationAllPages, p.Number:
+	case AnnotationLastPage:
+		if p.Number != p.Count {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	x, y, width, height := annotation.X, annotation.Y, annotation.Width, annotation.Height
+	if annotation.Grid {
+		grid := p.Grid()
+		x, y = grid.X(x), grid.Y(y)
+		width, height = grid.SpanWidth(width), grid.SpanHeight(height)
+	}
+
+	if annotation.Rotation != 0 {
+		p.doc.pdf.TransformBegin()
+		p.doc.pdf.TransformRotate(annotation.Rotation, x, y)
+		defer p.doc.pdf.TransformEnd()
+	}
+
+	if len(annotation.Text) > 0 {
+		if annotation.Vertical {
+			p.VerticalText(x, y, annotation.Text, annotation.FontSize)
+		} else {
+			p.Text(x, y, annotation.Text, annotation.FontSize)
+		}
+	}
+	if len(annotation.Image) > 0 {
+		return p.Image(x, y, width, height, annotation.Image)
+	}
+
+	return nil
+}
+
+// appendAnnotations add document annotations and call Options.AnnotatePage on each page, after layout
+func (doc *Document) appendAnnotations() error {
+	if len(doc.Annotations) == 0 && doc.Options.AnnotatePage == nil {
+		return nil
+	}
+
+	count := doc.pdf.PageCount()
+	for _, annotation := range doc.Annotations {
+		if (len(annotation.Text) == 0 && len(annotation.Image) == 0) || annotation.Page > count {
+			return ErrInvalidAnnotation
+		}
+	}
+
+	width, height := doc.pdf.GetPageSize()
+	current := doc.pdf.PageNo()
+	defer doc.pdf.SetPage(current)
+
+	for number := 1; number <= count; number++ {
+		doc.pdf.SetPage(number)
+		page := &Page{Number: number, Count: count, Width: width, Height: height, doc: doc}
+
+		// Content of the current page is still shifted by print layout
+		if number != current {
+			doc.beginPageShift()
+		}
+
+		for _, annotation := range doc.Annotations {
+			if err := page.Annotate(annotation); err != nil {
+				return err
+			}
+		}
+
+		if doc.Options.AnnotatePage != nil {
+			if err := doc.Options.AnnotatePage(page); err != nil {
+				return err
+			}
+		}
+
+		if number != current {
+			doc.endPageShift()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAnnotations(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	for i := 0; i < 60; i++ {
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	}
+	doc.AppendAnnotation(&Annotation{Page: AnnotationLastPage, X: 10, Y: 290, Text: "Scanned on 2026-10-14"})
+	doc.AppendAnnotation(&Annotation{Page: 1, X: 180, Y: 5, Width: 20, Image: testPhoto(40, 40)})
+
+	pages := 0
+	doc.Options.AnnotatePage = func(page *Page) error {
+		pages = page.Count
+		page.Text(page.Width-40, 10, fmt.Sprintf("ROUTE-%d/%d", page.Number, page.Count), 0)
+		return nil
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages < 2 {
+		t.Fatalf("expected several pages, got %d", pages)
+	}
+	if pdf.PageNo() != pages {
+		t.Errorf("expected current page restored to %d, got %d", pages, pdf.PageNo())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(Scanned on 2026-10-14)", "(ROUTE-1/", fmt.Sprintf("(ROUTE-%d/%d)", pages, pages), "/Subtype /Image"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %s in output", expected)
+		}
+	}
+
+	invalid, _ := New(Invoice, &Options{})
+	invalid.SetRef("1")
+	invalid.SetCompany(&Contact{Name: "Company"})
+	invalid.SetCustomer(&Contact{Name: "Customer"})
+	invalid.AppendAnnotation(&Annotation{Page: 3, Text: "Missing page"})
+	if _, err := invalid.Build(); !errors.Is(err, ErrInvalidAnnotation) {
+		t.Errorf("expected invalid annotation, got %v", err)
+	}
+}
+
+func TestRotatedAnnotations(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.AppendAnnotation(&Annotation{X: 5, Y: 200, Text: "ORIGINAL", FontSize: 14, Rotation: 90})
+	doc.AppendAnnotation(&Annotation{Page: 1, X: 200, Y: 40, Text: "ARCHIVE", Vertical: true})
+	doc.Options.AnnotatePage = func(page *Page) error {
+		page.RotatedText(page.Width-5, 150, "COPY", 0, -90)
+		return nil
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(ORIGINAL)", "(COPY)", "(A)", "(E)", " cm"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %s in output", expected)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("(ARCHIVE)")) {
+		t.Error("expected vertical text drawn character by character")
+	}
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// longDescriptions return distinct item descriptions longer than Options.MaxDescriptionLength, in items order,
+// numbered from 1 in the appendix. None without the appendix section, descriptions are then drawn in full.
+func (doc *Document) longDescriptions() []string {
+	limit := doc.Options.MaxDescriptionLength
+	if limit == 0 || !doc.hasSection(SectionAppendix) {
+		return nil
+	}
+
+	descriptions := []string{}
+	for _, item := range doc.Items {
+		if len([]rune(item.Description)) <= limit || descriptionNote(descriptions, item.Description) > 0 {
+			continue
+		}
+		descriptions = append(descriptions, item.Description)
+	}
+
+	return descriptions
+}
+
+// descriptionNote return the appendix note number of description, 0 when it isn't truncated
+func descriptionNote(descriptions []string, description string) int {
+	for i, long := range descriptions {
+		if long == description {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// truncateDescription return description cut at the last word before Options.MaxDescriptionLength characters,
+// followed by TextDescriptionContinued with its appendix note, as is when it is shorter
+func (doc *Document) truncateDescription(description string) string {
+	note := descriptionNote(doc.longDescriptions(), description)
+	if note == 0 {
+		return description
+	}
+
+	runes := []rune(description)
+	limit := doc.Options.MaxDescriptionLength
+
+	cut := limit
+	for cut > 0 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		cut = limit
+	}
+	truncated := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace)
+
+	return truncated + " " + fmt.Sprintf(doc.Options.TextDescriptionContinued, note)
+}
+
+// appendDescriptionAppendix append the full text of truncated item descriptions on a new page, each
+// following its note number and the name of the first item using it
+func (doc *Document) appendDescriptionAppendix() {
+	descriptions := doc.longDescriptions()
+	if len(descriptions) == 0 {
+		return
+	}
+
+	doc.pdf.AddPage()
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(190, 8, doc.encodeString(doc.Options.TextDescriptionAppendixTitle), "0", 2, "L", false, 0, "")
+
+	for i, description := range descriptions {
+		name := ""
+		for _, item := range doc.Items {
+			if item.Description == description {
+				name = item.Name
+				break
+			}
+		}
+
+		doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+3)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.MultiCell(190, 5, doc.encodeString(doc.wrapText(fmt.Sprintf("%d. %s", i+1, stripScripts(name)), 190)), "0", "L", false)
+
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.MultiCell(190, 4, doc.encodeString(doc.wrapText(description, 190)), "0", "L", false)
+	}
+}
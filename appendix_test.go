@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDescriptionAppendix(t *testing.T) {
+	excerpt := "The licensee shall maintain complete and accurate records of all sales and shall permit the licensor " +
+		"to audit such records upon thirty days written notice, at the licensor expense."
+
+	doc := newQuote()
+	doc.Options.MaxDescriptionLength = 40
+	doc.Items[0].Description = excerpt
+	doc.AppendItem(&Item{Name: "Audit", UnitCost: "50", Quantity: "1", Description: excerpt})
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "10", Quantity: "1", Description: "Short description"})
+
+	if descriptions := doc.longDescriptions(); len(descriptions) != 1 {
+		t.Fatalf("expected one distinct long description, got %d", len(descriptions))
+	}
+	if text := doc.itemDescription(excerpt); text != "The licensee shall maintain complete and … (continued in appendix, note 1)" {
+		t.Errorf("unexpected truncated description %q", text)
+	}
+	if text := doc.itemDescription("Short description"); text != "Short description" {
+		t.Errorf("expected short description as is, got %q", text)
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageCount() != 2 {
+		t.Errorf("expected appendix on a second page, got %d pages", pdf.PageCount())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(Appendix: full descriptions)", "(1. ", "expense.)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Descriptions are drawn in full without the appendix section
+	doc.Options.Sections = []string{SectionMeta, SectionItems, SectionTotals}
+	if text := doc.itemDescription(excerpt); text != excerpt {
+		t.Errorf("expected full description without appendix, got %q", text)
+	}
+}
@@ -0,0 +1,256 @@
+package generator
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+// ArchiveXMLEncoder write the structured XML of a document (UBL, CII...) in an archival bundle
+type ArchiveXMLEncoder func(w io.Writer, doc *Document) error
+
+// ArchiveFile define a file of an archival bundle and its hash
+type ArchiveFile struct {
+	Name   string `json:"name"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ArchiveManifest define the manifest of an archival bundle
+type ArchiveManifest struct {
+	Ref       string         `json:"ref"`
+	Type      string         `json:"type"`
+	Date      string         `json:"date,omitempty"`
+	CreatedAt string         `json:"created_at"`
+	Files     []*ArchiveFile `json:"files"`
+}
+
+// archiveFileNameRegexp match characters not allowed in bundle file names
+var archiveFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ArchiveBundle build the document and write a zip bundle containing the PDF,
+// the structured XML, the source JSON and a manifest with SHA-256 hashes of each file.
+// A nil xmlEncoder uses EncodeArchiveXML.
+func (doc *Document) ArchiveBundle(w io.Writer, xmlEncoder ArchiveXMLEncoder) error {
+	if xmlEncoder == nil {
+		xmlEncoder = EncodeArchiveXML
+	}
+
+	// Source JSON before build, as provided by user
+	source, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		return err
+	}
+
+	pdfBuffer := &bytes.Buffer{}
+	if err := pdf.Output(pdfBuffer); err != nil {
+		return err
+	}
+
+	if err := doc.checkOutputSize(pdfBuffer.Len()); err != nil {
+		return err
+	}
+
+	xmlBuffer := &bytes.Buffer{}
+	if err := xmlEncoder(xmlBuffer, doc); err != nil {
+		return err
+	}
+
+	name := archiveFileNameRegexp.ReplaceAllString(doc.Ref, "_")
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name + ".pdf", pdfBuffer.Bytes()},
+		{name + ".xml", xmlBuffer.Bytes()},
+		{name + ".json", source},
+	}
+
+	createdAt := time.Now()
+	manifest := &ArchiveManifest{
+		Ref:       doc.Ref,
+		Type:      doc.Type,
+		Date:      doc.Date,
+		CreatedAt: createdAt.Format(time.RFC3339),
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: createdAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := writer.Write(file.content); err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, &ArchiveFile{
+			Name:   file.name,
+			Size:   len(file.content),
+			SHA256: fmt.Sprintf("%x", sha256.Sum256(file.content)),
+		})
+	}
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     "manifest.json",
+		Method:   zip.Deflate,
+		Modified: createdAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(manifestContent); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// archiveXMLParty define a contact in archive XML
+type archiveXMLParty struct {
+	Name       string `xml:"Name"`
+	TaxID      string `xml:"TaxID,omitempty"`
+	Address    string `xml:"Address>Line,omitempty"`
+	Address2   string `xml:"Address>Line2,omitempty"`
+	PostalCode string `xml:"Address>PostalCode,omitempty"`
+	City       string `xml:"Address>City,omitempty"`
+	Country    string `xml:"Address>Country,omitempty"`
+}
+
+// archiveXMLLine define an item in archive XML
+type archiveXMLLine struct {
+	Number      int    `xml:"Number,attr"`
+	Name        string `xml:"Name"`
+	Description string `xml:"Description,omitempty"`
+	Quantity    string `xml:"Quantity"`
+	UnitCost    string `xml:"UnitCost"`
+	PriceBasis  string `xml:"PriceBasis,omitempty"`
+	TaxRate     string `xml:"TaxRate,omitempty"`
+	NetAmount   string `xml:"NetAmount"`
+	TaxAmount   string `xml:"TaxAmount"`
+}
+
+// archiveXMLTax define a tax breakdown line in archive XML
+type archiveXMLTax struct {
+	Type   string `xml:"Type,attr"`
+	Rate   string `xml:"Rate,omitempty"`
+	Base   string `xml:"Base"`
+	Amount string `xml:"Amount"`
+}
+
+// archiveXML define the default structured XML of archival bundles
+type archiveXML struct {
+	XMLName  xml.Name          `xml:"Document"`
+	Type     string            `xml:"Type,attr"`
+	Ref      string            `xml:"Ref"`
+	Version  string            `xml:"Version,omitempty"`
+	Date     string            `xml:"Date,omitempty"`
+	Company  *archiveXMLParty  `xml:"Company"`
+	Customer *archiveXMLParty  `xml:"Customer,omitempty"`
+	Lines    []*archiveXMLLine `xml:"Lines>Line"`
+	Taxes    []*archiveXMLTax  `xml:"Taxes>Tax"`
+	Net      string            `xml:"Totals>Net"`
+	Tax      string            `xml:"Totals>Tax"`
+	Gross    string            `xml:"Totals>Gross"`
+}
+
+// archiveXMLPartyOf return the archive XML party of a contact
+func archiveXMLPartyOf(c *Contact) *archiveXMLParty {
+	if c == nil {
+		return nil
+	}
+
+	party := &archiveXMLParty{Name: c.Name, TaxID: c.TaxID}
+	if c.Address != nil {
+		party.Address = c.Address.Address
+		party.Address2 = c.Address.Address2
+		party.PostalCode = c.Address.PostalCode
+		party.City = c.Address.City
+		party.Country = c.Address.Country
+	}
+
+	return party
+}
+
+// EncodeArchiveXML write the document as a generic structured XML with lines, tax breakdown and totals
+func EncodeArchiveXML(w io.Writer, doc *Document) error {
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+
+	file := &archiveXML{
+		Type:     doc.Type,
+		Ref:      doc.Ref,
+		Version:  doc.Version,
+		Date:     doc.Date,
+		Company:  archiveXMLPartyOf(doc.Company),
+		Customer: archiveXMLPartyOf(doc.Customer),
+		Net:      doc.TotalWithoutTax().StringFixed(2),
+		Tax:      doc.Tax().StringFixed(2),
+		Gross:    doc.TotalWithTax().StringFixed(2),
+	}
+
+	for i, item := range doc.Items {
+		line := &archiveXMLLine{
+			Number:      i + 1,
+			Name:        item.Name,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitCost:    item.UnitCost,
+			PriceBasis:  item.PriceBasis,
+			NetAmount:   item.TotalWithoutTaxAndWithDiscount().StringFixed(2),
+			TaxAmount:   item.TaxWithTotalDiscounted().StringFixed(2),
+		}
+
+		if item.Tax != nil {
+			if taxType, taxNumber := item.Tax.getTax(); taxType == TaxTypePercent {
+				line.TaxRate = taxNumber.String()
+			}
+		}
+
+		file.Lines = append(file.Lines, line)
+	}
+
+	for _, taxLine := range doc.TaxLines() {
+		tax := &archiveXMLTax{
+			Type:   taxLine.Type,
+			Base:   taxLine.Base.StringFixed(2),
+			Amount: taxLine.Amount.StringFixed(2),
+		}
+		if taxLine.Type == TaxTypePercent {
+			tax.Rate = taxLine.Rate.String()
+		}
+
+		file.Taxes = append(file.Taxes, tax)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(file)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestArchiveBundle(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV/2024/1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street", City: "Paris"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "20"}})
+
+	buffer := &bytes.Buffer{}
+	if err := doc.ArchiveBundle(buffer, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string][]byte{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[file.Name] = content
+	}
+
+	manifest := &ArchiveManifest{}
+	if err := json.Unmarshal(contents["manifest.json"], manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Files) != 3 {
+		t.Fatalf("expected 3 files in manifest, got %d", len(manifest.Files))
+	}
+
+	for _, file := range manifest.Files {
+		content, ok := contents[file.Name]
+		if !ok {
+			t.Fatalf("missing %s in bundle", file.Name)
+		}
+		if hash := fmt.Sprintf("%x", sha256.Sum256(content)); hash != file.SHA256 {
+			t.Errorf("expected %s hash %s, got %s", file.Name, file.SHA256, hash)
+		}
+	}
+
+	if !bytes.Contains(contents["INV_2024_1.xml"], []byte("<Gross>240.00</Gross>")) {
+		t.Errorf("expected gross total in xml, got %s", contents["INV_2024_1.xml"])
+	}
+}
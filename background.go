@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"github.com/creasty/defaults"
+)
+
+// Page background stripe sides
+const (
+	StripeLeft   string = "left"
+	StripeRight  string = "right"
+	StripeTop    string = "top"
+	StripeBottom string = "bottom"
+)
+
+// PageBackground define a tint filling pages and a brand stripe along a page edge, drawn behind all
+// content of each page
+type PageBackground struct {
+	Color []int `json:"color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"` // Page tint, none when empty
+
+	StripeColor []int   `json:"stripe_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"` // No stripe when empty
+	StripeWidth float64 `default:"6" json:"stripe_width,omitempty" validate:"min=0,max=50"`           // In millimeters
+	StripeSide  string  `default:"left" json:"stripe_side,omitempty" validate:"omitempty,oneof=left right top bottom"`
+}
+
+// applyPageBackground draw Options.PageBackground at each page start, before the header. Print layout
+// draws it itself, before its marks.
+func (doc *Document) applyPageBackground() {
+	if doc.Options.PageBackground == nil || doc.Options.Print != nil {
+		return
+	}
+
+	header := doc.headerFunc
+	doc.setHeaderFunc(func() {
+		doc.drawPageBackground()
+		if header != nil {
+			header()
+		}
+	})
+}
+
+// drawPageBackground fill current page tint and stripe of Options.PageBackground
+func (doc *Document) drawPageBackground() {
+	if doc.Options.PageBackground == nil {
+		return
+	}
+
+	background := *doc.Options.PageBackground
+	_ = defaults.Set(&background)
+
+	x, y := doc.pdf.GetXY()
+	r, g, b := doc.pdf.GetFillColor()
+	defer doc.pdf.SetXY(x, y)
+	defer doc.pdf.SetFillColor(r, g, b)
+
+	width, height := doc.pdf.GetPageSize()
+	if len(background.Color) > 0 {
+		doc.pdf.SetFillColor(background.Color[0], background.Color[1], background.Color[2])
+		doc.pdf.Rect(0, 0, width, height, "F")
+	}
+
+	if len(background.StripeColor) > 0 {
+		stripe := background.StripeWidth
+		doc.pdf.SetFillColor(background.StripeColor[0], background.StripeColor[1], background.StripeColor[2])
+		switch background.StripeSide {
+		case StripeRight:
+			doc.pdf.Rect(width-stripe, 0, stripe, height, "F")
+		case StripeTop:
+			doc.pdf.Rect(0, 0, width, stripe, "F")
+		case StripeBottom:
+			doc.pdf.Rect(0, height-stripe, width, stripe, "F")
+		default:
+			doc.pdf.Rect(0, 0, stripe, height, "F")
+		}
+	}
+}
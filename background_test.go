@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPageBackground(t *testing.T) {
+	doc := newQuote()
+	for i := 0; i < 40; i++ {
+		doc.AppendItem(&Item{Name: "Support", UnitCost: "10", Quantity: "1"})
+	}
+	doc.Options.PageBackground = &PageBackground{Color: []int{250, 250, 245}, StripeColor: []int{0, 80, 160}}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	stripe := []byte("0.000 0.314 0.627 rg")
+	if count := bytes.Count(buffer.Bytes(), stripe); count < pdf.PageCount() {
+		t.Errorf("expected a stripe on each of %d pages, got %d", pdf.PageCount(), count)
+	}
+	if tint, title := bytes.Index(buffer.Bytes(), []byte("0.980 0.980 0.961 rg")), bytes.Index(buffer.Bytes(), []byte("(QUOTATION)")); tint < 0 || tint > title {
+		t.Errorf("expected page tint drawn before content, got %d and %d", tint, title)
+	}
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"github.com/creasty/defaults"
+)
+
+// Bands define filled background bands behind the title and totals, ex in a brand color
+type Bands struct {
+	// TitleColor fill a band behind the title, from the title box to the right page edge, no band when empty
+	TitleColor     []int `json:"title_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	TitleTextColor []int `default:"[255,255,255]" json:"title_text_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+
+	// TotalsColor fill a single box behind totals instead of DarkBgColor titles and GreyBgColor amounts boxes,
+	// no box when empty
+	TotalsColor  []int   `json:"totals_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	TotalsRadius float64 `json:"totals_radius,omitempty" validate:"min=0,max=10"` // Totals box corners radius in millimeters
+	TotalsCard   bool    `json:"totals_card,omitempty"`                           // Draw totals box as a card, with Options.Card border and shadow
+}
+
+// bands return Options.Bands with defaults, nil when not set
+func (doc *Document) bands() *Bands {
+	if doc.Options.Bands == nil {
+		return nil
+	}
+
+	bands := *doc.Options.Bands
+	_ = defaults.Set(&bands)
+
+	return &bands
+}
+
+// totalsBand return true when a totals band replaces totals boxes
+func (doc *Document) totalsBand() bool {
+	bands := doc.bands()
+	return bands != nil && len(bands.TotalsColor) > 0
+}
+
+// appendTitleBand fill the title band and set the band text color, false when there is no title band
+func (doc *Document) appendTitleBand() bool {
+	bands := doc.bands()
+	if bands == nil || len(bands.TitleColor) == 0 {
+		return false
+	}
+
+	width, _ := doc.pdf.GetPageSize()
+	doc.pdf.SetFillColor(bands.TitleColor[0], bands.TitleColor[1], bands.TitleColor[2])
+	doc.pdf.Rect(120, BaseMarginTop, width-120, 10, "F")
+	doc.pdf.SetTextColor(bands.TitleTextColor[0], bands.TitleTextColor[1], bands.TitleTextColor[2])
+
+	return true
+}
+
+// appendTotalsBand fill the totals band behind totals lines of height starting at current Y
+func (doc *Document) appendTotalsBand(height float64) {
+	if !doc.totalsBand() {
+		return
+	}
+
+	bands := doc.bands()
+	if bands.TotalsCard {
+		card := doc.card()
+		card.FillColor = bands.TotalsColor
+		if bands.TotalsRadius > 0 {
+			card.Radius = bands.TotalsRadius
+		}
+		doc.drawCard(118, doc.pdf.GetY(), 84, height, card)
+		return
+	}
+
+	doc.drawRoundedRect(118, doc.pdf.GetY(), 84, height, bands.TotalsRadius, bands.TotalsColor, nil, 0)
+}
+
+// fillTotalBox fill a title or amount box of a totals line at current Y, unless a totals band is drawn
+func (doc *Document) fillTotalBox(x float64, color []int) {
+	if doc.totalsBand() {
+		return
+	}
+
+	doc.pdf.SetFillColor(color[0], color[1], color[2])
+	doc.pdf.Rect(x, doc.pdf.GetY(), 40, 10, "F")
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBands(t *testing.T) {
+	doc := newQuote()
+	doc.Options.Bands = &Bands{
+		TitleColor:   []int{0, 80, 160},
+		TotalsColor:  []int{240, 244, 250},
+		TotalsRadius: 3,
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if color := doc.bands().TitleTextColor; len(color) != 3 || color[0] != 255 {
+		t.Errorf("expected default white title text, got %v", color)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"0.000 0.314 0.627 rg", "0.941 0.957 0.980 rg"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Totals boxes are replaced by the band
+	plain := newQuote()
+	plainPDF, err := plain.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainBuffer := &bytes.Buffer{}
+	if err := plainPDF.Output(plainBuffer); err != nil {
+		t.Fatal(err)
+	}
+	grey := []byte("0.910 g")
+	if banded, boxed := bytes.Count(buffer.Bytes(), grey), bytes.Count(plainBuffer.Bytes(), grey); banded >= boxed {
+		t.Errorf("expected less grey boxes with a totals band, got %d and %d", banded, boxed)
+	}
+}
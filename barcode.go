@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"errors"
+)
+
+// ErrInvalidBarcodeContent when content can't be encoded in barcode
+var ErrInvalidBarcodeContent = errors.New("invalid barcode content")
+
+// code128Patterns define bars and spaces widths of Code 128 symbols, values 0 to 105
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232",
+}
+
+// code128Stop define the stop pattern, including termination bar
+const code128Stop string = "2331112"
+
+// Code 128 start symbols
+const (
+	code128StartB int = 104
+	code128StartC int = 105
+)
+
+// code128Values return the symbol values of content, start and check symbols included.
+// Even length numeric contents use code set C, other printable ASCII contents code set B.
+func code128Values(content string) ([]int, error) {
+	if len(content) == 0 {
+		return nil, ErrInvalidBarcodeContent
+	}
+
+	numeric := len(content)%2 == 0
+	for _, r := range content {
+		if r < '0' || r > '9' {
+			numeric = false
+		}
+		if r < 32 || r > 126 {
+			return nil, ErrInvalidBarcodeContent
+		}
+	}
+
+	values := []int{code128StartB}
+	if numeric {
+		values[0] = code128StartC
+		for i := 0; i < len(content); i += 2 {
+			values = append(values, int(content[i]-'0')*10+int(content[i+1]-'0'))
+		}
+	} else {
+		for i := 0; i < len(content); i++ {
+			values = append(values, int(content[i])-32)
+		}
+	}
+
+	// Check symbol
+	checksum := values[0]
+	for i, value := range values[1:] {
+		checksum += (i + 1) * value
+	}
+
+	return append(values, checksum%103), nil
+}
+
+// code128Widths return the alternating bar / space modules widths of content
+func code128Widths(content string) ([]int, error) {
+	values, err := code128Values(content)
+	if err != nil {
+		return nil, err
+	}
+
+	widths := []int{}
+	for _, value := range values {
+		for _, w := range code128Patterns[value] {
+			widths = append(widths, int(w-'0'))
+		}
+	}
+	for _, w := range code128Stop {
+		widths = append(widths, int(w-'0'))
+	}
+
+	return widths, nil
+}
+
+// itfPatterns define narrow (1) and wide (3) elements of Interleaved 2 of 5 digits
+var itfPatterns = []string{
+	"11331", "31113", "13113", "33111", "11313", "31311", "13311", "11133", "31131", "13131",
+}
+
+// ITF start and stop patterns
+const (
+	itfStart string = "1111"
+	itfStop  string = "311"
+)
+
+// itfWidths return the alternating bar / space modules widths of an even length numeric content
+// encoded in Interleaved 2 of 5, first digit of each pair in bars and second one in spaces
+func itfWidths(content string) ([]int, error) {
+	if len(content) == 0 || len(content)%2 != 0 || !digits(content) {
+		return nil, ErrInvalidBarcodeContent
+	}
+
+	widths := []int{}
+	for _, w := range itfStart {
+		widths = append(widths, int(w-'0'))
+	}
+	for i := 0; i < len(content); i += 2 {
+		bars, spaces := itfPatterns[content[i]-'0'], itfPatterns[content[i+1]-'0']
+		for j := 0; j < 5; j++ {
+			widths = append(widths, int(bars[j]-'0'), int(spaces[j]-'0'))
+		}
+	}
+	for _, w := range itfStop {
+		widths = append(widths, int(w-'0'))
+	}
+
+	return widths, nil
+}
+
+// drawBars draw alternating bars / spaces widths (in modules) in the given box
+func (doc *Document) drawBars(widths []int, x float64, y float64, width float64, height float64) {
+	modules := 0
+	for _, w := range widths {
+		modules += w
+	}
+
+	moduleWidth := width / float64(modules)
+
+	doc.pdf.SetFillColor(0, 0, 0)
+	for i, w := range widths {
+		// Even indexes are bars
+		if i%2 == 0 {
+			doc.pdf.Rect(x, y, float64(w)*moduleWidth, height, "F")
+		}
+		x += float64(w) * moduleWidth
+	}
+}
+
+// drawCode128 draw content as a Code 128 barcode in the given box
+func (doc *Document) drawCode128(content string, x float64, y float64, width float64, height float64) error {
+	widths, err := code128Widths(content)
+	if err != nil {
+		return err
+	}
+
+	doc.drawBars(widths, x, y, width, height)
+
+	return nil
+}
+
+// drawITF draw content as an Interleaved 2 of 5 barcode in the given box
+func (doc *Document) drawITF(content string, x float64, y float64, width float64, height float64) error {
+	widths, err := itfWidths(content)
+	if err != nil {
+		return err
+	}
+
+	doc.drawBars(widths, x, y, width, height)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package generator
+
+import "testing"
+
+func TestCode128Widths(t *testing.T) {
+	for value, pattern := range code128Patterns {
+		sum := 0
+		for _, w := range pattern {
+			sum += int(w - '0')
+		}
+		if sum != 11 {
+			t.Errorf("expected pattern %d to have 11 modules, got %d", value, sum)
+		}
+	}
+
+	// Start C, 22 pairs, check symbol and stop
+	widths, err := code128Widths("35180512345678000190550010000000011000000013")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := 0
+	for _, w := range widths {
+		modules += w
+	}
+	if modules != 24*11+13 {
+		t.Errorf("expected %d modules, got %d", 24*11+13, modules)
+	}
+
+	if _, err := code128Widths("é"); err != ErrInvalidBarcodeContent {
+		t.Errorf("expected %v, got %v", ErrInvalidBarcodeContent, err)
+	}
+}
@@ -0,0 +1,275 @@
+package generator
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Text directions, see Options.TextDirection
+const (
+	TextDirectionLTR string = "ltr"
+	TextDirectionRTL string = "rtl"
+)
+
+// bidiMirrors swap paired characters drawn right to left
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+}
+
+// rtl return true when text holds right to left letters or Arabic digits, reordered before being drawn
+func rtl(text string) bool {
+	for _, r := range text {
+		// Hebrew is the first right to left block
+		if r < 0x0590 {
+			continue
+		}
+
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.R, bidi.AL, bidi.AN:
+			return true
+		}
+	}
+
+	return false
+}
+
+// visualOrder return text lines in visual order, left to right as drawn on the page
+func (doc *Document) visualOrder(text string) string {
+	if !rtl(text) {
+		return text
+	}
+
+	runes, _ := doc.visualRunes([]rune(text), make([]string, utf8.RuneCountInString(text)))
+	return string(runes)
+}
+
+// visualRunes return runes lines and their scripts in visual order, mirrored runes drawn right to left
+func (doc *Document) visualRunes(runes []rune, scripts []string) ([]rune, []string) {
+	visualRunes := make([]rune, 0, len(runes))
+	visualScripts := make([]string, 0, len(scripts))
+
+	for start := 0; start <= len(runes); {
+		end := start
+		for end < len(runes) && runes[end] != '\n' {
+			end++
+		}
+
+		order, levels := bidiOrder(runes[start:end], doc.Options.TextDirection)
+		for _, index := range order {
+			r := runes[start+index]
+			if mirror, ok := bidiMirrors[r]; ok && levels[index]%2 == 1 {
+				r = mirror
+			}
+			visualRunes = append(visualRunes, r)
+			visualScripts = append(visualScripts, scripts[start+index])
+		}
+		if end < len(runes) {
+			visualRunes = append(visualRunes, '\n')
+			visualScripts = append(visualScripts, scripts[end])
+		}
+
+		start = end + 1
+	}
+
+	return visualRunes, visualScripts
+}
+
+// bidiOrder return the logical indexes of a line runes in visual order and their embedding levels,
+// following the Unicode bidirectional algorithm without explicit embeddings, which are handled as neutrals.
+// The paragraph direction is given by direction, or by the first strong character when empty.
+func bidiOrder(runes []rune, direction string) ([]int, []int) {
+	classes := make([]bidi.Class, len(runes))
+	for i, r := range runes {
+		props, _ := bidi.LookupRune(r)
+		classes[i] = props.Class()
+	}
+
+	// Paragraph level (P2, P3)
+	base := 0
+	switch direction {
+	case TextDirectionRTL:
+		base = 1
+	case TextDirectionLTR:
+	default:
+		for _, class := range classes {
+			if class == bidi.L {
+				break
+			}
+			if class == bidi.R || class == bidi.AL {
+				base = 1
+				break
+			}
+		}
+	}
+	sos := bidi.L
+	if base == 1 {
+		sos = bidi.R
+	}
+
+	types := make([]bidi.Class, len(classes))
+	for i, class := range classes {
+		switch class {
+		case bidi.L, bidi.R, bidi.AL, bidi.EN, bidi.ES, bidi.ET, bidi.AN, bidi.CS, bidi.NSM, bidi.WS:
+			types[i] = class
+		case bidi.B, bidi.S:
+			types[i] = bidi.WS
+		default:
+			types[i] = bidi.ON
+		}
+	}
+
+	// Weak types (W1 to W7)
+	previous, strong := sos, sos
+	for i, class := range types {
+		if class == bidi.NSM {
+			types[i] = previous
+		}
+		switch types[i] {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = types[i]
+		case bidi.EN:
+			if strong == bidi.AL {
+				types[i] = bidi.AN
+			}
+		}
+		previous = types[i]
+	}
+	for i, class := range types {
+		if class == bidi.AL {
+			types[i] = bidi.R
+		}
+	}
+	for i := 1; i+1 < len(types); i++ {
+		before, after := types[i-1], types[i+1]
+		switch {
+		case types[i] == bidi.ES && before == bidi.EN && after == bidi.EN:
+			types[i] = bidi.EN
+		case types[i] == bidi.CS && before == after && (before == bidi.EN || before == bidi.AN):
+			types[i] = before
+		}
+	}
+	for i := 0; i < len(types); i++ {
+		if types[i] != bidi.ET {
+			continue
+		}
+		end := i
+		for end < len(types) && types[end] == bidi.ET {
+			end++
+		}
+		if (i > 0 && types[i-1] == bidi.EN) || (end < len(types) && types[end] == bidi.EN) {
+			for j := i; j < end; j++ {
+				types[j] = bidi.EN
+			}
+		}
+		i = end - 1
+	}
+	strong = sos
+	for i, class := range types {
+		switch class {
+		case bidi.ES, bidi.ET, bidi.CS:
+			types[i] = bidi.ON
+		case bidi.L, bidi.R:
+			strong = class
+		case bidi.EN:
+			if strong == bidi.L {
+				types[i] = bidi.L
+			}
+		}
+	}
+
+	// Neutral types (N1, N2), numbers count as right to left
+	strongDirection := func(class bidi.Class) bidi.Class {
+		if class == bidi.EN || class == bidi.AN {
+			return bidi.R
+		}
+		return class
+	}
+	for i := 0; i < len(types); i++ {
+		if types[i] != bidi.WS && types[i] != bidi.ON {
+			continue
+		}
+		end := i
+		for end < len(types) && (types[end] == bidi.WS || types[end] == bidi.ON) {
+			end++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = strongDirection(types[i-1])
+		}
+		if end < len(types) {
+			after = strongDirection(types[end])
+		}
+		resolved := sos
+		if before == after {
+			resolved = before
+		}
+		for j := i; j < end; j++ {
+			types[j] = resolved
+		}
+		i = end - 1
+	}
+
+	// Implicit levels (I1, I2)
+	levels := make([]int, len(types))
+	for i, class := range types {
+		levels[i] = base
+		switch {
+		case base == 0 && class == bidi.R:
+			levels[i] = 1
+		case base == 0 && (class == bidi.EN || class == bidi.AN):
+			levels[i] = 2
+		case base == 1 && class != bidi.R:
+			levels[i] = 2
+		}
+	}
+
+	// Trailing whitespaces and separators at paragraph level (L1)
+	for i := len(classes) - 1; i >= 0; i-- {
+		if classes[i] != bidi.WS && classes[i] != bidi.S && classes[i] != bidi.B && classes[i] != bidi.BN {
+			break
+		}
+		levels[i] = base
+	}
+	for i, class := range classes {
+		if class == bidi.S || class == bidi.B {
+			levels[i] = base
+		}
+	}
+
+	// Reverse from the highest level to the lowest odd level (L2)
+	order := make([]int, len(runes))
+	highest, lowest := base, base
+	for i, level := range levels {
+		order[i] = i
+		if level > highest {
+			highest = level
+		}
+		if level < lowest {
+			lowest = level
+		}
+	}
+	for level := highest; level >= lowest|1; level-- {
+		for i := 0; i < len(order); i++ {
+			if levels[order[i]] < level {
+				continue
+			}
+			end := i
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = end
+		}
+	}
+
+	return order, levels
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVisualOrder(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+
+	for text, expected := range map[string]string{
+		"Plain text":              "Plain text",
+		"Pack אבג of 3":           "Pack גבא of 3",
+		"אבג Pack דהו":            "והד Pack גבא",
+		"אבג 123 דהו":             "והד 123 גבא",
+		"א(ב)":                    "(ב)א",
+		"شراء iPhone 15":          "iPhone 15 ءارش",
+		"אבג\nabc":                "גבא\nabc",
+		"Total: ٣٤٥ ر.س":          "Total: س.ر ٣٤٥",
+		"Invoice for אבג, 2 days": "Invoice for 2 ,גבא days",
+		"Invoice for אבג, net":    "Invoice for גבא, net",
+	} {
+		if visual := doc.visualOrder(text); visual != expected {
+			t.Errorf("expected %q in visual order as %q, got %q", text, expected, visual)
+		}
+	}
+
+	doc.Options.TextDirection = TextDirectionRTL
+	if visual := doc.visualOrder("Pack אבג"); visual != "גבא Pack" {
+		t.Errorf("unexpected right to left line %q", visual)
+	}
+}
+
+func TestBidiBuild(t *testing.T) {
+	font, err := os.ReadFile(filepath.Join(build.Default.GOPATH, "pkg", "mod", "github.com", "go-pdf", "fpdf@v0.6.0", "font", "DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Skip("fallback font not available")
+	}
+
+	doc, _ := New(Invoice, &Options{
+		FallbackFonts: []*FallbackFont{{Family: "Hebrew", File: font}},
+	})
+
+	runs := doc.textRuns("Pack <sup>1</sup> אבג")
+	if len(runs) != 4 || runs[1].script != ScriptSuper || runs[3].family != "Hebrew" || runs[3].text != "גבא" {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.SetDescription("הזמנה של Acme Corp עבור שלושה חודשים של שירותי ייעוץ, כולל תמיכה טכנית ודוחות חודשיים מפורטים")
+	doc.AppendItem(&Item{Name: "Consulting שירותי ייעוץ לחברה עם שם ארוך מאוד שנשבר על פני כמה שורות", UnitCost: "100", Quantity: "1"})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if wrapped := doc.wrapText(doc.Items[0].Name, 20); !bytes.Contains([]byte(wrapped), []byte("\n")) {
+		t.Error("expected long name wrapped")
+	}
+}
@@ -0,0 +1,267 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidBoleto when boleto barcode or digitable line length or check digits are invalid
+var ErrInvalidBoleto = errors.New("invalid boleto barcode")
+
+// Ficha de compensação layout in millimeters, following the FEBRABAN bank layout specs
+const (
+	boletoTop          float64 = 190 // Slip is drawn at the bottom of the page, cut along its top dashed line
+	boletoRowHeight    float64 = 8
+	boletoRightWidth   float64 = 50 // Due date, amounts and references column
+	boletoBarcodeX     float64 = 12
+	boletoBarcodeWidth float64 = 103
+	boletoBarcodeHt    float64 = 13
+)
+
+// Boleto define a Brazilian bank payment slip (ficha de compensação), the barcode or digitable line being
+// issued by the bank. The other one is computed from it.
+type Boleto struct {
+	Barcode       string `json:"barcode,omitempty"`        // 44 digits código de barras, spaces allowed
+	DigitableLine string `json:"digitable_line,omitempty"` // 47 digits linha digitável, spaces and dots allowed
+	BankName      string `json:"bank_name,omitempty"`
+	BankLogo      []byte `json:"bank_logo,omitempty"`
+
+	PaymentPlace    string `json:"payment_place,omitempty"` // Options.TextBoletoPaymentPlace when empty
+	DueDate         string `json:"due_date,omitempty"`
+	Beneficiary     string `json:"beneficiary,omitempty"`      // Company name when empty
+	BeneficiaryCode string `json:"beneficiary_code,omitempty"` // Agência / código do beneficiário
+	OurNumber       string `json:"our_number,omitempty"`       // Nosso número
+	DocumentNumber  string `json:"document_number,omitempty"`  // Document ref when empty
+	Instructions    string `json:"instructions,omitempty"`     // Instructions to the cashier, one per line
+}
+
+// Prepare normalize barcode and digitable line, computing the missing one, and check their check digits
+func (b *Boleto) Prepare() error {
+	b.Barcode = strings.ReplaceAll(b.Barcode, " ", "")
+	b.DigitableLine = strings.NewReplacer(" ", "", ".", "").Replace(b.DigitableLine)
+
+	if len(b.Barcode) == 0 {
+		if len(b.DigitableLine) != 47 || !digits(b.DigitableLine) {
+			return ErrInvalidBoleto
+		}
+		line := b.DigitableLine
+		b.Barcode = line[0:4] + line[32:33] + line[33:47] + line[4:9] + line[10:20] + line[21:31]
+	}
+
+	if len(b.Barcode) != 44 || !digits(b.Barcode) || boletoCheckDigit(b.Barcode) != int(b.Barcode[4]-'0') {
+		return ErrInvalidBoleto
+	}
+
+	line := boletoDigitableLine(b.Barcode)
+	if len(b.DigitableLine) > 0 && b.DigitableLine != line {
+		return ErrInvalidBoleto
+	}
+	b.DigitableLine = line
+
+	return nil
+}
+
+// boletoCheckDigit return the modulo 11 general check digit of barcode, computed without its 5th digit
+func boletoCheckDigit(barcode string) int {
+	sum := 0
+	weight := 2
+	for i := len(barcode) - 1; i >= 0; i-- {
+		if i == 4 {
+			continue
+		}
+
+		sum += int(barcode[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	check := 11 - sum%11
+	if check == 0 || check >= 10 {
+		return 1
+	}
+
+	return check
+}
+
+// boletoFieldCheckDigit return the modulo 10 check digit of a digitable line field
+func boletoFieldCheckDigit(field string) int {
+	sum := 0
+	weight := 2
+	for i := len(field) - 1; i >= 0; i-- {
+		product := int(field[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+
+	return (10 - sum%10) % 10
+}
+
+// boletoDigitableLine return the 47 digits digitable line of barcode: bank, currency and free field
+// in 3 fields with their check digit, then the general check digit, due date factor and amount
+func boletoDigitableLine(barcode string) string {
+	fields := []string{barcode[0:4] + barcode[19:24], barcode[24:34], barcode[34:44]}
+
+	line := ""
+	for _, field := range fields {
+		line += fmt.Sprintf("%s%d", field, boletoFieldCheckDigit(field))
+	}
+
+	return line + barcode[4:5] + barcode[5:19]
+}
+
+// digitableLineAsString return the digitable line as printed, ex 00190.00009 01234.567004 00000.001172 1 67890000012345
+func (b *Boleto) digitableLineAsString() string {
+	line := b.DigitableLine
+
+	return fmt.Sprintf(
+		"%s.%s %s.%s %s.%s %s %s",
+		line[0:5], line[5:10], line[10:15], line[15:21], line[21:26], line[26:32], line[32:33], line[33:47],
+	)
+}
+
+// bankCodeAsString return the bank code with its modulo 11 check digit, ex 001-9
+func (b *Boleto) bankCodeAsString() string {
+	sum := 0
+	for i, weight := range []int{4, 3, 2} {
+		sum += int(b.Barcode[i]-'0') * weight
+	}
+
+	check := 11 - sum%11
+	if check == 10 {
+		return fmt.Sprintf("%s-X", b.Barcode[0:3])
+	}
+	if check == 11 {
+		check = 0
+	}
+
+	return fmt.Sprintf("%s-%d", b.Barcode[0:3], check)
+}
+
+// Amount return the amount encoded in barcode, zero when left to the payer
+func (b *Boleto) Amount() decimal.Decimal {
+	cents, _ := decimal.NewFromString(b.Barcode[9:19])
+	return cents.Shift(-2)
+}
+
+// drawBoletoField draw a ficha de compensação field box, label on top and value below
+func (doc *Document) drawBoletoField(x float64, y float64, width float64, height float64, label string, value string, align string) {
+	doc.pdf.Rect(x, y, width, height, "D")
+
+	doc.pdf.SetXY(x+0.5, y+0.5)
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize-1)
+	doc.pdf.CellFormat(width-1, 2.5, doc.encodeString(label), "0", 2, "L", false, 0, "")
+
+	doc.pdf.SetX(x + 0.5)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(width-1, 3.5, doc.encodeString(value), "0", align, false)
+}
+
+// appendBoleto draw the ficha de compensação at the bottom of the page, on a new page when content
+// reaches it
+func (doc *Document) appendBoleto() error {
+	boleto := doc.Boleto
+	if boleto == nil {
+		return nil
+	}
+
+	if doc.pdf.GetY() > boletoTop-5 {
+		doc.pdf.AddPage()
+	}
+
+	lineWidth := doc.pdf.GetLineWidth()
+	doc.pdf.SetLineWidth(0.2)
+	defer doc.pdf.SetLineWidth(lineWidth)
+
+	// Cut line
+	doc.pdf.SetDashPattern([]float64{1, 1}, 0)
+	doc.pdf.Line(BaseMargin, boletoTop-3, BaseMargin+190, boletoTop-3)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+
+	// Bank, bank code and digitable line
+	y := boletoTop
+	if boleto.BankLogo != nil {
+		if _, err := doc.drawImage("Boleto bank logo", "boleto-bank-logo", boleto.BankLogo, BaseMargin, y, 0, boletoRowHeight-1); err != nil {
+			return err
+		}
+	} else {
+		doc.pdf.SetXY(BaseMargin, y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(40, boletoRowHeight, doc.encodeString(boleto.BankName), "0", 0, "L", false, 0, "")
+	}
+	doc.pdf.SetXY(BaseMargin+40, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize+2)
+	doc.pdf.CellFormat(22, boletoRowHeight, boleto.bankCodeAsString(), "LR", 0, "C", false, 0, "")
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(128, boletoRowHeight, boleto.digitableLineAsString(), "0", 0, "R", false, 0, "")
+	y += boletoRowHeight
+
+	paymentPlace := boleto.PaymentPlace
+	if len(paymentPlace) == 0 {
+		paymentPlace = doc.Options.TextBoletoPaymentPlace
+	}
+	beneficiary := boleto.Beneficiary
+	if len(beneficiary) == 0 && doc.Company != nil {
+		beneficiary = doc.Company.Name
+	}
+	documentNumber := boleto.DocumentNumber
+	if len(documentNumber) == 0 {
+		documentNumber = doc.Ref
+	}
+	amount := boleto.Amount()
+	if amount.IsZero() {
+		amount = doc.documentTotal()
+	}
+	payer := ""
+	if doc.Customer != nil {
+		payer = doc.Customer.Name
+		if doc.Customer.Address != nil {
+			payer += " - " + strings.ReplaceAll(doc.Customer.Address.ToString(), "\n", " ")
+		}
+	}
+
+	left := 190 - boletoRightWidth
+	right := BaseMargin + left
+	rows := []struct {
+		label string
+		value string
+		title string
+		field string
+	}{
+		{doc.Options.TextBoletoPaymentPlaceTitle, paymentPlace, doc.Options.TextBoletoDueDateTitle, boleto.DueDate},
+		{doc.Options.TextBoletoBeneficiaryTitle, beneficiary, doc.Options.TextBoletoBeneficiaryCodeTitle, boleto.BeneficiaryCode},
+		{doc.Options.TextBoletoDocumentNumberTitle, documentNumber, doc.Options.TextBoletoOurNumberTitle, boleto.OurNumber},
+	}
+	for _, row := range rows {
+		doc.drawBoletoField(BaseMargin, y, left, boletoRowHeight, row.label, row.value, "L")
+		doc.drawBoletoField(right, y, boletoRightWidth, boletoRowHeight, row.title, row.field, "R")
+		y += boletoRowHeight
+	}
+
+	// Instructions beside amounts
+	doc.drawBoletoField(BaseMargin, y, left, 3*boletoRowHeight, doc.Options.TextBoletoInstructionsTitle, boleto.Instructions, "L")
+	doc.drawBoletoField(right, y, boletoRightWidth, boletoRowHeight, doc.Options.TextBoletoAmountTitle, doc.formatMoney(amount), "R")
+	doc.drawBoletoField(right, y+boletoRowHeight, boletoRightWidth, boletoRowHeight, doc.Options.TextBoletoDeductionsTitle, "", "R")
+	doc.drawBoletoField(right, y+2*boletoRowHeight, boletoRightWidth, boletoRowHeight, doc.Options.TextBoletoAmountChargedTitle, "", "R")
+	y += 3 * boletoRowHeight
+
+	doc.drawBoletoField(BaseMargin, y, 190, 1.5*boletoRowHeight, doc.Options.TextBoletoPayerTitle, payer, "L")
+	y += 1.5*boletoRowHeight + 3
+
+	// Barcode, its quiet zones kept on both sides
+	if err := doc.drawITF(boleto.Barcode, boletoBarcodeX, y, boletoBarcodeWidth, boletoBarcodeHt); err != nil {
+		return err
+	}
+	doc.pdf.SetXY(right, y)
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.CellFormat(boletoRightWidth, 3, doc.encodeString(doc.Options.TextBoletoTitle), "0", 0, "R", false, 0, "")
+
+	doc.pdf.SetXY(BaseMargin, y+boletoBarcodeHt)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	return nil
+}
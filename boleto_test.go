@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBoletoPrepare(t *testing.T) {
+	boleto := &Boleto{Barcode: "0019 1678 9000 0012 3450 0000 0210 1234 5670 0000 0017"}
+	if err := boleto.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if line := boleto.digitableLineAsString(); line != "00190.00009 02101.234561 70000.000177 1 67890000012345" {
+		t.Errorf("unexpected digitable line %s", line)
+	}
+	if code := boleto.bankCodeAsString(); code != "001-9" {
+		t.Errorf("expected bank code 001-9, got %s", code)
+	}
+	if amount := boleto.Amount(); amount.String() != "123.45" {
+		t.Errorf("expected 123.45 amount, got %s", amount)
+	}
+
+	// Barcode computed from digitable line
+	fromLine := &Boleto{DigitableLine: "00190.00009 02101.234561 70000.000177 1 67890000012345"}
+	if err := fromLine.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if fromLine.Barcode != boleto.Barcode {
+		t.Errorf("expected barcode %s, got %s", boleto.Barcode, fromLine.Barcode)
+	}
+
+	for _, invalid := range []*Boleto{
+		{Barcode: "00192678900000123450000002101234567000000017"},
+		{Barcode: "0019167890000012345"},
+		{DigitableLine: "00190.00009 02101.234562 70000.000177 1 67890000012345"},
+		{},
+	} {
+		if err := invalid.Prepare(); err != ErrInvalidBoleto {
+			t.Errorf("expected %v, got %v", ErrInvalidBoleto, err)
+		}
+	}
+}
+
+func TestITFWidths(t *testing.T) {
+	// Start, 22 pairs of 2 wide and 3 narrow bars and spaces, stop
+	widths, err := itfWidths("00191678900000123450000002101234567000000017")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := 0
+	for _, w := range widths {
+		modules += w
+	}
+	if modules != 4+22*18+5 {
+		t.Errorf("expected %d modules, got %d", 4+22*18+5, modules)
+	}
+
+	if _, err := itfWidths("123"); err != ErrInvalidBarcodeContent {
+		t.Errorf("expected %v, got %v", ErrInvalidBarcodeContent, err)
+	}
+}
+
+func TestAppendBoleto(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "123.45", Quantity: "1"})
+	doc.Boleto = &Boleto{
+		Barcode:   "00191678900000123450000002101234567000000017",
+		BankName:  "Banco do Brasil",
+		DueDate:   "15/03/2024",
+		OurNumber: "21012345670000000",
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"(001-9)", "(00190.00009 02101.234561 70000.000177 1 67890000012345)", "(INV-1)", "(15/03/2024)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(text)) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+}
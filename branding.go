@@ -0,0 +1,20 @@
+package generator
+
+// producer return Options.Producer, empty for white label documents
+func (doc *Document) producer() string {
+	if doc.Options.WhiteLabel {
+		return ""
+	}
+
+	return doc.Options.Producer
+}
+
+// applyBranding set the Producer and Creator metadata of pdf, omitted when empty
+func (doc *Document) applyBranding() {
+	// Replace fpdf own name, an empty UTF-8 string would still be written as its byte order mark
+	producer := doc.producer()
+	doc.pdf.SetProducer(producer, len(producer) > 0)
+	if len(doc.Options.Creator) > 0 {
+		doc.pdf.SetCreator(doc.Options.Creator, true)
+	}
+}
@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBranding(t *testing.T) {
+	output := func(doc *Document) []byte {
+		buffer := &bytes.Buffer{}
+		if err := doc.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+
+		return buffer.Bytes()
+	}
+
+	doc := newEInvoice(EInvoiceProfileEN16931)
+	doc.Options.Creator = "Acme Billing"
+	pdf := output(doc)
+	for _, expected := range []string{"/Producer (go-invoice-generator)", "/Creator (Acme Billing)", "<pdf:Producer>go-invoice-generator</pdf:Producer>", "<xmp:CreatorTool>Acme Billing</xmp:CreatorTool>"} {
+		if !bytes.Contains(pdf, []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	doc = newEInvoice(EInvoiceProfileEN16931)
+	doc.Options.WhiteLabel = true
+	if pdf := output(doc); bytes.Contains(pdf, []byte("Producer")) || bytes.Contains(pdf, []byte("go-invoice-generator")) {
+		t.Error("expected no producer in white label PDF")
+	}
+	if snapshot, _ := doc.RulesSnapshot(); snapshot.Generator != "" {
+		t.Errorf("expected no generator in white label snapshot, got %s", snapshot.Generator)
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidAccessKey when NF-e access key length or check digit is invalid
+var ErrInvalidAccessKey = errors.New("invalid nf-e access key")
+
+// BrazilianFiscal define NF-e (DANFE) and NFS-e references of a document
+type BrazilianFiscal struct {
+	// NF-e
+	AccessKey string `json:"access_key,omitempty"` // 44 digits chave de acesso, spaces allowed
+	Protocol  string `json:"protocol,omitempty"`   // Authorization protocol number and date
+
+	// NFS-e
+	NFSeNumber       string `json:"nfse_number,omitempty"`
+	VerificationCode string `json:"verification_code,omitempty"`
+	Municipality     string `json:"municipality,omitempty"`
+	VerificationURL  string `json:"verification_url,omitempty"` // Municipal authenticity check url, rendered as QR code
+}
+
+// Prepare normalize and check NF-e access key
+func (b *BrazilianFiscal) Prepare() error {
+	if len(b.AccessKey) == 0 {
+		return nil
+	}
+
+	b.AccessKey = strings.ReplaceAll(b.AccessKey, " ", "")
+	if !validAccessKey(b.AccessKey) {
+		return ErrInvalidAccessKey
+	}
+
+	return nil
+}
+
+// validAccessKey check access key length and modulo 11 check digit
+func validAccessKey(key string) bool {
+	if len(key) != 44 {
+		return false
+	}
+
+	sum := 0
+	weight := 2
+	for i := 42; i >= 0; i-- {
+		if key[i] < '0' || key[i] > '9' {
+			return false
+		}
+
+		sum += int(key[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	check := 11 - sum%11
+	if check >= 10 {
+		check = 0
+	}
+
+	return int(key[43]-'0') == check
+}
+
+// accessKeyAsString return the access key by groups of 4 digits
+func (b *BrazilianFiscal) accessKeyAsString() string {
+	groups := []string{}
+	for i := 0; i < len(b.AccessKey); i += 4 {
+		end := i + 4
+		if end > len(b.AccessKey) {
+			end = len(b.AccessKey)
+		}
+		groups = append(groups, b.AccessKey[i:end])
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// appendBrazilianFiscal append DANFE access key barcode and NFS-e verification informations
+func (doc *Document) appendBrazilianFiscal() error {
+	fiscal := doc.BrazilianFiscal
+	if fiscal == nil {
+		return nil
+	}
+
+	y := doc.pdf.GetY() + 5
+
+	// NF-e access key with barcode
+	if len(fiscal.AccessKey) > 0 {
+		doc.pdf.SetXY(BaseMargin, y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+		doc.pdf.CellFormat(100, 4, doc.encodeString(doc.Options.TextBrazilianAccessKeyTitle), "0", 0, "L", false, 0, "")
+
+		if err := doc.drawCode128(fiscal.AccessKey, BaseMargin, y+5, 100, 12); err != nil {
+			return err
+		}
+
+		doc.pdf.SetXY(BaseMargin, y+18)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.CellFormat(100, 4, fiscal.accessKeyAsString(), "0", 0, "C", false, 0, "")
+
+		// Authorization protocol and authenticity check mention
+		doc.pdf.SetXY(120, y)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.MultiCell(80, 3, doc.encodeString(doc.Options.TextBrazilianAccessKeyMention), "0", "L", false)
+
+		if len(fiscal.Protocol) > 0 {
+			doc.pdf.SetXY(120, y+12)
+			doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+			doc.pdf.CellFormat(80, 4, doc.encodeString(doc.Options.TextBrazilianProtocolTitle), "0", 0, "L", false, 0, "")
+			doc.pdf.SetXY(120, y+16)
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+			doc.pdf.CellFormat(80, 4, doc.encodeString(fiscal.Protocol), "0", 0, "L", false, 0, "")
+		}
+
+		y += 24
+	}
+
+	// NFS-e number and verification code
+	if len(fiscal.NFSeNumber) > 0 || len(fiscal.VerificationCode) > 0 {
+		infos := []string{}
+		if len(fiscal.NFSeNumber) > 0 {
+			infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextBrazilianNFSeNumberTitle, fiscal.NFSeNumber))
+		}
+		if len(fiscal.VerificationCode) > 0 {
+			infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextBrazilianVerificationCodeTitle, fiscal.VerificationCode))
+		}
+		if len(fiscal.Municipality) > 0 {
+			infos = append(infos, fiscal.Municipality)
+		}
+
+		height := 8.0
+		if len(fiscal.VerificationURL) > 0 {
+			height = 22
+			if err := doc.drawQRCode(fiscal.VerificationURL, QRCodeECLevelM, 180, y, 20); err != nil {
+				return err
+			}
+		}
+
+		doc.pdf.SetXY(BaseMargin, y)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.MultiCell(165, 4, doc.encodeString(strings.Join(infos, " - ")), "0", "L", false)
+
+		if len(fiscal.VerificationURL) > 0 {
+			doc.pdf.SetX(BaseMargin)
+			doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+			doc.pdf.MultiCell(165, 3, doc.encodeString(fiscal.VerificationURL), "0", "L", false)
+		}
+
+		y += height
+	}
+
+	doc.pdf.SetXY(BaseMargin, y)
+
+	return nil
+}
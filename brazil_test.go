@@ -0,0 +1,12 @@
+package generator
+
+import "testing"
+
+func TestValidAccessKey(t *testing.T) {
+	if !validAccessKey("35180512345678000190550010000000011000000013") {
+		t.Error("expected access key to be valid")
+	}
+	if validAccessKey("35180512345678000190550010000000011000000014") {
+		t.Error("expected access key to be invalid")
+	}
+}
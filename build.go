@@ -9,12 +9,36 @@ import (
 
 // Build pdf document from data provided
 func (doc *Document) Build() (*fpdf.Fpdf, error) {
+	doc.warnings = nil
+
+	// Encode texts again to report their glyph substitutions
+	doc.glyphs = nil
+	doc.encoded = nil
+
 	// Validate document data
 	if err := doc.Validate(); err != nil {
 		return nil, err
 	}
 
+	// Check totals consistency
+	if doc.Options.CheckInvariants {
+		if err := CheckInvariants(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare totals to the caller ones
+	if err := doc.checkExpectedTotals(); err != nil {
+		return nil, err
+	}
+
+	// Hide empty tax, discount and fields columns
+	doc.emptyCols = doc.emptyItemColumns()
+
 	// Build base doc
+	doc.compact = doc.Options.Density == DensityCompact && doc.Options.Layout != LayoutFolio && doc.Options.Layout != LayoutRoyalty
+	doc.pdf.SetCompression(!doc.Options.DisableCompression)
+	doc.applyBranding()
 	doc.pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
 	doc.pdf.SetXY(10, 10)
 	doc.pdf.SetTextColor(
@@ -24,72 +48,126 @@ func (doc *Document) Build() (*fpdf.Fpdf, error) {
 	)
 
 	// Set header
-	if doc.Header != nil {
+	if doc.hasSection(SectionHeader) && doc.Header != nil {
 		if err := doc.Header.applyHeader(doc); err != nil {
 			return nil, err
 		}
 	}
 
 	// Set footer
-	if doc.Footer != nil {
+	if doc.hasSection(SectionFooter) && doc.Footer != nil {
 		if err := doc.Footer.applyFooter(doc); err != nil {
 			return nil, err
 		}
 	}
 
+	// Keep page 1 content above the remittance stub
+	doc.applyRemittanceStub()
+
+	// Draw page tint and stripe behind content
+	doc.applyPageBackground()
+
+	// Wrap header and footer with print marks and gutter
+	doc.applyPrintLayout()
+
 	// Add first page
 	doc.pdf.AddPage()
 
 	// Load font
+	doc.registerFallbackFonts()
+	doc.registerGiroFont()
 	doc.pdf.SetFont(doc.Options.Font, "", 12)
 
-	// Appenf document title
-	doc.appendTitle()
-
-	// Appenf document metas (ref & version)
-	doc.appendMetas()
-
-	// Append company contact to doc
-	companyBottom := doc.Company.appendCompanyContactToDoc(doc)
-
-	// Append customer contact to doc
-	customerBottom := doc.Customer.appendCustomerContactToDoc(doc)
-
-	if customerBottom > companyBottom {
-		doc.pdf.SetXY(10, customerBottom)
-	} else {
-		doc.pdf.SetXY(10, companyBottom)
+	// Append sections in order
+	rendered := map[string]bool{}
+	for _, section := range doc.sections() {
+		switch section {
+		case SectionMeta:
+			doc.appendMetaSection()
+		case SectionParties:
+			doc.appendPartiesSection()
+		case SectionDetails:
+			if err := doc.appendDetailsSection(); err != nil {
+				return nil, err
+			}
+		case SectionItems:
+			// Leave room below total boxes in summary first layouts
+			if rendered[SectionTotals] {
+				doc.pdf.SetY(doc.pdf.GetY() + 10)
+			}
+			doc.appendItemsSection()
+			if err := doc.checkPageLimit(); err != nil {
+				return nil, err
+			}
+		case SectionNotes:
+			// Notes are drawn beside totals
+			if !rendered[SectionTotals] {
+				doc.checkTotalsHeight()
+			}
+			doc.appendNotes()
+		case SectionTotals:
+			if !rendered[SectionNotes] {
+				doc.checkTotalsHeight()
+			}
+			doc.appendTotalsSection()
+		case SectionPayment:
+			if err := doc.appendPaymentSection(); err != nil {
+				return nil, err
+			}
+		case SectionOptions:
+			doc.appendQuoteOptions()
+		case SectionChart:
+			doc.appendChart()
+		case SectionLegal:
+			if err := doc.appendLegalSection(); err != nil {
+				return nil, err
+			}
+		case SectionAcceptance:
+			doc.appendAcceptance()
+		case SectionBoleto:
+			if err := doc.appendBoleto(); err != nil {
+				return nil, err
+			}
+		case SectionGiro:
+			doc.appendGiro()
+		case SectionRemittance:
+			doc.appendRemittanceStub()
+		case SectionTransactions:
+			doc.appendSettlementTransactions()
+		case SectionAppendix:
+			doc.appendDescriptionAppendix()
+		}
+		rendered[section] = true
 	}
 
-	// Append description
-	doc.appendDescription()
-
-	// Append items
-	doc.appendItems()
+	// Back pages start blank in duplex
+	doc.padDuplexPages()
 
-	// Check page height (total bloc height = 30, 45 when doc discount)
-	offset := doc.pdf.GetY() + 30
-	if doc.Discount != nil {
-		offset += 15
-	}
-	if offset > MaxPageHeight {
-		doc.pdf.AddPage()
+	// Append pages annotations
+	if err := doc.appendAnnotations(); err != nil {
+		return nil, err
 	}
 
-	// Append notes
-	doc.appendNotes()
+	// Close print layout transform before output
+	doc.endPageShift()
 
-	// Append total
-	doc.appendTotal()
+	// Mark quotes past their validity date
+	doc.appendExpiredWatermark()
 
-	// Append payment term
-	doc.appendPaymentTerm()
+	// Attach rules used to compute totals
+	if err := doc.attachRulesSnapshot(); err != nil {
+		return nil, err
+	}
 
 	// Append js to autoprint if AutoPrint == true
 	if doc.Options.AutoPrint {
 		doc.pdf.SetJavascript("print(true);")
 	}
 
+	if err := doc.checkPageLimit(); err != nil {
+		return nil, err
+	}
+
 	return doc.pdf, nil
 }
 
@@ -100,13 +178,16 @@ func (doc *Document) appendTitle() {
 	// Set x y
 	doc.pdf.SetXY(120, BaseMarginTop)
 
-	// Draw rect
-	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, BaseMarginTop, 80, 10, "F")
+	// Draw band or rect
+	if !doc.appendTitleBand() {
+		doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+		doc.pdf.Rect(120, BaseMarginTop, 80, 10, "F")
+	}
 
 	// Draw text
 	doc.pdf.SetFont(doc.Options.Font, "", 14)
-	doc.pdf.CellFormat(80, 10, doc.encodeString(title), "0", 0, "C", false, 0, "")
+	doc.cellFormat(80, 10, title, "C", doc.Options.Font, "")
+	doc.pdf.SetTextColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
 }
 
 // appendMetas to document
@@ -114,35 +195,50 @@ func (doc *Document) appendMetas() {
 	// Append ref
 	refString := fmt.Sprintf("%s: %s", doc.Options.TextRefTitle, doc.Ref)
 
-	doc.pdf.SetXY(120, BaseMarginTop+11)
+	// Below title, or in the letter info block
+	x, y, width, align := 120.0, BaseMarginTop+11, 80.0, "R"
+	if infoX, infoY, ok := doc.infoBlock(); ok {
+		x, y, width, align = infoX, infoY, 200-infoX, "L"
+	}
+
+	doc.pdf.SetXY(x, y)
 	doc.pdf.SetFont(doc.Options.Font, "", 8)
-	doc.pdf.CellFormat(80, 4, doc.encodeString(refString), "0", 0, "R", false, 0, "")
+	doc.cellFormat(width, 4, refString, align, doc.Options.Font, "")
 
 	// Append version
 	if len(doc.Version) > 0 {
 		versionString := fmt.Sprintf("%s: %s", doc.Options.TextVersionTitle, doc.Version)
-		doc.pdf.SetXY(120, BaseMarginTop+15)
+		doc.pdf.SetXY(x, y+4)
 		doc.pdf.SetFont(doc.Options.Font, "", 8)
-		doc.pdf.CellFormat(80, 4, doc.encodeString(versionString), "0", 0, "R", false, 0, "")
+		doc.cellFormat(width, 4, versionString, align, doc.Options.Font, "")
 	}
 
 	// Append date
-	date := time.Now().Format("02/01/2006")
+	date := time.Now().Format(doc.Options.DateFormat)
 	if len(doc.Date) > 0 {
 		date = doc.Date
 	}
-	dateString := fmt.Sprintf("%s: %s", doc.Options.TextDateTitle, date)
-	doc.pdf.SetXY(120, BaseMarginTop+19)
+	dateString := fmt.Sprintf("%s: %s", doc.Options.TextDateTitle, doc.dateDigits(date))
+	doc.pdf.SetXY(x, y+8)
 	doc.pdf.SetFont(doc.Options.Font, "", 8)
-	doc.pdf.CellFormat(80, 4, doc.encodeString(dateString), "0", 0, "R", false, 0, "")
+	doc.cellFormat(width, 4, dateString, align, doc.Options.Font, "")
 }
 
 // appendDescription to document
 func (doc *Document) appendDescription() {
 	if len(doc.Description) > 0 {
+		if doc.compact {
+			doc.pdf.SetY(doc.pdf.GetY() + 5)
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+			doc.pdf.MultiCell(190, 4, doc.encodeString(doc.wrapText(doc.Description, 190)), "0", "L", false)
+			doc.drawRule(BaseMargin, BaseMargin+190, doc.pdf.GetY())
+			return
+		}
+
 		doc.pdf.SetY(doc.pdf.GetY() + 10)
 		doc.pdf.SetFont(doc.Options.Font, "", 10)
-		doc.pdf.MultiCell(190, 5, doc.encodeString(doc.Description), "B", "L", false)
+		doc.pdf.MultiCell(190, 5, doc.encodeString(doc.wrapText(doc.Description, 190)), "0", "L", false)
+		doc.drawRule(BaseMargin, BaseMargin+190, doc.pdf.GetY())
 	}
 }
 
@@ -157,73 +253,28 @@ func (doc *Document) drawsTableTitles() {
 	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
 	doc.pdf.Rect(10, doc.pdf.GetY(), 190, 6, "F")
 
-	// Name
-	doc.pdf.SetX(ItemColNameOffset)
-	doc.pdf.CellFormat(
-		ItemColUnitPriceOffset-ItemColNameOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsNameTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Unit price
-	doc.pdf.SetX(ItemColUnitPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsUnitCostTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	doc.pdf.SetX(ItemColQuantityOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsQuantityTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	doc.pdf.SetX(ItemColTotalHTOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsTotalHTTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	// Columns titles
+	for _, column := range doc.itemColumns() {
+		doc.pdf.SetX(column.x)
+		doc.cellFormat(column.width, 6, doc.itemColumnTitle(column.ItemColumn), column.Align, doc.Options.BoldFont, "B")
+	}
 }
 
 // appendItems to document
 func (doc *Document) appendItems() {
+	doc.appendItemRows(doc.Items)
+}
+
+// appendItemRows draw table titles then items rows, titles repeated on each new page
+func (doc *Document) appendItemRows(items []*Item) {
 	doc.drawsTableTitles()
 
 	doc.pdf.SetX(10)
 	doc.pdf.SetY(doc.pdf.GetY() + 8)
-	doc.pdf.SetFont(doc.Options.Font, "", 8)
+	doc.pdf.SetFont(doc.Options.Font, "", doc.itemsFontSize())
 
-	for i := 0; i < len(doc.Items); i++ {
-		item := doc.Items[i]
+	for i := 0; i < len(items); i++ {
+		item := items[i]
 
 		// Check item tax
 		if item.Tax == nil {
@@ -233,15 +284,18 @@ func (doc *Document) appendItems() {
 		// Append to pdf
 		item.appendColTo(doc.Options, doc)
 
-		if doc.pdf.GetY() > MaxPageHeight {
+		if doc.pdf.GetY() > doc.pageBottom() {
 			// Add page
 			doc.pdf.AddPage()
+			if doc.pageLimitReached() {
+				return
+			}
 			doc.drawsTableTitles()
-			doc.pdf.SetFont(doc.Options.Font, "", 8)
+			doc.pdf.SetFont(doc.Options.Font, "", doc.itemsFontSize())
 		}
 
 		doc.pdf.SetX(10)
-		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.pdf.SetY(doc.pdf.GetY() + doc.itemsSpacing())
 	}
 }
 
@@ -251,16 +305,22 @@ func (doc *Document) appendNotes() {
 		return
 	}
 
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	_, lineHt := doc.pdf.GetFontSize()
+
+	// Move notes to next page rather than splitting them
+	doc.keepBlockTogether(BlockNotes, 10+doc.textHeight(doc.Notes, 100, lineHt))
+
 	currentY := doc.pdf.GetY()
 
-	doc.pdf.SetFont(doc.Options.Font, "", 9)
 	doc.pdf.SetX(BaseMargin)
 	doc.pdf.SetRightMargin(100)
 	doc.pdf.SetY(currentY + 10)
 
-	_, lineHt := doc.pdf.GetFontSize()
 	html := doc.pdf.HTMLBasicNew()
-	html.Write(lineHt, doc.encodeString(doc.Notes))
+	html.Write(lineHt, doc.encode(doc.Notes))
+
+	doc.notesBottom = doc.pdf.GetY()
 
 	doc.pdf.SetRightMargin(BaseMargin)
 	doc.pdf.SetY(currentY)
@@ -268,6 +328,8 @@ func (doc *Document) appendNotes() {
 
 // appendTotal to document
 func (doc *Document) appendTotal() {
+	subtotal, taxRate, tax, total := doc.totalsTexts()
+
 	doc.pdf.SetY(doc.pdf.GetY() + 10)
 	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
 	doc.pdf.SetTextColor(
@@ -275,21 +337,20 @@ func (doc *Document) appendTotal() {
 		doc.Options.BaseTextColor[1],
 		doc.Options.BaseTextColor[2],
 	)
+	doc.appendTotalsBand(30)
 
 	// Draw TOTAL HT title
 	doc.pdf.SetX(120)
-	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString("SUBTOTAL"), "0", 0, "R", false, 0, "")
+	doc.fillTotalBox(120, doc.Options.DarkBgColor)
+	doc.cellFormat(38, 10, doc.Options.TextTotalSubtotal, "R", doc.Options.Font, "")
 
 	// Draw TOTAL HT amount
 	doc.pdf.SetX(162)
-	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
+	doc.fillTotalBox(160, doc.Options.GreyBgColor)
 	doc.pdf.CellFormat(
 		40,
 		10,
-		doc.encodeString(doc.CustomSubtotal),
+		doc.encodeString(subtotal),
 		"0",
 		0,
 		"L",
@@ -302,18 +363,16 @@ func (doc *Document) appendTotal() {
 
 	// Draw tax title
 	doc.pdf.SetX(120)
-	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString("TAX (" + doc.CustomTaxRate + ")"), "0", 0, "R", false, 0, "")
+	doc.fillTotalBox(120, doc.Options.DarkBgColor)
+	doc.cellFormat(38, 10, doc.Options.TextTotalTax+" ("+taxRate+")", "R", doc.Options.Font, "")
 
 	// Draw tax amount
 	doc.pdf.SetX(162)
-	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
+	doc.fillTotalBox(160, doc.Options.GreyBgColor)
 	doc.pdf.CellFormat(
 		40,
 		10,
-		doc.encodeString(doc.CustomTax),
+		doc.encodeString(tax),
 		"0",
 		0,
 		"L",
@@ -325,18 +384,16 @@ func (doc *Document) appendTotal() {
 	// Draw total with tax title
 	doc.pdf.SetY(doc.pdf.GetY() + 10)
 	doc.pdf.SetX(120)
-	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
-	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString("TOTAL"), "0", 0, "R", false, 0, "")
+	doc.fillTotalBox(120, doc.Options.DarkBgColor)
+	doc.cellFormat(38, 10, doc.Options.TextTotalTotal, "R", doc.Options.Font, "")
 
 	// Draw total with tax amount
 	doc.pdf.SetX(162)
-	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
-	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
+	doc.fillTotalBox(160, doc.Options.GreyBgColor)
 	doc.pdf.CellFormat(
 		40,
 		10,
-		doc.encodeString(doc.CustomTotal),
+		doc.encodeString(total),
 		"0",
 		0,
 		"L",
@@ -346,6 +403,38 @@ func (doc *Document) appendTotal() {
 	)
 }
 
+// appendTotalLine append a title / amount line at current Y, using the total boxes style
+func (doc *Document) appendTotalLine(title string, amount string) {
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+	doc.appendTotalsBand(10)
+
+	// Draw title
+	doc.pdf.SetX(120)
+	doc.fillTotalBox(120, doc.Options.DarkBgColor)
+	doc.cellFormat(38, 10, title, "R", doc.Options.Font, "")
+
+	// Draw amount
+	doc.pdf.SetX(162)
+	doc.fillTotalBox(160, doc.Options.GreyBgColor)
+	doc.pdf.CellFormat(40, 10, doc.encodeString(amount), "0", 0, "L", false, 0, "")
+}
+
+// blockY return Y where a full width block of given height can start below totals and notes,
+// a page is added when the block does not fit
+func (doc *Document) blockY(height float64) float64 {
+	y := doc.pdf.GetY() + 15
+	if doc.notesBottom > y {
+		y = doc.notesBottom + 5
+	}
+
+	if y+height > doc.pageBottom() {
+		doc.pdf.AddPage()
+		y = doc.pdf.GetY()
+	}
+
+	return y
+}
+
 // appendPaymentTerm to document
 func (doc *Document) appendPaymentTerm() {
 	if len(doc.PaymentTerm) > 0 {
@@ -354,6 +443,7 @@ func (doc *Document) appendPaymentTerm() {
 			doc.encodeString(doc.Options.TextPaymentTermTitle),
 			doc.encodeString(doc.PaymentTerm),
 		)
+		doc.keepBlockTogether(BlockPaymentTerm, 19)
 		doc.pdf.SetY(doc.pdf.GetY() + 15)
 
 		doc.pdf.SetX(120)
@@ -361,3 +451,41 @@ func (doc *Document) appendPaymentTerm() {
 		doc.pdf.CellFormat(80, 4, doc.encodeString(paymentTermString), "0", 0, "R", false, 0, "")
 	}
 }
+
+// appendBankDetails to document, below notes, or the direct debit pre-notification collecting it
+func (doc *Document) appendBankDetails() {
+	if doc.DirectDebit != nil {
+		doc.appendDirectDebit()
+		return
+	}
+
+	if len(doc.BankDetails) == 0 {
+		return
+	}
+
+	y := doc.pdf.GetY() + 10
+	if doc.notesBottom > y {
+		y = doc.notesBottom + 5
+	}
+	doc.pdf.SetY(y)
+
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	_, lineHt := doc.pdf.GetFontSize()
+
+	// Move bank details to next page rather than splitting them
+	doc.keepBlockTogether(BlockBankDetails, 6+doc.textHeight(doc.BankDetails, 190, lineHt))
+
+	// Title after the bank icon
+	offset := 0.0
+	if icons := doc.icons(); icons != nil {
+		offset = doc.drawIcon(icons, IconBank, BaseMargin, doc.pdf.GetY()+(6-icons.Size)/2)
+	}
+	doc.pdf.SetX(BaseMargin + offset)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 9)
+	doc.pdf.CellFormat(190-offset, 6, doc.encodeString(doc.Options.TextBankDetailsTitle), "0", 2, "", false, 0, "")
+	doc.pdf.SetX(BaseMargin)
+
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	html := doc.pdf.HTMLBasicNew()
+	html.Write(lineHt, doc.encode(doc.bankDigits(doc.BankDetails, true)))
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidGSTHSTNumber when GST/HST registration number is missing or invalid
+var ErrInvalidGSTHSTNumber = errors.New("invalid gst/hst registration number")
+
+// ErrInvalidQSTNumber when QST registration number is missing or invalid
+var ErrInvalidQSTNumber = errors.New("invalid qst registration number")
+
+var (
+	gstHSTNumberRegexp = regexp.MustCompile(`^[0-9]{9}RT[0-9]{4}$`)
+	qstNumberRegexp    = regexp.MustCompile(`^[0-9]{10}TQ[0-9]{4}$`)
+)
+
+// Canadian harmonized sales tax rates per province, other provinces use GST only
+var canadianHSTRates = map[string]string{
+	"NB": "15",
+	"NL": "15",
+	"NS": "14",
+	"ON": "13",
+	"PE": "15",
+}
+
+// Canadian federal and Quebec sales tax rates
+const (
+	canadianGSTRate string = "5"
+	canadianQSTRate string = "9.975"
+)
+
+// CanadianTax define Canadian sales taxes registration numbers.
+// Items tax percent is the combined rate of the place of supply (ex 14.975 in Quebec).
+type CanadianTax struct {
+	Province     string `json:"province,omitempty" validate:"required,oneof=AB BC MB NB NL NS NT NU ON PE QC SK YT"`
+	GSTHSTNumber string `json:"gst_hst_number,omitempty"` // ex 123456789RT0001
+	QSTNumber    string `json:"qst_number,omitempty"`     // Quebec only, ex 1234567890TQ0001
+}
+
+// CanadianTaxLine define a GST, HST or QST line of document
+type CanadianTaxLine struct {
+	Name               string
+	Rate               decimal.Decimal
+	Base               decimal.Decimal
+	Amount             decimal.Decimal
+	RegistrationNumber string
+}
+
+// Prepare check registration numbers formats
+func (c *CanadianTax) Prepare() error {
+	c.GSTHSTNumber = strings.ToUpper(strings.ReplaceAll(c.GSTHSTNumber, " ", ""))
+	c.QSTNumber = strings.ToUpper(strings.ReplaceAll(c.QSTNumber, " ", ""))
+
+	if !gstHSTNumberRegexp.MatchString(c.GSTHSTNumber) || !validBusinessNumber(c.GSTHSTNumber[:9]) {
+		return ErrInvalidGSTHSTNumber
+	}
+
+	if c.Province == "QC" && !qstNumberRegexp.MatchString(c.QSTNumber) {
+		return ErrInvalidQSTNumber
+	}
+
+	return nil
+}
+
+// validBusinessNumber check the 9 digits business number Luhn checksum
+func validBusinessNumber(bn string) bool {
+	sum := 0
+	for i, r := range bn {
+		digit := int(r - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// CanadianTaxLines return GST/HST and QST lines computed on taxable items base
+func (doc *Document) CanadianTaxLines() []*CanadianTaxLine {
+	if doc.CanadianTax == nil {
+		return nil
+	}
+
+	base := decimal.Zero
+	for _, line := range doc.TaxLines() {
+		if line.Type == TaxTypePercent && line.Rate.IsPositive() {
+			base = base.Add(line.Base)
+		}
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	newLine := func(name string, rate string, number string) *CanadianTaxLine {
+		percent := decimal.RequireFromString(rate)
+		return &CanadianTaxLine{
+			Name:               name,
+			Rate:               percent,
+			Base:               base,
+			Amount:             base.Mul(percent).Div(decimal.NewFromInt(100)).Round(precision),
+			RegistrationNumber: number,
+		}
+	}
+
+	if rate, ok := canadianHSTRates[doc.CanadianTax.Province]; ok {
+		return []*CanadianTaxLine{newLine(doc.Options.TextCanadianHSTTitle, rate, doc.CanadianTax.GSTHSTNumber)}
+	}
+
+	lines := []*CanadianTaxLine{newLine(doc.Options.TextCanadianGSTTitle, canadianGSTRate, doc.CanadianTax.GSTHSTNumber)}
+	if doc.CanadianTax.Province == "QC" {
+		lines = append(lines, newLine(doc.Options.TextCanadianQSTTitle, canadianQSTRate, doc.CanadianTax.QSTNumber))
+	}
+
+	return lines
+}
+
+// appendCanadianTaxLines append GST/HST and QST lines with registration numbers under totals
+func (doc *Document) appendCanadianTaxLines() {
+	lines := doc.CanadianTaxLines()
+	if len(lines) == 0 {
+		return
+	}
+
+	// Current Y is the top of the total line
+	y := doc.pdf.GetY()
+	rowY := y + 10.5
+
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	for _, line := range lines {
+		title := fmt.Sprintf(
+			"%s %s%% (%s %s)",
+			line.Name,
+			line.Rate.String(),
+			doc.Options.TextCanadianRegistrationTitle,
+			line.RegistrationNumber,
+		)
+
+		doc.pdf.SetXY(100, rowY)
+		doc.pdf.CellFormat(60, 4, doc.encodeString(title), "0", 0, "R", false, 0, "")
+		doc.pdf.SetX(162)
+		doc.pdf.CellFormat(38, 4, doc.encodeString(doc.formatMoney(line.Amount)), "0", 0, "L", false, 0, "")
+		rowY += 4
+	}
+
+	doc.pdf.SetY(y + float64(len(lines))*4)
+}
@@ -0,0 +1,20 @@
+package generator
+
+import "testing"
+
+func TestCanadianTaxPrepare(t *testing.T) {
+	tax := &CanadianTax{Province: "QC", GSTHSTNumber: "123456782 RT0001", QSTNumber: "1234567890TQ0001"}
+	if err := tax.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+
+	tax.GSTHSTNumber = "123456789RT0001"
+	if err := tax.Prepare(); err != ErrInvalidGSTHSTNumber {
+		t.Errorf("expected %v, got %v", ErrInvalidGSTHSTNumber, err)
+	}
+
+	tax = &CanadianTax{Province: "QC", GSTHSTNumber: "123456782RT0001", QSTNumber: "1234567890RT0001"}
+	if err := tax.Prepare(); err != ErrInvalidQSTNumber {
+		t.Errorf("expected %v, got %v", ErrInvalidQSTNumber, err)
+	}
+}
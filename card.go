@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"github.com/creasty/defaults"
+)
+
+// Card define the style of card blocks: a rounded box with a subtle border and a drop shadow,
+// see Options.Card and Page.Card
+type Card struct {
+	Radius      float64 `default:"2" json:"radius,omitempty" validate:"min=0,max=20"` // Corners radius in millimeters
+	FillColor   []int   `default:"[255,255,255]" json:"fill_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	BorderColor []int   `default:"[222,222,222]" json:"border_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	BorderWidth float64 `default:"0.2" json:"border_width,omitempty" validate:"min=0,max=5"`
+
+	NoShadow     bool    `json:"no_shadow,omitempty"`
+	ShadowOffset float64 `default:"0.8" json:"shadow_offset,omitempty" validate:"min=0,max=10"` // Down and right, in millimeters
+	ShadowColor  []int   `default:"[0,0,0]" json:"shadow_color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	ShadowAlpha  float64 `default:"0.08" json:"shadow_alpha,omitempty" validate:"min=0,max=1"`
+}
+
+// card return Options.Card with defaults
+func (doc *Document) card() *Card {
+	card := &Card{}
+	if doc.Options.Card != nil {
+		copied := *doc.Options.Card
+		card = &copied
+	}
+	_ = defaults.Set(card)
+
+	return card
+}
+
+// drawRoundedRect draw a rounded rectangle filled with fill color and stroked with border color and width,
+// nothing is filled or stroked when its color is empty
+func (doc *Document) drawRoundedRect(
+	x float64,
+	y float64,
+	width float64,
+	height float64,
+	radius float64,
+	fill []int,
+	border []int,
+	borderWidth float64,
+) {
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+	defer doc.pdf.SetLineWidth(lineWidth)
+	defer doc.pdf.SetDrawColor(r, g, b)
+
+	style := ""
+	if len(fill) == 3 {
+		doc.pdf.SetFillColor(fill[0], fill[1], fill[2])
+		style += "F"
+	}
+	if len(border) == 3 && borderWidth > 0 {
+		doc.pdf.SetDrawColor(border[0], border[1], border[2])
+		doc.pdf.SetLineWidth(borderWidth)
+		style += "D"
+	}
+	if len(style) == 0 {
+		return
+	}
+
+	doc.pdf.RoundedRect(x, y, width, height, radius, "1234", style)
+}
+
+// drawCard draw a card block background with its shadow
+func (doc *Document) drawCard(x float64, y float64, width float64, height float64, card *Card) {
+	if !card.NoShadow && card.ShadowOffset > 0 && card.ShadowAlpha > 0 {
+		doc.pdf.SetAlpha(card.ShadowAlpha, "Normal")
+		offset := card.ShadowOffset
+		doc.drawRoundedRect(x+offset, y+offset, width, height, card.Radius, card.ShadowColor, nil, 0)
+		doc.pdf.SetAlpha(1, "Normal")
+	}
+
+	doc.drawRoundedRect(x, y, width, height, card.Radius, card.FillColor, card.BorderColor, card.BorderWidth)
+}
+
+// RoundedRect draw a rounded rectangle at x, y in millimeters, filled with fill color and stroked with
+// a 0.2 mm border color, nothing is filled or stroked when its color is nil
+func (p *Page) RoundedRect(x float64, y float64, width float64, height float64, radius float64, fill []int, border []int) {
+	p.doc.drawRoundedRect(x, y, width, height, radius, fill, border, 0.2)
+}
+
+// Card draw a card block background at x, y in millimeters with Options.Card style
+func (p *Page) Card(x float64, y float64, width float64, height float64) {
+	p.doc.drawCard(x, y, width, height, p.doc.card())
+}
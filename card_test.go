@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCard(t *testing.T) {
+	doc := newQuote()
+	doc.Options.Card = &Card{BorderColor: []int{200, 0, 0}, ShadowAlpha: 0.2}
+	doc.Options.Bands = &Bands{TotalsColor: []int{240, 244, 250}, TotalsCard: true}
+	doc.Options.AnnotatePage = func(page *Page) error {
+		page.Card(10, 250, 90, 20)
+		page.RoundedRect(110, 250, 90, 20, 4, []int{0, 80, 160}, nil)
+		return nil
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if card := doc.card(); card.Radius != 2 || card.BorderWidth != 0.2 || card.ShadowAlpha != 0.2 {
+		t.Errorf("expected card defaults with given shadow alpha, got %+v", card)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"0.784 0.000 0.000 RG", // Card border
+		"0.941 0.957 0.980 rg", // Totals card fill
+		"0.000 0.314 0.627 rg", // Rounded rect fill
+		"/ca 0.200 /CA 0.200",  // Shadow
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+// Chart types
+const (
+	ChartPie string = "pie"
+	ChartBar string = "bar"
+)
+
+// Maximum number of chart groups, smaller groups are merged in Options.TextChartOther
+const maxChartGroups = 6
+
+// chartColors colors of chart groups
+var chartColors = [][3]int{
+	{52, 101, 164},
+	{245, 121, 0},
+	{78, 154, 6},
+	{204, 0, 0},
+	{117, 80, 123},
+	{193, 125, 17},
+	{136, 138, 133},
+}
+
+// chartGroup define the amount of an item group
+type chartGroup struct {
+	Name    string
+	Amount  decimal.Decimal
+	Percent decimal.Decimal
+}
+
+// chartGroups return items net amounts grouped by Item.Group (item name when empty),
+// largest first. Negative and zero amounts are left out.
+func (doc *Document) chartGroups() []*chartGroup {
+	groups := []*chartGroup{}
+	groupsByName := map[string]*chartGroup{}
+	total := decimal.Zero
+
+	for _, item := range doc.Items {
+		amount := item.TotalWithoutTaxAndWithDiscount()
+		if !amount.IsPositive() {
+			continue
+		}
+
+		name := item.Group
+		if len(name) == 0 {
+			name = item.Name
+		}
+
+		group, ok := groupsByName[name]
+		if !ok {
+			group = &chartGroup{Name: name}
+			groups = append(groups, group)
+			groupsByName[name] = group
+		}
+		group.Amount = group.Amount.Add(amount)
+		total = total.Add(amount)
+	}
+
+	// Largest groups first, stable for equal amounts
+	for i := 1; i < len(groups); i++ {
+		for j := i; j > 0 && groups[j].Amount.GreaterThan(groups[j-1].Amount); j-- {
+			groups[j], groups[j-1] = groups[j-1], groups[j]
+		}
+	}
+
+	if len(groups) > maxChartGroups {
+		other := &chartGroup{Name: doc.Options.TextChartOther}
+		for _, group := range groups[maxChartGroups-1:] {
+			other.Amount = other.Amount.Add(group.Amount)
+		}
+		groups = append(groups[:maxChartGroups-1], other)
+	}
+
+	for _, group := range groups {
+		group.Percent = group.Amount.Mul(decimal.NewFromInt(100)).Div(total).Round(1)
+	}
+
+	return groups
+}
+
+// chartLabel return group legend label ex "Hosting 1 200.00 € (60 %)"
+func (doc *Document) chartLabel(group *chartGroup) string {
+	return fmt.Sprintf("%s %s (%s)", group.Name, doc.formatMoney(group.Amount), doc.formatPercent(group.Percent.String()))
+}
+
+// appendChart append Options.Chart spend breakdown chart to document
+func (doc *Document) appendChart() {
+	if len(doc.Options.Chart) == 0 {
+		return
+	}
+
+	groups := doc.chartGroups()
+	if len(groups) == 0 {
+		return
+	}
+
+	height := 46.0
+	if doc.Options.Chart == ChartBar {
+		height = 10 + 6*float64(len(groups))
+	}
+	y := doc.blockY(height)
+
+	// Title
+	doc.pdf.SetXY(10, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 6, doc.encodeString(doc.Options.TextChartTitle), "0", 0, "", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	if doc.Options.Chart == ChartBar {
+		doc.appendBarChart(groups, y+8)
+	} else {
+		doc.appendPieChart(groups, y+8)
+	}
+
+	doc.pdf.SetY(y + height)
+}
+
+// appendPieChart draw groups as pie slices with a legend
+func (doc *Document) appendPieChart(groups []*chartGroup, y float64) {
+	radius := 18.0
+	cx, cy := 10+radius, y+radius
+
+	start := -90.0
+	for i, group := range groups {
+		sweep, _ := group.Percent.Float64()
+		end := start + sweep*3.6
+		if i == len(groups)-1 {
+			end = 270
+		}
+
+		// Slice as polygon, an arc vertex every 5 degrees at most
+		points := []fpdf.PointType{{X: cx, Y: cy}}
+		steps := int(math.Ceil((end-start)/5)) + 1
+		for step := 0; step <= steps; step++ {
+			angle := (start + (end-start)*float64(step)/float64(steps)) * math.Pi / 180
+			points = append(points, fpdf.PointType{X: cx + radius*math.Cos(angle), Y: cy + radius*math.Sin(angle)})
+		}
+
+		color := chartColors[i%len(chartColors)]
+		doc.pdf.SetFillColor(color[0], color[1], color[2])
+		doc.pdf.Polygon(points, "F")
+
+		// Legend
+		legendY := y + 4 + float64(i)*5
+		doc.pdf.Rect(cx+radius+10, legendY+1, 3, 3, "F")
+		doc.pdf.SetXY(cx+radius+15, legendY)
+		doc.pdf.CellFormat(120, 5, doc.encodeString(doc.chartLabel(group)), "0", 0, "", false, 0, "")
+
+		start = end
+	}
+}
+
+// appendBarChart draw groups as horizontal bars proportional to the largest group
+func (doc *Document) appendBarChart(groups []*chartGroup, y float64) {
+	largest := groups[0].Amount
+
+	for i, group := range groups {
+		rowY := y + float64(i)*6
+		width, _ := group.Amount.Div(largest).Mul(decimal.NewFromInt(80)).Float64()
+
+		doc.pdf.SetXY(10, rowY)
+		doc.pdf.CellFormat(50, 5, doc.encodeString(group.Name), "0", 0, "", false, 0, "")
+
+		color := chartColors[i%len(chartColors)]
+		doc.pdf.SetFillColor(color[0], color[1], color[2])
+		doc.pdf.Rect(60, rowY+0.5, math.Max(width, 0.5), 4, "F")
+
+		doc.pdf.SetXY(60+width+2, rowY)
+		doc.pdf.CellFormat(50, 5, doc.encodeString(fmt.Sprintf("%s (%s)", doc.formatMoney(group.Amount), doc.formatPercent(group.Percent.String()))), "0", 0, "", false, 0, "")
+	}
+}
+
+// ChartSVG return the spend breakdown chart as SVG markup, to be inlined in HTML statements.
+// Options.Chart select the chart type, pie when empty.
+func (doc *Document) ChartSVG() string {
+	groups := doc.chartGroups()
+	svg := &strings.Builder{}
+
+	if doc.Options.Chart == ChartBar {
+		fmt.Fprintf(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 480 %d" font-family="sans-serif" font-size="12">`, 24*len(groups)+4)
+		for i, group := range groups {
+			width, _ := group.Amount.Div(groups[0].Amount).Mul(decimal.NewFromInt(240)).Float64()
+			color := chartColors[i%len(chartColors)]
+			y := 24*i + 4
+
+			fmt.Fprintf(svg, `<text x="0" y="%d">%s</text>`, y+14, html.EscapeString(group.Name))
+			fmt.Fprintf(svg, `<rect x="140" y="%d" width="%.2f" height="18" fill="rgb(%d,%d,%d)"/>`, y, math.Max(width, 1), color[0], color[1], color[2])
+			fmt.Fprintf(svg, `<text x="%.2f" y="%d">%s</text>`, 146+width, y+14, html.EscapeString(fmt.Sprintf("%s (%s)", doc.formatMoney(group.Amount), doc.formatPercent(group.Percent.String()))))
+		}
+		svg.WriteString(`</svg>`)
+		return svg.String()
+	}
+
+	svg.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 480 160" font-family="sans-serif" font-size="12">`)
+	start := -90.0
+	for i, group := range groups {
+		sweep, _ := group.Percent.Float64()
+		end := start + sweep*3.6
+		if i == len(groups)-1 {
+			end = 270
+		}
+		color := chartColors[i%len(chartColors)]
+
+		if len(groups) == 1 {
+			fmt.Fprintf(svg, `<circle cx="80" cy="80" r="70" fill="rgb(%d,%d,%d)"/>`, color[0], color[1], color[2])
+		} else {
+			largeArc := 0
+			if end-start > 180 {
+				largeArc = 1
+			}
+			x1, y1 := 80+70*math.Cos(start*math.Pi/180), 80+70*math.Sin(start*math.Pi/180)
+			x2, y2 := 80+70*math.Cos(end*math.Pi/180), 80+70*math.Sin(end*math.Pi/180)
+			fmt.Fprintf(svg, `<path d="M80 80 L%.2f %.2f A70 70 0 %d 1 %.2f %.2f Z" fill="rgb(%d,%d,%d)"/>`, x1, y1, largeArc, x2, y2, color[0], color[1], color[2])
+		}
+
+		y := 20 + 20*i
+		fmt.Fprintf(svg, `<rect x="170" y="%d" width="12" height="12" fill="rgb(%d,%d,%d)"/>`, y, color[0], color[1], color[2])
+		fmt.Fprintf(svg, `<text x="190" y="%d">%s</text>`, y+11, html.EscapeString(doc.chartLabel(group)))
+
+		start = end
+	}
+	svg.WriteString(`</svg>`)
+
+	return svg.String()
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChart(t *testing.T) {
+	for _, chart := range []string{ChartPie, ChartBar} {
+		doc, _ := New(Invoice, &Options{Chart: chart})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: "Compute", Group: "Infrastructure", UnitCost: "300", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Storage", Group: "Infrastructure", UnitCost: "100", Quantity: "1"})
+		doc.AppendItem(&Item{Name: "Seats", Group: "Licenses", UnitCost: "20", Quantity: "5"})
+		doc.AppendItem(&Item{Name: "Credit", UnitCost: "-50", Quantity: "1"})
+		doc.Options.AllowNegativeLines = true
+
+		if _, err := doc.Build(); err != nil {
+			t.Fatal(err)
+		}
+
+		groups := doc.chartGroups()
+		if len(groups) != 2 || groups[0].Name != "Infrastructure" || groups[0].Percent.String() != "80" {
+			t.Errorf("unexpected groups %+v", groups)
+		}
+
+		svg := doc.ChartSVG()
+		if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "Licenses") {
+			t.Errorf("unexpected svg %s", svg)
+		}
+	}
+}
+
+func TestChartOtherGroup(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	for _, name := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		doc.AppendItem(&Item{Name: name, UnitCost: "10", Quantity: "1"})
+	}
+
+	groups := doc.chartGroups()
+	if len(groups) != maxChartGroups || groups[maxChartGroups-1].Name != "Other" || groups[maxChartGroups-1].Amount.String() != "30" {
+		t.Errorf("unexpected groups %+v", groups[maxChartGroups-1])
+	}
+}
@@ -0,0 +1,197 @@
+// Command preview serve a live-reloading preview of a JSON document while editing it.
+//
+//	go run ./cmd/preview -document invoice.json -theme theme.json -addr localhost:8080
+//
+// The document file holds a generator.Document (with its "type"), the optional theme file
+// holds generator.Options applied over document options (colors, fonts, texts...).
+// The browser page reloads the PDF when one of the files changes and shows build errors.
+// A JSON document posted to /document.pdf is built instead of the document file, with the theme.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	generator "github.com/ebtsi/go-invoice-generator"
+)
+
+// page is the preview page, polling files version and reloading the PDF frame
+var page = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.}} preview</title>
+<style>
+body { margin: 0; font-family: sans-serif; }
+#error { display: none; padding: 1em; background: #fdd; color: #900; white-space: pre-wrap; }
+iframe { border: 0; width: 100vw; height: 100vh; }
+</style>
+</head>
+<body>
+<div id="error"></div>
+<iframe id="pdf"></iframe>
+<script>
+let version = "";
+async function refresh() {
+	try {
+		const current = await (await fetch("/version")).text();
+		if (current !== version) {
+			version = current;
+			const res = await fetch("/document.pdf?v=" + encodeURIComponent(version));
+			const error = document.getElementById("error");
+			if (res.ok) {
+				error.style.display = "none";
+				document.getElementById("pdf").src = URL.createObjectURL(await res.blob());
+			} else {
+				error.textContent = await res.text();
+				error.style.display = "block";
+			}
+		}
+	} catch (e) {}
+	setTimeout(refresh, 500);
+}
+refresh();
+</script>
+</body>
+</html>
+`))
+
+// preview build documents from watched files
+type preview struct {
+	documentPath string
+	themePath    string
+}
+
+// version return a string changing when a watched file changes
+func (p *preview) version() string {
+	version := ""
+	for _, path := range []string{p.documentPath, p.themePath} {
+		if len(path) == 0 {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			version += fmt.Sprintf("%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+
+	return version
+}
+
+// maxPostSize is the maximum size of a posted JSON document
+const maxPostSize int64 = 10 << 20
+
+// build read watched files and return the generated PDF
+func (p *preview) build() ([]byte, error) {
+	source, err := os.ReadFile(p.documentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.buildSource(source)
+}
+
+// buildSource return the PDF of JSON document source, with the theme file applied
+func (p *preview) buildSource(source []byte) ([]byte, error) {
+	header := struct {
+		Type string `json:"type"`
+	}{Type: generator.Invoice}
+	if err := json.Unmarshal(source, &header); err != nil {
+		return nil, err
+	}
+
+	doc, err := generator.New(header.Type, &generator.Options{})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(source, doc); err != nil {
+		return nil, err
+	}
+
+	if len(p.themePath) > 0 {
+		theme, err := os.ReadFile(p.themePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(theme, doc.Options); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// handler return the preview page, files version and PDF handler
+func (p *preview) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = page.Execute(w, p.documentPath)
+	})
+
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write([]byte(p.version()))
+	})
+
+	mux.HandleFunc("/document.pdf", func(w http.ResponseWriter, r *http.Request) {
+		var pdf []byte
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			pdf, err = p.build()
+		case http.MethodPost:
+			var source []byte
+			if source, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxPostSize)); err == nil {
+				pdf, err = p.buildSource(source)
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(pdf)
+	})
+
+	return mux
+}
+
+func main() {
+	p := &preview{}
+	addr := flag.String("addr", "localhost:8080", "listen address")
+	flag.StringVar(&p.documentPath, "document", "invoice.json", "JSON document file")
+	flag.StringVar(&p.themePath, "theme", "", "JSON options file applied over document options")
+	flag.Parse()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           p.handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Printf("previewing %s on http://%s", p.documentPath, *addr)
+	log.Fatal(server.ListenAndServe())
+}
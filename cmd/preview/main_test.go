@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const document = `{
+	"type": "INVOICE",
+	"ref": "INV-1",
+	"company": {"name": "Company"},
+	"customer": {"name": "Customer"},
+	"items": [{"name": "Consulting", "unit_cost": "100", "quantity": "2"}]
+}`
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	p := &preview{documentPath: filepath.Join(dir, "invoice.json"), themePath: filepath.Join(dir, "theme.json")}
+	if err := os.WriteFile(p.themePath, []byte(`{"base_text_color": [10, 20, 30]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(p.handler())
+	defer server.Close()
+
+	// Posted document
+	res, err := http.Post(server.URL+"/document.pdf", "application/json", strings.NewReader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := &bytes.Buffer{}
+	_, _ = body.ReadFrom(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "application/pdf" {
+		t.Fatalf("expected PDF, got %d %s: %s", res.StatusCode, res.Header.Get("Content-Type"), body)
+	}
+	if !bytes.HasPrefix(body.Bytes(), []byte("%PDF-")) || !bytes.Contains(body.Bytes(), []byte("%%EOF")) {
+		t.Error("expected PDF of posted document")
+	}
+
+	// Invalid document
+	res, err = http.Post(server.URL+"/document.pdf", "application/json", strings.NewReader(`{"type": "INVOICE"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected %d for invalid document, got %d", http.StatusUnprocessableEntity, res.StatusCode)
+	}
+
+	// Watched document file
+	if err := os.WriteFile(p.documentPath, []byte(document), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	res, err = http.Get(server.URL + "/document.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected PDF of document file, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownComplianceProfile when Options.Compliance has no registered profile
+var ErrUnknownComplianceProfile = errors.New("unknown compliance profile")
+
+// ErrComplianceRequiredField when a field required by the compliance profile is missing,
+// wrapped with the field name ex "missing field required by compliance profile: Company.TaxID"
+var ErrComplianceRequiredField = errors.New("missing field required by compliance profile")
+
+// complianceRequiredField return ErrComplianceRequiredField wrapped with field name
+func complianceRequiredField(field string) error {
+	return fmt.Errorf("%w: %s", ErrComplianceRequiredField, field)
+}
+
+// ComplianceProfile bundle the invoicing rules of a country: required fields,
+// validations, mandated mentions and layout tweaks.
+type ComplianceProfile struct {
+	Country string
+
+	// Required fields
+	RequireCompanyTaxID  bool
+	RequireCustomerTaxID bool
+	RequireDate          bool
+	RequirePaymentTerm   bool
+
+	// Mentions appended at the end of invoices
+	Mentions []string
+
+	// Apply set country modes and defaults before validation (layout tweaks)
+	Apply func(doc *Document) error
+
+	// Validate run additional checks after document preparation
+	Validate func(doc *Document) error
+}
+
+// complianceProfilesMutex guard complianceProfiles, profiles can be registered while documents are built
+var complianceProfilesMutex sync.RWMutex
+
+// complianceProfiles registered by country code
+var complianceProfiles = map[string]*ComplianceProfile{
+	"FR": {
+		Country:             "FR",
+		RequireCompanyTaxID: true,
+		RequireDate:         true,
+		RequirePaymentTerm:  true,
+		Mentions: []string{
+			"En cas de retard de paiement, des pénalités de retard au taux de trois fois le taux d'intérêt légal sont exigibles, ainsi qu'une indemnité forfaitaire pour frais de recouvrement de 40 €.",
+			"Pas d'escompte pour paiement anticipé.",
+		},
+	},
+	"DE": {
+		Country:             "DE",
+		RequireCompanyTaxID: true,
+		RequireDate:         true,
+	},
+	"AU": {
+		Country:             "AU",
+		RequireCompanyTaxID: true,
+		Apply: func(doc *Document) error {
+			if doc.GST == nil {
+				doc.GST = &GST{Country: GSTCountryAU}
+			}
+			return nil
+		},
+	},
+	"NZ": {
+		Country:             "NZ",
+		RequireCompanyTaxID: true,
+		Apply: func(doc *Document) error {
+			if doc.GST == nil {
+				doc.GST = &GST{Country: GSTCountryNZ}
+			}
+			return nil
+		},
+	},
+	"ZA": {
+		Country:             "ZA",
+		RequireCompanyTaxID: true,
+		RequireDate:         true,
+		Apply: func(doc *Document) error {
+			if doc.SouthAfricanVAT == nil {
+				doc.SouthAfricanVAT = &SouthAfricanVAT{}
+			}
+			return nil
+		},
+	},
+	"CA": {
+		Country: "CA",
+		Validate: func(doc *Document) error {
+			if doc.CanadianTax == nil {
+				return complianceRequiredField("CanadianTax")
+			}
+			return nil
+		},
+	},
+	"PT": {
+		Country:             "PT",
+		RequireCompanyTaxID: true,
+		Validate: func(doc *Document) error {
+			if doc.PortugueseFiscal == nil {
+				return ErrMissingPortugueseFiscal
+			}
+			return nil
+		},
+	},
+	"ES": {
+		Country:             "ES",
+		RequireCompanyTaxID: true,
+		Validate: func(doc *Document) error {
+			if doc.SpanishFiscal == nil {
+				return complianceRequiredField("SpanishFiscal")
+			}
+			return nil
+		},
+	},
+	"TR": {
+		Country:             "TR",
+		RequireCompanyTaxID: true,
+		Validate: func(doc *Document) error {
+			if doc.TurkishFiscal == nil {
+				return complianceRequiredField("TurkishFiscal")
+			}
+			return nil
+		},
+	},
+	"BR": {
+		Country:             "BR",
+		RequireCompanyTaxID: true,
+		Validate: func(doc *Document) error {
+			if doc.BrazilianFiscal == nil {
+				return complianceRequiredField("BrazilianFiscal")
+			}
+			return nil
+		},
+	},
+}
+
+// RegisterComplianceProfile add or replace the compliance profile of a country
+func RegisterComplianceProfile(profile *ComplianceProfile) {
+	complianceProfilesMutex.Lock()
+	defer complianceProfilesMutex.Unlock()
+
+	complianceProfiles[strings.ToUpper(profile.Country)] = profile
+}
+
+// GetComplianceProfile return the compliance profile of a country, nil if none
+func GetComplianceProfile(country string) *ComplianceProfile {
+	complianceProfilesMutex.RLock()
+	defer complianceProfilesMutex.RUnlock()
+
+	return complianceProfiles[strings.ToUpper(country)]
+}
+
+// complianceProfile return the profile selected in options
+func (doc *Document) complianceProfile() (*ComplianceProfile, error) {
+	if len(doc.Options.Compliance) == 0 {
+		return nil, nil
+	}
+
+	profile := GetComplianceProfile(doc.Options.Compliance)
+	if profile == nil {
+		return nil, ErrUnknownComplianceProfile
+	}
+
+	return profile, nil
+}
+
+// applyCompliance apply the selected compliance profile before validation
+func (doc *Document) applyCompliance() error {
+	profile, err := doc.complianceProfile()
+	if err != nil || profile == nil {
+		return err
+	}
+
+	if profile.Apply != nil {
+		return profile.Apply(doc)
+	}
+
+	return nil
+}
+
+// validateCompliance check required fields and rules of the selected compliance profile
+func (doc *Document) validateCompliance() error {
+	profile, err := doc.complianceProfile()
+	if err != nil || profile == nil {
+		return err
+	}
+
+	if profile.RequireCompanyTaxID && len(doc.Company.TaxID) == 0 {
+		return complianceRequiredField("Company.TaxID")
+	}
+	if profile.RequireCustomerTaxID && (doc.Customer == nil || len(doc.Customer.TaxID) == 0) {
+		return complianceRequiredField("Customer.TaxID")
+	}
+	if profile.RequireDate && len(doc.Date) == 0 {
+		return complianceRequiredField("Date")
+	}
+	if profile.RequirePaymentTerm && doc.Type == Invoice && len(doc.PaymentTerm) == 0 {
+		return complianceRequiredField("PaymentTerm")
+	}
+
+	if profile.Validate != nil {
+		return profile.Validate(doc)
+	}
+
+	return nil
+}
+
+// appendComplianceMentions append the mentions mandated by the compliance profile
+func (doc *Document) appendComplianceMentions() {
+	profile, _ := doc.complianceProfile()
+	if profile == nil || len(profile.Mentions) == 0 || doc.Type != Invoice {
+		return
+	}
+
+	mentions := doc.encodeString(strings.Join(profile.Mentions, "\n"))
+
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	lines := doc.pdf.SplitLines([]byte(mentions), 190)
+
+	y := doc.blockY(float64(len(lines)) * 3)
+	doc.pdf.SetXY(BaseMargin, y)
+	doc.pdf.MultiCell(190, 3, mentions, "0", "L", false)
+}
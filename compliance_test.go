@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestComplianceProfile(t *testing.T) {
+	errCustom := errors.New("custom rule")
+	t.Cleanup(func() {
+		complianceProfilesMutex.Lock()
+		delete(complianceProfiles, "XX")
+		complianceProfilesMutex.Unlock()
+	})
+	RegisterComplianceProfile(&ComplianceProfile{
+		Country:             "xx",
+		RequireCompanyTaxID: true,
+		Validate: func(doc *Document) error {
+			if doc.Customer.TaxID != "XX1" {
+				return errCustom
+			}
+			return nil
+		},
+	})
+
+	doc, _ := New(Invoice, &Options{Compliance: "XX"})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+
+	if err := doc.Validate(); !errors.Is(err, ErrComplianceRequiredField) || err.Error() != "missing field required by compliance profile: Company.TaxID" {
+		t.Errorf("expected %v of Company.TaxID, got %v", ErrComplianceRequiredField, err)
+	}
+
+	doc.Company.TaxID = "XX0"
+	if err := doc.Validate(); err != errCustom {
+		t.Errorf("expected %v, got %v", errCustom, err)
+	}
+
+	doc.Customer.TaxID = "XX1"
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Options.Compliance = "YY"
+	if err := doc.Validate(); err != ErrUnknownComplianceProfile {
+		t.Errorf("expected %v, got %v", ErrUnknownComplianceProfile, err)
+	}
+}
+
+func TestRegisterComplianceProfileConcurrently(t *testing.T) {
+	t.Cleanup(func() {
+		complianceProfilesMutex.Lock()
+		delete(complianceProfiles, "ZZ")
+		complianceProfilesMutex.Unlock()
+	})
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			RegisterComplianceProfile(&ComplianceProfile{Country: "ZZ"})
+		}
+		done <- true
+	}()
+	for i := 0; i < 100; i++ {
+		GetComplianceProfile("FR")
+	}
+	<-done
+
+	if GetComplianceProfile("zz") == nil {
+		t.Error("expected registered profile")
+	}
+}
@@ -10,6 +10,25 @@ const (
 	// DeliveryNote define the "delievry note" document type
 	DeliveryNote string = "DELIVERY_NOTE"
 
+	// Reminder define the "payment reminder" document type
+	Reminder string = "REMINDER"
+
+	// DonationReceipt define the "donation receipt" document type
+	DonationReceipt string = "DONATION_RECEIPT"
+
+	// SettlementStatement define the "settlement statement" document type, marketplace payouts
+	SettlementStatement string = "SETTLEMENT_STATEMENT"
+
+	// LayoutDefault define the default document layout
+	LayoutDefault string = "default"
+
+	// LayoutFolio define the hotel folio layout, items are ordered and grouped by date
+	LayoutFolio string = "folio"
+
+	// LayoutRoyalty define the royalty statement layout, items are grouped by title (Item.Group) and period,
+	// followed by a rights holder summary page
+	LayoutRoyalty string = "royalty"
+
 	// BaseMargin define base margin used in documents
 	BaseMargin float64 = 10
 
@@ -21,6 +40,9 @@ const (
 
 	// MaxPageHeight define the maximum height for a single page
 	MaxPageHeight float64 = 260
+
+	// MaxEncodedStrings define the maximum number of encoded strings cached by a document
+	MaxEncodedStrings int = 4096
 )
 
 // Cols offsets
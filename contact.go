@@ -1,18 +1,21 @@
 package generator
 
 import (
-	"bytes"
 	b64 "encoding/base64"
-	"image"
-
-	"github.com/go-pdf/fpdf"
+	"strings"
 )
 
 // Contact contact a company informations
 type Contact struct {
 	Name    string   `json:"name,omitempty" validate:"required,min=1,max=256"`
-	Logo    []byte   `json:"logo,omitempty"` // Logo byte array
+	Logo    []byte   `json:"logo,omitempty"`                         // Logo byte array
+	LogoURL string   `json:"logo_url,omitempty" validate:"max=2048"` // Logo fetched with Options.Fetcher when Logo is empty
 	Address *Address `json:"address,omitempty"`
+	TaxID   string   `json:"tax_id,omitempty" validate:"max=64"` // VAT or tax registration number
+	NZBN    string   `json:"nzbn,omitempty" validate:"max=13"`   // New Zealand Business Number
+	Phone   string   `json:"phone,omitempty" validate:"max=64"`
+	Email   string   `json:"email,omitempty" validate:"max=256"`
+	Website string   `json:"website,omitempty" validate:"max=256"`
 
 	// AddtionnalInfo to append after contact informations. You can use basic html here (bold, italic tags).
 	AddtionnalInfo []string `json:"additional_info,omitempty"`
@@ -28,26 +31,13 @@ func (c *Contact) appendContactTODoc(
 ) float64 {
 	doc.pdf.SetXY(x, y)
 
-	// Logo
-	if c.Logo != nil {
+	// Logo, placeholder when its URL failed to fetch
+	if c.Logo != nil || len(c.LogoURL) > 0 {
 		// Create filename
 		fileName := b64.StdEncoding.EncodeToString([]byte(c.Name))
 
-		// Create reader from logo bytes
-		ioReader := bytes.NewReader(c.Logo)
-
-		// Get image format
-		_, format, _ := image.DecodeConfig(bytes.NewReader(c.Logo))
-
-		// Register image in pdf
-		imageInfo := doc.pdf.RegisterImageOptionsReader(fileName, fpdf.ImageOptions{
-			ImageType: format,
-		}, ioReader)
-
-		if imageInfo != nil {
-			var imageOpt fpdf.ImageOptions
-			imageOpt.ImageType = format
-			doc.pdf.ImageOptions(fileName, doc.pdf.GetX(), y, 0, 15, false, imageOpt, 0, "")
+		// Register image in pdf, downsampled following options
+		if drawn, _ := doc.drawImage(c.Name+" logo", fileName, c.Logo, doc.pdf.GetX(), y, 0, 15); drawn {
 			doc.pdf.SetY(y + 15)
 		}
 	}
@@ -75,23 +65,49 @@ func (c *Contact) appendContactTODoc(
 	doc.pdf.SetFont(doc.Options.Font, "", 10)
 
 	if c.Address != nil {
-		// Address rect
-		var addrRectHeight float64 = 17
+		address := c.addressString(doc)
 
-		if len(c.Address.Address2) > 0 {
-			addrRectHeight = addrRectHeight + 5
-		}
-
-		if len(c.Address.Country) == 0 {
-			addrRectHeight = addrRectHeight - 5
-		}
+		// Address rect, 5 per line
+		addrRectHeight := 5*float64(strings.Count(address, "\n")+1) + 2
 
 		doc.pdf.Rect(x, doc.pdf.GetY()+9, 70, addrRectHeight, "F")
 
 		// Set address
 		doc.pdf.SetFont(doc.Options.Font, "", 10)
 		doc.pdf.SetXY(x, doc.pdf.GetY()+10)
-		doc.pdf.MultiCell(70, 5, doc.encodeString(c.Address.ToString()), "0", "L", false)
+		doc.pdf.MultiCell(70, 5, doc.encodeString(address), "0", "L", false)
+	}
+
+	// Registration numbers
+	if lines := c.registrationLines(doc); len(lines) > 0 {
+		doc.pdf.SetFontSize(SmallTextFontSize)
+		doc.pdf.SetXY(x, doc.pdf.GetY()+2)
+
+		for _, line := range lines {
+			doc.pdf.SetXY(x, doc.pdf.GetY())
+			doc.pdf.MultiCell(70, 3, doc.encodeString(line), "0", "L", false)
+		}
+
+		doc.pdf.SetFontSize(BaseTextFontSize)
+	}
+
+	// Phone, email and website, after their icon when Options.Icons is set
+	if lines := c.contactLines(); len(lines) > 0 {
+		doc.pdf.SetFontSize(SmallTextFontSize)
+		doc.pdf.SetXY(x, doc.pdf.GetY()+2)
+
+		icons := doc.icons()
+		for _, line := range lines {
+			lineY := doc.pdf.GetY()
+			offset := 0.0
+			if icons != nil {
+				offset = doc.drawIcon(icons, line[0], x, lineY+(3-icons.Size)/2)
+			}
+			doc.pdf.SetXY(x+offset, lineY)
+			doc.pdf.MultiCell(70-offset, 3, doc.encodeString(line[1]), "0", "L", false)
+		}
+
+		doc.pdf.SetFontSize(BaseTextFontSize)
 	}
 
 	// Addtionnal info
@@ -112,6 +128,48 @@ func (c *Contact) appendContactTODoc(
 	return doc.pdf.GetY()
 }
 
+// addressString return the contact address lines, the customer one following the postal conventions of its
+// country when Options.PostalAddresses is set
+func (c *Contact) addressString(doc *Document) string {
+	if c != doc.Customer || !doc.Options.PostalAddresses {
+		return c.Address.ToString()
+	}
+
+	from := ""
+	if doc.Company != nil && doc.Company.Address != nil {
+		from = doc.Company.Address.Country
+	}
+
+	return c.Address.Format(from)
+}
+
+// registrationLines return the registration numbers displayed under contact
+func (c *Contact) registrationLines(doc *Document) []string {
+	lines := []string{}
+
+	if doc.GST != nil {
+		lines = append(lines, doc.GST.registrationLines(c, c == doc.Company, doc.Options)...)
+	}
+
+	if doc.SouthAfricanVAT != nil {
+		lines = append(lines, doc.SouthAfricanVAT.registrationLines(c, c == doc.Company, doc.Options)...)
+	}
+
+	return lines
+}
+
+// contactLines return icon name and text of phone, email and website
+func (c *Contact) contactLines() [][]string {
+	lines := [][]string{}
+	for _, line := range [][]string{{IconPhone, c.Phone}, {IconMail, c.Email}, {IconGlobe, c.Website}} {
+		if len(line[1]) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
 // appendCompanyContactToDoc append the company contact to the document
 func (c *Contact) appendCompanyContactToDoc(doc *Document) float64 {
 	x, y, _, _ := doc.pdf.GetMargins()
@@ -0,0 +1,90 @@
+package generator
+
+import "strings"
+
+// Densities
+const (
+	DensityCompact string = "compact"
+	DensityAuto    string = "auto"
+)
+
+// Items table header height, from table titles to first line
+const itemsTitlesHeight float64 = 19
+
+// itemsFontSize return items font size following density
+func (doc *Document) itemsFontSize() float64 {
+	if doc.compact {
+		return SmallTextFontSize
+	}
+
+	return BaseTextFontSize
+}
+
+// itemsSmallFontSize return items descriptions font size following density
+func (doc *Document) itemsSmallFontSize() float64 {
+	if doc.compact {
+		return ExtraSmallTextFontSize
+	}
+
+	return SmallTextFontSize
+}
+
+// itemsLineHeight return items text line height following density
+func (doc *Document) itemsLineHeight() float64 {
+	if doc.compact {
+		return 2.5
+	}
+
+	return 3
+}
+
+// itemsSpacing return space between items following density
+func (doc *Document) itemsSpacing() float64 {
+	if doc.compact {
+		return 3
+	}
+
+	return 6
+}
+
+// itemDescription return item description truncated to Options.MaxDescriptionLength, first line only in
+// compact density
+func (doc *Document) itemDescription(description string) string {
+	description = doc.truncateDescription(description)
+	if doc.compact {
+		return strings.SplitN(description, "\n", 2)[0]
+	}
+
+	return description
+}
+
+// itemsHeight return the estimated height of items table in current density
+func (doc *Document) itemsHeight() float64 {
+	height := itemsTitlesHeight
+
+	// Name column wrap lines, one line per item without it
+	name := doc.itemColumn(ItemColumnName)
+	if name == nil || name.Formatter != nil {
+		return height + float64(len(doc.Items))*(doc.itemsLineHeight()+doc.itemsSpacing())
+	}
+
+	for _, item := range doc.Items {
+		height += doc.itemHeight(item, name.width)
+	}
+
+	return height
+}
+
+// itemHeight return the estimated height of item row, its name and description wrapped in width
+func (doc *Document) itemHeight(item *Item, width float64) float64 {
+	doc.pdf.SetFont(doc.Options.Font, "", doc.itemsFontSize())
+	height := float64(len(doc.pdf.SplitLines([]byte(doc.encodeString(stripScripts(doc.wrapText(item.Name, width)))), width))) * doc.itemsLineHeight()
+
+	if len(item.Description) > 0 {
+		doc.pdf.SetFont(doc.Options.Font, "", doc.itemsSmallFontSize())
+		lines := doc.pdf.SplitLines([]byte(doc.encodeString(doc.wrapText(doc.itemDescription(item.Description), width))), width)
+		height += 1 + float64(len(lines))*doc.itemsLineHeight()
+	}
+
+	return height + doc.itemsSpacing()
+}
@@ -0,0 +1,46 @@
+package generator
+
+import "testing"
+
+func TestDensity(t *testing.T) {
+	pages := func(density string, items int) int {
+		doc, _ := New(Invoice, &Options{Density: density})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		for i := 0; i < items; i++ {
+			doc.AppendItem(&Item{Name: "Item", Description: "First line\nSecond line", UnitCost: "10", Quantity: "1"})
+		}
+
+		pdf, err := doc.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pdf.PageCount()
+	}
+
+	if count := pages("", 14); count != 2 {
+		t.Fatalf("expected 2 pages in normal density, got %d", count)
+	}
+	if count := pages(DensityAuto, 14); count != 1 {
+		t.Errorf("expected 1 page in auto density, got %d", count)
+	}
+	if count := pages(DensityCompact, 14); count != 1 {
+		t.Errorf("expected 1 page in compact density, got %d", count)
+	}
+
+	// Short documents keep normal density
+	if count := pages(DensityAuto, 3); count != 1 {
+		t.Errorf("expected 1 page, got %d", count)
+	}
+}
+
+func TestItemsHeightAccents(t *testing.T) {
+	doc, _ := New(Invoice, &Options{Density: DensityAuto})
+	doc.AppendItem(&Item{Name: "Développement", Description: "Réunion à Paris, café offert", UnitCost: "10", Quantity: "1"})
+	doc.pdf.SetFont(doc.Options.Font, "", 12)
+
+	if height := doc.itemsHeight(); height <= itemsTitlesHeight {
+		t.Errorf("expected items height above titles height, got %f", height)
+	}
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Change types
+const (
+	ChangeAdded   string = "added"
+	ChangeRemoved string = "removed"
+	ChangeChanged string = "changed"
+)
+
+// Change define a difference between two documents
+type Change struct {
+	Type  string          `json:"type"`
+	Path  string          `json:"path"` // ex customer.address.city, items[2].quantity, total_with_tax
+	Old   string          `json:"old,omitempty"`
+	New   string          `json:"new,omitempty"`
+	Delta decimal.Decimal `json:"delta,omitempty"` // New - old, for totals
+}
+
+// flattenValue add leaves of a decoded JSON value to fields, by path
+func flattenValue(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if len(path) > 0 {
+				childPath = path + "." + key
+			}
+			flattenValue(childPath, child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	case string:
+		fields[path] = v
+	case nil:
+	default:
+		encoded, _ := json.Marshal(v)
+		fields[path] = string(encoded)
+	}
+}
+
+// semanticFields return flattened semantic fields of value, as hashed by Document.Hash
+func semanticFields(path string, value interface{}) map[string]string {
+	source, _ := json.Marshal(value)
+
+	var decoded interface{}
+	_ = json.Unmarshal(source, &decoded)
+
+	fields := map[string]string{}
+	flattenValue(path, canonicalValue(decoded), fields)
+	return fields
+}
+
+// diffFields append changes between old and new fields, sorted by path
+func diffFields(changes []Change, old map[string]string, new map[string]string) []Change {
+	paths := []string{}
+	for path := range old {
+		paths = append(paths, path)
+	}
+	for path := range new {
+		if _, ok := old[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		oldValue, inOld := old[path]
+		newValue, inNew := new[path]
+
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Type: ChangeRemoved, Path: path, Old: oldValue})
+		case !inOld:
+			changes = append(changes, Change{Type: ChangeAdded, Path: path, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{Type: ChangeChanged, Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+// Diff return semantic differences from document a to b: fields, items added, removed
+// or changed (matched by name, in order) and totals deltas. Items paths use the index in a,
+// in b for added items. Presentation is ignored as in Document.Hash.
+// Documents should be validated so default taxes are applied to items.
+func Diff(a *Document, b *Document) []Change {
+	changes := []Change{}
+
+	// Document fields, items compared separately
+	docA, docB := *a, *b
+	docA.Items, docB.Items = nil, nil
+	changes = diffFields(changes, semanticFields("", &docA), semanticFields("", &docB))
+	itemsA, itemsB := a.Items, b.Items
+
+	// Items matched by name
+	matched := make([]bool, len(itemsB))
+	for i, itemA := range itemsA {
+		match := -1
+		for j, itemB := range itemsB {
+			if !matched[j] && itemB.Name == itemA.Name {
+				match = j
+				break
+			}
+		}
+
+		path := fmt.Sprintf("items[%d]", i)
+		if match < 0 {
+			changes = append(changes, Change{Type: ChangeRemoved, Path: path, Old: itemA.Name})
+			continue
+		}
+
+		matched[match] = true
+		changes = diffFields(changes, semanticFields(path, itemA), semanticFields(path, itemsB[match]))
+	}
+	for j, itemB := range itemsB {
+		if !matched[j] {
+			changes = append(changes, Change{Type: ChangeAdded, Path: fmt.Sprintf("items[%d]", j), New: itemB.Name})
+		}
+	}
+
+	// Totals
+	for _, total := range []struct {
+		path string
+		old  decimal.Decimal
+		new  decimal.Decimal
+	}{
+		{"total_without_tax", a.TotalWithoutTax(), b.TotalWithoutTax()},
+		{"tax", a.Tax(), b.Tax()},
+		{"total_with_tax", a.TotalWithTax(), b.TotalWithTax()},
+	} {
+		if !total.old.Equal(total.new) {
+			changes = append(changes, Change{
+				Type:  ChangeChanged,
+				Path:  total.path,
+				Old:   total.old.String(),
+				New:   total.new.String(),
+				Delta: total.new.Sub(total.old),
+			})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDiff(t *testing.T) {
+	newDoc := func() *Document {
+		doc, _ := New(Invoice, &Options{})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "1 Main St", City: "Paris"}})
+		doc.SetDefaultTax(&Tax{Percent: "20"})
+		doc.AppendItem(&Item{Name: "Consulting", UnitCost: "100", Quantity: "2"})
+		doc.AppendItem(&Item{Name: "Travel", UnitCost: "50", Quantity: "1"})
+		return doc
+	}
+
+	a, b := newDoc(), newDoc()
+	if changes := Diff(a, b); len(changes) != 0 {
+		t.Errorf("expected no change, got %+v", changes)
+	}
+
+	b.Customer.Address.City = "Lyon"
+	b.Items = []*Item{
+		{Name: "Consulting", UnitCost: "100.00", Quantity: "3"},
+		{Name: "Training", UnitCost: "10", Quantity: "1"},
+	}
+	_ = a.Validate()
+	_ = b.Validate()
+
+	expected := []Change{
+		{Type: ChangeChanged, Path: "customer.address.city", Old: "Paris", New: "Lyon"},
+		{Type: ChangeChanged, Path: "items[0].quantity", Old: "2", New: "3"},
+		{Type: ChangeRemoved, Path: "items[1]", Old: "Travel"},
+		{Type: ChangeAdded, Path: "items[1]", New: "Training"},
+		{Type: ChangeChanged, Path: "total_without_tax", Old: "250", New: "310", Delta: decimal.NewFromInt(60)},
+		{Type: ChangeChanged, Path: "tax", Old: "50", New: "62", Delta: decimal.NewFromInt(12)},
+		{Type: ChangeChanged, Path: "total_with_tax", Old: "300", New: "372", Delta: decimal.NewFromInt(72)},
+	}
+
+	changes := Diff(a, b)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.Type != expected[i].Type || change.Path != expected[i].Path || change.Old != expected[i].Old ||
+			change.New != expected[i].New || !change.Delta.Equal(expected[i].Delta) {
+			t.Errorf("expected %+v, got %+v", expected[i], change)
+		}
+	}
+}
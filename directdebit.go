@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/creasty/defaults"
+)
+
+// ErrInvalidCreditorIdentifier when SEPA creditor identifier format or check digits are invalid
+var ErrInvalidCreditorIdentifier = errors.New("invalid sepa creditor identifier")
+
+// SEPA direct debit schemes
+const (
+	DirectDebitCore string = "CORE"
+	DirectDebitB2B  string = "B2B"
+)
+
+// DirectDebit define the SEPA direct debit (SDD) collecting the document,
+// its pre-notification replacing bank transfer details
+type DirectDebit struct {
+	Scheme     string `default:"CORE" json:"scheme,omitempty" validate:"omitempty,oneof=CORE B2B"`
+	MandateRef string `json:"mandate_ref" validate:"required,max=35"`
+	CreditorID string `json:"creditor_id" validate:"required,max=35"` // SEPA creditor identifier, ex DE98ZZZ09999999999
+	DebitDate  string `json:"debit_date" validate:"required"`         // Collection date, in Options.DateFormat
+	IBAN       string `json:"iban,omitempty" validate:"max=34"`       // Debtor account, masked but its last 4 characters
+}
+
+// Prepare set scheme default, normalize and check the creditor identifier
+func (d *DirectDebit) Prepare() error {
+	if err := defaults.Set(d); err != nil {
+		return err
+	}
+
+	d.CreditorID = strings.ToUpper(strings.ReplaceAll(d.CreditorID, " ", ""))
+	d.IBAN = strings.ToUpper(strings.ReplaceAll(d.IBAN, " ", ""))
+
+	if !validCreditorIdentifier(d.CreditorID) {
+		return ErrInvalidCreditorIdentifier
+	}
+
+	return nil
+}
+
+// validCreditorIdentifier check creditor identifier ISO 7064 MOD 97-10 check digits, computed on the national
+// identifier followed by the country code, the creditor business code being ignored
+func validCreditorIdentifier(id string) bool {
+	if len(id) < 8 || !alphanumeric(id) {
+		return false
+	}
+
+	return mod97(id[7:]+id[:4]) == 1
+}
+
+// alphanumeric return true when s only holds ASCII digits and upper case letters
+func alphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mod97 return the ISO 7064 modulo 97 of s digits and upper case letters, letters as numbers from 10 to 35
+func mod97(s string) int {
+	remainder := 0
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+
+	return remainder
+}
+
+// maskedIBAN return the debtor IBAN with its country code and last 4 characters only, in groups of 4
+func (d *DirectDebit) maskedIBAN() string {
+	if len(d.IBAN) <= 6 {
+		return d.IBAN
+	}
+
+	masked := d.IBAN[:2] + strings.Repeat("*", len(d.IBAN)-6) + d.IBAN[len(d.IBAN)-4:]
+	groups := []string{}
+	for len(masked) > 4 {
+		groups = append(groups, masked[:4])
+		masked = masked[4:]
+	}
+
+	return strings.Join(append(groups, masked), " ")
+}
+
+// appendDirectDebit append the direct debit pre-notification in place of bank details
+func (doc *Document) appendDirectDebit() {
+	debit := doc.DirectDebit
+
+	lines := []string{
+		fmt.Sprintf("%s: %s", doc.Options.TextDirectDebitMandateTitle, debit.MandateRef),
+		fmt.Sprintf("%s: %s", doc.Options.TextDirectDebitCreditorTitle, debit.CreditorID),
+		fmt.Sprintf("%s: %s", doc.Options.TextDirectDebitDateTitle, doc.dateDigits(debit.DebitDate)),
+	}
+	if len(debit.IBAN) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextDirectDebitIBANTitle, doc.bankDigits(debit.maskedIBAN(), false)))
+	}
+	mention := doc.merge(doc.Options.TextDirectDebitMention)
+
+	y := doc.pdf.GetY() + 10
+	if doc.notesBottom > y {
+		y = doc.notesBottom + 5
+	}
+	doc.pdf.SetY(y)
+
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	_, lineHt := doc.pdf.GetFontSize()
+
+	// Move pre-notification to next page rather than splitting it, like bank details
+	doc.keepBlockTogether(BlockBankDetails, 6+float64(len(lines))*lineHt+doc.textHeight(mention, 190, lineHt))
+
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 9)
+	title := fmt.Sprintf("%s (%s)", doc.Options.TextDirectDebitTitle, debit.Scheme)
+	doc.pdf.CellFormat(190, 6, doc.encodeString(title), "0", 2, "", false, 0, "")
+
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	for _, line := range lines {
+		doc.pdf.CellFormat(190, lineHt, doc.encodeString(line), "0", 2, "", false, 0, "")
+	}
+	doc.pdf.MultiCell(190, lineHt, doc.encodeString(mention), "0", "L", false)
+}
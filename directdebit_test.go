@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValidCreditorIdentifier(t *testing.T) {
+	for id, expected := range map[string]bool{
+		"DE98ZZZ09999999999": true,
+		"FR72ZZZ123456":      true,
+		"DE97ZZZ09999999999": false,
+		"DE98ZZZ0999999999é": false,
+		"DE98":               false,
+	} {
+		if valid := validCreditorIdentifier(id); valid != expected {
+			t.Errorf("expected %s valid %v, got %v", id, expected, valid)
+		}
+	}
+}
+
+func TestAppendDirectDebit(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.SetBankDetails("IBAN: FR76 3000 6000 0112 3456 7890 189")
+	doc.DirectDebit = &DirectDebit{
+		MandateRef: "MANDATE-42",
+		CreditorID: "de98 zzz0 9999 9999 99",
+		DebitDate:  "15/02/2024",
+		IBAN:       "DE89 3704 0044 0532 0130 00",
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.DirectDebit.Scheme != DirectDebitCore {
+		t.Errorf("expected %s scheme by default, got %s", DirectDebitCore, doc.DirectDebit.Scheme)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"(SEPA Direct Debit \\(CORE\\))",
+		"(Creditor identifier: DE98ZZZ09999999999)",
+		"(Debited account: DE** **** **** **** **30 00)",
+		"under mandate MANDATE-42",
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("FR76")) {
+		t.Error("expected bank details to be replaced by direct debit notice")
+	}
+
+	doc.DirectDebit.CreditorID = "DE97ZZZ09999999999"
+	if _, err := doc.Build(); err != ErrInvalidCreditorIdentifier {
+		t.Errorf("expected %v, got %v", ErrInvalidCreditorIdentifier, err)
+	}
+}
@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"time"
+
 	"github.com/go-pdf/fpdf"
 	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
 )
 
 // Document define base document
@@ -10,13 +13,26 @@ type Document struct {
 	pdf *fpdf.Fpdf
 	ac  accounting.Accounting
 
+	notesBottom float64
+	compact     bool
+	redaction   *Redaction
+	encoded     map[string]string
+	warnings    []*Warning
+	headerFunc  func()
+	footerFunc  func()
+	shifted     bool
+	emptyCols   map[string]bool
+	glyphs      map[glyphKey]*GlyphSubstitution
+	splitBill   bool
+
 	Options      *Options      `json:"options,omitempty"`
 	Header       *HeaderFooter `json:"header,omitempty"`
 	Footer       *HeaderFooter `json:"footer,omitempty"`
-	Type         string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION"`
+	Type         string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION REMINDER DONATION_RECEIPT SETTLEMENT_STATEMENT"`
 	Ref          string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
 	Version      string        `json:"version,omitempty" validate:"max=32"`
 	ClientRef    string        `json:"client_ref,omitempty" validate:"max=64"`
+	QuoteRef     string        `json:"quote_ref,omitempty" validate:"max=32"` // Ref of the quote the invoice was converted from, see ToInvoice
 	Description  string        `json:"description,omitempty" validate:"max=1024"`
 	Notes        string        `json:"notes,omitempty"`
 	Company      *Contact      `json:"company,omitempty" validate:"required"`
@@ -24,9 +40,51 @@ type Document struct {
 	Items        []*Item       `json:"items,omitempty"`
 	Date         string        `json:"date,omitempty"`
 	ValidityDate string        `json:"validity_date,omitempty"`
+	ValidForDays int           `json:"valid_for_days,omitempty" validate:"min=0"` // Quote validity from date, when ValidityDate is empty
 	PaymentTerm  string        `json:"payment_term,omitempty"`
+	BankDetails  string        `json:"bank_details,omitempty"` // You can use basic html here (bold, italic tags)
+	Footnotes    []string      `json:"footnotes,omitempty"`    // Numbered from 1, referenced as <sup>1</sup>
 	DefaultTax   *Tax          `json:"default_tax,omitempty"`
 	Discount     *Discount     `json:"discount,omitempty"`
+	Medical      *Medical      `json:"medical,omitempty"`
+	Payers       []*Payer      `json:"payers,omitempty" validate:"dive"`
+	Sellers      []*Seller     `json:"sellers,omitempty" validate:"dive"` // Document is issued by Company on behalf of sellers
+	Donation     *Donation     `json:"donation,omitempty"`
+	Stay         *Stay         `json:"stay,omitempty"`
+	LateInterest *LateInterest `json:"late_interest,omitempty"`
+	Statement    *Statement    `json:"statement,omitempty"`
+	Retainer     *Retainer     `json:"retainer,omitempty"`
+	Settlement   *Settlement   `json:"settlement,omitempty"`
+	Royalty      *Royalty      `json:"royalty,omitempty"`
+
+	GST              *GST              `json:"gst,omitempty"`
+	CanadianTax      *CanadianTax      `json:"canadian_tax,omitempty"`
+	SouthAfricanVAT  *SouthAfricanVAT  `json:"south_african_vat,omitempty"`
+	PortugueseFiscal *PortugueseFiscal `json:"portuguese_fiscal,omitempty"`
+	SpanishFiscal    *SpanishFiscal    `json:"spanish_fiscal,omitempty"`
+	TurkishFiscal    *TurkishFiscal    `json:"turkish_fiscal,omitempty"`
+	BrazilianFiscal  *BrazilianFiscal  `json:"brazilian_fiscal,omitempty"`
+
+	MeterReadings []*MeterReading `json:"meter_readings,omitempty" validate:"dive"`
+
+	PostalBarcode *PostalBarcode `json:"postal_barcode,omitempty"`
+	CoverLetter   *CoverLetter   `json:"cover_letter,omitempty"`
+	DirectDebit   *DirectDebit   `json:"direct_debit,omitempty"`
+
+	PaymentMethods []*PaymentMethod `json:"payment_methods,omitempty" validate:"dive"`
+	RemittanceStub *RemittanceStub  `json:"remittance_stub,omitempty"`
+	Boleto         *Boleto          `json:"boleto,omitempty"`
+	Giro           *Giro            `json:"giro,omitempty"`
+
+	QuoteOptions []*QuoteOption `json:"quote_options,omitempty" validate:"dive"`
+	Acceptance   *Acceptance    `json:"acceptance,omitempty"`
+
+	Annotations []*Annotation `json:"annotations,omitempty" validate:"dive"`
+
+	DownPaymentOf string         `json:"down_payment_of,omitempty" validate:"max=32"` // Ref of the order a down payment invoice is a part of, see DownPaymentInvoice
+	DownPayments  []*DownPayment `json:"down_payments,omitempty" validate:"dive"`     // Prior down payment invoices deducted from a final invoice
+
+	ExpectedTotals *ExpectedTotals `json:"expected_totals,omitempty"`
 
 	CustomTotal string
 	CustomTax string
@@ -43,15 +101,39 @@ func (doc *Document) Pdf() *fpdf.Fpdf {
 // See https://pkg.go.dev/github.com/go-pdf/fpdf#UnicodeTranslator
 func (doc *Document) SetUnicodeTranslator(fn UnicodeTranslateFunc) {
 	doc.Options.UnicodeTranslateFunc = fn
+	doc.encoded = nil
 }
 
-// encodeString encodes the string using doc.Options.UnicodeTranslateFunc
+// encodeString encodes the string using doc.Options.UnicodeTranslateFunc, characters it can't encode replaced
+// and lines mixing right to left characters reordered.
+// Encoded strings are cached, repeated cells (quantities, prices, taxes) are translated once.
 func (doc *Document) encodeString(str string) string {
-	return doc.Options.UnicodeTranslateFunc(str)
+	if encoded, ok := doc.encoded[str]; ok {
+		return encoded
+	}
+
+	encoded := doc.encode(doc.visualOrder(str))
+	if doc.encoded == nil {
+		doc.encoded = map[string]string{}
+	}
+	if len(doc.encoded) < MaxEncodedStrings {
+		doc.encoded[str] = encoded
+	}
+
+	return encoded
+}
+
+// encode encodes the string using doc.Options.UnicodeTranslateFunc as is, ex HTML or text already in visual order
+func (doc *Document) encode(str string) string {
+	return doc.Options.UnicodeTranslateFunc(doc.replaceSymbols(str))
 }
 
 // typeAsString return the document type as string
 func (d *Document) typeAsString() string {
+	if d.Type == Invoice && ((d.GST != nil && d.GST.TaxInvoiceRequired()) || d.SouthAfricanVAT != nil) {
+		return d.Options.TextTypeTaxInvoice
+	}
+
 	if d.Type == Invoice {
 		return d.Options.TextTypeInvoice
 	}
@@ -60,5 +142,145 @@ func (d *Document) typeAsString() string {
 		return d.Options.TextTypeQuotation
 	}
 
+	if d.Type == Reminder {
+		return d.Options.TextTypeReminder
+	}
+
+	if d.Type == DonationReceipt {
+		return d.Options.TextTypeDonationReceipt
+	}
+
+	if d.Type == SettlementStatement {
+		return d.Options.TextTypeSettlementStatement
+	}
+
 	return d.Options.TextTypeDeliveryNote
 }
+
+// TotalWithoutTaxAndWithoutDocumentDiscount returns the sum of items totals without tax
+func (doc *Document) TotalWithoutTaxAndWithoutDocumentDiscount() decimal.Decimal {
+	total := decimal.Zero
+
+	for _, item := range doc.Items {
+		total = total.Add(item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	return total
+}
+
+// TotalWithoutTax returns the document total without tax, document discount applied
+func (doc *Document) TotalWithoutTax() decimal.Decimal {
+	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+
+	if doc.Discount != nil {
+		discountType, discountNumber := doc.Discount.getDiscount()
+
+		if discountType == DiscountTypeAmount {
+			total = total.Sub(discountNumber)
+		} else {
+			// Percent
+			toSub := total.Mul(discountNumber.Div(decimal.NewFromInt(100)))
+			total = total.Sub(toSub)
+		}
+	}
+
+	return total
+}
+
+// Tax returns the document total tax.
+// Percent taxes are reduced proportionally to the document discount.
+func (doc *Document) Tax() decimal.Decimal {
+	totalWithoutDocDiscount := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+
+	totalWithoutTax := doc.TotalWithoutTax()
+	applyDiscount := doc.Discount != nil && !totalWithoutDocDiscount.IsZero()
+
+	tax := decimal.Zero
+	for _, item := range doc.Items {
+		itemTax := item.TaxWithTotalDiscounted()
+
+		if applyDiscount && item.Tax != nil {
+			if taxType, _ := item.Tax.getTax(); taxType == TaxTypePercent {
+				itemTax = itemTax.Mul(totalWithoutTax).Div(totalWithoutDocDiscount)
+			}
+		}
+
+		tax = tax.Add(itemTax)
+	}
+
+	return tax
+}
+
+// TotalWithTax returns the document total with tax
+func (doc *Document) TotalWithTax() decimal.Decimal {
+	return doc.TotalWithoutTax().Add(doc.Tax())
+}
+
+// TaxLines returns the document tax breakdown, items grouped by tax rate in order of appearance.
+// Fixed amount taxes are grouped in a single line, items without tax in a zero rate line.
+func (doc *Document) TaxLines() []*TaxLine {
+	return doc.taxLines(func(item *Item) string {
+		return taxLineKey(item.Tax)
+	})
+}
+
+// taxLines return the document tax breakdown, items grouped by key in order of appearance
+func (doc *Document) taxLines(group func(item *Item) string) []*TaxLine {
+	totalWithoutDocDiscount := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	totalWithoutTax := doc.TotalWithoutTax()
+	applyDiscount := doc.Discount != nil && !totalWithoutDocDiscount.IsZero()
+
+	lines := []*TaxLine{}
+	linesByKey := map[string]*TaxLine{}
+
+	for _, item := range doc.Items {
+		key := group(item)
+
+		line, ok := linesByKey[key]
+		if !ok {
+			line = &TaxLine{Type: TaxTypePercent, Tax: item.Tax, item: item}
+			if item.Tax != nil {
+				line.Type, line.Rate = item.Tax.getTax()
+				if line.Type == TaxTypeAmount {
+					line.Rate = decimal.Zero
+				}
+			}
+
+			lines = append(lines, line)
+			linesByKey[key] = line
+		}
+
+		base := item.TotalWithoutTaxAndWithDiscount()
+		tax := item.TaxWithTotalDiscounted()
+
+		if applyDiscount {
+			base = base.Mul(totalWithoutTax).Div(totalWithoutDocDiscount)
+			if line.Type == TaxTypePercent {
+				tax = tax.Mul(totalWithoutTax).Div(totalWithoutDocDiscount)
+			}
+		}
+
+		line.Base = line.Base.Add(base)
+		line.Amount = line.Amount.Add(tax)
+	}
+
+	return lines
+}
+
+// totalAmount return the document total as decimal, CustomTotal when set or computed total
+func (doc *Document) totalAmount() (decimal.Decimal, error) {
+	if len(doc.CustomTotal) == 0 {
+		return doc.TotalWithTax(), nil
+	}
+
+	return decimal.NewFromString(doc.CustomTotal)
+}
+
+// documentDate return parsed document date, now if not set
+func (doc *Document) documentDate() (time.Time, error) {
+	if len(doc.Date) == 0 {
+		return time.Now(), nil
+	}
+
+	return time.Parse(doc.Options.DateFormat, doc.Date)
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Donation jurisdictions with built-in receipt wording
+const (
+	DonationJurisdictionUS string = "US"
+	DonationJurisdictionCA string = "CA"
+	DonationJurisdictionFR string = "FR"
+	DonationJurisdictionDE string = "DE"
+)
+
+// donationStatements define the jurisdiction specific mentions of a donation receipt
+var donationStatements = map[string][]string{
+	DonationJurisdictionUS: {
+		"This organization is exempt from federal income tax under section 501(c)(3) of the Internal Revenue Code. Contributions are deductible to the extent allowed by law.",
+	},
+	DonationJurisdictionCA: {
+		"Official receipt for income tax purposes.",
+		"Canada Revenue Agency: www.canada.ca/charities-giving",
+	},
+	DonationJurisdictionFR: {
+		"Reçu au titre des dons à certains organismes d'intérêt général (articles 200, 238 bis et 978 du code général des impôts).",
+	},
+	DonationJurisdictionDE: {
+		"Bestätigung über Zuwendungen im Sinne des § 10b des Einkommensteuergesetzes.",
+		"Es wird bestätigt, dass die Zuwendung nur zur Förderung steuerbegünstigter Zwecke verwendet wird.",
+	},
+}
+
+// Donation define donation receipt informations, the donor being the document customer
+type Donation struct {
+	Purpose      string `json:"purpose,omitempty" validate:"max=1024"`
+	Jurisdiction string `json:"jurisdiction,omitempty" validate:"omitempty,oneof=US CA FR DE"`
+
+	// GoodsOrServices describe goods or services provided in exchange of the donation (with their value).
+	// When empty, the "no goods or services provided" statement is rendered.
+	GoodsOrServices string `json:"goods_or_services,omitempty" validate:"max=1024"`
+
+	// Statements replace jurisdiction mentions when set
+	Statements []string `json:"statements,omitempty"`
+}
+
+// statements return mentions rendered on the receipt
+func (d *Donation) statements(options *Options) []string {
+	statements := []string{}
+
+	if len(d.GoodsOrServices) > 0 {
+		statements = append(statements, fmt.Sprintf("%s: %s", options.TextDonationGoodsOrServicesTitle, d.GoodsOrServices))
+	} else {
+		statements = append(statements, options.TextDonationNoGoodsOrServices)
+	}
+
+	if len(d.Statements) > 0 {
+		return append(statements, d.Statements...)
+	}
+
+	return append(statements, donationStatements[d.Jurisdiction]...)
+}
+
+// appendDonation append donation purpose and mentions to document
+func (doc *Document) appendDonation() {
+	if doc.Donation == nil {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 5)
+	doc.pdf.SetX(BaseMargin)
+
+	if len(doc.Donation.Purpose) > 0 {
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.MultiCell(
+			190,
+			4,
+			doc.encodeString(fmt.Sprintf("%s: %s", doc.Options.TextDonationPurposeTitle, doc.Donation.Purpose)),
+			"0",
+			"L",
+			false,
+		)
+		doc.pdf.SetY(doc.pdf.GetY() + 1)
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(
+		190,
+		4,
+		doc.encodeString(strings.Join(doc.Donation.statements(doc.Options), "\n")),
+		"0",
+		"L",
+		false,
+	)
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDonationStatements(t *testing.T) {
+	doc, _ := New(DonationReceipt, &Options{})
+
+	for jurisdiction, expected := range map[string]string{
+		DonationJurisdictionUS: "section 501(c)(3)",
+		DonationJurisdictionCA: "Official receipt for income tax purposes.",
+		DonationJurisdictionFR: "238 bis",
+		DonationJurisdictionDE: "§ 10b des Einkommensteuergesetzes",
+	} {
+		statements := (&Donation{Jurisdiction: jurisdiction}).statements(doc.Options)
+		if statements[0] != doc.Options.TextDonationNoGoodsOrServices {
+			t.Errorf("%s: expected no goods or services statement first, got %q", jurisdiction, statements[0])
+		}
+		if !strings.Contains(strings.Join(statements[1:], "\n"), expected) {
+			t.Errorf("%s: expected %q in statements %q", jurisdiction, expected, statements[1:])
+		}
+	}
+
+	// Goods or services with their value, caller statements replace jurisdiction ones
+	donation := &Donation{
+		Jurisdiction:    DonationJurisdictionUS,
+		GoodsOrServices: "Gala dinner, value $80",
+		Statements:      []string{"Registered charity 12345"},
+	}
+	statements := donation.statements(doc.Options)
+	if len(statements) != 2 ||
+		statements[0] != "Goods or services provided in exchange: Gala dinner, value $80" ||
+		statements[1] != "Registered charity 12345" {
+		t.Errorf("unexpected statements %q", statements)
+	}
+
+	// Without jurisdiction only the goods or services statement is rendered
+	if statements := (&Donation{}).statements(doc.Options); len(statements) != 1 {
+		t.Errorf("expected a single statement without jurisdiction, got %q", statements)
+	}
+}
+
+func TestDonationReceipt(t *testing.T) {
+	doc, _ := New(DonationReceipt, &Options{DisableCompression: true})
+	doc.SetRef("DON-1")
+	doc.SetCompany(&Contact{Name: "Charity"})
+	doc.SetCustomer(&Contact{Name: "Donor"})
+	doc.CustomTotal = "100"
+	doc.Donation = &Donation{Purpose: "Shelter", Jurisdiction: DonationJurisdictionCA}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(DONATION RECEIPT)", "(Purpose of the donation: Shelter)", "Official receipt for income tax purposes."} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	doc.Donation.Jurisdiction = "UK"
+	if err := doc.Validate(); err == nil {
+		t.Error("expected unknown jurisdiction error")
+	}
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DownPayment define a prior down payment invoice deducted from a final invoice
+type DownPayment struct {
+	Ref    string `json:"ref" validate:"required,max=32"`
+	Date   string `json:"date,omitempty"`
+	Amount string `json:"amount" validate:"required"` // Down payment without tax ex 300
+	Tax    string `json:"tax,omitempty"`              // Down payment tax amount ex 60
+
+	_amount decimal.Decimal
+	_tax    decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (d *DownPayment) Prepare() error {
+	amount, err := decimal.NewFromString(d.Amount)
+	if err != nil {
+		return err
+	}
+	d._amount = amount
+
+	d._tax = decimal.Zero
+	if len(d.Tax) > 0 {
+		tax, err := decimal.NewFromString(d.Tax)
+		if err != nil {
+			return err
+		}
+		d._tax = tax
+	}
+
+	return nil
+}
+
+// Total return down payment amount with tax
+func (d *DownPayment) Total() decimal.Decimal {
+	return d._amount.Add(d._tax)
+}
+
+// DownPaymentsTotal return the total with tax of prior down payments
+func (doc *Document) DownPaymentsTotal() decimal.Decimal {
+	total := decimal.Zero
+	for _, downPayment := range doc.DownPayments {
+		total = total.Add(downPayment.Total())
+	}
+
+	return total
+}
+
+// DownPaymentInvoice return a down payment invoice of percent of document: one item per tax rate, its base
+// being percent of the rate base, so the down payment carries its own tax. Ref is left empty for the invoice
+// numbering and date is set to today. Document must be validated.
+func (doc *Document) DownPaymentInvoice(percent string) (*Document, error) {
+	ratio, err := decimal.NewFromString(percent)
+	if err != nil {
+		return nil, err
+	}
+	ratio = ratio.Div(decimal.NewFromInt(100))
+
+	invoice, err := doc.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Type = Invoice
+	invoice.DownPaymentOf = doc.Ref
+	invoice.Ref = ""
+	invoice.Version = ""
+	invoice.Date = time.Now().Format(doc.Options.DateFormat)
+	invoice.Discount = nil
+	invoice.DefaultTax = nil
+	invoice.QuoteOptions = nil
+	invoice.Acceptance = nil
+	invoice.ValidityDate = ""
+	invoice.ValidForDays = 0
+	invoice.DownPayments = nil
+	invoice.Statement = nil
+	invoice.ExpectedTotals = nil
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	name := fmt.Sprintf("%s %s %% - %s", doc.Options.TextDownPaymentItem, percent, doc.Ref)
+	invoice.Items = []*Item{}
+	for _, line := range doc.TaxLines() {
+		item := &Item{
+			Name:     name,
+			UnitCost: line.Base.Mul(ratio).Round(precision).String(),
+			Quantity: "1",
+		}
+
+		switch {
+		case line.Tax == nil:
+		case line.Type == TaxTypePercent:
+			item.Tax = &Tax{Percent: line.Rate.String()}
+		default:
+			item.Tax = &Tax{Amount: line.Amount.Mul(ratio).Round(precision).String()}
+		}
+
+		invoice.Items = append(invoice.Items, item)
+	}
+
+	return invoice, nil
+}
+
+// AsDownPayment return the down payment of a down payment invoice, to deduct it from the final invoice.
+// Document must be validated.
+func (doc *Document) AsDownPayment() *DownPayment {
+	precision := int32(doc.Options.CurrencyPrecision)
+	downPayment := &DownPayment{
+		Ref:    doc.Ref,
+		Date:   doc.Date,
+		Amount: doc.TotalWithoutTax().Round(precision).String(),
+		Tax:    doc.Tax().Round(precision).String(),
+	}
+	_ = downPayment.Prepare()
+
+	return downPayment
+}
+
+// downPaymentsHeight return the height of down payments total lines and references
+func (doc *Document) downPaymentsHeight() float64 {
+	if len(doc.DownPayments) == 0 {
+		return 0
+	}
+
+	return 22 + 4*float64(len(doc.DownPayments))
+}
+
+// appendDownPaymentOf append the mention of the document down payment invoices are a part of
+func (doc *Document) appendDownPaymentOf() {
+	if len(doc.DownPaymentOf) == 0 {
+		return
+	}
+
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+5)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 4, doc.encodeString(doc.Options.TextDownPaymentOfTitle+": "+doc.DownPaymentOf), "0", 2, "L", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
+
+// appendDownPayments append prior down payments deduction and amount due below totals, then each
+// down payment invoice reference
+func (doc *Document) appendDownPayments() {
+	if len(doc.DownPayments) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 12)
+	doc.appendTotalLine(doc.Options.TextDownPaymentsTitle, doc.formatMoney(doc.DownPaymentsTotal().Neg()))
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.appendTotalLine(doc.Options.TextDownPaymentsAmountDueTitle, doc.formatMoney(doc.documentTotal()))
+
+	doc.pdf.SetXY(120, doc.pdf.GetY()+11)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	for _, downPayment := range doc.DownPayments {
+		reference := downPayment.Ref
+		if len(downPayment.Date) > 0 {
+			reference += " - " + downPayment.Date
+		}
+		line := fmt.Sprintf(
+			"%s: %s + %s = %s",
+			reference,
+			doc.formatMoney(downPayment._amount),
+			doc.formatMoney(downPayment._tax),
+			doc.formatMoney(downPayment.Total()),
+		)
+		doc.pdf.SetX(120)
+		doc.pdf.CellFormat(80, 4, doc.encodeString(line), "0", 2, "R", false, 0, "")
+	}
+	doc.pdf.SetY(doc.pdf.GetY() - 4)
+}
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDownPaymentChain(t *testing.T) {
+	order := newQuote()
+	order.Items = []*Item{
+		{Name: "Kitchen", UnitCost: "1000", Quantity: "1", Tax: &Tax{Percent: "20"}},
+		{Name: "Books", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "5.5"}},
+	}
+	if err := order.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Down payment of 30 % with its own tax
+	downPaymentInvoice, err := order.DownPaymentInvoice("30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downPaymentInvoice.SetRef("INV-001")
+	if err := downPaymentInvoice.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if downPaymentInvoice.Type != Invoice || downPaymentInvoice.DownPaymentOf != "Q-001" || len(downPaymentInvoice.Items) != 2 {
+		t.Fatalf("unexpected down payment invoice %s %s with %d items", downPaymentInvoice.Type, downPaymentInvoice.DownPaymentOf, len(downPaymentInvoice.Items))
+	}
+	if total := downPaymentInvoice.TotalWithoutTax().StringFixed(2); total != "330.00" {
+		t.Errorf("expected down payment 330.00, got %s", total)
+	}
+	if tax := downPaymentInvoice.Tax().StringFixed(2); tax != "61.65" {
+		t.Errorf("expected down payment tax 61.65, got %s", tax)
+	}
+
+	// Final invoice deducting the down payment
+	final, err := order.ToInvoice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	final.SetRef("INV-002")
+	final.DownPayments = []*DownPayment{downPaymentInvoice.AsDownPayment()}
+
+	pdf, err := final.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due := final.documentTotal().StringFixed(2); due != "913.85" {
+		t.Errorf("expected amount due 913.85, got %s", due)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(Down payments)", "(Amount due)", "(INV-001 - "} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+	// Payment stub and boleto ask for the amount due
+	final.RemittanceStub = &RemittanceStub{AccountNumber: "C-0042"}
+	if _, err := final.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if amount := final.remittanceAmount(); amount != final.formatMoney(final.documentTotal()) {
+		t.Errorf("expected stub amount due 913.85, got %s", amount)
+	}
+
+	barcode := []byte("00190678900000000000000002101234567000000017")
+	barcode[4] = byte('0' + boletoCheckDigit(string(barcode)))
+	final.RemittanceStub = nil
+	final.Options.DisableCompression = true
+	final.Boleto = &Boleto{Barcode: string(barcode), BankName: "Banco do Brasil", DueDate: "15/03/2024"}
+	pdf, err = final.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if i := bytes.Index(buffer.Bytes(), []byte("(Banco do Brasil)")); i < 0 || !bytes.Contains(buffer.Bytes()[i:], []byte("913.85)")) {
+		t.Error("expected boleto amount due 913.85")
+	}
+}
+
+func TestDownPaymentCII(t *testing.T) {
+	doc := newEInvoice(EInvoiceProfileBasic)
+	doc.DownPayments = []*DownPayment{{Ref: "F-2024-000", Amount: "10", Tax: "2"}}
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := EncodeCII(buffer, doc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "<ram:TotalPrepaidAmount>12.00</ram:TotalPrepaidAmount>") {
+		t.Errorf("expected prepaid amount in %s", buffer.String())
+	}
+
+	doc.DownPayments = nil
+	doc.DownPaymentOf = "ORDER-1"
+	buffer.Reset()
+	if err := EncodeCII(buffer, doc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "<ram:TypeCode>386</ram:TypeCode>") {
+		t.Error("expected prepayment invoice type code")
+	}
+}
@@ -0,0 +1,627 @@
+package generator
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidEInvoice when a document can't be expressed as a Factur-X / ZUGFeRD invoice:
+// not an invoice, fixed amount taxes, or contacts without ISO 3166-1 alpha-2 country codes
+var ErrInvalidEInvoice = errors.New("invalid e-invoice")
+
+// E-invoice profiles
+const (
+	EInvoiceProfileBasic   string = "BASIC"
+	EInvoiceProfileEN16931 string = "EN16931"
+)
+
+// EInvoiceFileName name of the embedded XML, required by Factur-X and ZUGFeRD 2
+const EInvoiceFileName = "factur-x.xml"
+
+// EInvoice define a Factur-X / ZUGFeRD hybrid invoice: Output embeds the document as
+// UN/CEFACT CII XML (see EncodeCII) with its AFRelationship and the Factur-X XMP metadata.
+//
+// Full PDF/A-3 conformance additionally requires embedded fonts (UTF-8 fonts, see Options.Font)
+// and an output intent, given with ICCProfile. The PDF/A-3 identification is only written with both.
+type EInvoice struct {
+	Profile    string `default:"EN16931" json:"profile,omitempty" validate:"omitempty,oneof=BASIC EN16931"`
+	ICCProfile []byte `json:"icc_profile,omitempty"` // RGB ICC profile of the PDF/A output intent, ex sRGB
+}
+
+// eInvoiceGuidelines CII guideline identifiers of profiles
+var eInvoiceGuidelines = map[string]string{
+	EInvoiceProfileBasic:   "urn:cen.eu:en16931:2017#compliant#urn:factur-x.eu:1p0:basic",
+	EInvoiceProfileEN16931: "urn:cen.eu:en16931:2017",
+}
+
+// eInvoiceConformanceLevels Factur-X XMP conformance levels of profiles
+var eInvoiceConformanceLevels = map[string]string{
+	EInvoiceProfileBasic:   "BASIC",
+	EInvoiceProfileEN16931: "EN 16931",
+}
+
+// eInvoiceUnitCodes UN/ECE recommendation 20 codes of common item units, C62 (one) otherwise
+var eInvoiceUnitCodes = map[string]string{
+	"h":     "HUR",
+	"hour":  "HUR",
+	"hours": "HUR",
+	"d":     "DAY",
+	"day":   "DAY",
+	"days":  "DAY",
+	"month": "MON",
+	"kg":    "KGM",
+	"g":     "GRM",
+	"l":     "LTR",
+	"m":     "MTR",
+	"km":    "KMT",
+	"kwh":   "KWH",
+}
+
+// ciiAmount define an amount with an optional currency
+type ciiAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"currencyID,attr,omitempty"`
+}
+
+// ciiQuantity define a quantity and its unit code
+type ciiQuantity struct {
+	Value    string `xml:",chardata"`
+	UnitCode string `xml:"unitCode,attr"`
+}
+
+// ciiDate define a date in format 102 (YYYYMMDD)
+type ciiDate struct {
+	Value  string `xml:",chardata"`
+	Format string `xml:"format,attr"`
+}
+
+// ciiTax define a trade tax of a line, an allowance or the breakdown
+type ciiTax struct {
+	CalculatedAmount string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode         string `xml:"ram:TypeCode"`
+	BasisAmount      string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode     string `xml:"ram:CategoryCode"`
+	Rate             string `xml:"ram:RateApplicablePercent"`
+}
+
+// ciiAllowanceCharge define an allowance (discount) or a charge
+type ciiAllowanceCharge struct {
+	ChargeIndicator bool    `xml:"ram:ChargeIndicator>udt:Indicator"`
+	ActualAmount    string  `xml:"ram:ActualAmount"`
+	Reason          string  `xml:"ram:Reason,omitempty"`
+	Tax             *ciiTax `xml:"ram:CategoryTradeTax,omitempty"`
+}
+
+// ciiAddress define a postal address
+type ciiAddress struct {
+	PostcodeCode string `xml:"ram:PostcodeCode,omitempty"`
+	LineOne      string `xml:"ram:LineOne,omitempty"`
+	LineTwo      string `xml:"ram:LineTwo,omitempty"`
+	CityName     string `xml:"ram:CityName,omitempty"`
+	CountryID    string `xml:"ram:CountryID"`
+}
+
+// ciiTaxRegistration define a VAT identifier
+type ciiTaxRegistration struct {
+	ID     string `xml:",chardata"`
+	Scheme string `xml:"schemeID,attr"`
+}
+
+// ciiParty define a seller or buyer
+type ciiParty struct {
+	Name            string              `xml:"ram:Name"`
+	Address         *ciiAddress         `xml:"ram:PostalTradeAddress"`
+	TaxRegistration *ciiTaxRegistration `xml:"ram:SpecifiedTaxRegistration>ram:ID,omitempty"`
+}
+
+// ciiLine define an invoice line
+type ciiLine struct {
+	LineID           string                `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Name             string                `xml:"ram:SpecifiedTradeProduct>ram:Name"`
+	Description      string                `xml:"ram:SpecifiedTradeProduct>ram:Description,omitempty"`
+	NetPrice         string                `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+	PriceBasis       *ciiQuantity          `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:BasisQuantity,omitempty"`
+	BilledQuantity   *ciiQuantity          `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Tax              *ciiTax               `xml:"ram:SpecifiedLineTradeSettlement>ram:ApplicableTradeTax"`
+	AllowanceCharges []*ciiAllowanceCharge `xml:"ram:SpecifiedLineTradeSettlement>ram:SpecifiedTradeAllowanceCharge,omitempty"`
+	LineTotal        string                `xml:"ram:SpecifiedLineTradeSettlement>ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+// ciiPaymentTerms define payment terms, a due date or a description
+type ciiPaymentTerms struct {
+	Description string   `xml:"ram:Description,omitempty"`
+	DueDate     *ciiDate `xml:"ram:DueDateDateTime>udt:DateTimeString,omitempty"`
+}
+
+// ciiSummation define the document totals
+type ciiSummation struct {
+	LineTotal      string     `xml:"ram:LineTotalAmount"`
+	ChargeTotal    string     `xml:"ram:ChargeTotalAmount,omitempty"`
+	AllowanceTotal string     `xml:"ram:AllowanceTotalAmount,omitempty"`
+	TaxBasisTotal  string     `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal       *ciiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotal     string     `xml:"ram:GrandTotalAmount"`
+	TotalPrepaid   string     `xml:"ram:TotalPrepaidAmount,omitempty"`
+	DuePayable     string     `xml:"ram:DuePayableAmount"`
+}
+
+// ciiInvoice define a UN/CEFACT Cross Industry Invoice (D16B) restricted to Factur-X profiles
+type ciiInvoice struct {
+	XMLName      xml.Name `xml:"rsm:CrossIndustryInvoice"`
+	RSMNamespace string   `xml:"xmlns:rsm,attr"`
+	RAMNamespace string   `xml:"xmlns:ram,attr"`
+	UDTNamespace string   `xml:"xmlns:udt,attr"`
+
+	Guideline string   `xml:"rsm:ExchangedDocumentContext>ram:GuidelineSpecifiedDocumentContextParameter>ram:ID"`
+	ID        string   `xml:"rsm:ExchangedDocument>ram:ID"`
+	TypeCode  string   `xml:"rsm:ExchangedDocument>ram:TypeCode"`
+	IssueDate *ciiDate `xml:"rsm:ExchangedDocument>ram:IssueDateTime>udt:DateTimeString"`
+	Note      string   `xml:"rsm:ExchangedDocument>ram:IncludedNote>ram:Content,omitempty"`
+
+	Lines          []*ciiLine            `xml:"rsm:SupplyChainTradeTransaction>ram:IncludedSupplyChainTradeLineItem"`
+	BuyerReference string                `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeAgreement>ram:BuyerReference,omitempty"`
+	Seller         *ciiParty             `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeAgreement>ram:SellerTradeParty"`
+	Buyer          *ciiParty             `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeAgreement>ram:BuyerTradeParty"`
+	Delivery       string                `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeDelivery"`
+	Currency       string                `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeSettlement>ram:InvoiceCurrencyCode"`
+	Taxes          []*ciiTax             `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeSettlement>ram:ApplicableTradeTax"`
+	Allowances     []*ciiAllowanceCharge `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeSettlement>ram:SpecifiedTradeAllowanceCharge,omitempty"`
+	PaymentTerms   *ciiPaymentTerms      `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeSettlement>ram:SpecifiedTradePaymentTerms,omitempty"`
+	Summation      *ciiSummation         `xml:"rsm:SupplyChainTradeTransaction>ram:ApplicableHeaderTradeSettlement>ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+// eInvoiceProfile return Options.EInvoice profile, EN16931 when not set
+func (doc *Document) eInvoiceProfile() string {
+	if doc.Options.EInvoice != nil && len(doc.Options.EInvoice.Profile) > 0 {
+		return doc.Options.EInvoice.Profile
+	}
+
+	return EInvoiceProfileEN16931
+}
+
+// ciiPartyOf return the CII party of a contact, country must be an ISO 3166-1 alpha-2 code
+func ciiPartyOf(c *Contact) (*ciiParty, error) {
+	if c.Address == nil || len(c.Address.Country) != 2 {
+		return nil, ErrInvalidEInvoice
+	}
+
+	party := &ciiParty{
+		Name: c.Name,
+		Address: &ciiAddress{
+			PostcodeCode: c.Address.PostalCode,
+			LineOne:      c.Address.Address,
+			LineTwo:      c.Address.Address2,
+			CityName:     c.Address.City,
+			CountryID:    strings.ToUpper(c.Address.Country),
+		},
+	}
+	if len(c.TaxID) > 0 {
+		party.TaxRegistration = &ciiTaxRegistration{ID: c.TaxID, Scheme: "VA"}
+	}
+
+	return party, nil
+}
+
+// ciiTaxOf return the VAT category of a tax line: standard rate (S) or zero rated (Z)
+func ciiTaxOf(rate decimal.Decimal) *ciiTax {
+	tax := &ciiTax{TypeCode: "VAT", CategoryCode: "S", Rate: rate.String()}
+	if rate.IsZero() {
+		tax.CategoryCode = "Z"
+	}
+
+	return tax
+}
+
+// ciiAllowanceChargeOf return an allowance for a positive difference, a charge for a negative one,
+// nil when difference is zero
+func ciiAllowanceChargeOf(difference decimal.Decimal, reason string) *ciiAllowanceCharge {
+	if difference.IsZero() {
+		return nil
+	}
+
+	return &ciiAllowanceCharge{
+		ChargeIndicator: difference.IsNegative(),
+		ActualAmount:    difference.Abs().StringFixed(2),
+		Reason:          reason,
+	}
+}
+
+// EncodeCII write the document as a Factur-X / ZUGFeRD UN/CEFACT CII XML of Options.EInvoice profile,
+// EN16931 when not set. Amounts are rounded to cents line by line, document discount is split by VAT rate.
+// It can be used as an ArchiveXMLEncoder.
+func EncodeCII(w io.Writer, doc *Document) error {
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+	if doc.Type != Invoice {
+		return ErrInvalidEInvoice
+	}
+
+	seller, err := ciiPartyOf(doc.Company)
+	if err != nil {
+		return err
+	}
+	buyer, err := ciiPartyOf(doc.Customer)
+	if err != nil {
+		return err
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return err
+	}
+
+	profile := doc.eInvoiceProfile()
+	invoice := &ciiInvoice{
+		RSMNamespace:   "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100",
+		RAMNamespace:   "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100",
+		UDTNamespace:   "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100",
+		Guideline:      eInvoiceGuidelines[profile],
+		ID:             doc.Ref,
+		TypeCode:       "380",
+		IssueDate:      &ciiDate{Value: date.Format("20060102"), Format: "102"},
+		Note:           doc.Notes,
+		BuyerReference: doc.ClientRef,
+		Seller:         seller,
+		Buyer:          buyer,
+		Currency:       doc.Options.CurrencyCode,
+	}
+
+	// Lines, net amounts rounded to cents
+	lineTotal := decimal.Zero
+	netByKey := map[string]decimal.Decimal{}
+	for i, item := range doc.Items {
+		if item.Tax != nil {
+			if taxType, _ := item.Tax.getTax(); taxType == TaxTypeAmount {
+				return ErrInvalidEInvoice
+			}
+		}
+
+		unitCode, ok := eInvoiceUnitCodes[strings.ToLower(item.Unit)]
+		if !ok {
+			unitCode = "C62"
+		}
+
+		gross := item.TotalWithoutTaxAndWithoutDiscount().Round(2)
+		net := item.TotalWithoutTaxAndWithDiscount().Round(2)
+		rate := decimal.Zero
+		if item.Tax != nil {
+			_, rate = item.Tax.getTax()
+		}
+
+		line := &ciiLine{
+			LineID:         fmt.Sprintf("%d", i+1),
+			Name:           item.Name,
+			NetPrice:       item.UnitCost,
+			BilledQuantity: &ciiQuantity{Value: item.Quantity, UnitCode: unitCode},
+			Tax:            ciiTaxOf(rate),
+			LineTotal:      net.StringFixed(2),
+		}
+		if profile == EInvoiceProfileEN16931 {
+			line.Description = item.Description
+		}
+		if len(item.PriceBasis) > 0 {
+			line.PriceBasis = &ciiQuantity{Value: item.PriceBasis, UnitCode: unitCode}
+		}
+		if allowance := ciiAllowanceChargeOf(gross.Sub(net), doc.Options.TextItemsDiscountTitle); allowance != nil {
+			line.AllowanceCharges = append(line.AllowanceCharges, allowance)
+		}
+
+		invoice.Lines = append(invoice.Lines, line)
+		lineTotal = lineTotal.Add(net)
+
+		key := taxLineKey(item.Tax)
+		netByKey[key] = netByKey[key].Add(net)
+	}
+
+	// Tax breakdown, document discount as an allowance by VAT rate
+	basisTotal, taxTotal := decimal.Zero, decimal.Zero
+	allowanceTotal, chargeTotal := decimal.Zero, decimal.Zero
+	for _, taxLine := range doc.TaxLines() {
+		basis := taxLine.Base.Round(2)
+		amount := basis.Mul(taxLine.Rate).Div(decimal.NewFromInt(100)).Round(2)
+
+		tax := ciiTaxOf(taxLine.Rate)
+		tax.BasisAmount = basis.StringFixed(2)
+		tax.CalculatedAmount = amount.StringFixed(2)
+		invoice.Taxes = append(invoice.Taxes, tax)
+
+		difference := netByKey[taxLineKey(taxLine.Tax)].Sub(basis)
+		if allowance := ciiAllowanceChargeOf(difference, doc.Options.TextTotalDiscounted); allowance != nil {
+			allowance.Tax = ciiTaxOf(taxLine.Rate)
+			invoice.Allowances = append(invoice.Allowances, allowance)
+
+			if allowance.ChargeIndicator {
+				chargeTotal = chargeTotal.Sub(difference)
+			} else {
+				allowanceTotal = allowanceTotal.Add(difference)
+			}
+		}
+
+		basisTotal = basisTotal.Add(basis)
+		taxTotal = taxTotal.Add(amount)
+	}
+
+	grandTotal := basisTotal.Add(taxTotal)
+	if grandTotal.IsNegative() {
+		invoice.TypeCode = "381"
+	} else if len(doc.DownPaymentOf) > 0 {
+		// Prepayment invoice
+		invoice.TypeCode = "386"
+	}
+
+	invoice.Summation = &ciiSummation{
+		LineTotal:     lineTotal.StringFixed(2),
+		TaxBasisTotal: basisTotal.StringFixed(2),
+		TaxTotal:      &ciiAmount{Value: taxTotal.StringFixed(2), Currency: doc.Options.CurrencyCode},
+		GrandTotal:    grandTotal.StringFixed(2),
+		DuePayable:    grandTotal.Sub(doc.DownPaymentsTotal()).StringFixed(2),
+	}
+	if len(doc.DownPayments) > 0 {
+		invoice.Summation.TotalPrepaid = doc.DownPaymentsTotal().StringFixed(2)
+	}
+	if !chargeTotal.IsZero() {
+		invoice.Summation.ChargeTotal = chargeTotal.StringFixed(2)
+	}
+	if !allowanceTotal.IsZero() {
+		invoice.Summation.AllowanceTotal = allowanceTotal.StringFixed(2)
+	}
+
+	// Payment term as a due date when it is one
+	if len(doc.PaymentTerm) > 0 {
+		if dueDate, err := time.Parse(doc.Options.DateFormat, doc.PaymentTerm); err == nil {
+			invoice.PaymentTerms = &ciiPaymentTerms{DueDate: &ciiDate{Value: dueDate.Format("20060102"), Format: "102"}}
+		} else {
+			invoice.PaymentTerms = &ciiPaymentTerms{Description: doc.PaymentTerm}
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(invoice)
+}
+
+// pdfEmbeddedFilesRegexp match the embedded files name tree written by fpdf, capturing its attachments
+var pdfEmbeddedFilesRegexp = regexp.MustCompile(`/EmbeddedFiles << /Names \[((?:\s*\(Attachement\d+\) \d+ 0 R)*)\s*\] >>`)
+
+// pdfStandardFontRegexp match the standard 14 fonts, not embedded by fpdf
+var pdfStandardFontRegexp = regexp.MustCompile(`/BaseFont /(Helvetica|Times|Courier|Symbol|ZapfDingbats)\b`)
+
+// eInvoiceXMP return the XMP metadata of a Factur-X document, identified as PDF/A-3 when pdfa is true
+func eInvoiceXMP(title string, producer string, creator string, conformanceLevel string, pdfa bool, date time.Time) string {
+	escape := func(s string) string {
+		buffer := &bytes.Buffer{}
+		_ = xml.EscapeText(buffer, []byte(s))
+		return buffer.String()
+	}
+
+	property := func(name string, description string) string {
+		return fmt.Sprintf(
+			`<rdf:li rdf:parseType="Resource"><pdfaProperty:name>%s</pdfaProperty:name><pdfaProperty:valueType>Text</pdfaProperty:valueType><pdfaProperty:category>external</pdfaProperty:category><pdfaProperty:description>%s</pdfaProperty:description></rdf:li>`,
+			name,
+			description,
+		)
+	}
+
+	// Producer is omitted by white label documents and creator when empty, like in Info
+	if len(producer) > 0 {
+		producer = `<rdf:Description rdf:about="" xmlns:pdf="http://ns.adobe.com/pdf/1.3/"><pdf:Producer>` + escape(producer) + `</pdf:Producer></rdf:Description>
+`
+	}
+	if len(creator) > 0 {
+		creator = `<xmp:CreatorTool>` + escape(creator) + `</xmp:CreatorTool>`
+	}
+	identification := ""
+	if pdfa {
+		identification = `<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/"><pdfaid:part>3</pdfaid:part><pdfaid:conformance>B</pdfaid:conformance></rdf:Description>
+`
+	}
+
+	return `<?xpacket begin="` + "\ufeff" + `" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+` + identification + `<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title><rdf:Alt><rdf:li xml:lang="x-default">` + escape(title) + `</rdf:li></rdf:Alt></dc:title></rdf:Description>
+` + producer + `<rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/"><xmp:CreateDate>` + date.Format(time.RFC3339) + `</xmp:CreateDate><xmp:ModifyDate>` + date.Format(time.RFC3339) + `</xmp:ModifyDate>` + creator + `</rdf:Description>
+<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#"><fx:DocumentType>INVOICE</fx:DocumentType><fx:DocumentFileName>` + EInvoiceFileName + `</fx:DocumentFileName><fx:Version>1.0</fx:Version><fx:ConformanceLevel>` + conformanceLevel + `</fx:ConformanceLevel></rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/" xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#" xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+<pdfaExtension:schemas><rdf:Bag><rdf:li rdf:parseType="Resource">
+<pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+<pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+<pdfaSchema:prefix>fx</pdfaSchema:prefix>
+<pdfaSchema:property><rdf:Seq>` +
+		property("DocumentFileName", "Name of the embedded XML invoice file") +
+		property("DocumentType", "INVOICE") +
+		property("Version", "Version of the Factur-X XML schema") +
+		property("ConformanceLevel", "Conformance level of the embedded XML invoice") + `</rdf:Seq></pdfaSchema:property>
+</rdf:li></rdf:Bag></pdfaExtension:schemas></rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
+}
+
+// embedEInvoice embed the CII XML of document in pdf as the Factur-X associated file,
+// with XMP metadata and output intent, as an incremental update of pdf
+func (doc *Document) embedEInvoice(pdf []byte) ([]byte, error) {
+	cii := &bytes.Buffer{}
+	if err := EncodeCII(cii, doc); err != nil {
+		return nil, err
+	}
+
+	size, err := pdfSubmatchInt(pdfSizeRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	rootNumber, err := pdfSubmatchInt(pdfRootRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	startXref, err := pdfSubmatchInt(pdfStartXrefRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	infoNumber, err := pdfSubmatchInt(pdfInfoRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := pdfObject(pdf, rootNumber)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(catalog, "/AF ") || strings.Contains(catalog, "/Metadata") {
+		return nil, ErrInvalidPDFStructure
+	}
+
+	now := time.Now().Truncate(time.Second)
+	profile := doc.eInvoiceProfile()
+	title := fmt.Sprintf("%s %s", doc.typeAsString(), doc.Ref)
+
+	fileNumber := size
+	fileSpecNumber := size + 1
+	metadataNumber := size + 2
+	nextNumber := size + 3
+
+	// Embedded file, compressed like other streams
+	content := cii.Bytes()
+	filter := ""
+	if !doc.Options.DisableCompression {
+		compressed := &bytes.Buffer{}
+		writer := zlib.NewWriter(compressed)
+		if _, err := writer.Write(content); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		content = compressed.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+	embeddedFile := fmt.Sprintf(
+		"<</Type /EmbeddedFile /Subtype /text#2Fxml%s /Length %d /Params <</ModDate %s /Size %d /CheckSum <%x>>>>>\nstream\n%s\nendstream",
+		filter,
+		len(content),
+		pdfDate(now),
+		cii.Len(),
+		md5.Sum(cii.Bytes()),
+		content,
+	)
+
+	// File specification, the XML is an alternative representation of the invoice
+	fileSpec := fmt.Sprintf(
+		"<</Type /Filespec /F (%s) /UF (%s) /Desc (Factur-X invoice) /AFRelationship /Alternative /EF <</F %d 0 R /UF %d 0 R>>>>",
+		EInvoiceFileName,
+		EInvoiceFileName,
+		fileNumber,
+		fileNumber,
+	)
+
+	// PDF/A-3 needs embedded fonts and an output intent
+	pdfa := len(doc.Options.EInvoice.ICCProfile) > 0 && !pdfStandardFontRegexp.Match(pdf)
+	xmp := eInvoiceXMP(title, doc.producer(), doc.Options.Creator, eInvoiceConformanceLevels[profile], pdfa, now)
+	metadata := fmt.Sprintf("<</Type /Metadata /Subtype /XML /Length %d>>\nstream\n%s\nendstream", len(xmp), xmp)
+
+	// Info matching XMP metadata
+	branding := ""
+	if producer := doc.producer(); len(producer) > 0 {
+		branding += "\n/Producer " + pdfTextString(producer)
+	}
+	if len(doc.Options.Creator) > 0 {
+		branding += "\n/Creator " + pdfTextString(doc.Options.Creator)
+	}
+	info := fmt.Sprintf(
+		"<<\n/Title %s%s\n/CreationDate %s\n/ModDate %s\n>>",
+		pdfTextString(title),
+		branding,
+		pdfDate(now),
+		pdfDate(now),
+	)
+
+	// Catalog, fpdf attachments (ex rules snapshot) kept before the XML as names are sorted
+	names := fmt.Sprintf("(%s) %d 0 R", EInvoiceFileName, fileSpecNumber)
+	if match := pdfEmbeddedFilesRegexp.FindStringSubmatch(catalog); match != nil {
+		if attachments := strings.Join(strings.Fields(match[1]), " "); len(attachments) > 0 {
+			names = attachments + " " + names
+		}
+		catalog = pdfEmbeddedFilesRegexp.ReplaceAllLiteralString(catalog, "/EmbeddedFiles << /Names ["+names+"] >>")
+	} else if !strings.Contains(catalog, "/Names") {
+		names = "/EmbeddedFiles << /Names [" + names + "] >>"
+		catalog = pdfDictionaryAppend(catalog, "/Names <<"+names+">>")
+	} else {
+		return nil, ErrInvalidPDFStructure
+	}
+	catalog = pdfDictionaryAppend(catalog, fmt.Sprintf("/Version /1.7 /AF [%d 0 R] /Metadata %d 0 R", fileSpecNumber, metadataNumber))
+
+	type object struct {
+		number int
+		body   string
+	}
+	objects := []object{
+		{infoNumber, info},
+		{fileNumber, embeddedFile},
+		{fileSpecNumber, fileSpec},
+		{metadataNumber, metadata},
+	}
+
+	// Output intent
+	if iccProfile := doc.Options.EInvoice.ICCProfile; len(iccProfile) > 0 {
+		iccNumber := nextNumber
+		intentNumber := nextNumber + 1
+		nextNumber += 2
+
+		objects = append(objects,
+			object{iccNumber, fmt.Sprintf("<</N 3 /Length %d>>\nstream\n%s\nendstream", len(iccProfile), iccProfile)},
+			object{intentNumber, fmt.Sprintf("<</Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier (sRGB) /DestOutputProfile %d 0 R>>", iccNumber)},
+		)
+		catalog = pdfDictionaryAppend(catalog, fmt.Sprintf("/OutputIntents [%d 0 R]", intentNumber))
+	}
+
+	objects = append(objects, object{rootNumber, catalog})
+
+	// Incremental update
+	update := &bytes.Buffer{}
+	update.Write(pdf)
+	if !bytes.HasSuffix(pdf, []byte("\n")) {
+		update.WriteString("\n")
+	}
+
+	offsets := map[int]int{}
+	for _, object := range objects {
+		offsets[object.number] = update.Len()
+		fmt.Fprintf(update, "%d 0 obj\n%s\nendobj\n", object.number, object.body)
+	}
+
+	xref := update.Len()
+	update.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for _, object := range objects {
+		fmt.Fprintf(update, "%d 1\n%010d 00000 n \n", object.number, offsets[object.number])
+	}
+
+	id := md5.Sum(pdf)
+	fmt.Fprintf(
+		update,
+		"trailer\n<<\n/Size %d\n/Root %d 0 R\n/Info %d 0 R\n/ID [<%x> <%x>]\n/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n",
+		nextNumber,
+		rootNumber,
+		infoNumber,
+		id,
+		id,
+		startXref,
+		xref,
+	)
+
+	return update.Bytes(), nil
+}
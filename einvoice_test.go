@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newEInvoice(profile string) *Document {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, EInvoice: &EInvoice{Profile: profile}})
+	doc.SetRef("F-2024-001")
+	doc.SetDate("15/03/2024")
+	doc.SetPaymentTerm("15/04/2024")
+	doc.SetCompany(&Contact{Name: "Seller", TaxID: "FR32123456789", Address: &Address{Address: "1 rue de Paris", PostalCode: "75001", City: "Paris", Country: "FR"}})
+	doc.SetCustomer(&Contact{Name: "Buyer", Address: &Address{Address: "2 Hauptstrasse", PostalCode: "10115", City: "Berlin", Country: "DE"}})
+	doc.AppendItem(&Item{Name: "Consulting", Description: "March", UnitCost: "10.333", Quantity: "2", Unit: "h", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "Books", UnitCost: "5", Quantity: "3", Tax: &Tax{Percent: "5.5"}})
+	doc.SetDiscount(&Discount{Amount: "3"})
+
+	return doc
+}
+
+func TestEncodeCII(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := EncodeCII(buffer, newEInvoice(EInvoiceProfileEN16931)); err != nil {
+		t.Fatal(err)
+	}
+
+	invoice := &struct {
+		Guideline string `xml:"ExchangedDocumentContext>GuidelineSpecifiedDocumentContextParameter>ID"`
+		TypeCode  string `xml:"ExchangedDocument>TypeCode"`
+		Lines     []struct {
+			Quantity struct {
+				Unit string `xml:"unitCode,attr"`
+			} `xml:"SpecifiedLineTradeDelivery>BilledQuantity"`
+			LineTotal string `xml:"SpecifiedLineTradeSettlement>SpecifiedTradeSettlementLineMonetarySummation>LineTotalAmount"`
+		} `xml:"SupplyChainTradeTransaction>IncludedSupplyChainTradeLineItem"`
+		Taxes []struct {
+			Basis  string `xml:"BasisAmount"`
+			Amount string `xml:"CalculatedAmount"`
+		} `xml:"SupplyChainTradeTransaction>ApplicableHeaderTradeSettlement>ApplicableTradeTax"`
+		Allowances []string `xml:"SupplyChainTradeTransaction>ApplicableHeaderTradeSettlement>SpecifiedTradeAllowanceCharge>ActualAmount"`
+		DueDate    string   `xml:"SupplyChainTradeTransaction>ApplicableHeaderTradeSettlement>SpecifiedTradePaymentTerms>DueDateDateTime>DateTimeString"`
+		Summation  struct {
+			LineTotal      string `xml:"LineTotalAmount"`
+			AllowanceTotal string `xml:"AllowanceTotalAmount"`
+			TaxBasisTotal  string `xml:"TaxBasisTotalAmount"`
+			TaxTotal       string `xml:"TaxTotalAmount"`
+			GrandTotal     string `xml:"GrandTotalAmount"`
+		} `xml:"SupplyChainTradeTransaction>ApplicableHeaderTradeSettlement>SpecifiedTradeSettlementHeaderMonetarySummation"`
+	}{}
+	if err := xml.Unmarshal(buffer.Bytes(), invoice); err != nil {
+		t.Fatal(err)
+	}
+
+	if invoice.Guideline != "urn:cen.eu:en16931:2017" || invoice.TypeCode != "380" || invoice.DueDate != "20240415" {
+		t.Errorf("unexpected document context %s, type %s, due date %s", invoice.Guideline, invoice.TypeCode, invoice.DueDate)
+	}
+	if len(invoice.Lines) != 2 || invoice.Lines[0].Quantity.Unit != "HUR" || invoice.Lines[0].LineTotal != "18.60" || invoice.Lines[1].LineTotal != "15.00" {
+		t.Errorf("unexpected lines %+v", invoice.Lines)
+	}
+
+	// Document discount split by VAT rate
+	if len(invoice.Allowances) != 2 || invoice.Allowances[0] != "1.66" || invoice.Allowances[1] != "1.34" {
+		t.Errorf("unexpected document allowances %v", invoice.Allowances)
+	}
+	if len(invoice.Taxes) != 2 || invoice.Taxes[0].Basis != "16.94" || invoice.Taxes[0].Amount != "3.39" || invoice.Taxes[1].Basis != "13.66" || invoice.Taxes[1].Amount != "0.75" {
+		t.Errorf("unexpected tax breakdown %+v", invoice.Taxes)
+	}
+
+	summation := invoice.Summation
+	if summation.LineTotal != "33.60" || summation.AllowanceTotal != "3.00" || summation.TaxBasisTotal != "30.60" || summation.TaxTotal != "4.14" || summation.GrandTotal != "34.74" {
+		t.Errorf("unexpected totals %+v", summation)
+	}
+}
+
+func TestEncodeCIIBasic(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := EncodeCII(buffer, newEInvoice(EInvoiceProfileBasic)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buffer.String(), "urn:factur-x.eu:1p0:basic") {
+		t.Error("expected BASIC guideline")
+	}
+	if strings.Contains(buffer.String(), "<ram:Description>March</ram:Description>") {
+		t.Error("expected no item description in BASIC profile")
+	}
+}
+
+func TestEncodeCIIErrors(t *testing.T) {
+	doc := newEInvoice(EInvoiceProfileEN16931)
+	doc.Customer.Address.Country = "Germany"
+	if err := EncodeCII(&bytes.Buffer{}, doc); !errors.Is(err, ErrInvalidEInvoice) {
+		t.Errorf("expected ErrInvalidEInvoice for country name, got %v", err)
+	}
+
+	doc = newEInvoice(EInvoiceProfileEN16931)
+	doc.AppendItem(&Item{Name: "Eco tax", UnitCost: "1", Quantity: "1", Tax: &Tax{Amount: "0.10"}})
+	if err := EncodeCII(&bytes.Buffer{}, doc); !errors.Is(err, ErrInvalidEInvoice) {
+		t.Errorf("expected ErrInvalidEInvoice for amount tax, got %v", err)
+	}
+
+	doc = newEInvoice("XRECHNUNG")
+	if err := doc.Validate(); err == nil {
+		t.Error("expected invalid profile error")
+	}
+}
+
+func TestOutputEInvoice(t *testing.T) {
+	doc := newEInvoice(EInvoiceProfileBasic)
+	doc.Options.EInvoice.ICCProfile = []byte("icc")
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buffer.Bytes()
+
+	for _, expected := range []string{
+		"/EmbeddedFiles << /Names [(factur-x.xml) ",
+		"/AFRelationship /Alternative",
+		"/Subtype /text#2Fxml",
+		"/AF [",
+		"/Metadata ",
+		"/OutputIntents [",
+		"<fx:ConformanceLevel>BASIC</fx:ConformanceLevel>",
+		"<fx:DocumentFileName>factur-x.xml</fx:DocumentFileName>",
+		"<ram:ID>F-2024-001</ram:ID>",
+		"/ID [<",
+	} {
+		if !bytes.Contains(pdf, []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Incremental update of the catalog
+	rootNumber, err := pdfSubmatchInt(pdfRootRegexp, pdf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := pdfObject(pdf, rootNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(catalog, "/AF [") || bytes.Count(pdf, []byte("%%EOF")) != 2 {
+		t.Errorf("expected an incremental update of catalog, got %s", catalog)
+	}
+
+	// Not PDF/A with standard fonts
+	if bytes.Contains(pdf, []byte("<pdfaid:part>")) {
+		t.Error("expected no PDF/A identification without embedded fonts")
+	}
+}
+
+func TestOutputEInvoicePDFA(t *testing.T) {
+	font, err := os.ReadFile(filepath.Join(build.Default.GOPATH, "pkg", "mod", "github.com", "go-pdf", "fpdf@v0.6.0", "font", "DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Skip("embedded font not available")
+	}
+
+	doc := newEInvoice(EInvoiceProfileBasic)
+	doc.Pdf().AddUTF8FontFromBytes("DejaVu", "", font)
+	doc.Pdf().AddUTF8FontFromBytes("DejaVu", "B", font)
+	doc.Options.Font = "DejaVu"
+	doc.Options.BoldFont = "DejaVu"
+
+	for _, iccProfile := range [][]byte{nil, []byte("icc")} {
+		doc.Options.EInvoice.ICCProfile = iccProfile
+
+		buffer := &bytes.Buffer{}
+		if err := doc.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		if pdfStandardFontRegexp.Match(buffer.Bytes()) {
+			t.Fatal("expected embedded fonts only")
+		}
+
+		pdfa := bytes.Contains(buffer.Bytes(), []byte("<pdfaid:part>3</pdfaid:part><pdfaid:conformance>B</pdfaid:conformance>"))
+		if pdfa != (iccProfile != nil) {
+			t.Errorf("expected PDF/A-3 identification %v with ICC profile %q", !pdfa, iccProfile)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Email define the subject and plain text body of a notification email sent with a document, see Document.Email
+type Email struct {
+	Subject string `json:"subject"` // ex Invoice INV-2024-0132 for €1,234.56 due 30 Jun
+	Body    string `json:"body"`
+}
+
+// Email validate document and return the subject and short summary of notification emails, from TextEmailSubject
+// and TextEmailBody between cover letter greeting and closing. Besides MergeFields, texts can use {type_name},
+// the document type in title case of Options.Locale, and {due}, TextEmailDue when the payment term is a date.
+func (doc *Document) Email() (*Email, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	fields := doc.MergeFields()
+	fields["type_name"] = cases.Title(language.Make(doc.Options.Locale)).String(strings.ToLower(doc.typeAsString()))
+	fields["due"] = ""
+	if due, err := time.Parse(doc.Options.DateFormat, doc.PaymentTerm); err == nil {
+		fields["due"] = fmt.Sprintf(doc.Options.TextEmailDue, due.Format(doc.Options.EmailDateFormat))
+	}
+
+	body := []string{
+		mergeFields(doc.Options.TextCoverLetterGreeting, fields),
+		mergeFields(doc.Options.TextEmailBody, fields),
+		mergeFields(doc.Options.TextCoverLetterClosing, fields) + "\n" + doc.Company.Name,
+	}
+
+	return &Email{
+		Subject: mergeFields(doc.Options.TextEmailSubject, fields),
+		Body:    strings.Join(body, "\n\n"),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestEmail(t *testing.T) {
+	doc := newMailingDocument(&Options{Locale: "en-GB", CurrencyCode: "EUR"})
+	doc.SetRef("INV-2024-0132")
+	doc.SetPaymentTerm("30/06/2024")
+	doc.Items[0].UnitCost = "617.28"
+
+	email, err := doc.Email()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Invoice INV-2024-0132 for €1,234.56 due 30 Jun"; email.Subject != expected {
+		t.Errorf("expected subject %q, got %q", expected, email.Subject)
+	}
+	expected := "Dear Customer,\n\n" +
+		"Please find attached our Invoice INV-2024-0132 of 02/01/2024, for a total of €1,234.56 due 30 Jun.\n\n" +
+		"Kind regards,\nCompany"
+	if email.Body != expected {
+		t.Errorf("expected body %q, got %q", expected, email.Body)
+	}
+
+	// No due mention when the payment term isn't a date
+	doc.SetPaymentTerm("On receipt")
+	doc.Options.TextEmailSubject = "{type_name} {ref}{due}"
+	if email, _ := doc.Email(); email.Subject != "Invoice INV-2024-0132" {
+		t.Errorf("unexpected subject %q", email.Subject)
+	}
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Expected totals check modes, see ExpectedTotals.Mode
+const (
+	ExpectedTotalsFail string = "fail"
+	ExpectedTotalsWarn string = "warn"
+)
+
+// ExpectedTotals define the totals computed by the caller, ex by its ERP, that document totals must match
+type ExpectedTotals struct {
+	WithoutTax string `json:"without_tax,omitempty"` // Not checked when empty
+	Tax        string `json:"tax,omitempty"`
+	WithTax    string `json:"with_tax,omitempty"`
+	Tolerance  string `json:"tolerance,omitempty"`                                 // Allowed difference, none when empty
+	Mode       string `json:"mode,omitempty" validate:"omitempty,oneof=fail warn"` // Build fails or warns on mismatch, fail when empty
+
+	_withoutTax decimal.Decimal
+	_tax        decimal.Decimal
+	_withTax    decimal.Decimal
+	_tolerance  decimal.Decimal
+}
+
+// TotalMismatchError define a document total differing from the expected one
+type TotalMismatchError struct {
+	Total    string
+	Expected decimal.Decimal
+	Actual   decimal.Decimal
+}
+
+// Error return the total and amounts
+func (e *TotalMismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch: expected %s, computed %s", e.Total, e.Expected, e.Actual)
+}
+
+// Prepare convert expected totals and tolerance to decimal
+func (e *ExpectedTotals) Prepare() error {
+	for _, amount := range []struct {
+		value  string
+		parsed *decimal.Decimal
+	}{
+		{e.WithoutTax, &e._withoutTax},
+		{e.Tax, &e._tax},
+		{e.WithTax, &e._withTax},
+		{e.Tolerance, &e._tolerance},
+	} {
+		if len(amount.value) == 0 {
+			continue
+		}
+
+		value, err := decimal.NewFromString(amount.value)
+		if err != nil {
+			return err
+		}
+		*amount.parsed = value
+	}
+
+	return nil
+}
+
+// checkExpectedTotals compare document totals rounded to Options.CurrencyPrecision to the expected ones,
+// return the first mismatch or record them as warnings following ExpectedTotals.Mode
+func (doc *Document) checkExpectedTotals() error {
+	expected := doc.ExpectedTotals
+	if expected == nil {
+		return nil
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	for _, total := range []struct {
+		name     string
+		value    string
+		expected decimal.Decimal
+		actual   decimal.Decimal
+	}{
+		{"total without tax", expected.WithoutTax, expected._withoutTax, doc.TotalWithoutTax()},
+		{"tax", expected.Tax, expected._tax, doc.Tax()},
+		{"total with tax", expected.WithTax, expected._withTax, doc.TotalWithTax()},
+	} {
+		if len(total.value) == 0 {
+			continue
+		}
+
+		actual := total.actual.Round(precision)
+		if actual.Sub(total.expected).Abs().LessThanOrEqual(expected._tolerance) {
+			continue
+		}
+
+		err := &TotalMismatchError{Total: total.name, Expected: total.expected, Actual: actual}
+		if expected.Mode != ExpectedTotalsWarn {
+			return err
+		}
+		doc.warn("expected totals", err)
+	}
+
+	return nil
+}
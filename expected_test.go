@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpectedTotals(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10.333", Quantity: "3", Tax: &Tax{Percent: "20"}})
+
+	doc.ExpectedTotals = &ExpectedTotals{WithoutTax: "31.00", Tax: "6.20", WithTax: "37.20"}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.ExpectedTotals = &ExpectedTotals{WithTax: "37.22"}
+	_, err := doc.Build()
+	mismatch := &TotalMismatchError{}
+	if !errors.As(err, &mismatch) || mismatch.Total != "total with tax" || mismatch.Actual.String() != "37.2" {
+		t.Fatalf("expected total with tax mismatch, got %v", err)
+	}
+
+	doc.ExpectedTotals = &ExpectedTotals{WithTax: "37.22", Tolerance: "0.05"}
+	if _, err := doc.Build(); err != nil {
+		t.Errorf("expected difference within tolerance, got %v", err)
+	}
+
+	doc.ExpectedTotals = &ExpectedTotals{Tax: "6.00", Mode: ExpectedTotalsWarn}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if warnings := doc.Warnings(); len(warnings) != 1 || warnings[0].Subject != "expected totals" {
+		t.Errorf("expected tax mismatch warning, got %v", warnings)
+	}
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExplainStep define a step of totals calculation
+type ExplainStep struct {
+	Label  string `json:"label"`            // ex line 1 Consulting net
+	Detail string `json:"detail,omitempty"` // Operation, ex 1800.00 - 10 % = 1620.00
+	Amount string `json:"amount"`           // Result rounded to Options.CurrencyPrecision, computations use full precision
+}
+
+// Explanation define the steps of totals calculation, from lines net amounts to gross total
+type Explanation []*ExplainStep
+
+// String return one step per line, ex to paste in support tickets
+func (e Explanation) String() string {
+	lines := []string{}
+	for _, step := range e {
+		if len(step.Detail) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", step.Label, step.Detail))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %s", step.Label, step.Amount))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Explain return the calculation trace of totals: line net, discount, tax base, tax then gross.
+// Empty for totals not computed by Document.Totals.
+func (t *Totals) Explain() Explanation {
+	doc := t.doc
+	if doc == nil {
+		return Explanation{}
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	amount := func(value decimal.Decimal) string {
+		return value.StringFixed(precision)
+	}
+	steps := Explanation{}
+	step := func(label string, detail string, value decimal.Decimal) {
+		steps = append(steps, &ExplainStep{Label: label, Detail: detail, Amount: amount(value)})
+	}
+
+	for i, item := range doc.Items {
+		label := fmt.Sprintf("line %d %s", i+1, item.Name)
+		net := item.TotalWithoutTaxAndWithDiscount()
+
+		switch {
+		case item.Free:
+			step(label+" net", "free of charge = "+amount(net), net)
+		case len(item.Total) > 0:
+			step(label+" net", "total given = "+amount(net), net)
+		default:
+			gross := item.TotalWithoutTaxAndWithoutDiscount()
+			if item.Kind == ItemKindFlatFee {
+				step(label+" amount", "flat fee = "+amount(gross), gross)
+			} else if len(item.PriceBasis) > 0 {
+				step(label+" amount", fmt.Sprintf("%s x %s / %s = %s", item.UnitCost, item.Quantity, item.PriceBasis, amount(gross)), gross)
+			} else {
+				step(label+" amount", fmt.Sprintf("%s x %s = %s", item.UnitCost, item.Quantity, amount(gross)), gross)
+			}
+
+			if item.Discount != nil {
+				discountType, discount := item.Discount.getDiscount()
+				if discountType == DiscountTypePercent {
+					step(label+" net", fmt.Sprintf("%s - %s %% = %s", amount(gross), discount, amount(net)), net)
+				} else {
+					step(label+" net", fmt.Sprintf("%s - %s = %s", amount(gross), amount(gross.Sub(net)), amount(net)), net)
+				}
+			}
+		}
+
+		if item.Tax != nil {
+			tax := item.TaxWithTotalDiscounted()
+			if taxType, rate := item.Tax.getTax(); taxType == TaxTypePercent {
+				step(label+" tax", fmt.Sprintf("%s x %s %% = %s", amount(net), rate, amount(tax)), tax)
+			} else {
+				step(label+" tax", "fixed amount = "+amount(tax), tax)
+			}
+		}
+	}
+
+	lines := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	base := doc.TotalWithoutTax()
+	step("lines net", "", lines)
+	if doc.Discount != nil {
+		discountType, discount := doc.Discount.getDiscount()
+		if discountType == DiscountTypePercent {
+			step("tax base", fmt.Sprintf("%s - %s %% document discount = %s", amount(lines), discount, amount(base)), base)
+		} else {
+			step("tax base", fmt.Sprintf("%s - %s document discount = %s", amount(lines), amount(discount), amount(base)), base)
+		}
+	} else {
+		step("tax base", "", base)
+	}
+
+	for _, line := range doc.TaxLines() {
+		if line.Type == TaxTypeAmount {
+			step("fixed taxes", fmt.Sprintf("on %s = %s", amount(line.Base), amount(line.Amount)), line.Amount)
+			continue
+		}
+		step(fmt.Sprintf("tax %s %%", line.Rate), fmt.Sprintf("%s x %s %% = %s", amount(line.Base), line.Rate, amount(line.Amount)), line.Amount)
+	}
+
+	tax := doc.Tax()
+	gross := doc.TotalWithTax()
+	step("tax", "", tax)
+	step("gross", fmt.Sprintf("%s + %s = %s", amount(base), amount(tax), amount(gross)), gross)
+
+	return steps
+}
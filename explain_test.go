@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTotalsExplain(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "150", Quantity: "12", Tax: &Tax{Percent: "20"}, Discount: &Discount{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "Travel", UnitCost: "80", Quantity: "1"})
+	doc.SetDiscount(&Discount{Amount: "100"})
+
+	explanation := doc.Totals().Explain()
+	text := explanation.String()
+	for _, expected := range []string{
+		"line 1 Consulting amount: 150 x 12 = 1800.00",
+		"line 1 Consulting net: 1800.00 - 10 % = 1620.00",
+		"line 1 Consulting tax: 1620.00 x 20 % = 324.00",
+		"line 2 Travel amount: 80 x 1 = 80.00",
+		"lines net: 1700.00",
+		"tax base: 1700.00 - 100.00 document discount = 1600.00",
+		"tax: 304.94",
+		"gross: 1600.00 + 304.94 = 1904.94",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in explanation:\n%s", expected, text)
+		}
+	}
+
+	last := explanation[len(explanation)-1]
+	if last.Amount != doc.TotalWithTax().StringFixed(2) {
+		t.Errorf("expected explanation to end with gross total, got %s", last.Amount)
+	}
+
+	if steps := (&Totals{}).Explain(); len(steps) != 0 {
+		t.Errorf("expected no steps without document, got %d", len(steps))
+	}
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Glyph substitution actions, see GlyphSubstitution
+const (
+	GlyphFallback string = "fallback" // Drawn with a fallback font
+	GlyphReplaced string = "replaced" // Replaced following SymbolReplacements or DefaultSymbolReplacements
+	GlyphDropped  string = "dropped"  // Emoji removed
+	GlyphUnknown  string = "unknown"  // Replaced with TextUnknownSymbol
+)
+
+// FallbackFont define a Unicode (TTF) font drawing item names characters the document font can't encode
+type FallbackFont struct {
+	Family string `json:"family" validate:"required"` // Font family, registered on document pdf when File is empty
+	File   []byte `json:"file,omitempty"`             // TTF font data
+	Runes  string `json:"runes,omitempty"`            // Characters drawn by the font, any character when empty
+}
+
+// GlyphSubstitution report a character the document font can't encode and how it was drawn instead
+type GlyphSubstitution struct {
+	Character   string `json:"character"`
+	Code        string `json:"code"` // ex U+2713
+	Action      string `json:"action"`
+	Font        string `json:"font,omitempty"`        // Fallback font family
+	Replacement string `json:"replacement,omitempty"` // Replacement text
+}
+
+// glyphKey identify a substitution, a character can fall back in item names and be replaced elsewhere
+type glyphKey struct {
+	r      rune
+	action string
+}
+
+// DefaultSymbolReplacements replace common symbols the document font can't encode, when no fallback font draws them
+var DefaultSymbolReplacements = map[string]string{
+	"✓": "v",
+	"✔": "v",
+	"✗": "x",
+	"✘": "x",
+	"№": "No",
+	"₹": "Rs",
+	"→": "->",
+	"←": "<-",
+	"≤": "<=",
+	"≥": ">=",
+	"★": "*",
+	"☆": "*",
+	"−": "-",
+
+	// Narrow and figure spaces of French typography
+	"\u202f": "\u00a0",
+	"\u2007": "\u00a0",
+}
+
+// encodable return true when the document font can encode r
+func (doc *Document) encodable(r rune) bool {
+	if r < utf8.RuneSelf {
+		return true
+	}
+
+	// The translator turns unknown characters into dots
+	return doc.Options.UnicodeTranslateFunc(string(r)) != "."
+}
+
+// emoji return true for pictographs, dropped when they can't be encoded nor replaced
+func emoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || r == 0xFE0F || r == 0x200D
+}
+
+// replaceSymbols replace characters the document font can't encode following Options.SymbolReplacements then
+// DefaultSymbolReplacements, emoji are dropped and other characters replaced with Options.TextUnknownSymbol
+func (doc *Document) replaceSymbols(text string) string {
+	ascii := true
+	for i := 0; i < len(text) && ascii; i++ {
+		ascii = text[i] < utf8.RuneSelf
+	}
+	if ascii {
+		return text
+	}
+
+	replaced := strings.Builder{}
+	for _, r := range text {
+		if doc.encodable(r) {
+			replaced.WriteRune(r)
+			continue
+		}
+
+		if replacement, ok := doc.Options.SymbolReplacements[string(r)]; ok {
+			replaced.WriteString(replacement)
+			doc.substitute(r, GlyphReplaced, "", replacement)
+		} else if replacement, ok := DefaultSymbolReplacements[string(r)]; ok {
+			replaced.WriteString(replacement)
+			doc.substitute(r, GlyphReplaced, "", replacement)
+		} else if !emoji(r) {
+			replaced.WriteString(doc.Options.TextUnknownSymbol)
+			doc.substitute(r, GlyphUnknown, "", doc.Options.TextUnknownSymbol)
+		} else {
+			doc.substitute(r, GlyphDropped, "", "")
+		}
+	}
+
+	return replaced.String()
+}
+
+// fallbackFont return the family of the first fallback font drawing r, empty when none does
+func (doc *Document) fallbackFont(r rune) string {
+	for _, font := range doc.Options.FallbackFonts {
+		if len(font.Runes) == 0 || strings.ContainsRune(font.Runes, r) {
+			return font.Family
+		}
+	}
+
+	return ""
+}
+
+// registerFallbackFonts register fallback fonts given with their data on document pdf
+func (doc *Document) registerFallbackFonts() {
+	for _, font := range doc.Options.FallbackFonts {
+		if font.File != nil {
+			doc.pdf.AddUTF8FontFromBytes(font.Family, "", font.File)
+		}
+	}
+}
+
+// substitute record how r, which the document font can't encode, was drawn
+func (doc *Document) substitute(r rune, action string, font string, replacement string) {
+	key := glyphKey{r: r, action: action}
+	if _, ok := doc.glyphs[key]; ok {
+		return
+	}
+	if doc.glyphs == nil {
+		doc.glyphs = map[glyphKey]*GlyphSubstitution{}
+	}
+
+	doc.glyphs[key] = &GlyphSubstitution{
+		Character:   string(r),
+		Code:        fmt.Sprintf("U+%04X", r),
+		Action:      action,
+		Font:        font,
+		Replacement: replacement,
+	}
+}
+
+// GlyphSubstitutions return the characters of the last Build the document font couldn't encode and how they
+// were drawn, ordered by code then action, so that reports of a same document are identical
+func (doc *Document) GlyphSubstitutions() []*GlyphSubstitution {
+	keys := make([]glyphKey, 0, len(doc.glyphs))
+	for key := range doc.glyphs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].r != keys[b].r {
+			return keys[a].r < keys[b].r
+		}
+		return keys[a].action < keys[b].action
+	})
+
+	substitutions := make([]*GlyphSubstitution, 0, len(keys))
+	for _, key := range keys {
+		substitutions = append(substitutions, doc.glyphs[key])
+	}
+
+	return substitutions
+}
+
+// MissingGlyphs return the substitutions of the last Build losing characters, dropped or replaced with
+// TextUnknownSymbol, for pipelines to block documents customers can't fully read
+func (doc *Document) MissingGlyphs() []*GlyphSubstitution {
+	missing := []*GlyphSubstitution{}
+	for _, substitution := range doc.GlyphSubstitutions() {
+		if substitution.Action == GlyphUnknown || substitution.Action == GlyphDropped {
+			missing = append(missing, substitution)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"bytes"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaceSymbols(t *testing.T) {
+	doc, _ := New(Invoice, &Options{SymbolReplacements: map[string]string{"✓": "OK"}})
+
+	for text, expected := range map[string]string{
+		"Café crème":          "Café crème",
+		"Audit ✓":             "Audit OK",
+		"Invoice № 12":        "Invoice No 12",
+		"₹ 1,200":             "Rs 1,200",
+		"Party 🎉 pack":        "Party  pack",
+		"Thumbs 👍🏽 up":        "Thumbs  up",
+		"Hiragana ひらがな":       "Hiragana ????",
+		"Plain ASCII text...": "Plain ASCII text...",
+	} {
+		if replaced := doc.replaceSymbols(text); replaced != expected {
+			t.Errorf("expected %q replaced as %q, got %q", text, expected, replaced)
+		}
+	}
+
+	// Replaced before encoding, no garbage dots
+	if encoded := doc.encodeString("✔ €"); encoded != "v \x80" {
+		t.Errorf("unexpected encoded string %q", encoded)
+	}
+}
+
+func TestFallbackFonts(t *testing.T) {
+	font, err := os.ReadFile(filepath.Join(build.Default.GOPATH, "pkg", "mod", "github.com", "go-pdf", "fpdf@v0.6.0", "font", "DejaVuSansCondensed.ttf"))
+	if err != nil {
+		t.Skip("fallback font not available")
+	}
+
+	doc, _ := New(Invoice, &Options{
+		DisableCompression: true,
+		FallbackFonts: []*FallbackFont{
+			{Family: "Symbols", File: font, Runes: "✓№₹"},
+		},
+	})
+
+	runs := doc.textRuns("Audit ✓ № 12 🎉")
+	if len(runs) != 5 || runs[1].family != "Symbols" || runs[1].text != "✓" || runs[3].text != "№" || runs[4].family != "" {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+	if doc.textRuns("Café crème") != nil {
+		t.Error("expected no runs when document font draws the text")
+	}
+
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Audit ✓ with a long name wrapping on a few lines of the items table, № 12", UnitCost: "100", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Next item", UnitCost: "100", Quantity: "1"})
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("/BaseFont /utf8symbols")) {
+		t.Error("expected fallback font embedded")
+	}
+}
+
+func TestGlyphSubstitutions(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company ✓"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Party pack 🎉", Description: "ひ", UnitCost: "10", Quantity: "1"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := doc.Build(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Same report on each build
+		substitutions := doc.GlyphSubstitutions()
+		if len(substitutions) != 3 {
+			t.Fatalf("expected 3 substitutions, got %d", len(substitutions))
+		}
+		if s := substitutions[0]; s.Code != "U+2713" || s.Action != GlyphReplaced || s.Replacement != "v" {
+			t.Errorf("unexpected substitution %+v", s)
+		}
+		if s := substitutions[1]; s.Character != "ひ" || s.Action != GlyphUnknown || s.Replacement != "?" {
+			t.Errorf("unexpected substitution %+v", s)
+		}
+		if s := substitutions[2]; s.Code != "U+1F389" || s.Action != GlyphDropped {
+			t.Errorf("unexpected substitution %+v", s)
+		}
+		if missing := doc.MissingGlyphs(); len(missing) != 2 {
+			t.Errorf("expected 2 missing glyphs, got %d", len(missing))
+		}
+	}
+
+	doc.Options.FallbackFonts = []*FallbackFont{{Family: "Kana", Runes: "ひ"}}
+	doc.textRuns("Kana ひ")
+	if s := doc.GlyphSubstitutions()[1]; s.Action != GlyphFallback || s.Font != "Kana" {
+		t.Errorf("expected fallback substitution, got %+v", s)
+	}
+}
@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrFetchNotAllowed when a logo URL scheme, host or address isn't allowed by the fetcher
+var ErrFetchNotAllowed = errors.New("fetch not allowed")
+
+// ErrFetchTooLarge when a fetched logo exceeds HTTPFetcher.MaxBytes
+var ErrFetchTooLarge = errors.New("fetched content too large")
+
+// Fetcher fetch logos given as URLs (Contact.LogoURL)
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+// HTTPFetcher fetch logos over HTTP with SSRF protections: scheme and host allowlists,
+// private, loopback and link-local addresses refused at connection time (after DNS resolution),
+// timeout and size cap
+type HTTPFetcher struct {
+	Timeout              time.Duration
+	MaxBytes             int64
+	AllowedSchemes       []string // Default https
+	AllowedHosts         []string // Exact hosts or ".example.com" suffixes, empty allows any public host
+	AllowPrivateNetworks bool     // Allow private and loopback addresses, for tests or internal assets
+	MaxRedirects         int
+}
+
+// NewHTTPFetcher return an HTTP fetcher with safe defaults: https only,
+// public addresses only, 10s timeout, 5MB cap, 3 redirects
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{
+		Timeout:        10 * time.Second,
+		MaxBytes:       5 << 20,
+		AllowedSchemes: []string{"https"},
+		MaxRedirects:   3,
+	}
+}
+
+// allowedURL check URL scheme and host against allowlists
+func (f *HTTPFetcher) allowedURL(u *url.URL) bool {
+	schemes := f.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	schemeAllowed := false
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			schemeAllowed = true
+		}
+	}
+	if !schemeAllowed || len(u.Hostname()) == 0 || u.User != nil {
+		return false
+	}
+
+	if len(f.AllowedHosts) == 0 {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range f.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowedIP return false for private, loopback, link-local, multicast and unspecified addresses
+func (f *HTTPFetcher) allowedIP(ip net.IP) bool {
+	if f.AllowPrivateNetworks {
+		return true
+	}
+
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// client return an HTTP client checking dialed addresses and redirects
+func (f *HTTPFetcher) client() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: f.Timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !f.allowedIP(ip) {
+				return ErrFetchNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: f.Timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: f.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.MaxRedirects || !f.allowedURL(req.URL) {
+				return ErrFetchNotAllowed
+			}
+			return nil
+		},
+	}
+}
+
+// Fetch return content of rawURL
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if !f.allowedURL(u) {
+		return nil, ErrFetchNotAllowed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.client().Do(req)
+	if err != nil {
+		if errors.Is(err, ErrFetchNotAllowed) {
+			return nil, ErrFetchNotAllowed
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", u.Redacted(), res.Status)
+	}
+	if f.MaxBytes > 0 && res.ContentLength > f.MaxBytes {
+		return nil, ErrFetchTooLarge
+	}
+
+	reader := io.Reader(res.Body)
+	if f.MaxBytes > 0 {
+		reader = io.LimitReader(res.Body, f.MaxBytes+1)
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if f.MaxBytes > 0 && int64(len(content)) > f.MaxBytes {
+		return nil, ErrFetchTooLarge
+	}
+
+	return content, nil
+}
+
+// fetchLogos fetch contacts logos given as URLs with Options.Fetcher, once.
+// Failures are warnings unless Options.MissingImages is strict.
+func (doc *Document) fetchLogos() error {
+	for _, contact := range []*Contact{doc.Company, doc.Customer} {
+		if contact == nil || contact.Logo != nil || len(contact.LogoURL) == 0 {
+			continue
+		}
+
+		fetcher := doc.Options.Fetcher
+		if fetcher == nil {
+			fetcher = NewHTTPFetcher()
+		}
+
+		logo, err := fetcher.Fetch(context.Background(), contact.LogoURL)
+		if err != nil {
+			if doc.strictImages() {
+				return err
+			}
+			doc.warn(contact.Name+" logo", err)
+			continue
+		}
+		contact.Logo = logo
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPFetcher(t *testing.T) {
+	logo := testPhoto(40, 30)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			return
+		}
+		_, _ = w.Write(logo)
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher()
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/logo.jpg"); err != ErrFetchNotAllowed {
+		t.Errorf("expected http scheme to be refused, got %v", err)
+	}
+
+	fetcher.AllowedSchemes = []string{"http"}
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/logo.jpg"); err != ErrFetchNotAllowed {
+		t.Errorf("expected loopback address to be refused, got %v", err)
+	}
+
+	fetcher.AllowPrivateNetworks = true
+	content, err := fetcher.Fetch(context.Background(), server.URL+"/logo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, logo) {
+		t.Errorf("expected fetched logo")
+	}
+
+	fetcher.AllowedHosts = []string{".example.com"}
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/logo.jpg"); err != ErrFetchNotAllowed {
+		t.Errorf("expected host outside allowlist to be refused, got %v", err)
+	}
+
+	fetcher.AllowedHosts = []string{"127.0.0.1"}
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/redirect"); err != ErrFetchNotAllowed {
+		t.Errorf("expected redirect outside allowlist to be refused, got %v", err)
+	}
+
+	fetcher.AllowedHosts = nil
+	fetcher.AllowPrivateNetworks = false
+	fetcher.MaxBytes = 10
+	if _, err := fetcher.Fetch(context.Background(), "http://localhost/logo.jpg"); err != ErrFetchNotAllowed {
+		t.Errorf("expected localhost to be refused, got %v", err)
+	}
+
+	fetcher.AllowPrivateNetworks = true
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/logo.jpg"); err != ErrFetchTooLarge {
+		t.Errorf("expected %v, got %v", ErrFetchTooLarge, err)
+	}
+
+	// Document logos are fetched once on validation
+	doc, _ := New(Invoice, &Options{Fetcher: &HTTPFetcher{AllowedSchemes: []string{"http"}, AllowPrivateNetworks: true}})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company", LogoURL: server.URL + "/logo.jpg"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(doc.Company.Logo, logo) {
+		t.Errorf("expected company logo to be fetched")
+	}
+}
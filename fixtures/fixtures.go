@@ -0,0 +1,131 @@
+// Package fixtures provide canonical example documents to test integrations against realistic data.
+// Each function return a new document, ready to be built.
+package fixtures
+
+import (
+	"fmt"
+
+	generator "github.com/ebtsi/go-invoice-generator"
+)
+
+// Fixtures return all scenario fixtures by name
+func Fixtures() map[string]func() *generator.Document {
+	return map[string]func() *generator.Document{
+		"minimal":               Minimal,
+		"eu_b2b_reverse_charge": EUReverseCharge,
+		"us_sales_tax":          USSalesTax,
+		"usage_500_lines":       UsageInvoice,
+		"credit_note":           CreditNote,
+	}
+}
+
+// newDocument return a document with options, ref, date and company
+func newDocument(options *generator.Options, ref string, company *generator.Contact) *generator.Document {
+	doc, _ := generator.New(generator.Invoice, options)
+	doc.SetRef(ref)
+	doc.SetDate("15/01/2024")
+	doc.SetCompany(company)
+	return doc
+}
+
+// Minimal return the smallest valid invoice, one item and a default tax
+func Minimal() *generator.Document {
+	doc := newDocument(&generator.Options{}, "INV-0001", &generator.Contact{Name: "Acme SARL"})
+	doc.SetCustomer(&generator.Contact{Name: "Jane Doe"})
+	doc.SetDefaultTax(&generator.Tax{Percent: "20"})
+	doc.AppendItem(&generator.Item{Name: "Consulting", UnitCost: "100", Quantity: "1"})
+	return doc
+}
+
+// EUReverseCharge return an intra-community B2B invoice, VAT due by the customer
+func EUReverseCharge() *generator.Document {
+	doc := newDocument(&generator.Options{}, "INV-2024-0042", &generator.Contact{
+		Name:  "Acme SARL",
+		TaxID: "FR40303265045",
+		Address: &generator.Address{
+			Address:    "12 rue de la Paix",
+			PostalCode: "75002",
+			City:       "Paris",
+			Country:    "FR",
+		},
+	})
+	doc.SetCustomer(&generator.Contact{
+		Name:  "Beispiel GmbH",
+		TaxID: "DE136695976",
+		Address: &generator.Address{
+			Address:    "Friedrichstraße 123",
+			PostalCode: "10117",
+			City:       "Berlin",
+			Country:    "DE",
+		},
+	})
+	doc.SetPaymentTerm("14/02/2024")
+	doc.SetDefaultTax(&generator.Tax{Percent: "0"})
+	doc.SetNotes("Reverse charge - VAT to be accounted for by the recipient, article 196 of Council Directive 2006/112/EC.")
+	doc.AppendItem(&generator.Item{Name: "Software license", UnitCost: "1200", Quantity: "3"})
+	doc.AppendItem(&generator.Item{Name: "Onboarding workshop", UnitCost: "850", Quantity: "1"})
+	return doc
+}
+
+// USSalesTax return a US invoice with state and city sales tax
+func USSalesTax() *generator.Document {
+	doc := newDocument(&generator.Options{
+		CurrencySymbol:   "$",
+		CurrencyThousand: ",",
+		DateFormat:       "01/02/2006",
+	}, "10045", &generator.Contact{
+		Name:  "Acme Inc.",
+		TaxID: "12-3456789",
+		Address: &generator.Address{
+			Address:    "350 Fifth Avenue",
+			PostalCode: "10118",
+			City:       "New York, NY",
+			Country:    "US",
+		},
+	})
+	doc.SetDate("01/15/2024")
+	doc.SetCustomer(&generator.Contact{
+		Name: "John Smith",
+		Address: &generator.Address{
+			Address:    "1 Main Street",
+			PostalCode: "11201",
+			City:       "Brooklyn, NY",
+			Country:    "US",
+		},
+	})
+	doc.SetPaymentTerm("Net 30")
+	doc.SetDefaultTax(&generator.Tax{Percent: "8.875"})
+	doc.AppendItem(&generator.Item{Name: "Office chair", UnitCost: "249.99", Quantity: "2"})
+	doc.AppendItem(&generator.Item{Name: "Desk lamp", UnitCost: "39.50", Quantity: "4", Discount: &generator.Discount{Percent: "10"}})
+	doc.AppendItem(&generator.Item{Name: "Delivery", UnitCost: "25", Quantity: "1", Tax: &generator.Tax{Percent: "0"}})
+	return doc
+}
+
+// UsageInvoice return a 500 lines telecom usage statement
+func UsageInvoice() *generator.Document {
+	doc := newDocument(&generator.Options{}, "TEL-2024-01-000123", &generator.Contact{Name: "Acme Telecom"})
+	doc.SetCustomer(&generator.Contact{Name: "Globex Corporation"})
+	doc.SetDescription("Usage statement, January 2024")
+	doc.SetDefaultTax(&generator.Tax{Percent: "20"})
+	doc.AppendItem(&generator.Item{Name: "Monthly subscription", UnitCost: "29.99", Quantity: "1"})
+	for i := 1; i < 500; i++ {
+		doc.AppendItem(&generator.Item{
+			Name:        fmt.Sprintf("Call +33 1 %02d %02d %02d %02d", i%100, (i*7)%100, (i*13)%100, (i*17)%100),
+			Description: fmt.Sprintf("%02d/01/2024 - %d min", i%31+1, i%45+1),
+			UnitCost:    "0.05",
+			Quantity:    fmt.Sprintf("%d", i%45+1),
+		})
+	}
+	return doc
+}
+
+// CreditNote return a credit note cancelling part of an invoice, as negative quantities
+func CreditNote() *generator.Document {
+	doc := newDocument(&generator.Options{TextTypeInvoice: "CREDIT NOTE", AllowNegativeLines: true}, "CN-0007", &generator.Contact{Name: "Acme SARL"})
+	doc.SetCustomer(&generator.Contact{Name: "Jane Doe"})
+	doc.SetDescription("Credit note for invoice INV-0001, returned goods")
+	doc.SetDefaultTax(&generator.Tax{Percent: "20"})
+	doc.AppendItem(&generator.Item{Name: "Returned headphones", UnitCost: "79.90", Quantity: "-1"})
+	doc.AppendItem(&generator.Item{Name: "Shipping refund", UnitCost: "4.90", Quantity: "-1"})
+	return doc
+}
@@ -0,0 +1,21 @@
+package fixtures
+
+import (
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	for name, fixture := range Fixtures() {
+		doc := fixture()
+		if _, err := doc.Build(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	if total := CreditNote().TotalWithTax(); !total.IsNegative() {
+		t.Errorf("expected negative credit note total, got %s", total)
+	}
+	if items := len(UsageInvoice().Items); items != 500 {
+		t.Errorf("expected 500 items, got %d", items)
+	}
+}
@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidStay when departure is before arrival
+var ErrInvalidStay = errors.New("invalid stay")
+
+// Stay define hotel stay informations displayed on folio layout
+type Stay struct {
+	Guest        string `json:"guest,omitempty" validate:"max=256"`
+	Room         string `json:"room,omitempty" validate:"max=32"`
+	Arrival      string `json:"arrival,omitempty" validate:"required"`
+	Departure    string `json:"departure,omitempty" validate:"required"`
+	Guests       int    `json:"guests,omitempty" validate:"min=0"`
+	Confirmation string `json:"confirmation,omitempty" validate:"max=64"`
+
+	// CityTaxRate per guest and per night, a city tax line is generated for each night when set
+	CityTaxRate string `json:"city_tax_rate,omitempty"`
+
+	_arrival   time.Time
+	_departure time.Time
+}
+
+// Prepare parse stay dates using the provided date layout
+func (s *Stay) Prepare(dateLayout string) error {
+	arrival, err := time.Parse(dateLayout, s.Arrival)
+	if err != nil {
+		return err
+	}
+	s._arrival = arrival
+
+	departure, err := time.Parse(dateLayout, s.Departure)
+	if err != nil {
+		return err
+	}
+	s._departure = departure
+
+	if s._departure.Before(s._arrival) {
+		return ErrInvalidStay
+	}
+
+	return nil
+}
+
+// Nights return the number of nights of the stay
+func (s *Stay) Nights() int {
+	return int(s._departure.Sub(s._arrival).Hours() / 24)
+}
+
+// cityTaxItems return one city tax item per night of the stay
+func (s *Stay) cityTaxItems(options *Options) ([]*Item, error) {
+	rate, err := decimal.NewFromString(s.CityTaxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	guests := s.Guests
+	if guests == 0 {
+		guests = 1
+	}
+	quantity := decimal.NewFromInt(int64(guests))
+
+	items := make([]*Item, 0, s.Nights())
+	for night := 0; night < s.Nights(); night++ {
+		items = append(items, &Item{
+			Name:     options.TextFolioCityTaxTitle,
+			Date:     s._arrival.AddDate(0, 0, night).Format(options.DateFormat),
+			UnitCost: s.CityTaxRate,
+			Quantity: quantity.String(),
+			Total:    rate.Mul(quantity).StringFixed(int32(options.CurrencyPrecision)),
+			cityTax:  true,
+		})
+	}
+
+	return items, nil
+}
+
+// prepareStay parse stay and (re)generate city tax items
+func (doc *Document) prepareStay() error {
+	if doc.Stay == nil {
+		return nil
+	}
+
+	if err := doc.Stay.Prepare(doc.Options.DateFormat); err != nil {
+		return err
+	}
+
+	// Remove city tax items generated by a previous build
+	items := make([]*Item, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		if !item.cityTax {
+			items = append(items, item)
+		}
+	}
+	doc.Items = items
+
+	if len(doc.Stay.CityTaxRate) > 0 {
+		cityTaxItems, err := doc.Stay.cityTaxItems(doc.Options)
+		if err != nil {
+			return err
+		}
+		doc.Items = append(doc.Items, cityTaxItems...)
+	}
+
+	return nil
+}
+
+// MarshalJSON marshal document without the city tax items generated from Stay, they are generated again on build
+func (doc *Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	copied := document(*doc)
+
+	copied.Items = make([]*Item, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		if !item.cityTax {
+			copied.Items = append(copied.Items, item)
+		}
+	}
+	if doc.Items == nil {
+		copied.Items = nil
+	}
+
+	return json.Marshal(copied)
+}
+
+// appendStay append guest and stay informations block
+func (doc *Document) appendStay() {
+	if doc.Stay == nil {
+		return
+	}
+
+	infos := []string{}
+	if len(doc.Stay.Guest) > 0 {
+		infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextFolioGuestTitle, doc.Stay.Guest))
+	}
+	if len(doc.Stay.Room) > 0 {
+		infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextFolioRoomTitle, doc.Stay.Room))
+	}
+	infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextFolioArrivalTitle, doc.Stay.Arrival))
+	infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextFolioDepartureTitle, doc.Stay.Departure))
+	infos = append(infos, fmt.Sprintf("%s: %d", doc.Options.TextFolioNightsTitle, doc.Stay.Nights()))
+	if doc.Stay.Guests > 0 {
+		infos = append(infos, fmt.Sprintf("%s: %d", doc.Options.TextFolioGuestsTitle, doc.Stay.Guests))
+	}
+	if len(doc.Stay.Confirmation) > 0 {
+		infos = append(infos, fmt.Sprintf("%s: %s", doc.Options.TextFolioConfirmationTitle, doc.Stay.Confirmation))
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 5)
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.MultiCell(190, 6, doc.encodeString(strings.Join(infos, "   ")), "0", "L", true)
+}
+
+// appendFolioItems append items ordered by date and grouped per day
+func (doc *Document) appendFolioItems() {
+	items := make([]*Item, len(doc.Items))
+	copy(items, doc.Items)
+
+	// Sort items by date, undated items last
+	sort.SliceStable(items, func(a, b int) bool {
+		dateA, errA := time.Parse(doc.Options.DateFormat, items[a].Date)
+		dateB, errB := time.Parse(doc.Options.DateFormat, items[b].Date)
+		if errA != nil || errB != nil {
+			return errA == nil && errB != nil
+		}
+		return dateA.Before(dateB)
+	})
+
+	doc.drawsTableTitles()
+
+	doc.pdf.SetX(10)
+	doc.pdf.SetY(doc.pdf.GetY() + 8)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	for i := 0; i < len(items); i++ {
+		// Day heading
+		if i == 0 || items[i].Date != items[i-1].Date {
+			doc.appendFolioDayTitle(items[i].Date)
+		}
+
+		item := items[i]
+		if item.Tax == nil {
+			item.Tax = doc.DefaultTax
+		}
+		item.appendColTo(doc.Options, doc)
+
+		doc.pdf.SetX(10)
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+
+		// Day subtotal
+		if i == len(items)-1 || items[i].Date != items[i+1].Date {
+			doc.appendFolioDaySubtotal(items, item.Date)
+			if i < len(items)-1 {
+				doc.appendSeparator(SeparatorGroups, doc.pdf.GetY()-1.5)
+			}
+		}
+
+		if doc.pdf.GetY() > doc.pageBottom() {
+			doc.pdf.AddPage()
+			if doc.pageLimitReached() {
+				return
+			}
+			doc.drawsTableTitles()
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+			doc.pdf.SetY(doc.pdf.GetY() + 8)
+		}
+	}
+}
+
+// appendFolioDayTitle append a day heading row
+func (doc *Document) appendFolioDayTitle(date string) {
+	title := doc.dateDigits(date)
+	if parsed, err := time.Parse(doc.Options.DateFormat, date); err == nil {
+		title = fmt.Sprintf("%s %s", parsed.Weekday().String(), doc.dateDigits(date))
+	}
+	if len(date) == 0 {
+		title = doc.Options.TextFolioUndatedTitle
+	}
+
+	doc.pdf.SetX(10)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 5, doc.encodeString(title), "0", 0, "", false, 0, "")
+	doc.drawRule(10, 200, doc.pdf.GetY()+5)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetY(doc.pdf.GetY() + 7)
+}
+
+// appendFolioDaySubtotal append the sum of item totals of a day
+func (doc *Document) appendFolioDaySubtotal(items []*Item, date string) {
+	subtotal := decimal.Zero
+	for _, item := range items {
+		if item.Date != date {
+			continue
+		}
+		subtotal = subtotal.Add(item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	doc.pdf.SetX(120)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(
+		ItemColTotalHTOffset-120,
+		4,
+		doc.encodeString(doc.Options.TextFolioDaySubtotalTitle),
+		"0",
+		0,
+		"R",
+		false,
+		0,
+		"",
+	)
+	doc.pdf.SetX(ItemColTotalHTOffset)
+	doc.pdf.CellFormat(25, 4, doc.encodeString(subtotal.StringFixed(int32(doc.Options.CurrencyPrecision))), "0", 0, "", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetY(doc.pdf.GetY() + 7)
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// newFolio return a folio layout invoice of a 3 nights stay for 2 guests
+func newFolio() *Document {
+	doc, _ := New(Invoice, &Options{Layout: LayoutFolio})
+	doc.SetRef("F-1")
+	doc.SetCompany(&Contact{Name: "Hotel"})
+	doc.SetCustomer(&Contact{Name: "Guest"})
+	doc.Stay = &Stay{Guest: "Jane Doe", Arrival: "01/06/2024", Departure: "04/06/2024", Guests: 2, CityTaxRate: "1.50"}
+	doc.AppendItem(&Item{Name: "Room", UnitCost: "120", Quantity: "3", Date: "01/06/2024"})
+
+	return doc
+}
+
+func TestStayCityTax(t *testing.T) {
+	doc := newFolio()
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if nights := doc.Stay.Nights(); nights != 3 {
+		t.Errorf("expected 3 nights, got %d", nights)
+	}
+	if len(doc.Items) != 4 {
+		t.Fatalf("expected a city tax item per night, got %d items", len(doc.Items))
+	}
+	if item := doc.Items[3]; item.Name != "City tax" || item.Date != "03/06/2024" || item.Quantity != "2" || item.Total != "3.00" {
+		t.Errorf("unexpected city tax item %s %s %s = %s", item.Name, item.Date, item.Quantity, item.Total)
+	}
+	if total := doc.TotalWithoutTax().String(); total != "369" {
+		t.Errorf("expected 369 total, got %s", total)
+	}
+
+	// Generated again by each build
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Items) != 4 {
+		t.Errorf("expected city tax items once after rebuild, got %d items", len(doc.Items))
+	}
+}
+
+func TestStayJSON(t *testing.T) {
+	doc := newFolio()
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := FromDocumentJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Items) != 1 {
+		t.Fatalf("expected city tax items left out of JSON, got %d items", len(next.Items))
+	}
+	if _, err := next.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Items) != 4 {
+		t.Errorf("expected city tax items generated once, got %d items", len(next.Items))
+	}
+}
+
+func TestStayInvalid(t *testing.T) {
+	doc := newFolio()
+	doc.Stay.Departure = "31/05/2024"
+	if err := doc.Validate(); err != ErrInvalidStay {
+		t.Errorf("expected ErrInvalidStay, got %v", err)
+	}
+
+	doc.Stay.Departure = "2024-06-04"
+	if err := doc.Validate(); err == nil {
+		t.Error("expected departure date error")
+	}
+}
+
+func TestFolioDaySubtotal(t *testing.T) {
+	doc := newFolio()
+	doc.Options.DisableCompression = true
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	// Room computed from unit cost and quantity, with the city tax of the first night
+	if !bytes.Contains(buffer.Bytes(), []byte("(363.00)")) {
+		t.Error("expected 363.00 subtotal of first day")
+	}
+}
@@ -13,9 +13,14 @@ var ErrInvalidDocumentType = errors.New("invalid document type")
 
 // New return a new documents with provided types and defaults
 func New(docType string, options *Options) (*Document, error) {
+	if err := options.applyLocale(); err != nil {
+		return nil, err
+	}
 	_ = defaults.Set(options)
+	options.applyTranslations()
 
-	if docType != Invoice && docType != Quotation && docType != DeliveryNote {
+	if docType != Invoice && docType != Quotation && docType != DeliveryNote && docType != Reminder &&
+		docType != DonationReceipt && docType != SettlementStatement {
 		return nil, ErrInvalidDocumentType
 	}
 
@@ -31,9 +36,10 @@ func New(docType string, options *Options) (*Document, error) {
 	// Prepare accounting
 	doc.ac = accounting.Accounting{
 		Symbol:    doc.Options.CurrencySymbol,
-		Precision: doc.Options.CurrencyPrecision,
+		Precision: doc.Options.totalsPrecision(),
 		Thousand:  doc.Options.CurrencyThousand,
 		Decimal:   doc.Options.CurrencyDecimal,
+		Format:    doc.Options.CurrencyFormat,
 	}
 
 	return doc, nil
@@ -144,3 +144,35 @@ func TestNew(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 }
+
+func TestEncodeStringCache(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+
+	calls := 0
+	doc.SetUnicodeTranslator(func(s string) string {
+		calls++
+		return s
+	})
+
+	for i := 0; i < 3; i++ {
+		doc.encodeString("1")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 translation, got %d", calls)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		doc.encodeString("1")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation for cached strings, got %v", allocs)
+	}
+
+	// Changing translator reset cache
+	doc.SetUnicodeTranslator(func(s string) string {
+		return s + "!"
+	})
+	if encoded := doc.encodeString("1"); encoded != "1!" {
+		t.Errorf("expected 1!, got %s", encoded)
+	}
+}
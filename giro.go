@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidGiro when giro account or OCR reference check digit is invalid
+var ErrInvalidGiro = errors.New("invalid giro account or reference")
+
+// Giro types, see Giro.Type
+const (
+	GiroBankgiro string = "bankgiro" // Swedish Bankgiro inbetalningskort
+	GiroPlusgiro string = "plusgiro" // Swedish PlusGiro inbetalningskort
+	GiroNorway   string = "no"       // Norwegian giro with KID
+)
+
+// Giro layout in millimeters, the OCR line is read on the bottom line of the giro form
+const (
+	giroTop       float64 = 235
+	giroOCRLineY  float64 = 268
+	giroOCRFont   string  = "ocrb"
+	giroOCRSize   float64 = 12 // 12 pt OCR-B / Courier draw the 2.54 mm (1/10 inch) character pitch readers expect
+	giroRightEdge float64 = 200
+)
+
+// Giro define a Nordic giro form, its machine readable OCR line printed at the bottom of the last page
+type Giro struct {
+	Type      string `json:"type" validate:"required,oneof=bankgiro plusgiro no"`
+	Account   string `json:"account" validate:"required"` // Bankgiro, PlusGiro or Norwegian account number, spaces and dashes allowed
+	Reference string `json:"reference,omitempty"`         // OCR reference or KID with check digit, generated from document ref when empty
+	OCRFont   []byte `json:"ocr_font,omitempty"`          // OCR-B TTF font data, Courier when empty
+}
+
+// Prepare normalize account and reference, generating it from ref, and check their check digits
+func (g *Giro) Prepare(ref string) error {
+	g.Account = strings.NewReplacer(" ", "", "-", "", ".", "").Replace(g.Account)
+	if !digits(g.Account) {
+		return ErrInvalidGiro
+	}
+
+	switch g.Type {
+	case GiroBankgiro, GiroPlusgiro:
+		if len(g.Account) < 2 || len(g.Account) > 8 || luhnCheckDigit(g.Account[:len(g.Account)-1]) != int(g.Account[len(g.Account)-1]-'0') {
+			return ErrInvalidGiro
+		}
+	case GiroNorway:
+		if !validNorwegianAccount(g.Account) {
+			return ErrInvalidGiro
+		}
+	}
+
+	if len(g.Reference) == 0 {
+		reference, err := KIDNumber(ref)
+		if err != nil {
+			return err
+		}
+		g.Reference = reference
+		return nil
+	}
+
+	g.Reference = strings.ReplaceAll(g.Reference, " ", "")
+	if len(g.Reference) < 2 || len(g.Reference) > 25 || !digits(g.Reference) ||
+		luhnCheckDigit(g.Reference[:len(g.Reference)-1]) != int(g.Reference[len(g.Reference)-1]-'0') {
+		return ErrInvalidGiro
+	}
+
+	return nil
+}
+
+// validNorwegianAccount check the 11 digits account number and its MOD11 check digit
+func validNorwegianAccount(account string) bool {
+	if len(account) != 11 {
+		return false
+	}
+
+	sum := 0
+	for i, weight := range []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2} {
+		sum += int(account[i]-'0') * weight
+	}
+
+	check := 11 - sum%11
+	if check == 11 {
+		check = 0
+	}
+
+	return check != 10 && check == int(account[10]-'0')
+}
+
+// ocrLine return the OCR line of amount: reference, amount units and cents with their check digit,
+// then account and form type
+func (g *Giro) ocrLine(amount decimal.Decimal) string {
+	amount = amount.Round(2)
+	units := amount.Truncate(0).String()
+	cents := fmt.Sprintf("%02d", amount.Sub(amount.Truncate(0)).Shift(2).IntPart())
+	check := luhnCheckDigit(units + cents)
+
+	switch g.Type {
+	case GiroBankgiro:
+		return fmt.Sprintf("# %s #%8s %s   %d >%14s#41#", g.Reference, units, cents, check, g.Account)
+	case GiroPlusgiro:
+		return fmt.Sprintf("# %s #%8s %s   %d >%14s#14#", g.Reference, units, cents, check, g.Account)
+	}
+
+	return fmt.Sprintf("%s # %8s %s %d > %s<", g.Reference, units, cents, check, g.Account)
+}
+
+// registerGiroFont register the OCR-B font of giro on document pdf
+func (doc *Document) registerGiroFont() {
+	if doc.Giro != nil && doc.Giro.OCRFont != nil {
+		doc.pdf.AddUTF8FontFromBytes(giroOCRFont, "", doc.Giro.OCRFont)
+	}
+}
+
+// appendGiro draw the giro form references and its OCR line at the bottom of the page, on a new page
+// when content reaches it
+func (doc *Document) appendGiro() {
+	giro := doc.Giro
+	if giro == nil {
+		return
+	}
+
+	if doc.pdf.GetY() > giroTop-5 {
+		doc.pdf.AddPage()
+	}
+
+	lineWidth := doc.pdf.GetLineWidth()
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDashPattern([]float64{1, 1}, 0)
+	doc.pdf.Line(BaseMargin, giroTop, BaseMargin+190, giroTop)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+	doc.pdf.SetLineWidth(lineWidth)
+
+	total := doc.documentTotal()
+
+	doc.pdf.SetXY(BaseMargin, giroTop+4)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(190, 6, doc.encodeString(doc.Options.TextGiroTitle), "0", 2, "L", false, 0, "")
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	for _, line := range []string{
+		fmt.Sprintf("%s: %s", doc.Options.TextGiroAccountTitle, giro.Account),
+		fmt.Sprintf("%s: %s", doc.Options.TextGiroReferenceTitle, giro.Reference),
+		fmt.Sprintf("%s: %s", doc.Options.TextGiroAmountTitle, doc.formatMoney(total)),
+	} {
+		doc.pdf.CellFormat(190, 4, doc.encodeString(line), "0", 2, "L", false, 0, "")
+	}
+
+	// OCR line right aligned on the reading zone, nothing else printed on its line
+	font := "Courier"
+	if giro.OCRFont != nil {
+		font = giroOCRFont
+	}
+	doc.pdf.SetFont(font, "", giroOCRSize)
+	line := giro.ocrLine(total)
+	doc.pdf.Text(giroRightEdge-doc.pdf.GetStringWidth(line), giroOCRLineY, line)
+
+	doc.pdf.SetXY(BaseMargin, giroOCRLineY+2)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGiroPrepare(t *testing.T) {
+	giro := &Giro{Type: GiroNorway, Account: "8601.11.17947"}
+	if err := giro.Prepare("INV-2024-001"); err != nil {
+		t.Fatal(err)
+	}
+	if giro.Account != "86011117947" || giro.Reference != "20240016" {
+		t.Errorf("unexpected account %s and reference %s", giro.Account, giro.Reference)
+	}
+	if line := giro.ocrLine(decimal.RequireFromString("1234.5")); line != "20240016 #     1234 50 9 > 86011117947<" {
+		t.Errorf("unexpected OCR line %q", line)
+	}
+
+	for _, invalid := range []*Giro{
+		{Type: GiroNorway, Account: "86011117948"},
+		{Type: GiroBankgiro, Account: "5555-5556"},
+		{Type: GiroBankgiro, Account: "5555-5551", Reference: "20240017"},
+	} {
+		if err := invalid.Prepare("INV-2024-001"); err != ErrInvalidGiro {
+			t.Errorf("expected %v, got %v", ErrInvalidGiro, err)
+		}
+	}
+}
+
+func TestAppendGiro(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-2024-001")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "1234.5", Quantity: "1"})
+	doc.Giro = &Giro{Type: GiroBankgiro, Account: "5555-5551", Reference: "20240016"}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(# 20240016 #    1234 50   9 >      55555551#41#)")) {
+		t.Error("expected bankgiro OCR line in output")
+	}
+}
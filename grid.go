@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"math"
+
+	"github.com/creasty/defaults"
+)
+
+// Grid define a layout grid of columns and rows over the page content area, inside margins, to position
+// annotations and hooks drawings in logical units which follow margins changes, see Options.Grid and Page.Grid
+type Grid struct {
+	Columns  int     `default:"12" json:"columns,omitempty" validate:"min=0,max=48"`
+	Rows     int     `default:"24" json:"rows,omitempty" validate:"min=0,max=96"`
+	Gutter   float64 `default:"4" json:"gutter,omitempty" validate:"min=0,max=20"` // Space between columns in millimeters
+	Baseline float64 `json:"baseline,omitempty" validate:"min=0,max=20"`           // Rows Y are snapped to this step in millimeters, none when 0
+
+	left   float64
+	top    float64
+	width  float64
+	height float64
+}
+
+// grid return Options.Grid with defaults over the content area of current page
+func (doc *Document) grid() *Grid {
+	grid := &Grid{}
+	if doc.Options.Grid != nil {
+		copied := *doc.Options.Grid
+		grid = &copied
+	}
+	_ = defaults.Set(grid)
+
+	width, _ := doc.pdf.GetPageSize()
+	grid.left = BaseMargin
+	grid.top = BaseMarginTop
+	grid.width = width - 2*BaseMargin
+	grid.height = doc.pageBottom() - BaseMarginTop
+
+	return grid
+}
+
+// columnWidth return the width of a single column
+func (g *Grid) columnWidth() float64 {
+	return (g.width - float64(g.Columns-1)*g.Gutter) / float64(g.Columns)
+}
+
+// X return the left edge of zero-based column, fractional columns are allowed
+func (g *Grid) X(column float64) float64 {
+	return g.left + column*(g.columnWidth()+g.Gutter)
+}
+
+// Y return the top of zero-based row, snapped to the baseline
+func (g *Grid) Y(row float64) float64 {
+	return g.Snap(g.top + row*g.height/float64(g.Rows))
+}
+
+// SpanWidth return the width of columns columns and their inner gutters
+func (g *Grid) SpanWidth(columns float64) float64 {
+	return columns*g.columnWidth() + math.Max(columns-1, 0)*g.Gutter
+}
+
+// SpanHeight return the height of rows rows
+func (g *Grid) SpanHeight(rows float64) float64 {
+	return rows * g.height / float64(g.Rows)
+}
+
+// Cell return position and size in millimeters of the area starting at column, row spanning columns and rows
+func (g *Grid) Cell(column float64, row float64, columns float64, rows float64) (float64, float64, float64, float64) {
+	return g.X(column), g.Y(row), g.SpanWidth(columns), g.SpanHeight(rows)
+}
+
+// Snap return y snapped to the nearest baseline from the grid top, y when there is no baseline
+func (g *Grid) Snap(y float64) float64 {
+	if g.Baseline <= 0 {
+		return y
+	}
+
+	return g.top + math.Round((y-g.top)/g.Baseline)*g.Baseline
+}
+
+// Grid return the layout grid of the page, see Options.Grid
+func (p *Page) Grid() *Grid {
+	return p.doc.grid()
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestGrid(t *testing.T) {
+	doc := newQuote()
+	doc.Options.Grid = &Grid{Columns: 10, Gutter: 2, Baseline: 5}
+	doc.AppendAnnotation(&Annotation{Page: 1, X: 5, Y: 20, Text: "On the grid", Grid: true})
+
+	var grid *Grid
+	doc.Options.AnnotatePage = func(page *Page) error {
+		grid = page.Grid()
+		return nil
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 190 mm content width: 10 columns of 17.2 mm and 9 gutters of 2 mm
+	if x := grid.X(0); x != BaseMargin {
+		t.Errorf("expected first column at margin, got %v", x)
+	}
+	if x := grid.X(2); math.Abs(x-48.4) > 0.01 {
+		t.Errorf("expected third column at 48.4, got %v", x)
+	}
+	if width := grid.SpanWidth(10); math.Abs(width-190) > 0.01 {
+		t.Errorf("expected full span of 190, got %v", width)
+	}
+	if x, _, width, _ := grid.Cell(5, 0, 5, 1); math.Abs(x+width-200) > 0.01 {
+		t.Errorf("expected right half to end at 200, got %v", x+width)
+	}
+
+	// Rows are snapped to the 5 mm baseline from the top margin
+	if y := grid.Snap(BaseMarginTop + 12.4); y != BaseMarginTop+10 {
+		t.Errorf("expected snapped Y %v, got %v", BaseMarginTop+10, y)
+	}
+	if y := grid.Y(3); math.Mod(y-BaseMarginTop, 5) != 0 {
+		t.Errorf("expected row on baseline, got %v", y)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(On the grid)")) {
+		t.Error("expected grid annotation in PDF")
+	}
+}
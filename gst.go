@@ -0,0 +1,241 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidABN when company ABN is missing or invalid
+var ErrInvalidABN = errors.New("invalid abn")
+
+// ErrInvalidNZGSTNumber when company GST number is missing or invalid
+var ErrInvalidNZGSTNumber = errors.New("invalid nz gst number")
+
+// ErrInvalidNZBN when a NZBN is invalid
+var ErrInvalidNZBN = errors.New("invalid nzbn")
+
+// ErrMissingGSTRecipient when recipient informations are required above the supply threshold
+var ErrMissingGSTRecipient = errors.New("missing gst recipient informations")
+
+// GST countries
+const (
+	GSTCountryAU string = "AU"
+	GSTCountryNZ string = "NZ"
+)
+
+// GST define Australian and New Zealand tax invoice requirements.
+// Company TaxID holds the ABN (AU) or the GST number (NZ).
+type GST struct {
+	Country          string `json:"country,omitempty" validate:"required,oneof=AU NZ"`
+	PricesIncludeGST bool   `json:"prices_include_gst,omitempty"` // Print "total price includes GST" instead of the GST amount
+
+	// Amounts GST included
+	FullInformationThreshold string `json:"full_information_threshold,omitempty"` // Defaults to 1000
+	TaxInvoiceThreshold      string `json:"tax_invoice_threshold,omitempty"`      // Defaults to 82.50 AU, 200 NZ
+
+	_total decimal.Decimal
+}
+
+// Prepare check registration numbers and thresholds rules of document
+func (g *GST) Prepare(doc *Document) error {
+	if len(g.FullInformationThreshold) == 0 {
+		g.FullInformationThreshold = "1000"
+	}
+
+	if len(g.TaxInvoiceThreshold) == 0 {
+		g.TaxInvoiceThreshold = "82.50"
+		if g.Country == GSTCountryNZ {
+			g.TaxInvoiceThreshold = "200"
+		}
+	}
+
+	taxID := strings.ReplaceAll(doc.Company.TaxID, " ", "")
+	if g.Country == GSTCountryAU && !validABN(taxID) {
+		return ErrInvalidABN
+	}
+	if g.Country == GSTCountryNZ && !validNZGSTNumber(strings.ReplaceAll(taxID, "-", "")) {
+		return ErrInvalidNZGSTNumber
+	}
+
+	for _, contact := range []*Contact{doc.Company, doc.Customer} {
+		if contact != nil && len(contact.NZBN) > 0 && !validNZBN(contact.NZBN) {
+			return ErrInvalidNZBN
+		}
+	}
+
+	total, err := doc.totalAmount()
+	if err != nil {
+		return err
+	}
+	g._total = total
+
+	// Above threshold the recipient identity is required
+	if g.FullInformationRequired() {
+		if doc.Customer == nil {
+			return ErrMissingGSTRecipient
+		}
+
+		// NZ recipient name must come with a contact detail
+		if g.Country == GSTCountryNZ && doc.Customer.Address == nil && len(doc.Customer.TaxID) == 0 && len(doc.Customer.NZBN) == 0 {
+			return ErrMissingGSTRecipient
+		}
+	}
+
+	return nil
+}
+
+// TaxInvoiceRequired return true when document total (GST included) exceeds the low value threshold.
+// Below it the document is titled as a plain invoice, without GST included statement.
+func (g *GST) TaxInvoiceRequired() bool {
+	threshold, err := decimal.NewFromString(g.TaxInvoiceThreshold)
+	if err != nil {
+		return true
+	}
+
+	return g._total.GreaterThan(threshold)
+}
+
+// FullInformationRequired return true when recipient informations must be displayed
+func (g *GST) FullInformationRequired() bool {
+	threshold, err := decimal.NewFromString(g.FullInformationThreshold)
+	if err != nil {
+		return true
+	}
+
+	if g.Country == GSTCountryNZ {
+		return g._total.GreaterThan(threshold)
+	}
+
+	return g._total.GreaterThanOrEqual(threshold)
+}
+
+// validABN check ABN format and checksum
+func validABN(abn string) bool {
+	if len(abn) != 11 {
+		return false
+	}
+
+	weights := []int{10, 1, 3, 5, 7, 9, 11, 13, 15, 17, 19}
+	sum := 0
+	for i, r := range abn {
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		digit := int(r - '0')
+		if i == 0 {
+			digit--
+		}
+		sum += digit * weights[i]
+	}
+
+	return sum%89 == 0
+}
+
+// validNZGSTNumber check NZ GST (IRD) number format and check digit
+func validNZGSTNumber(number string) bool {
+	if len(number) == 8 {
+		number = "0" + number
+	}
+	if len(number) != 9 {
+		return false
+	}
+
+	digits := make([]int, 9)
+	for i, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+		digits[i] = int(r - '0')
+	}
+
+	checkDigit := func(weights []int) int {
+		sum := 0
+		for i, weight := range weights {
+			sum += digits[i] * weight
+		}
+
+		remainder := sum % 11
+		if remainder == 0 {
+			return 0
+		}
+		return 11 - remainder
+	}
+
+	check := checkDigit([]int{3, 2, 7, 6, 5, 4, 3, 2})
+	if check == 10 {
+		check = checkDigit([]int{7, 4, 3, 2, 5, 2, 7, 6})
+	}
+
+	return check != 10 && check == digits[8]
+}
+
+// validNZBN check NZBN (GS1 GLN, 13 digits) format and check digit
+func validNZBN(nzbn string) bool {
+	if len(nzbn) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i, r := range nzbn {
+		if r < '0' || r > '9' {
+			return false
+		}
+
+		if i == 12 {
+			break
+		}
+
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+
+	return (10-sum%10)%10 == int(nzbn[12]-'0')
+}
+
+// gstRegistrationTitle return the company registration number label
+func (g *GST) gstRegistrationTitle(options *Options) string {
+	if g.Country == GSTCountryNZ {
+		return options.TextGSTNumberTitle
+	}
+
+	return options.TextABNTitle
+}
+
+// registrationLines return ABN / GST number and NZBN lines of contact.
+// Recipient numbers are only displayed above the full information threshold.
+func (g *GST) registrationLines(c *Contact, company bool, options *Options) []string {
+	lines := []string{}
+	if !company && !g.FullInformationRequired() {
+		return lines
+	}
+
+	if len(c.TaxID) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", g.gstRegistrationTitle(options), c.TaxID))
+	}
+	if len(c.NZBN) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", options.TextNZBNTitle, c.NZBN))
+	}
+
+	return lines
+}
+
+// appendGSTMention append the GST included statement under totals
+func (doc *Document) appendGSTMention() {
+	if doc.GST == nil || !doc.GST.PricesIncludeGST || !doc.GST.TaxInvoiceRequired() {
+		return
+	}
+
+	// Current Y is the top of the total line
+	y := doc.pdf.GetY()
+	doc.pdf.SetXY(120, y+10.5)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(80, 4, doc.encodeString(doc.Options.TextGSTIncludedMention), "0", 0, "R", false, 0, "")
+	doc.pdf.SetY(y + 4)
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGSTNumbers(t *testing.T) {
+	if !validABN("51824753556") {
+		t.Error("expected ABN 51824753556 to be valid")
+	}
+	if validABN("51824753557") {
+		t.Error("expected ABN 51824753557 to be invalid")
+	}
+
+	if !validNZGSTNumber("49091850") || !validNZGSTNumber("136410132") {
+		t.Error("expected NZ GST numbers to be valid")
+	}
+	if validNZGSTNumber("136410133") {
+		t.Error("expected NZ GST number 136410133 to be invalid")
+	}
+
+	if !validNZBN("9429041905555") {
+		t.Error("expected NZBN 9429041905555 to be valid")
+	}
+}
+
+// newGSTInvoice return an Australian invoice of total GST included
+func newGSTInvoice(unitCost string) *Document {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", TaxID: "51 824 753 556"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Coffee beans", UnitCost: unitCost, Quantity: "1", Tax: &Tax{Percent: "10"}})
+	doc.GST = &GST{Country: GSTCountryAU, PricesIncludeGST: true}
+
+	return doc
+}
+
+func TestGSTPrepare(t *testing.T) {
+	doc := newGSTInvoice("100")
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.GST.TaxInvoiceThreshold != "82.50" || doc.GST.FullInformationThreshold != "1000" {
+		t.Errorf("unexpected thresholds %s %s", doc.GST.TaxInvoiceThreshold, doc.GST.FullInformationThreshold)
+	}
+	if !doc.GST.TaxInvoiceRequired() || doc.GST.FullInformationRequired() {
+		t.Error("expected tax invoice without recipient informations for 110 total")
+	}
+
+	doc = newGSTInvoice("50")
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.GST.TaxInvoiceRequired() {
+		t.Error("expected no tax invoice for 55 total")
+	}
+
+	doc = newGSTInvoice("100")
+	doc.Company.TaxID = "51824753557"
+	if err := doc.Validate(); err != ErrInvalidABN {
+		t.Errorf("expected ErrInvalidABN, got %v", err)
+	}
+
+	// NZ recipient needs a contact detail above 1000
+	doc = newGSTInvoice("1000")
+	doc.Company.TaxID = "49-091-850"
+	doc.GST.Country = GSTCountryNZ
+	if err := doc.Validate(); err != ErrMissingGSTRecipient {
+		t.Errorf("expected ErrMissingGSTRecipient, got %v", err)
+	}
+	doc.Customer.NZBN = "9429041905555"
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+	if doc.GST.TaxInvoiceThreshold != "200" || !doc.GST.FullInformationRequired() {
+		t.Errorf("expected NZ thresholds, got %s", doc.GST.TaxInvoiceThreshold)
+	}
+}
+
+func TestGSTBuild(t *testing.T) {
+	output := func(doc *Document) []byte {
+		pdf, err := doc.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		buffer := &bytes.Buffer{}
+		if err := pdf.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	// Over threshold
+	taxInvoice := output(newGSTInvoice("100"))
+	if !bytes.Contains(taxInvoice, []byte("(TAX INVOICE)")) || !bytes.Contains(taxInvoice, []byte("(Total price includes GST)")) {
+		t.Error("expected tax invoice title and GST included statement")
+	}
+	if !bytes.Contains(taxInvoice, []byte("(ABN: 51 824 753 556)")) {
+		t.Error("expected company ABN")
+	}
+
+	// Under threshold
+	invoice := output(newGSTInvoice("50"))
+	if bytes.Contains(invoice, []byte("(TAX INVOICE)")) || !bytes.Contains(invoice, []byte("(INVOICE)")) {
+		t.Error("expected invoice title under tax invoice threshold")
+	}
+	if bytes.Contains(invoice, []byte("(Total price includes GST)")) {
+		t.Error("expected no GST included statement under tax invoice threshold")
+	}
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Presentation fields ignored by Document.Hash
+var hashIgnoredKeys = map[string]bool{
+	"options":     true,
+	"header":      true,
+	"footer":      true,
+	"logo":        true,
+	"logo_url":    true,
+	"annotations": true,
+}
+
+// Amount fields normalized by Document.Hash, 10.50 and 10.5 are the same amount
+var hashAmountKeys = map[string]bool{
+	"unit_cost": true,
+	"quantity":  true,
+	"total":     true,
+	"percent":   true,
+	"amount":    true,
+}
+
+// canonicalValue remove presentation fields and normalize amounts of a decoded JSON value
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if hashIgnoredKeys[key] {
+				delete(v, key)
+				continue
+			}
+
+			if s, ok := child.(string); ok && hashAmountKeys[key] {
+				if amount, err := decimal.NewFromString(s); err == nil {
+					v[key] = amount.String()
+					continue
+				}
+			}
+
+			v[key] = canonicalValue(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = canonicalValue(child)
+		}
+	}
+
+	return value
+}
+
+// Hash return a SHA-256 hash of document semantic content: presentation (options, header,
+// footer, logos) is ignored and amounts are normalized, so the hash only changes with content.
+// Values generated on validation (Turkish ETTN, fiscal hashes...) are part of the content.
+func (doc *Document) Hash() string {
+	source, _ := json.Marshal(doc)
+
+	var value interface{}
+	_ = json.Unmarshal(source, &value)
+
+	// Maps keys are sorted when encoded
+	canonical, _ := json.Marshal(canonicalValue(value))
+
+	return fmt.Sprintf("%x", sha256.Sum256(canonical))
+}
@@ -0,0 +1,31 @@
+package generator
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	newDoc := func(options *Options, unitCost string) *Document {
+		doc, _ := New(Invoice, options)
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: "Item", UnitCost: unitCost, Quantity: "2", Tax: &Tax{Percent: "20.0"}})
+		return doc
+	}
+
+	hash := newDoc(&Options{}, "10.50").Hash()
+	if len(hash) != 64 {
+		t.Errorf("expected SHA-256 hex hash, got %s", hash)
+	}
+
+	// Presentation and amounts formatting don't change the hash
+	styled := newDoc(&Options{TextTypeInvoice: "FACTURE", BaseTextColor: []int{1, 2, 3}}, "10.5")
+	styled.Company.Logo = []byte{1, 2, 3}
+	styled.SetFooter(&HeaderFooter{Text: "Footer"})
+	if styled.Hash() != hash {
+		t.Errorf("expected same hash for presentation changes")
+	}
+
+	if newDoc(&Options{}, "10.51").Hash() == hash {
+		t.Errorf("expected different hash for content changes")
+	}
+}
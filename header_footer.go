@@ -13,12 +13,15 @@ type HeaderFooter struct {
 	Text          string  `json:"text,omitempty"`
 	FontSize      float64 `json:"font_size,omitempty" default:"7"`
 	Pagination    bool    `json:"pagination,omitempty"`
+
+	fn fnc
 }
 
 type fnc func()
 
 // ApplyFunc allow user to apply custom func
 func (hf *HeaderFooter) ApplyFunc(pdf *fpdf.Fpdf, fn fnc) {
+	hf.fn = fn
 	pdf.SetHeaderFunc(fn)
 }
 
@@ -28,8 +31,13 @@ func (hf *HeaderFooter) applyHeader(doc *Document) error {
 		return err
 	}
 
+	// Custom funcs are set as pdf header by ApplyFunc
+	if hf.UseCustomFunc && hf.fn != nil {
+		doc.headerFunc = hf.fn
+	}
+
 	if !hf.UseCustomFunc {
-		doc.pdf.SetHeaderFunc(func() {
+		doc.setHeaderFunc(func() {
 			currentY := doc.pdf.GetY()
 			currentX := doc.pdf.GetX()
 
@@ -43,7 +51,7 @@ func (hf *HeaderFooter) applyHeader(doc *Document) error {
 			doc.pdf.SetFont(doc.Options.Font, "", hf.FontSize)
 			_, lineHt := doc.pdf.GetFontSize()
 			html := doc.pdf.HTMLBasicNew()
-			html.Write(lineHt, doc.encodeString(hf.Text))
+			html.Write(lineHt, doc.encode(hf.Text))
 
 			// Apply pagination
 			if !hf.Pagination {
@@ -78,19 +86,32 @@ func (hf *HeaderFooter) applyFooter(doc *Document) error {
 		return err
 	}
 
+	// Custom funcs are set as pdf header by ApplyFunc
+	if hf.UseCustomFunc && hf.fn != nil {
+		doc.headerFunc = hf.fn
+	}
+
 	if !hf.UseCustomFunc {
-		doc.pdf.SetFooterFunc(func() {
+		doc.setFooterFunc(func() {
 			currentY := doc.pdf.GetY()
 			currentX := doc.pdf.GetX()
 
 			doc.pdf.SetTopMargin(HeaderMarginTop)
+
+			// Separator above footer text and pagination
+			separatorY := 287 - HeaderMarginTop - 1
+			if hf.Pagination {
+				separatorY -= 8
+			}
+			doc.appendSeparator(SeparatorFooter, separatorY)
+
 			doc.pdf.SetY(287 - HeaderMarginTop)
 
 			// Parse Text as html (simple)
 			doc.pdf.SetFont(doc.Options.Font, "", hf.FontSize)
 			_, lineHt := doc.pdf.GetFontSize()
 			html := doc.pdf.HTMLBasicNew()
-			html.Write(lineHt, doc.encodeString(hf.Text))
+			html.Write(lineHt, doc.encode(hf.Text))
 
 			// Apply pagination
 			if hf.Pagination {
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/creasty/defaults"
+	"github.com/go-pdf/fpdf"
+)
+
+// Embedded icons names
+const (
+	IconPhone string = "phone"
+	IconMail  string = "mail"
+	IconGlobe string = "globe"
+	IconBank  string = "bank"
+)
+
+// iconPaths embedded icon set, outlined SVG paths on a 24 x 24 grid
+var iconPaths = map[string]string{
+	IconPhone: "M5 3 L9 3 L11 8 L8.5 9.5 C9.5 11.5 12.5 14.5 14.5 15.5 L16 13 L21 15 L21 19 " +
+		"C21 20 20 21 19 21 C11 21 3 13 3 5 C3 4 4 3 5 3 Z",
+	IconMail: "M3 5 L21 5 L21 19 L3 19 Z M3 5 L12 13 L21 5",
+	IconGlobe: "M3 12 C3 7 7 3 12 3 C17 3 21 7 21 12 C21 17 17 21 12 21 C7 21 3 17 3 12 Z M3 12 L21 12 " +
+		"M12 3 C8 7 8 17 12 21 M12 3 C16 7 16 17 12 21",
+	IconBank: "M3 9 L12 4 L21 9 Z M5 9 L5 17 M9.5 9 L9.5 17 M14.5 9 L14.5 17 M19 9 L19 17 M3 20 L21 20",
+}
+
+// RegisterIcon add or replace an icon of the set, path being an outlined SVG path on a 24 x 24 grid
+// using M, L, C, Q, H, V and Z absolute commands
+func RegisterIcon(name string, path string) {
+	iconPaths[name] = path
+}
+
+// Icons define the size and tint of icons drawn next to contact and payment labels
+type Icons struct {
+	Size  float64 `default:"2.5" json:"size,omitempty" validate:"min=0,max=10"` // Width and height in millimeters
+	Color []int   `default:"[82,82,82]" json:"color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+}
+
+// icons return Options.Icons with defaults, nil when icons are disabled
+func (doc *Document) icons() *Icons {
+	if doc.Options.Icons == nil {
+		return nil
+	}
+
+	icons := *doc.Options.Icons
+	_ = defaults.Set(&icons)
+
+	return &icons
+}
+
+// drawIcon draw icon name with its top left corner at x, y, return the width taken by the icon and its
+// spacing, 0 when icons are disabled (nil) or name is unknown
+func (doc *Document) drawIcon(icons *Icons, name string, x float64, y float64) float64 {
+	path, ok := iconPaths[name]
+	if icons == nil || !ok {
+		return 0
+	}
+
+	svg, err := fpdf.SVGBasicParse([]byte(fmt.Sprintf(`<svg width="24" height="24"><path d="%s"/></svg>`, path)))
+	if err != nil {
+		return 0
+	}
+
+	currentX, currentY := doc.pdf.GetXY()
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+
+	scale := icons.Size / svg.Wd
+	doc.pdf.SetLineWidth(2 * scale)
+	doc.pdf.SetDrawColor(icons.Color[0], icons.Color[1], icons.Color[2])
+	doc.pdf.SetXY(x, y)
+	doc.pdf.SVGBasicWrite(&svg, scale)
+
+	doc.pdf.SetLineWidth(lineWidth)
+	doc.pdf.SetDrawColor(r, g, b)
+	doc.pdf.SetXY(currentX, currentY)
+
+	return icons.Size + 1
+}
+
+// Icon draw icon name of the embedded set with its top left corner at x, y in millimeters, with
+// Options.Icons size and tint, defaults when nil, return the width taken by the icon
+func (p *Page) Icon(name string, x float64, y float64) float64 {
+	icons := p.doc.icons()
+	if icons == nil {
+		icons = &Icons{}
+		_ = defaults.Set(icons)
+	}
+
+	return p.doc.drawIcon(icons, name, x, y)
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIcons(t *testing.T) {
+	doc := newQuote()
+	doc.Company.Phone = "+33 1 23 45 67 89"
+	doc.Company.Email = "billing@seller.test"
+	doc.Company.Website = "seller.test"
+	doc.BankDetails = "IBAN FR76 3000 6000 0112 3456 7890 189"
+	doc.Options.Icons = &Icons{Color: []int{200, 0, 0}}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"0.784 0.000 0.000 RG", "(billing@seller.test)", "(seller.test)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	if width := doc.drawIcon(doc.icons(), "unknown", 0, 0); width != 0 {
+		t.Errorf("expected unknown icon to be skipped, got %v", width)
+	}
+
+	// Phone and email are personal data
+	doc.Options.DataMinimization = DataMinimizationPseudonymize
+	doc.MinimizePersonalData()
+	if !strings.HasPrefix(doc.Company.Phone, "phone-") || !strings.HasPrefix(doc.Company.Email, "email-") {
+		t.Errorf("expected pseudonymized phone and email, got %s %s", doc.Company.Phone, doc.Company.Email)
+	}
+}
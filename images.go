@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"sync"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Smallest dimension in pixels images are downsampled to when targeting Options.MaxOutputSize
+const minImageSize = 32
+
+// ImageCache cache downsampled images across documents sharing the same assets,
+// safe for concurrent use. Set the same cache in options of each document.
+type ImageCache struct {
+	mutex  sync.Mutex
+	images map[string][]byte
+}
+
+// NewImageCache return an empty image cache
+func NewImageCache() *ImageCache {
+	return &ImageCache{images: map[string][]byte{}}
+}
+
+// Len return the number of cached images
+func (c *ImageCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.images)
+}
+
+// downsample return image data downsampled with downsampleImage, from cache when already done
+func (c *ImageCache) downsample(data []byte, maxSize int, budget int, quality int) ([]byte, error) {
+	if c == nil {
+		return downsampleImage(data, maxSize, budget, quality)
+	}
+
+	key := fmt.Sprintf("%x-%d-%d-%d", sha256.Sum256(data), maxSize, budget, quality)
+
+	c.mutex.Lock()
+	cached, ok := c.images[key]
+	c.mutex.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	downsampled, err := downsampleImage(data, maxSize, budget, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.images[key] = downsampled
+	c.mutex.Unlock()
+
+	return downsampled, nil
+}
+
+// images return images embedded by Build
+func (doc *Document) images() [][]byte {
+	images := [][]byte{}
+	for _, contact := range []*Contact{doc.Company, doc.Customer} {
+		if contact != nil && contact.Logo != nil {
+			images = append(images, contact.Logo)
+		}
+	}
+	if doc.TurkishFiscal != nil && doc.TurkishFiscal.Logo != nil {
+		images = append(images, doc.TurkishFiscal.Logo)
+	}
+	for _, annotation := range doc.Annotations {
+		if annotation.Image != nil {
+			images = append(images, annotation.Image)
+		}
+	}
+
+	return images
+}
+
+// registerImage register image data in pdf, downsampled following Options.ImageMaxSize
+// and Options.MaxOutputSize, and return its info and format
+func (doc *Document) registerImage(name string, data []byte) (*fpdf.ImageInfoType, string) {
+	budget := 0
+	if doc.Options.MaxOutputSize > 0 {
+		// Images share the output size with the rest of the document
+		budget = doc.Options.MaxOutputSize / (len(doc.images()) + 1)
+	}
+
+	if doc.Options.ImageMaxSize > 0 || budget > 0 {
+		if downsampled, err := doc.Options.ImageCache.downsample(data, doc.Options.ImageMaxSize, budget, doc.Options.ImageQuality); err == nil {
+			data = downsampled
+		}
+	}
+
+	_, format, _ := image.DecodeConfig(bytes.NewReader(data))
+	imageInfo := doc.pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{
+		ImageType: format,
+	}, bytes.NewReader(data))
+
+	return imageInfo, format
+}
+
+// downsampleImage return image data scaled down to maxSize pixels (width or height) and
+// re-encoded until it fits budget bytes. Opaque images are encoded as JPEG with quality,
+// others as PNG. Original data is returned when it is already small enough.
+func downsampleImage(data []byte, maxSize int, budget int, quality int) ([]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	size := config.Width
+	if config.Height > size {
+		size = config.Height
+	}
+
+	target := size
+	if maxSize > 0 && target > maxSize {
+		target = maxSize
+	}
+	if target == size && (budget <= 0 || len(data) <= budget) {
+		return data, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	opaque := format == "jpeg"
+	if o, ok := src.(interface{ Opaque() bool }); ok && o.Opaque() {
+		opaque = true
+	}
+
+	for {
+		scaled := scaleImage(src, config.Width*target/size, config.Height*target/size)
+
+		buffer := &bytes.Buffer{}
+		if opaque {
+			err = jpeg.Encode(buffer, scaled, &jpeg.Options{Quality: quality})
+		} else {
+			err = png.Encode(buffer, scaled)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if budget <= 0 || buffer.Len() <= budget || target <= minImageSize {
+			if target == size && buffer.Len() >= len(data) {
+				return data, nil
+			}
+			return buffer.Bytes(), nil
+		}
+
+		// Lower resolution and quality until image fits budget
+		target = target * 3 / 4
+		if target < minImageSize {
+			target = minImageSize
+		}
+		if quality > 50 {
+			quality -= 10
+		}
+	}
+}
+
+// scaleImage scale src to width x height pixels, averaging source pixels of each destination pixel
+func scaleImage(src image.Image, width int, height int) *image.RGBA {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					sr, sg, sb, sa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(sr), g+uint64(sg), b+uint64(sb), a+uint64(sa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
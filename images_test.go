@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testPhoto return a noisy JPEG photo of width x height pixels
+func testPhoto(width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 7 % 256), uint8((x*y + y) % 256), uint8(y * 13 % 256), 255})
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	_ = jpeg.Encode(buffer, img, &jpeg.Options{Quality: 95})
+	return buffer.Bytes()
+}
+
+func TestDownsampleImage(t *testing.T) {
+	photo := testPhoto(1200, 900)
+
+	downsampled, err := downsampleImage(photo, 300, 0, 85)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, format, _ := image.DecodeConfig(bytes.NewReader(downsampled))
+	if config.Width != 300 || config.Height != 225 || format != "jpeg" {
+		t.Errorf("expected 300x225 jpeg, got %dx%d %s", config.Width, config.Height, format)
+	}
+
+	// Small images are kept
+	if kept, _ := downsampleImage(downsampled, 300, 0, 85); !bytes.Equal(kept, downsampled) {
+		t.Errorf("expected image under max size to be kept")
+	}
+
+	fitted, err := downsampleImage(photo, 0, 20000, 85)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fitted) > 20000 {
+		t.Errorf("expected image under 20000 bytes, got %d", len(fitted))
+	}
+}
+
+func TestCompressionOptions(t *testing.T) {
+	output := func(options *Options) []byte {
+		doc, _ := New(Invoice, options)
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company", Logo: testPhoto(1200, 900)})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+		pdf, err := doc.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		buffer := &bytes.Buffer{}
+		if err := pdf.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	original := output(&Options{})
+	if downsampled := output(&Options{ImageMaxSize: 200}); len(downsampled) >= len(original) {
+		t.Errorf("expected downsampled output smaller than %d, got %d", len(original), len(downsampled))
+	}
+	if limited := output(&Options{MaxOutputSize: 60000}); len(limited) > 60000 {
+		t.Errorf("expected output under 60000 bytes, got %d", len(limited))
+	}
+
+	if uncompressed := output(&Options{DisableCompression: true, ImageMaxSize: 200}); bytes.Contains(uncompressed, []byte("/Filter /FlateDecode")) {
+		t.Errorf("expected uncompressed streams")
+	}
+}
+
+func TestImageCache(t *testing.T) {
+	cache := NewImageCache()
+	logo := testPhoto(800, 600)
+
+	for i := 0; i < 2; i++ {
+		doc, _ := New(Invoice, &Options{ImageMaxSize: 200, ImageCache: cache})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company", Logo: logo})
+		doc.SetCustomer(&Contact{Name: "Customer", Logo: logo})
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+		if _, err := doc.Build(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if cache.Len() != 1 {
+		t.Errorf("expected 1 cached image, got %d", cache.Len())
+	}
+}
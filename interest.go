@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidLateInterest when late interest data are invalid
+var ErrInvalidLateInterest = errors.New("invalid late interest")
+
+// Late interest jurisdictions
+const (
+	LateInterestJurisdictionEU string = "EU"
+	LateInterestJurisdictionDE string = "DE"
+	LateInterestJurisdictionFR string = "FR"
+	LateInterestJurisdictionUK string = "UK"
+)
+
+// lateInterestRule define statutory margin (in points over reference rate) and recovery fee
+type lateInterestRule struct {
+	margin         decimal.Decimal
+	consumerMargin decimal.Decimal
+}
+
+// lateInterestRules per jurisdiction, any other EU member state use the 2011/7/EU directive minimum
+var lateInterestRules = map[string]lateInterestRule{
+	LateInterestJurisdictionEU: {margin: decimal.NewFromInt(8), consumerMargin: decimal.NewFromInt(8)},
+	LateInterestJurisdictionDE: {margin: decimal.NewFromInt(9), consumerMargin: decimal.NewFromInt(5)},
+	LateInterestJurisdictionFR: {margin: decimal.NewFromInt(10), consumerMargin: decimal.NewFromInt(10)},
+	LateInterestJurisdictionUK: {margin: decimal.NewFromInt(8), consumerMargin: decimal.NewFromInt(8)},
+}
+
+// daysInInterestYear used for simple interest computation
+var daysInInterestYear = decimal.NewFromInt(365)
+
+// LateInterest define statutory late payment interest computation of an overdue document
+type LateInterest struct {
+	Jurisdiction  string `json:"jurisdiction,omitempty" validate:"required,oneof=EU DE FR UK"`
+	ReferenceRate string `json:"reference_rate,omitempty" validate:"required"` // Central bank reference rate in percent ex 4.5
+	Margin        string `json:"margin,omitempty"`                             // Override statutory margin in points ex 9
+	Consumer      bool   `json:"consumer,omitempty"`                           // Consumer debtor, no recovery fee
+	DueDate       string `json:"due_date,omitempty" validate:"required"`
+	UntilDate     string `json:"until_date,omitempty"` // Defaults to today
+	Principal     string `json:"principal,omitempty"`  // Defaults to document total
+
+	_rate      decimal.Decimal
+	_margin    decimal.Decimal
+	_principal decimal.Decimal
+	_days      int64
+}
+
+// Prepare compute rate, overdue days and principal
+func (l *LateInterest) Prepare(dateLayout string, total decimal.Decimal) error {
+	rule := lateInterestRules[l.Jurisdiction]
+
+	rate, err := decimal.NewFromString(l.ReferenceRate)
+	if err != nil {
+		return err
+	}
+	l._rate = rate
+
+	l._margin = rule.margin
+	if l.Consumer {
+		l._margin = rule.consumerMargin
+	}
+	if len(l.Margin) > 0 {
+		margin, err := decimal.NewFromString(l.Margin)
+		if err != nil {
+			return err
+		}
+		l._margin = margin
+	}
+
+	due, err := time.Parse(dateLayout, l.DueDate)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now()
+	if len(l.UntilDate) > 0 {
+		until, err = time.Parse(dateLayout, l.UntilDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	l._days = int64(until.Sub(due).Hours() / 24)
+	if l._days < 0 {
+		return ErrInvalidLateInterest
+	}
+
+	l._principal = total
+	if len(l.Principal) > 0 {
+		principal, err := decimal.NewFromString(l.Principal)
+		if err != nil {
+			return err
+		}
+		l._principal = principal
+	}
+
+	return nil
+}
+
+// Days return the number of overdue days
+func (l *LateInterest) Days() int64 {
+	return l._days
+}
+
+// Rate return the applied yearly interest rate in percent
+func (l *LateInterest) Rate() decimal.Decimal {
+	return l._rate.Add(l._margin)
+}
+
+// Interest return the simple interest due for the overdue period
+func (l *LateInterest) Interest() decimal.Decimal {
+	return l._principal.
+		Mul(l.Rate()).
+		Mul(decimal.NewFromInt(l._days)).
+		Div(decimal.NewFromInt(100).Mul(daysInInterestYear))
+}
+
+// RecoveryFee return the fixed compensation for recovery costs
+// (40 in EU member states, 40/70/100 GBP depending on debt in UK), none for consumers
+func (l *LateInterest) RecoveryFee() decimal.Decimal {
+	if l.Consumer || l._days == 0 {
+		return decimal.Zero
+	}
+
+	if l.Jurisdiction == LateInterestJurisdictionUK {
+		if l._principal.GreaterThanOrEqual(decimal.NewFromInt(10000)) {
+			return decimal.NewFromInt(100)
+		}
+		if l._principal.GreaterThanOrEqual(decimal.NewFromInt(1000)) {
+			return decimal.NewFromInt(70)
+		}
+	}
+
+	return decimal.NewFromInt(40)
+}
+
+// TotalDue return principal, interest and recovery fee
+func (l *LateInterest) TotalDue(precision int32) decimal.Decimal {
+	return l._principal.Add(l.Interest().Round(precision)).Add(l.RecoveryFee())
+}
+
+// detailLines return the calculation detail rendered on document
+func (l *LateInterest) detailLines(doc *Document) []string {
+	precision := int32(doc.Options.CurrencyPrecision)
+
+	lines := []string{
+		fmt.Sprintf("%s: %s", doc.Options.TextLateInterestPrincipalTitle, doc.formatMoney(l._principal)),
+		fmt.Sprintf("%s: %s (%d %s)", doc.Options.TextLateInterestOverdueTitle, l.DueDate, l._days, doc.Options.TextLateInterestDaysTitle),
+		fmt.Sprintf(
+			"%s: %s %% + %s = %s %%",
+			doc.Options.TextLateInterestRateTitle,
+			l._rate.String(),
+			l._margin.String(),
+			l.Rate().String(),
+		),
+		fmt.Sprintf(
+			"%s: %s x %s %% x %d / %s = %s",
+			doc.Options.TextLateInterestInterestTitle,
+			doc.formatMoney(l._principal),
+			l.Rate().String(),
+			l._days,
+			daysInInterestYear.String(),
+			doc.formatMoney(l.Interest().Round(precision)),
+		),
+	}
+
+	if fee := l.RecoveryFee(); !fee.IsZero() {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextLateInterestRecoveryFeeTitle, doc.formatMoney(fee)))
+	}
+
+	return append(lines, fmt.Sprintf(
+		"%s: %s",
+		doc.Options.TextLateInterestTotalDueTitle,
+		doc.formatMoney(l.TotalDue(precision)),
+	))
+}
+
+// appendLateInterest append late interest calculation detail to document
+func (doc *Document) appendLateInterest() {
+	if doc.LateInterest == nil {
+		return
+	}
+
+	lines := doc.LateInterest.detailLines(doc)
+
+	doc.pdf.SetY(doc.blockY(float64(len(lines)*4 + 6)))
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.CellFormat(190, 6, doc.encodeString(doc.Options.TextLateInterestTitle), "0", 2, "", true, 0, "")
+
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(190, 4, doc.encodeString(strings.Join(lines, "\n")), "0", "L", false)
+}
@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLateInterest(t *testing.T) {
+	l := &LateInterest{
+		Jurisdiction:  LateInterestJurisdictionDE,
+		ReferenceRate: "3.62",
+		DueDate:       "01/03/2021",
+		UntilDate:     "31/03/2021",
+	}
+
+	if err := l.Prepare("02/01/2006", decimal.NewFromInt(1000)); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if l.Days() != 30 || l.Rate().String() != "12.62" {
+		t.Errorf("unexpected days %d or rate %s", l.Days(), l.Rate())
+	}
+
+	if total := l.TotalDue(2).String(); total != "1050.37" {
+		t.Errorf("expected total due 1050.37, got %s", total)
+	}
+
+	l.Consumer = true
+	if err := l.Prepare("02/01/2006", decimal.NewFromInt(1000)); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !l.RecoveryFee().IsZero() || l.Rate().String() != "8.62" {
+		t.Errorf("unexpected consumer fee %s or rate %s", l.RecoveryFee(), l.Rate())
+	}
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// InvariantError define a violated totals invariant
+type InvariantError struct {
+	Invariant string
+	Expected  decimal.Decimal
+	Actual    decimal.Decimal
+}
+
+// Error return the invariant and amounts
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("invariant %s violated: expected %s, got %s", e.Invariant, e.Expected, e.Actual)
+}
+
+// CheckInvariants check document totals consistency, within a minor currency unit (Options.CurrencyPrecision):
+// each line total is unit cost x quantity - discount, sum of lines - document discount is the taxable base,
+// tax breakdown sums to taxable base and taxes, taxable base + taxes is the gross total.
+// Document must be validated.
+func CheckInvariants(doc *Document) error {
+	tolerance := decimal.New(1, -int32(doc.Options.CurrencyPrecision))
+	check := func(invariant string, expected decimal.Decimal, actual decimal.Decimal) error {
+		if expected.Sub(actual).Abs().GreaterThanOrEqual(tolerance) {
+			return &InvariantError{Invariant: invariant, Expected: expected, Actual: actual}
+		}
+		return nil
+	}
+
+	lines := decimal.Zero
+	for i, item := range doc.Items {
+		line := item.TotalWithoutTaxAndWithDiscount()
+		lines = lines.Add(line)
+
+		// Totals set by user are taken as is
+		if len(item.Total) > 0 {
+			continue
+		}
+
+		quantity, _ := decimal.NewFromString(item.Quantity)
+		expected := item._unitCost.Mul(quantity).Div(item.priceBasis())
+		if item.Free {
+			expected = decimal.Zero
+		}
+		if item.Discount != nil {
+			if discountType, discount := item.Discount.getDiscount(); discountType == DiscountTypeAmount && expected.IsNegative() {
+				expected = expected.Add(discount)
+			} else if discountType == DiscountTypeAmount {
+				expected = expected.Sub(discount)
+			} else {
+				expected = expected.Sub(expected.Mul(discount).Div(decimal.NewFromInt(100)))
+			}
+		}
+
+		if err := check(fmt.Sprintf("item %d total", i+1), expected, line); err != nil {
+			return err
+		}
+	}
+
+	discount := decimal.Zero
+	if doc.Discount != nil {
+		if discountType, value := doc.Discount.getDiscount(); discountType == DiscountTypeAmount {
+			discount = value
+		} else {
+			discount = lines.Mul(value).Div(decimal.NewFromInt(100))
+		}
+	}
+
+	base := doc.TotalWithoutTax()
+	if err := check("lines - discount = taxable base", lines.Sub(discount), base); err != nil {
+		return err
+	}
+
+	breakdownBase, breakdownTax := decimal.Zero, decimal.Zero
+	for _, line := range doc.TaxLines() {
+		breakdownBase = breakdownBase.Add(line.Base)
+		breakdownTax = breakdownTax.Add(line.Amount)
+	}
+
+	tax := doc.Tax()
+	if err := check("tax breakdown bases = taxable base", base, breakdownBase); err != nil {
+		return err
+	}
+	if err := check("tax breakdown amounts = taxes", tax, breakdownTax); err != nil {
+		return err
+	}
+
+	return check("taxable base + taxes = gross", base.Add(tax), doc.TotalWithTax())
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+)
+
+// randomDocument generate documents with random items, taxes and discounts
+type randomDocument struct {
+	doc *Document
+}
+
+// Generate implement quick.Generator
+func (randomDocument) Generate(r *rand.Rand, size int) reflect.Value {
+	amount := func(max int) string {
+		return strconv.FormatFloat(float64(r.Intn(max*100))/100, 'f', 2, 64)
+	}
+
+	doc, _ := New(Invoice, &Options{CheckInvariants: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.SetDefaultTax(&Tax{Percent: strconv.Itoa(r.Intn(25))})
+
+	for i := 0; i <= r.Intn(size+1); i++ {
+		item := &Item{Name: "Item", UnitCost: amount(1000), Quantity: strconv.Itoa(r.Intn(20) + 1)}
+		switch r.Intn(4) {
+		case 0:
+			item.Tax = &Tax{Percent: "5.5"}
+		case 1:
+			item.Tax = &Tax{Amount: amount(50)}
+		}
+		if r.Intn(3) == 0 {
+			item.Discount = &Discount{Percent: strconv.Itoa(r.Intn(50))}
+		}
+		doc.AppendItem(item)
+	}
+
+	switch r.Intn(3) {
+	case 0:
+		doc.SetDiscount(&Discount{Percent: strconv.Itoa(r.Intn(30))})
+	case 1:
+		doc.SetDiscount(&Discount{Amount: amount(100)})
+	}
+
+	return reflect.ValueOf(randomDocument{doc})
+}
+
+func TestCheckInvariants(t *testing.T) {
+	property := func(random randomDocument) bool {
+		if err := random.doc.Validate(); err != nil {
+			t.Log(err)
+			return false
+		}
+		if err := CheckInvariants(random.doc); err != nil {
+			t.Log(err)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+
+	// Inconsistent custom line total is reported
+	doc, _ := New(Invoice, &Options{CheckInvariants: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "2", Discount: &Discount{Amount: "1"}})
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Items[0]._unitCost = doc.Items[0]._unitCost.Add(doc.Items[0]._unitCost)
+	if err, ok := CheckInvariants(doc).(*InvariantError); !ok || err.Invariant != "item 1 total" {
+		t.Errorf("expected item total invariant error, got %v", err)
+	}
+}
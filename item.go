@@ -1,25 +1,72 @@
 package generator
 
 import (
+	"errors"
+
 	"github.com/shopspring/decimal"
 )
 
+// ErrInvalidItemKind when an item kind isn't one of the ItemKind constants
+var ErrInvalidItemKind = errors.New("invalid item kind")
+
+// ErrNegativeLine when an item has a negative quantity, unit cost or total
+// without Options.AllowNegativeLines
+var ErrNegativeLine = errors.New("negative line")
+
+// ErrInvalidPriceBasis when an item price basis isn't a positive number
+var ErrInvalidPriceBasis = errors.New("invalid price basis")
+
+// Item kinds
+const (
+	ItemKindService string = "service"
+	ItemKindFlatFee string = "flat_fee" // Line amount only, without unit price and quantity
+	ItemKindExpense string = "expense"
+)
+
 // Item represent a 'product' or a 'service'
 type Item struct {
 	Name        string    `json:"name,omitempty" validate:"required"`
+	Kind        string    `json:"kind,omitempty"` // ItemKindService when empty
 	Description string    `json:"description,omitempty"`
 	UnitCost    string    `json:"unit_cost,omitempty"`
 	Quantity    string    `json:"quantity,omitempty"`
+	Unit        string    `json:"unit,omitempty"`        // Unit of measure displayed after quantity ex h, kg
+	PriceBasis  string    `json:"price_basis,omitempty"` // Quantity the unit cost applies to, ex 100 for a price per 100 units
 	Tax         *Tax      `json:"tax,omitempty"`
 	Discount    *Discount `json:"discount,omitempty"`
 	Total       string    `json:"total,omitempty"`
+	Free        bool      `json:"free,omitempty"` // Free of charge, samples and warranty replacements, unit cost is the commercial value
+	Rental      *Rental   `json:"rental,omitempty"`
+	Meter       string    `json:"meter,omitempty"`       // Meter identifier, quantity is taken from its consumption
+	Date        string    `json:"date,omitempty"`        // Charge date, used by folio layout
+	Assignee    string    `json:"assignee,omitempty"`    // Guest or party the item is assigned to when splitting bills
+	Account     string    `json:"account,omitempty"`     // Ledger (GL) revenue account, used by journal exports, not rendered
+	Group       string    `json:"group,omitempty"`       // Item group, used by spend breakdown charts and as title by royalty layout
+	Period      string    `json:"period,omitempty"`      // Sales or license period ex 2024-Q1, used by royalty layout
+	CostCenter  string    `json:"cost_center,omitempty"` // Cost center, used by journal exports, not rendered
+	Seller      string    `json:"seller,omitempty"`      // Key of the seller the item is sold by, see Document.Sellers
+
+	// Fields custom values ex SKU or period, rendered by item columns of the same key, see Options.ItemColumns
+	Fields map[string]string `json:"fields,omitempty"`
 
 	_unitCost decimal.Decimal
 	_quantity decimal.Decimal
+
+	cityTax bool
 }
 
 // Prepare convert strings to decimal
 func (i *Item) Prepare() error {
+	// Kind
+	switch i.Kind {
+	case "", ItemKindService, ItemKindExpense:
+	case ItemKindFlatFee:
+		// Flat fee unit cost is the line amount
+		i.Quantity = "1"
+	default:
+		return ErrInvalidItemKind
+	}
+
 	// Unit cost
 	unitCost, err := decimal.NewFromString(i.UnitCost)
 	if err != nil {
@@ -27,6 +74,14 @@ func (i *Item) Prepare() error {
 	}
 	i._unitCost = unitCost
 
+	// Price basis
+	if len(i.PriceBasis) > 0 {
+		priceBasis, err := decimal.NewFromString(i.PriceBasis)
+		if err != nil || !priceBasis.IsPositive() {
+			return ErrInvalidPriceBasis
+		}
+	}
+
 	// Quantity
 	//quantity, err := decimal.NewFromString(i.Quantity)
 	//if err != nil {
@@ -51,28 +106,206 @@ func (i *Item) Prepare() error {
 	return nil
 }
 
+// TotalWithoutTaxAndWithoutDiscount returns the item total without tax and without discount
+func (i *Item) TotalWithoutTaxAndWithoutDiscount() decimal.Decimal {
+	quantity, _ := decimal.NewFromString(i.Quantity)
+	price, _ := decimal.NewFromString(i.UnitCost)
+
+	if i.Kind == ItemKindFlatFee {
+		return price
+	}
+
+	return price.Mul(quantity).Div(i.priceBasis())
+}
+
+// TotalWithoutTaxAndWithDiscount returns the item total without tax and with discount.
+// When set, Total is used as is.
+func (i *Item) TotalWithoutTaxAndWithDiscount() decimal.Decimal {
+	if i.Free {
+		return decimal.Zero
+	}
+
+	if total, err := decimal.NewFromString(i.Total); err == nil {
+		return total
+	}
+
+	total := i.TotalWithoutTaxAndWithoutDiscount()
+
+	// Check discount
+	if i.Discount != nil {
+		discountType, discountNumber := i.Discount.getDiscount()
+
+		if discountType == DiscountTypeAmount {
+			// Amount discounts reduce negative correction lines too
+			if total.IsNegative() {
+				total = total.Add(discountNumber)
+			} else {
+				total = total.Sub(discountNumber)
+			}
+		} else {
+			// Percent
+			toSub := total.Mul(discountNumber.Div(decimal.NewFromInt(100)))
+			total = total.Sub(toSub)
+		}
+	}
+
+	return total
+}
+
+// priceBasis return the quantity the unit cost applies to, 1 by default
+func (i *Item) priceBasis() decimal.Decimal {
+	if priceBasis, err := decimal.NewFromString(i.PriceBasis); err == nil && priceBasis.IsPositive() {
+		return priceBasis
+	}
+
+	return decimal.NewFromInt(1)
+}
+
+// unitPrice return the price of a single unit, unit cost divided by price basis
+func (i *Item) unitPrice() string {
+	if len(i.PriceBasis) == 0 {
+		return i.UnitCost
+	}
+
+	unitCost, _ := decimal.NewFromString(i.UnitCost)
+	return unitCost.Div(i.priceBasis()).String()
+}
+
+// freeOfCharge return true for free items and fully discounted items
+func (i *Item) freeOfCharge() bool {
+	if i.Free {
+		return true
+	}
+
+	if i.Discount == nil || len(i.Total) > 0 {
+		return false
+	}
+	discountType, discount := i.Discount.getDiscount()
+
+	return discountType == DiscountTypePercent && discount.Equal(decimal.NewFromInt(100))
+}
+
+// negative return true when quantity, unit cost or total is negative, as returns and corrections
+func (i *Item) negative() bool {
+	for _, amount := range []string{i.Quantity, i.UnitCost, i.Total} {
+		if value, err := decimal.NewFromString(amount); err == nil && value.IsNegative() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TaxWithTotalDiscounted returns the item tax computed on the discounted total
+func (i *Item) TaxWithTotalDiscounted() decimal.Decimal {
+	if i.Tax == nil {
+		return decimal.Zero
+	}
+
+	taxType, taxNumber := i.Tax.getTax()
+	if taxType == TaxTypeAmount {
+		if i.Free {
+			return decimal.Zero
+		}
+		return taxNumber
+	}
+
+	// Percent
+	return i.TotalWithoutTaxAndWithDiscount().Mul(taxNumber.Div(decimal.NewFromInt(100)))
+}
+
+// TotalWithTaxAndDiscount returns the item total with tax and discount
+func (i *Item) TotalWithTaxAndDiscount() decimal.Decimal {
+	return i.TotalWithoutTaxAndWithDiscount().Add(i.TaxWithTotalDiscounted())
+}
+
+// quantityWithUnit return quantity as displayed followed by its unit of measure, when set
+func (i *Item) quantityWithUnit(doc *Document) string {
+	if len(i.Unit) == 0 {
+		return doc.formatQuantity(i.Quantity)
+	}
+
+	return doc.formatQuantity(i.Quantity) + " " + i.Unit
+}
+
 // appendColTo document doc
 func (i *Item) appendColTo(options *Options, doc *Document) {
 	// Get base Y (top of line)
 	baseY := doc.pdf.GetY()
+	columns := doc.itemColumns()
+
+	// Name, description and rental window give line height
+	colHeight := doc.itemsLineHeight()
+	for _, column := range columns {
+		if column.Key == ItemColumnName && column.Formatter == nil {
+			i.appendNameTo(options, doc, column.x, column.width)
+			colHeight = doc.pdf.GetY() - baseY
+		}
+	}
+
+	doc.pdf.SetY(baseY)
+
+	for _, column := range columns {
+		if column.Key == ItemColumnName && column.Formatter == nil {
+			continue
+		}
+
+		text, style := i.columnText(column.Key, doc)
+		if column.Formatter != nil {
+			text, style = column.Formatter(i), ""
+		}
+		if len(text) == 0 {
+			continue
+		}
+
+		doc.pdf.SetX(column.x)
+		if len(style) > 0 {
+			doc.pdf.SetFont(doc.Options.Font, style, 0)
+		}
+		doc.pdf.CellFormat(
+			column.width,
+			colHeight,
+			doc.encodeString(text),
+			"0",
+			0,
+			column.Align,
+			false,
+			0,
+			"",
+		)
+		if len(style) > 0 {
+			doc.pdf.SetFont(doc.Options.Font, "", 0)
+		}
+	}
 
-	// Name
-	doc.pdf.SetX(ItemColNameOffset)
-	doc.pdf.MultiCell(
-		ItemColUnitPriceOffset-ItemColNameOffset,
-		3,
-		doc.encodeString(i.Name),
-		"",
-		"",
-		false,
-	)
+	// Set Y for next line
+	doc.pdf.SetY(baseY + colHeight)
+}
+
+// appendNameTo document doc at x in width, followed by description and rental window
+func (i *Item) appendNameTo(options *Options, doc *Document, x float64, width float64) {
+	// Name, with scripts and fallback fonts for characters the document font can't encode
+	doc.pdf.SetX(x)
+	name := doc.wrapText(i.Name, width)
+	if runs := doc.textRuns(name); runs != nil {
+		doc.writeRuns(runs, x, width, doc.itemsLineHeight(), doc.Options.Font, "")
+	} else {
+		doc.pdf.MultiCell(
+			width,
+			doc.itemsLineHeight(),
+			doc.encodeString(name),
+			"",
+			"",
+			false,
+		)
+	}
 
 	// Description
 	if len(i.Description) > 0 {
-		doc.pdf.SetX(ItemColNameOffset)
 		doc.pdf.SetY(doc.pdf.GetY() + 1)
+		doc.pdf.SetX(x)
 
-		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.SetFont(doc.Options.Font, "", doc.itemsSmallFontSize())
 		doc.pdf.SetTextColor(
 			doc.Options.GreyTextColor[0],
 			doc.Options.GreyTextColor[1],
@@ -80,16 +313,16 @@ func (i *Item) appendColTo(options *Options, doc *Document) {
 		)
 
 		doc.pdf.MultiCell(
-			ItemColUnitPriceOffset-ItemColNameOffset,
-			3,
-			doc.encodeString(i.Description),
+			width,
+			doc.itemsLineHeight(),
+			doc.encodeString(doc.wrapText(doc.itemDescription(i.Description), width)),
 			"",
 			"",
 			false,
 		)
 
 		// Reset font
-		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetFont(doc.Options.Font, "", doc.itemsFontSize())
 		doc.pdf.SetTextColor(
 			doc.Options.BaseTextColor[0],
 			doc.Options.BaseTextColor[1],
@@ -97,52 +330,33 @@ func (i *Item) appendColTo(options *Options, doc *Document) {
 		)
 	}
 
-	// Compute line height
-	colHeight := doc.pdf.GetY() - baseY
+	// Rental window
+	if i.Rental != nil {
+		doc.pdf.SetY(doc.pdf.GetY() + 1)
+		doc.pdf.SetX(x)
 
-	// Unit price
-	doc.pdf.SetY(baseY)
-	doc.pdf.SetX(ItemColUnitPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		colHeight,
-		doc.encodeString(i.UnitCost),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+		doc.pdf.SetFont(doc.Options.Font, "", doc.itemsSmallFontSize())
+		doc.pdf.SetTextColor(
+			doc.Options.GreyTextColor[0],
+			doc.Options.GreyTextColor[1],
+			doc.Options.GreyTextColor[2],
+		)
 
-	// Quantity
-	doc.pdf.SetX(ItemColQuantityOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		colHeight,
-		doc.encodeString(i.Quantity),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	doc.pdf.SetX(ItemColTotalHTOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		colHeight,
-		doc.encodeString(i.Total),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+		doc.pdf.MultiCell(
+			width,
+			doc.itemsLineHeight(),
+			doc.encodeString(i.Rental.windowAsString(options)),
+			"",
+			"",
+			false,
+		)
 
-	// Set Y for next line
-	doc.pdf.SetY(baseY + colHeight)
+		// Reset font
+		doc.pdf.SetFont(doc.Options.Font, "", doc.itemsFontSize())
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+	}
 }
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestItemKinds(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Setup fee", Kind: ItemKindFlatFee, UnitCost: "250"})
+	doc.AppendItem(&Item{Name: "Travel", Kind: ItemKindExpense, UnitCost: "40", Quantity: "2"})
+	doc.AppendItem(&Item{Name: "Consulting", Kind: ItemKindService, UnitCost: "100", Quantity: "3"})
+
+	if total := doc.TotalWithoutTax().String(); total != "630" {
+		t.Errorf("expected total 630, got %s", total)
+	}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Items[0].Quantity != "1" {
+		t.Errorf("expected flat fee quantity 1, got %s", doc.Items[0].Quantity)
+	}
+	if err := CheckInvariants(doc); err != nil {
+		t.Error(err)
+	}
+
+	doc.AppendItem(&Item{Name: "Other", Kind: "other", UnitCost: "1", Quantity: "1"})
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidItemKind) {
+		t.Errorf("expected invalid item kind, got %v", err)
+	}
+}
+
+func TestNegativeLines(t *testing.T) {
+	newDocument := func(options *Options) *Document {
+		doc, _ := New(Invoice, options)
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: "Headphones", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "20"}})
+		doc.AppendItem(&Item{Name: "Returned headphones", UnitCost: "100", Quantity: "-1", Tax: &Tax{Percent: "20"}, Discount: &Discount{Amount: "10"}})
+		return doc
+	}
+
+	if err := newDocument(&Options{}).Validate(); !errors.Is(err, ErrNegativeLine) {
+		t.Errorf("expected negative line, got %v", err)
+	}
+
+	doc := newDocument(&Options{AllowNegativeLines: true})
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if line := doc.Items[1].TotalWithoutTaxAndWithDiscount().String(); line != "-90" {
+		t.Errorf("expected return line -90, got %s", line)
+	}
+	if total := doc.TotalWithTax().String(); total != "132" {
+		t.Errorf("expected total 132, got %s", total)
+	}
+	if err := CheckInvariants(doc); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFreeOfChargeItems(t *testing.T) {
+	doc, _ := New(Invoice, &Options{ShowFreeOfChargeValue: true, DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.SetDefaultTax(&Tax{Amount: "5"})
+	doc.AppendItem(&Item{Name: "Printer", UnitCost: "300", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Toner sample", UnitCost: "40", Quantity: "2", Free: true})
+	doc.AppendItem(&Item{Name: "Warranty replacement", UnitCost: "25", Quantity: "1", Discount: &Discount{Percent: "100"}})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := doc.TotalWithTax().String(); total != "310" {
+		t.Errorf("expected total 310, got %s", total)
+	}
+	if err := CheckInvariants(doc); err != nil {
+		t.Error(err)
+	}
+	if !doc.Items[1].freeOfCharge() || !doc.Items[2].freeOfCharge() || doc.Items[0].freeOfCharge() {
+		t.Errorf("expected only last items free of charge")
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if count := bytes.Count(buffer.Bytes(), []byte("(Free of charge)")); count != 2 {
+		t.Errorf("expected 2 free of charge labels, got %d", count)
+	}
+}
+
+func TestPriceBasis(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Diesel", UnitCost: "1450", PriceBasis: "1000", Quantity: "2500", Unit: "l"})
+	doc.AppendItem(&Item{Name: "Screws", UnitCost: "12.50", PriceBasis: "100", Quantity: "30"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := doc.TotalWithoutTax().String(); total != "3628.75" {
+		t.Errorf("expected total 3628.75, got %s", total)
+	}
+	if price := doc.Items[1].unitPrice(); price != "0.125" {
+		t.Errorf("expected unit price 0.125, got %s", price)
+	}
+	if err := CheckInvariants(doc); err != nil {
+		t.Error(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(1450 / 1000 l)")) {
+		t.Errorf("expected price basis indicator")
+	}
+
+	doc.Items[0].PriceBasis = "0"
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidPriceBasis) {
+		t.Errorf("expected invalid price basis, got %v", err)
+	}
+}
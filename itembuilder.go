@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidItemName when an item name is empty
+var ErrInvalidItemName = errors.New("invalid item name")
+
+// ErrInvalidQuantity when an item quantity isn't a finite number
+var ErrInvalidQuantity = errors.New("invalid quantity")
+
+// ErrInvalidUnitCost when an item unit cost isn't a plain decimal number
+var ErrInvalidUnitCost = errors.New("invalid unit cost")
+
+// VAT20 is a 20% tax
+var VAT20 = &Tax{Percent: "20"}
+
+// ItemError define an invalid item field, Err is one of the ErrInvalid errors
+type ItemError struct {
+	Item  string
+	Field string
+	Err   error
+}
+
+// Error return the item, field and cause
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %q: %s: %s", e.Item, e.Field, e.Err)
+}
+
+// Unwrap return the cause
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// TaxRate return a tax of percent
+func TaxRate(percent string) *Tax {
+	return &Tax{Percent: percent}
+}
+
+// Pct return a discount of percent
+func Pct(percent float64) *Discount {
+	return &Discount{Percent: decimal.NewFromFloat(percent).String()}
+}
+
+// AmountOff return a discount of amount
+func AmountOff(amount string) *Discount {
+	return &Discount{Amount: amount}
+}
+
+// ItemBuilder build an item field by field, each value is validated when set
+// and the first error is returned by Build
+type ItemBuilder struct {
+	item *Item
+	err  error
+}
+
+// NewItem return an item builder with name
+func NewItem(name string) *ItemBuilder {
+	b := &ItemBuilder{item: &Item{Name: name, Quantity: "1"}}
+	if len(strings.TrimSpace(name)) == 0 {
+		b.fail("name", ErrInvalidItemName)
+	}
+
+	return b
+}
+
+// fail keep the first error
+func (b *ItemBuilder) fail(field string, err error) *ItemBuilder {
+	if b.err == nil {
+		b.err = &ItemError{Item: b.item.Name, Field: field, Err: err}
+	}
+
+	return b
+}
+
+// Kind set item kind, one of the ItemKind constants
+func (b *ItemBuilder) Kind(kind string) *ItemBuilder {
+	switch kind {
+	case ItemKindService, ItemKindFlatFee, ItemKindExpense:
+		b.item.Kind = kind
+		return b
+	}
+
+	return b.fail("kind", ErrInvalidItemKind)
+}
+
+// Description set item description
+func (b *ItemBuilder) Description(description string) *ItemBuilder {
+	b.item.Description = description
+	return b
+}
+
+// Qty set item quantity
+func (b *ItemBuilder) Qty(quantity float64) *ItemBuilder {
+	if quantity != quantity || quantity > 1e18 || quantity < -1e18 {
+		return b.fail("quantity", ErrInvalidQuantity)
+	}
+
+	b.item.Quantity = decimal.NewFromFloat(quantity).String()
+	return b
+}
+
+// Unit set item unit of measure
+func (b *ItemBuilder) Unit(unit string) *ItemBuilder {
+	b.item.Unit = unit
+	return b
+}
+
+// Per set the quantity the unit cost applies to, ex 100 for a price per 100 units
+func (b *ItemBuilder) Per(priceBasis string) *ItemBuilder {
+	basis, err := decimal.NewFromString(priceBasis)
+	if err != nil || sanitizeAmount(priceBasis) != nil || !basis.IsPositive() {
+		return b.fail("price basis", ErrInvalidPriceBasis)
+	}
+
+	b.item.PriceBasis = priceBasis
+	return b
+}
+
+// Price set item unit cost
+func (b *ItemBuilder) Price(unitCost string) *ItemBuilder {
+	if sanitizeAmount(unitCost) != nil || len(unitCost) == 0 {
+		return b.fail("unit cost", ErrInvalidUnitCost)
+	}
+
+	b.item.UnitCost = unitCost
+	return b
+}
+
+// Tax set item tax, tax is copied
+func (b *ItemBuilder) Tax(tax *Tax) *ItemBuilder {
+	if tax == nil {
+		return b.fail("tax", ErrInvalidTax)
+	}
+
+	itemTax := &Tax{Percent: tax.Percent, Amount: tax.Amount}
+	if sanitizeAmount(itemTax.Percent) != nil || sanitizeAmount(itemTax.Amount) != nil || itemTax.Prepare() != nil {
+		return b.fail("tax", ErrInvalidTax)
+	}
+
+	b.item.Tax = itemTax
+	return b
+}
+
+// Discount set item discount, discount is copied
+func (b *ItemBuilder) Discount(discount *Discount) *ItemBuilder {
+	if discount == nil {
+		return b.fail("discount", ErrInvalidDiscount)
+	}
+
+	itemDiscount := &Discount{Percent: discount.Percent, Amount: discount.Amount}
+	if sanitizeAmount(itemDiscount.Percent) != nil || sanitizeAmount(itemDiscount.Amount) != nil || itemDiscount.Prepare() != nil {
+		return b.fail("discount", ErrInvalidDiscount)
+	}
+
+	b.item.Discount = itemDiscount
+	return b
+}
+
+// Field set a custom item field, rendered by the item column of key
+func (b *ItemBuilder) Field(key string, value string) *ItemBuilder {
+	if b.item.Fields == nil {
+		b.item.Fields = map[string]string{}
+	}
+
+	b.item.Fields[key] = value
+	return b
+}
+
+// Free mark item free of charge, price is its commercial value
+func (b *ItemBuilder) Free() *ItemBuilder {
+	b.item.Free = true
+	return b
+}
+
+// Build return the item, or the first invalid field as an *ItemError
+func (b *ItemBuilder) Build() (*Item, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.item.UnitCost) == 0 {
+		return nil, &ItemError{Item: b.item.Name, Field: "unit cost", Err: ErrInvalidUnitCost}
+	}
+
+	item := *b.item
+	if err := item.Prepare(); err != nil {
+		return nil, &ItemError{Item: item.Name, Field: "unit cost", Err: ErrInvalidUnitCost}
+	}
+
+	return &item, nil
+}
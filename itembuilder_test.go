@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestItemBuilder(t *testing.T) {
+	item, err := NewItem("Consulting").Qty(12).Unit("h").Price("150").Tax(VAT20).Discount(Pct(10)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, _ := New(Invoice, &Options{})
+	if item.Quantity != "12" || item.quantityWithUnit(doc) != "12 h" || item.Tax == VAT20 {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if total := item.TotalWithTaxAndDiscount().String(); total != "1944" {
+		t.Errorf("expected total 1944, got %s", total)
+	}
+
+	cases := []struct {
+		builder *ItemBuilder
+		err     error
+	}{
+		{NewItem(" ").Price("1"), ErrInvalidItemName},
+		{NewItem("Item").Qty(math.NaN()).Price("1"), ErrInvalidQuantity},
+		{NewItem("Item").Price("1e3"), ErrInvalidUnitCost},
+		{NewItem("Item"), ErrInvalidUnitCost},
+		{NewItem("Item").Price("1").Tax(TaxRate("abc")), ErrInvalidTax},
+		{NewItem("Item").Price("1").Discount(&Discount{}), ErrInvalidDiscount},
+	}
+	for _, c := range cases {
+		_, err := c.builder.Build()
+		itemError := &ItemError{}
+		if !errors.As(err, &itemError) || !errors.Is(err, c.err) {
+			t.Errorf("expected %v, got %v", c.err, err)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package generator
+
+import "errors"
+
+// ErrInvalidItemColumns when item columns are wider than the items table
+var ErrInvalidItemColumns = errors.New("invalid item columns")
+
+// Items table columns, other keys render Item.Fields values
+const (
+	ItemColumnName         string = "name" // Name, description and rental window
+	ItemColumnUnitPrice    string = "unit_price"
+	ItemColumnQuantity     string = "quantity" // Quantity, followed by unit when there is no unit column
+	ItemColumnUnit         string = "unit"
+	ItemColumnTotal        string = "total" // Total without tax
+	ItemColumnTax          string = "tax"
+	ItemColumnDiscount     string = "discount"
+	ItemColumnTotalWithTax string = "total_with_tax"
+)
+
+// ItemsTableWidth define the width of items table
+const ItemsTableWidth float64 = 190
+
+// ItemColumn define a column of the items table
+type ItemColumn struct {
+	Key       string                  `json:"key,omitempty" validate:"required"` // ItemColumn constant or Item.Fields key
+	Title     string                  `json:"title,omitempty"`                   // Options.TextItems title for built-in columns when empty, key otherwise
+	Width     float64                 `json:"width,omitempty" validate:"min=0"`  // Width in millimeters, columns without width share remaining width
+	Align     string                  `json:"align,omitempty" validate:"omitempty,oneof=L C R"`
+	Hidden    bool                    `json:"hidden,omitempty"` // Not rendered, its width is given to the name column
+	Formatter func(item *Item) string `json:"-"`                // Cell text, replace built-in values
+}
+
+// DefaultItemColumns define the default items table columns
+var DefaultItemColumns = []*ItemColumn{
+	{Key: ItemColumnName, Width: ItemColUnitPriceOffset - ItemColNameOffset},
+	{Key: ItemColumnUnitPrice, Width: ItemColQuantityOffset - ItemColUnitPriceOffset},
+	{Key: ItemColumnQuantity, Width: ItemColTotalHTOffset - ItemColQuantityOffset},
+	{Key: ItemColumnTotal},
+}
+
+// itemColumn define a laid out items table column
+type itemColumn struct {
+	*ItemColumn
+	x     float64
+	width float64
+}
+
+// itemColumns return Options.ItemColumns laid out from left margin, DefaultItemColumns when empty
+func (doc *Document) itemColumns() []*itemColumn {
+	columns := doc.Options.ItemColumns
+	if len(columns) == 0 {
+		columns = DefaultItemColumns
+	}
+
+	// Columns without width share remaining width
+	remaining, automatic := ItemsTableWidth, 0
+	for _, column := range columns {
+		remaining -= column.Width
+		if column.Width == 0 {
+			automatic++
+		}
+	}
+
+	laidOut := make([]*itemColumn, 0, len(columns))
+	freed := 0.0
+	for _, column := range columns {
+		width := column.Width
+		if width == 0 {
+			width = remaining / float64(automatic)
+		}
+
+		if column.Hidden || doc.emptyCols[column.Key] {
+			freed += width
+			continue
+		}
+		laidOut = append(laidOut, &itemColumn{ItemColumn: column, width: width})
+	}
+
+	// Hidden columns width goes to the name column, the first column when there is none
+	if freed > 0 && len(laidOut) > 0 {
+		widened := laidOut[0]
+		for _, column := range laidOut {
+			if column.Key == ItemColumnName {
+				widened = column
+				break
+			}
+		}
+		widened.width += freed
+	}
+
+	x := ItemColNameOffset
+	for _, column := range laidOut {
+		column.x = x
+		x += column.width
+	}
+
+	return laidOut
+}
+
+// emptyItemColumns return the tax, discount and Item.Fields columns without value on any item,
+// hidden when Options.HideEmptyItemColumns is set
+func (doc *Document) emptyItemColumns() map[string]bool {
+	if !doc.Options.HideEmptyItemColumns {
+		return nil
+	}
+
+	empty := map[string]bool{}
+	for _, column := range doc.Options.ItemColumns {
+		switch {
+		case column.Formatter != nil:
+			continue
+		case column.Key == ItemColumnTax && doc.DefaultTax != nil:
+			continue
+		case column.Key == ItemColumnName, column.Key == ItemColumnUnitPrice, column.Key == ItemColumnQuantity,
+			column.Key == ItemColumnUnit, column.Key == ItemColumnTotal, column.Key == ItemColumnTotalWithTax:
+			continue
+		}
+
+		empty[column.Key] = true
+		for _, item := range doc.Items {
+			if text, _ := item.columnText(column.Key, doc); len(text) > 0 {
+				delete(empty, column.Key)
+				break
+			}
+		}
+	}
+
+	return empty
+}
+
+// checkItemColumns check item columns fit in items table
+func (doc *Document) checkItemColumns() error {
+	width, automatic := 0.0, false
+	for _, column := range doc.Options.ItemColumns {
+		width += column.Width
+		automatic = automatic || column.Width == 0
+	}
+
+	if width > ItemsTableWidth || (automatic && width >= ItemsTableWidth) {
+		return ErrInvalidItemColumns
+	}
+
+	return nil
+}
+
+// itemColumn return the laid out column of key, nil when not rendered
+func (doc *Document) itemColumn(key string) *itemColumn {
+	for _, column := range doc.itemColumns() {
+		if column.Key == key {
+			return column
+		}
+	}
+
+	return nil
+}
+
+// itemColumnTitle return the title of column
+func (doc *Document) itemColumnTitle(column *ItemColumn) string {
+	if len(column.Title) > 0 {
+		return column.Title
+	}
+
+	switch column.Key {
+	case ItemColumnName:
+		return doc.Options.TextItemsNameTitle
+	case ItemColumnUnitPrice:
+		return doc.Options.TextItemsUnitCostTitle
+	case ItemColumnQuantity:
+		return doc.Options.TextItemsQuantityTitle
+	case ItemColumnUnit:
+		return doc.Options.TextItemsUnitTitle
+	case ItemColumnTotal:
+		return doc.Options.TextItemsTotalHTTitle
+	case ItemColumnTax:
+		return doc.Options.TextItemsTaxTitle
+	case ItemColumnDiscount:
+		return doc.Options.TextItemsDiscountTitle
+	case ItemColumnTotalWithTax:
+		return doc.Options.TextItemsTotalTTCTitle
+	}
+
+	return column.Key
+}
+
+// unitCostText return the unit cost as displayed, formatted with Options.Locale, masked by redaction
+// and followed by price basis
+func (i *Item) unitCostText(doc *Document) string {
+	unitCost := doc.formatUnitPrice(i.UnitCost)
+	if doc.redaction != nil && doc.redaction.UnitPrices {
+		unitCost = doc.redaction.Mask
+	}
+
+	// Price basis indicator, ex 12.50 / 100 l
+	if len(i.PriceBasis) > 0 {
+		unitCost += " / " + i.PriceBasis
+		if len(i.Unit) > 0 {
+			unitCost += " " + i.Unit
+		}
+	}
+
+	return unitCost
+}
+
+// columnText return the text and font style of item cell in column of key
+func (i *Item) columnText(key string, doc *Document) (string, string) {
+	options := doc.Options
+
+	switch key {
+	case ItemColumnName:
+		return i.Name, ""
+
+	case ItemColumnUnitPrice:
+		// Flat fees only render the line amount
+		if i.Kind == ItemKindFlatFee {
+			return "", ""
+		}
+
+		// Free of charge items show their commercial value struck through, when enabled
+		if i.freeOfCharge() {
+			if options.ShowFreeOfChargeValue {
+				return i.unitCostText(doc), "S"
+			}
+			return "", ""
+		}
+
+		return i.unitCostText(doc), ""
+
+	case ItemColumnQuantity:
+		if i.Kind == ItemKindFlatFee {
+			return "", ""
+		}
+		if doc.itemColumn(ItemColumnUnit) != nil {
+			return doc.formatQuantity(i.Quantity), ""
+		}
+
+		return i.quantityWithUnit(doc), ""
+
+	case ItemColumnUnit:
+		if i.Kind == ItemKindFlatFee {
+			return "", ""
+		}
+
+		return i.Unit, ""
+
+	case ItemColumnTotal:
+		if i.freeOfCharge() {
+			return options.TextItemsFreeOfCharge, ""
+		}
+		if i.Kind == ItemKindFlatFee && len(i.Total) == 0 {
+			return i.unitCostText(doc), ""
+		}
+
+		return i.totalText(doc), ""
+
+	case ItemColumnTax:
+		if i.Tax == nil {
+			return "", ""
+		}
+		taxType, tax := i.Tax.getTax()
+		if taxType == TaxTypePercent {
+			return doc.formatPercent(tax.String()), ""
+		}
+
+		return doc.formatMoney(tax), ""
+
+	case ItemColumnDiscount:
+		if i.Discount == nil {
+			return "", ""
+		}
+		discountType, discount := i.Discount.getDiscount()
+		if discountType == DiscountTypePercent {
+			return doc.formatPercent(discount.String()), ""
+		}
+
+		return doc.formatMoney(discount), ""
+
+	case ItemColumnTotalWithTax:
+		return doc.formatMoney(i.TotalWithTaxAndDiscount()), ""
+	}
+
+	return i.Fields[key], ""
+}
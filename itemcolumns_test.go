@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestItemColumns(t *testing.T) {
+	doc, _ := New(Invoice, &Options{
+		ItemColumns: []*ItemColumn{
+			{Key: "sku", Title: "Reference", Width: 30},
+			{Key: ItemColumnName},
+			{Key: ItemColumnQuantity, Width: 20, Align: "R"},
+			{Key: ItemColumnUnit, Width: 15},
+			{Key: ItemColumnTotal, Width: 30, Align: "R", Formatter: func(item *Item) string {
+				return strings.ToUpper(item.Name)
+			}},
+		},
+	})
+
+	columns := doc.itemColumns()
+	if columns[0].x != ItemColNameOffset || columns[1].x != 40 || columns[1].width != 95 || columns[4].x != 170 {
+		t.Errorf("unexpected layout, name at %f width %f, total at %f", columns[1].x, columns[1].width, columns[4].x)
+	}
+
+	item, err := NewItem("Paper").Qty(12).Unit("kg").Price("2.5").Field("sku", "PAP-001").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []struct {
+		key  string
+		text string
+	}{
+		{"sku", "PAP-001"},
+		{ItemColumnQuantity, "12"},
+		{ItemColumnUnit, "kg"},
+		{ItemColumnTax, ""},
+		{"period", ""},
+	} {
+		if text, _ := item.columnText(expected.key, doc); text != expected.text {
+			t.Errorf("expected %s column %q, got %q", expected.key, expected.text, text)
+		}
+	}
+
+	if title := doc.itemColumnTitle(columns[0].ItemColumn); title != "Reference" {
+		t.Errorf("expected custom title, got %s", title)
+	}
+	if title := doc.itemColumnTitle(columns[3].ItemColumn); title != doc.Options.TextItemsUnitTitle {
+		t.Errorf("expected unit title, got %s", title)
+	}
+
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(item)
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestItemColumnsDefault(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	item := &Item{Name: "Hosting", UnitCost: "100", Quantity: "2", Unit: "month", Tax: &Tax{Percent: "20"}, Discount: &Discount{Amount: "10"}}
+	if err := item.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+
+	columns := doc.itemColumns()
+	if len(columns) != 4 || columns[3].x != ItemColTotalHTOffset || columns[3].width != 25 {
+		t.Errorf("unexpected default layout %+v", columns[3])
+	}
+
+	for _, expected := range []struct {
+		key  string
+		text string
+	}{
+		{ItemColumnQuantity, "2 month"},
+		{ItemColumnTax, "20 %"},
+		{ItemColumnDiscount, doc.ac.FormatMoneyDecimal(item.Discount._amount)},
+		{ItemColumnTotalWithTax, doc.ac.FormatMoneyDecimal(item.TotalWithTaxAndDiscount())},
+	} {
+		if text, _ := item.columnText(expected.key, doc); text != expected.text {
+			t.Errorf("expected %s column %q, got %q", expected.key, expected.text, text)
+		}
+	}
+}
+
+func TestItemColumnsTooWide(t *testing.T) {
+	doc, _ := New(Invoice, &Options{ItemColumns: []*ItemColumn{
+		{Key: ItemColumnName, Width: 150},
+		{Key: ItemColumnTotal, Width: 50},
+	}})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidItemColumns) {
+		t.Errorf("expected ErrInvalidItemColumns, got %v", err)
+	}
+
+	doc.Options.ItemColumns = []*ItemColumn{{Key: ItemColumnName, Align: "justify"}}
+	if err := doc.Validate(); err == nil {
+		t.Error("expected invalid align error")
+	}
+}
+
+func TestHiddenItemColumns(t *testing.T) {
+	doc, _ := New(Invoice, &Options{
+		HideEmptyItemColumns: true,
+		ItemColumns: []*ItemColumn{
+			{Key: ItemColumnName, Width: 80},
+			{Key: ItemColumnQuantity, Width: 20},
+			{Key: ItemColumnTax, Width: 20},
+			{Key: ItemColumnDiscount, Width: 20},
+			{Key: "sku", Width: 20, Hidden: true},
+			{Key: ItemColumnTotal},
+		},
+	})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Paper", UnitCost: "2.5", Quantity: "12", Tax: &Tax{Percent: "20"}})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Discount is empty and sku hidden, their 40 mm go to the name column
+	columns := doc.itemColumns()
+	if len(columns) != 4 || doc.itemColumn(ItemColumnDiscount) != nil || doc.itemColumn("sku") != nil {
+		t.Fatalf("expected name, quantity, tax and total columns, got %d columns", len(columns))
+	}
+	if name := doc.itemColumn(ItemColumnName); name.width != 120 {
+		t.Errorf("expected name column widened to 120, got %f", name.width)
+	}
+	if total := doc.itemColumn(ItemColumnTotal); total.x != 170 || total.width != 30 {
+		t.Errorf("expected total column kept at 170 with 30, got %f with %f", total.x, total.width)
+	}
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrJobNotFound when a job ID is unknown to the queue
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobQueueFull when the queue can't accept more pending jobs
+var ErrJobQueueFull = errors.New("job queue full")
+
+// Job statuses
+const (
+	JobStatusPending string = "pending"
+	JobStatusRunning string = "running"
+	JobStatusDone    string = "done"
+	JobStatusFailed  string = "failed"
+)
+
+// Job define a document render job, the document is carried as JSON to be stored by external queues
+type Job struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"`
+	Document    []byte    `json:"document"`               // Document JSON
+	PDF         []byte    `json:"pdf,omitempty"`          // Rendered PDF when done
+	Error       string    `json:"error,omitempty"`        // Render error when failed
+	SubmittedAt time.Time `json:"submitted_at"`           // Time of submission
+	CompletedAt time.Time `json:"completed_at,omitempty"` // Time of success or failure
+}
+
+// Completed return true when job is done or failed
+func (j *Job) Completed() bool {
+	return j.Status == JobStatusDone || j.Status == JobStatusFailed
+}
+
+// JobQueue store render jobs, see MemoryJobQueue. Implement it to render over an external queue (Redis, SQS...),
+// jobs are then submitted and rendered by different processes.
+type JobQueue interface {
+	// Push enqueue a pending job
+	Push(ctx context.Context, job *Job) error
+	// Pop block until a pending job is available or ctx is done
+	Pop(ctx context.Context) (*Job, error)
+	// Update store job status and result
+	Update(ctx context.Context, job *Job) error
+	// Get return job by ID, ErrJobNotFound when unknown
+	Get(ctx context.Context, id string) (*Job, error)
+}
+
+// MemoryJobQueue is an in-memory JobQueue with a bounded number of pending jobs.
+// Completed jobs are kept until Delete.
+type MemoryJobQueue struct {
+	pending chan string
+	jobs    map[string]*Job
+	mutex   sync.Mutex
+}
+
+// NewMemoryJobQueue return an in-memory queue accepting size pending jobs
+func NewMemoryJobQueue(size int) *MemoryJobQueue {
+	return &MemoryJobQueue{
+		pending: make(chan string, size),
+		jobs:    map[string]*Job{},
+	}
+}
+
+// Push enqueue a pending job, ErrJobQueueFull when size pending jobs are waiting
+func (q *MemoryJobQueue) Push(ctx context.Context, job *Job) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	select {
+	case q.pending <- job.ID:
+		stored := *job
+		q.jobs[job.ID] = &stored
+		return nil
+	default:
+		return ErrJobQueueFull
+	}
+}
+
+// Pop block until a pending job is available or ctx is done
+func (q *MemoryJobQueue) Pop(ctx context.Context) (*Job, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case id := <-q.pending:
+			// Skip jobs deleted while pending
+			if job, err := q.Get(ctx, id); err == nil {
+				return job, nil
+			}
+		}
+	}
+}
+
+// Update store job status and result
+func (q *MemoryJobQueue) Update(ctx context.Context, job *Job) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, ok := q.jobs[job.ID]; !ok {
+		return ErrJobNotFound
+	}
+	stored := *job
+	q.jobs[job.ID] = &stored
+
+	return nil
+}
+
+// Get return a copy of job by ID, ErrJobNotFound when unknown
+func (q *MemoryJobQueue) Get(ctx context.Context, id string) (*Job, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	copied := *job
+
+	return &copied, nil
+}
+
+// Delete forget job, ex once its result has been fetched
+func (q *MemoryJobQueue) Delete(id string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	delete(q.jobs, id)
+}
+
+// Len return the number of stored jobs, pending or completed
+func (q *MemoryJobQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.jobs)
+}
+
+// Renderer submit documents to a queue and render its jobs with workers
+type Renderer struct {
+	Queue   JobQueue
+	Workers int // Number of concurrent renders, 1 when 0
+
+	// Prepare set options which aren't carried by JSON (Fetcher, AnnotatePage...) before rendering
+	Prepare func(doc *Document) error
+	// OnComplete is called when a job is done or failed, ex to post a webhook
+	OnComplete func(job *Job)
+}
+
+// NewRenderer return a renderer of queue jobs
+func NewRenderer(queue JobQueue) *Renderer {
+	return &Renderer{Queue: queue, Workers: 1}
+}
+
+// Submit enqueue a render job of doc and return it pending
+func (r *Renderer) Submit(ctx context.Context, doc *Document) (*Job, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SubmitJSON(ctx, data)
+}
+
+// SubmitJSON enqueue a render job of document JSON and return it pending
+func (r *Renderer) SubmitJSON(ctx context.Context, data []byte) (*Job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		ID:          hex.EncodeToString(id),
+		Status:      JobStatusPending,
+		Document:    data,
+		SubmittedAt: time.Now(),
+	}
+	if err := r.Queue.Push(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Poll return job by ID, its PDF is set once done
+func (r *Renderer) Poll(ctx context.Context, id string) (*Job, error) {
+	return r.Queue.Get(ctx, id)
+}
+
+// Run render queue jobs until ctx is done, return ctx error or the first queue error
+func (r *Renderer) Run(ctx context.Context) error {
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.work(ctx)
+		}()
+	}
+
+	// First worker to stop stop the others
+	err := <-errs
+	cancel()
+	wg.Wait()
+
+	return err
+}
+
+// work pop and render jobs until ctx is done or the queue fail
+func (r *Renderer) work(ctx context.Context) error {
+	for {
+		job, err := r.Queue.Pop(ctx)
+		if err != nil {
+			return err
+		}
+
+		job.Status = JobStatusRunning
+		if err := r.Queue.Update(ctx, job); err != nil {
+			return err
+		}
+
+		pdf, err := r.render(job.Document)
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = JobStatusDone
+			job.PDF = pdf
+		}
+		job.CompletedAt = time.Now()
+
+		if err := r.Queue.Update(ctx, job); err != nil {
+			return err
+		}
+		if r.OnComplete != nil {
+			r.OnComplete(job)
+		}
+	}
+}
+
+// render return the PDF of document JSON, panics of a single job are returned as errors
+func (r *Renderer) render(data []byte) (pdf []byte, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("render panic: %v", recovered)
+		}
+	}()
+
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.Prepare != nil {
+		if err := r.Prepare(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRenderer(t *testing.T) {
+	queue := NewMemoryJobQueue(4)
+	renderer := NewRenderer(queue)
+	renderer.Workers = 2
+
+	completed := make(chan *Job, 2)
+	renderer.OnComplete = func(job *Job) {
+		completed <- job
+	}
+
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Hosting", UnitCost: "100", Quantity: "1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job, err := renderer.Submit(ctx, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid, err := renderer.SubmitJSON(ctx, []byte(`{"type":"INVOICE"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := renderer.Poll(ctx, job.ID); pending.Status != JobStatusPending {
+		t.Errorf("expected pending job, got %s", pending.Status)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- renderer.Run(ctx)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-completed:
+		case <-time.After(10 * time.Second):
+			t.Fatal("expected jobs to complete")
+		}
+	}
+
+	result, err := renderer.Poll(ctx, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != JobStatusDone || !bytes.HasPrefix(result.PDF, []byte("%PDF-")) || result.CompletedAt.IsZero() {
+		t.Errorf("unexpected job %s %s", result.Status, result.Error)
+	}
+
+	failed, _ := renderer.Poll(ctx, invalid.ID)
+	if failed.Status != JobStatusFailed || len(failed.Error) == 0 || !failed.Completed() {
+		t.Errorf("expected failed job, got %s", failed.Status)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+func TestMemoryJobQueue(t *testing.T) {
+	ctx := context.Background()
+	queue := NewMemoryJobQueue(1)
+
+	if err := queue.Push(ctx, &Job{ID: "1", Status: JobStatusPending}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Push(ctx, &Job{ID: "2", Status: JobStatusPending}); !errors.Is(err, ErrJobQueueFull) {
+		t.Errorf("expected ErrJobQueueFull, got %v", err)
+	}
+
+	queue.Delete("1")
+	if _, err := queue.Get(ctx, "1"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	if err := queue.Update(ctx, &Job{ID: "1"}); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+
+	// Deleted pending jobs are skipped
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := queue.Pop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
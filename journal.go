@@ -0,0 +1,305 @@
+package generator
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/shopspring/decimal"
+)
+
+// ErrMissingAccount when no account is configured for a journal line
+var ErrMissingAccount = errors.New("missing account")
+
+// Journal debit / credit indicators
+const (
+	JournalDebit  string = "S"
+	JournalCredit string = "H"
+)
+
+// JournalAccounts define accounts used to export a document as accounting journal lines.
+// Maps are keyed by tax rate as written in Tax.Percent ex "19", "amount" for fixed amount taxes
+// and "none" for items without tax.
+type JournalAccounts struct {
+	Receivable     string            `json:"receivable,omitempty"` // Customer (debtor) account ex 10000
+	Revenue        string            `json:"revenue,omitempty"`    // Default revenue account ex 8400
+	RevenueByTax   map[string]string `json:"revenue_by_tax,omitempty"`
+	TaxKeys        map[string]string `json:"tax_keys,omitempty"` // Tax key (DATEV BU-Schlüssel) by tax rate
+	CostCenter     string            `json:"cost_center,omitempty"`
+	CurrencyCode   string            `json:"currency_code,omitempty"` // Options.CurrencyCode when empty
+	BookingTextMax int               `json:"booking_text_max,omitempty" default:"60"`
+
+	// DATEV EXTF header informations, see ExportDATEV
+	DATEVConsultant      string `json:"datev_consultant,omitempty"`                    // Beraternummer ex 1001
+	DATEVClient          string `json:"datev_client,omitempty"`                        // Mandantennummer ex 1
+	AccountLength        int    `json:"account_length,omitempty" default:"4"`          // Sachkontenlänge
+	FiscalYearStartMonth int    `json:"fiscal_year_start_month,omitempty" default:"1"` // First month of fiscal year, 1 to 12
+}
+
+// JournalLine define an accounting journal line
+type JournalLine struct {
+	Date           time.Time       `json:"date"`
+	Ref            string          `json:"ref"`
+	Text           string          `json:"text"`
+	Account        string          `json:"account"`
+	CounterAccount string          `json:"counter_account"`
+	TaxKey         string          `json:"tax_key"`
+	DebitCredit    string          `json:"debit_credit"`
+	Net            decimal.Decimal `json:"net"`
+	Tax            decimal.Decimal `json:"tax"`
+	Amount         decimal.Decimal `json:"amount"` // Gross amount, always positive
+	CostCenter     string          `json:"cost_center"`
+	Currency       string          `json:"currency"`
+}
+
+// JournalLines return the document journal lines, one line per tax of the tax breakdown,
+// split by item account and cost center when set
+func (doc *Document) JournalLines(accounts *JournalAccounts) ([]*JournalLine, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := defaults.Set(accounts); err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if len(doc.Date) > 0 {
+		parsed, err := time.Parse(doc.Options.DateFormat, doc.Date)
+		if err != nil {
+			return nil, err
+		}
+		date = parsed
+	}
+
+	text := []rune(doc.Customer.Name)
+	if len(text) > accounts.BookingTextMax {
+		text = text[:accounts.BookingTextMax]
+	}
+
+	currency := accounts.CurrencyCode
+	if len(currency) == 0 {
+		currency = doc.Options.CurrencyCode
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	lines := []*JournalLine{}
+
+	taxLines := doc.taxLines(func(item *Item) string {
+		return taxLineKey(item.Tax) + "\x00" + item.Account + "\x00" + item.CostCenter
+	})
+
+	for _, taxLine := range taxLines {
+		key := taxLineKey(taxLine.Tax)
+
+		counterAccount := accounts.Revenue
+		if account, ok := accounts.RevenueByTax[key]; ok {
+			counterAccount = account
+		}
+		if len(taxLine.item.Account) > 0 {
+			counterAccount = taxLine.item.Account
+		}
+
+		costCenter := accounts.CostCenter
+		if len(taxLine.item.CostCenter) > 0 {
+			costCenter = taxLine.item.CostCenter
+		}
+
+		if len(accounts.Receivable) == 0 || len(counterAccount) == 0 {
+			return nil, ErrMissingAccount
+		}
+
+		net := taxLine.Base.Round(precision)
+		tax := taxLine.Amount.Round(precision)
+		amount := net.Add(tax)
+
+		debitCredit := JournalDebit
+		if amount.IsNegative() {
+			debitCredit = JournalCredit
+		}
+
+		lines = append(lines, &JournalLine{
+			Date:           date,
+			Ref:            doc.Ref,
+			Text:           string(text),
+			Account:        accounts.Receivable,
+			CounterAccount: counterAccount,
+			TaxKey:         accounts.TaxKeys[key],
+			DebitCredit:    debitCredit,
+			Net:            net,
+			Tax:            tax,
+			Amount:         amount.Abs(),
+			CostCenter:     costCenter,
+			Currency:       currency,
+		})
+	}
+
+	return lines, nil
+}
+
+// ExportDATEV write document journal lines as a DATEV booking batch: EXTF header record with consultant,
+// client and fiscal year of accounts, then booking columns (semicolon separated, decimal comma, DDMM document date)
+func (doc *Document) ExportDATEV(w io.Writer, accounts *JournalAccounts) error {
+	lines, err := doc.JournalLines(accounts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, datevHeader(lines, accounts, time.Now())+"\n"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	if err := writer.Write([]string{
+		"Umsatz (ohne Soll/Haben-Kz)",
+		"Soll/Haben-Kennzeichen",
+		"WKZ Umsatz",
+		"Konto",
+		"Gegenkonto (ohne BU-Schlüssel)",
+		"BU-Schlüssel",
+		"Belegdatum",
+		"Belegfeld 1",
+		"Buchungstext",
+		"KOST1 - Kostenstelle",
+	}); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if err := writer.Write([]string{
+			strings.Replace(line.Amount.StringFixed(2), ".", ",", 1),
+			line.DebitCredit,
+			line.Currency,
+			line.Account,
+			line.CounterAccount,
+			line.TaxKey,
+			line.Date.Format("0201"),
+			line.Ref,
+			line.Text,
+			line.CostCenter,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// datevHeader return the EXTF header record of a DATEV booking batch (format 700, category 21 version 13)
+// of lines, all in the fiscal year of the first one
+func datevHeader(lines []*JournalLine, accounts *JournalAccounts, created time.Time) string {
+	from, to, currency := created, created, ""
+	if len(lines) > 0 {
+		from, to, currency = lines[0].Date, lines[0].Date, lines[0].Currency
+	}
+	for _, line := range lines {
+		if line.Date.Before(from) {
+			from = line.Date
+		}
+		if line.Date.After(to) {
+			to = line.Date
+		}
+	}
+
+	// Fiscal year of the batch first date
+	fiscalYearStart := time.Date(from.Year(), time.Month(accounts.FiscalYearStartMonth), 1, 0, 0, 0, 0, time.UTC)
+	if fiscalYearStart.After(from) {
+		fiscalYearStart = fiscalYearStart.AddDate(-1, 0, 0)
+	}
+
+	quote := func(text string) string {
+		return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+	}
+
+	fields := []string{
+		quote("EXTF"),
+		"700",
+		"21",
+		quote("Buchungsstapel"),
+		"13",
+		strings.Replace(created.Format("20060102150405.000"), ".", "", 1),
+		"",
+		quote("RE"),
+		quote(""),
+		quote(""),
+		accounts.DATEVConsultant,
+		accounts.DATEVClient,
+		fiscalYearStart.Format("20060102"),
+		strconv.Itoa(accounts.AccountLength),
+		from.Format("20060102"),
+		to.Format("20060102"),
+		quote("Rechnungen"),
+		quote(""),
+		"1", // Financial accounting
+		"0",
+		"0", // Not locked
+		quote(currency),
+		"",
+		quote(""),
+		"",
+		"",
+		quote(""),
+		"",
+		"",
+		quote(""),
+		quote(""),
+	}
+
+	return strings.Join(fields, ";")
+}
+
+// ExportJournalCSV write document journal lines as generic comma separated values
+func (doc *Document) ExportJournalCSV(w io.Writer, accounts *JournalAccounts) error {
+	lines, err := doc.JournalLines(accounts)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{
+		"date",
+		"ref",
+		"text",
+		"account",
+		"counter_account",
+		"tax_key",
+		"debit_credit",
+		"net",
+		"tax",
+		"amount",
+		"currency",
+		"cost_center",
+	}); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if err := writer.Write([]string{
+			line.Date.Format("2006-01-02"),
+			line.Ref,
+			line.Text,
+			line.Account,
+			line.CounterAccount,
+			line.TaxKey,
+			line.DebitCredit,
+			line.Net.StringFixed(2),
+			line.Tax.StringFixed(2),
+			line.Amount.StringFixed(2),
+			line.Currency,
+			line.CostCenter,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
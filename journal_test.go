@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportDATEV(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-42")
+	doc.SetDate("15/03/2021")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "19"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "7"}})
+
+	buf := &bytes.Buffer{}
+	err := doc.ExportDATEV(buf, &JournalAccounts{
+		Receivable:   "10000",
+		RevenueByTax: map[string]string{"19": "8400", "7": "8300"},
+		TaxKeys:      map[string]string{"19": "3", "7": "2"},
+
+		DATEVConsultant: "1001",
+		DATEVClient:     "7",
+	})
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+
+	header := strings.Split(lines[0], ";")
+	if len(header) != 31 || strings.Join(header[:5], ";") != `"EXTF";700;21;"Buchungsstapel";13` || len(header[5]) != 17 {
+		t.Errorf("unexpected EXTF header %s", lines[0])
+	}
+	if fields := strings.Join(header[10:16], ";"); fields != "1001;7;20210101;4;20210315;20210315" {
+		t.Errorf("unexpected consultant, client, fiscal year and period %s", fields)
+	}
+	if header[21] != `"EUR"` {
+		t.Errorf("unexpected header currency %s", header[21])
+	}
+
+	if lines[2] != "238,00;S;EUR;10000;8400;3;1503;INV-42;Customer;" {
+		t.Errorf("unexpected line %s", lines[2])
+	}
+
+	if lines[3] != "10,70;S;EUR;10000;8300;2;1503;INV-42;Customer;" {
+		t.Errorf("unexpected line %s", lines[3])
+	}
+}
+
+func TestExportDATEVCurrency(t *testing.T) {
+	doc, _ := New(Invoice, &Options{CurrencyCode: "CHF"})
+	doc.SetRef("INV-44")
+	doc.SetDate("15/03/2021")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "8.1"}})
+
+	buf := &bytes.Buffer{}
+	err := doc.ExportDATEV(buf, &JournalAccounts{Receivable: "10000", Revenue: "3400", FiscalYearStartMonth: 7})
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if header := strings.Split(lines[0], ";"); header[12] != "20200701" || header[21] != `"CHF"` {
+		t.Errorf("unexpected fiscal year start or currency %s", lines[0])
+	}
+	if lines[2] != "108,10;S;CHF;10000;3400;;1503;INV-44;Customer;" {
+		t.Errorf("unexpected line %s", lines[2])
+	}
+}
+
+func TestJournalItemAccounts(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-43")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Hosting", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "19"}, Account: "8410", CostCenter: "OPS"})
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "50", Quantity: "1", Tax: &Tax{Percent: "19"}})
+	doc.AppendItem(&Item{Name: "Backup", UnitCost: "20", Quantity: "1", Tax: &Tax{Percent: "19"}, Account: "8410", CostCenter: "OPS"})
+
+	lines, err := doc.JournalLines(&JournalAccounts{Receivable: "10000", Revenue: "8400", CostCenter: "GEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	if lines[0].CounterAccount != "8410" || lines[0].CostCenter != "OPS" || lines[0].Net.String() != "120" {
+		t.Errorf("unexpected line %+v", lines[0])
+	}
+	if lines[1].CounterAccount != "8400" || lines[1].CostCenter != "GEN" || lines[1].Net.String() != "50" {
+		t.Errorf("unexpected line %+v", lines[1])
+	}
+}
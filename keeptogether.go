@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Blocks kept together on a page, see KeepTogether
+const (
+	BlockNotes       string = "notes"
+	BlockPaymentTerm string = "payment_term"
+	BlockBankDetails string = "bank_details"
+)
+
+// KeepTogether define the blocks moved wholesale to the next page instead of being split
+// at a page break. Blocks higher than a page are split anyway.
+type KeepTogether struct {
+	Notes       bool `json:"notes,omitempty"`
+	PaymentTerm bool `json:"payment_term,omitempty"`
+	BankDetails bool `json:"bank_details,omitempty"`
+}
+
+// htmlBreakRegexp match basic html line breaks
+var htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// htmlTagRegexp match basic html tags
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// keepTogether return true when block must not be split, every blocks when Options.KeepTogether is nil
+func (doc *Document) keepTogether(block string) bool {
+	keep := doc.Options.KeepTogether
+	if keep == nil {
+		return true
+	}
+
+	switch block {
+	case BlockNotes:
+		return keep.Notes
+	case BlockPaymentTerm:
+		return keep.PaymentTerm
+	case BlockBankDetails:
+		return keep.BankDetails
+	}
+
+	return false
+}
+
+// textHeight return the estimated height of a basic html text written in width with current font
+func (doc *Document) textHeight(text string, width float64, lineHeight float64) float64 {
+	text = htmlTagRegexp.ReplaceAllString(htmlBreakRegexp.ReplaceAllString(text, "\n"), "")
+	text = strings.TrimRight(text, "\n")
+
+	return float64(len(doc.pdf.SplitLines([]byte(doc.encodeString(text)), width))) * lineHeight
+}
+
+// keepBlockTogether add a page when block of height starting at current position would be split,
+// return true when a page was added
+func (doc *Document) keepBlockTogether(block string, height float64) bool {
+	if !doc.keepTogether(block) || height > MaxPageHeight-BaseMarginTop {
+		return false
+	}
+
+	if doc.pdf.GetY()+height > doc.pageBottom() {
+		doc.pdf.AddPage()
+		return true
+	}
+
+	return false
+}
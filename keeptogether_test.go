@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeepTogether(t *testing.T) {
+	newDocAt := func(keepTogether *KeepTogether) *Document {
+		doc, _ := New(Invoice, &Options{KeepTogether: keepTogether})
+		doc.SetNotes("Thanks for your business.<br>Late payments are subject to interest.<br>" + strings.Repeat("Conditions apply. ", 8))
+		doc.SetBankDetails("<b>IBAN</b> FR76 3000 6000 0112 3456 7890 189<br>BIC AGRIFRPP")
+		doc.pdf.SetFont(doc.Options.Font, "", 12)
+		doc.pdf.AddPage()
+		doc.pdf.SetY(245)
+
+		return doc
+	}
+
+	doc := newDocAt(nil)
+	doc.appendNotes()
+	if doc.pdf.PageNo() != 2 {
+		t.Errorf("expected notes moved to page 2, got page %d", doc.pdf.PageNo())
+	}
+
+	doc = newDocAt(nil)
+	doc.appendBankDetails()
+	if doc.pdf.PageNo() != 2 {
+		t.Errorf("expected bank details moved to page 2, got page %d", doc.pdf.PageNo())
+	}
+
+	doc = newDocAt(&KeepTogether{BankDetails: true})
+	doc.appendNotes()
+	if doc.pdf.PageNo() != 1 {
+		t.Errorf("expected notes started on page 1, got page %d", doc.pdf.PageNo())
+	}
+	if !doc.keepTogether(BlockBankDetails) || doc.keepTogether(BlockPaymentTerm) {
+		t.Error("expected only bank details kept together")
+	}
+
+	// Blocks higher than a page are split
+	doc = newDocAt(nil)
+	if doc.keepBlockTogether(BlockNotes, MaxPageHeight) {
+		t.Error("expected block higher than a page not moved")
+	}
+}
+
+func TestBankDetails(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "100", Quantity: "2"})
+	doc.SetBankDetails("IBAN FR76 3000 6000 0112 3456 7890 189")
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := doc.ShareSafeCopy(&Redaction{BankDetails: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(copied.BankDetails, "7890") {
+		t.Errorf("expected bank details masked, got %s", copied.BankDetails)
+	}
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+)
+
+// ErrTooManyItems when document items exceed Limits.MaxItems
+var ErrTooManyItems = errors.New("too many items")
+
+// ErrTooManyPages when document pages exceed Limits.MaxPages
+var ErrTooManyPages = errors.New("too many pages")
+
+// ErrImageTooLarge when an image exceeds Limits.MaxImageBytes or Limits.MaxImagePixels
+var ErrImageTooLarge = errors.New("image too large")
+
+// ErrOutputTooLarge when generated PDF exceeds Limits.MaxOutputBytes
+var ErrOutputTooLarge = errors.New("output too large")
+
+// Limits define resource limits protecting rendering services from abusive payloads, zero is unlimited
+type Limits struct {
+	MaxItems       int `json:"max_items,omitempty" validate:"min=0"`
+	MaxPages       int `json:"max_pages,omitempty" validate:"min=0"`
+	MaxImageBytes  int `json:"max_image_bytes,omitempty" validate:"min=0"`
+	MaxImagePixels int `json:"max_image_pixels,omitempty" validate:"min=0"` // Width x height, checked before decoding
+	MaxOutputBytes int `json:"max_output_bytes,omitempty" validate:"min=0"`
+}
+
+// checkImageLimits check image size and dimensions
+func (doc *Document) checkImageLimits(data []byte) error {
+	limits := doc.Options.Limits
+	if limits == nil || data == nil {
+		return nil
+	}
+
+	if limits.MaxImageBytes > 0 && len(data) > limits.MaxImageBytes {
+		return ErrImageTooLarge
+	}
+
+	if limits.MaxImagePixels > 0 {
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			// Corrupted images follow Options.MissingImages when drawn
+			if !doc.strictImages() {
+				return nil
+			}
+			return err
+		}
+		if config.Width*config.Height > limits.MaxImagePixels {
+			return ErrImageTooLarge
+		}
+	}
+
+	return nil
+}
+
+// checkLimits check items count and images before preparing document
+func (doc *Document) checkLimits() error {
+	limits := doc.Options.Limits
+	if limits == nil {
+		return nil
+	}
+
+	if limits.MaxItems > 0 && len(doc.Items) > limits.MaxItems {
+		return ErrTooManyItems
+	}
+
+	for _, data := range doc.images() {
+		if err := doc.checkImageLimits(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageLimitReached return true when pages exceed Limits.MaxPages, to stop drawing items
+func (doc *Document) pageLimitReached() bool {
+	limits := doc.Options.Limits
+	return limits != nil && limits.MaxPages > 0 && doc.pdf.PageCount() > limits.MaxPages
+}
+
+// checkPageLimit check pages count
+func (doc *Document) checkPageLimit() error {
+	if doc.pageLimitReached() {
+		return ErrTooManyPages
+	}
+
+	return nil
+}
+
+// Output build the document and write the PDF, checking Limits.MaxOutputBytes.
+// The Factur-X XML is embedded when Options.EInvoice is set.
+func (doc *Document) Output(w io.Writer) error {
+	pdf, err := doc.Build()
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		return err
+	}
+
+	if doc.Options.EInvoice != nil {
+		embedded, err := doc.embedEInvoice(buffer.Bytes())
+		if err != nil {
+			return err
+		}
+		buffer = bytes.NewBuffer(embedded)
+	}
+
+	if err := doc.checkOutputSize(buffer.Len()); err != nil {
+		return err
+	}
+
+	_, err = buffer.WriteTo(w)
+	return err
+}
+
+// checkOutputSize check generated PDF size
+func (doc *Document) checkOutputSize(size int) error {
+	limits := doc.Options.Limits
+	if limits != nil && limits.MaxOutputBytes > 0 && size > limits.MaxOutputBytes {
+		return ErrOutputTooLarge
+	}
+
+	return nil
+}
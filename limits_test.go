@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	newDoc := func(limits *Limits, items int, logo []byte) *Document {
+		doc, _ := New(Invoice, &Options{Limits: limits})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company", Logo: logo})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		for i := 0; i < items; i++ {
+			doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", i), UnitCost: "10", Quantity: "1"})
+		}
+		return doc
+	}
+
+	if err := newDoc(&Limits{MaxItems: 10}, 11, nil).Validate(); err != ErrTooManyItems {
+		t.Errorf("expected %v, got %v", ErrTooManyItems, err)
+	}
+	if _, err := newDoc(&Limits{MaxPages: 2}, 200, nil).Build(); err != ErrTooManyPages {
+		t.Errorf("expected %v, got %v", ErrTooManyPages, err)
+	}
+
+	logo := testPhoto(400, 300)
+	if err := newDoc(&Limits{MaxImageBytes: len(logo) - 1}, 1, logo).Validate(); err != ErrImageTooLarge {
+		t.Errorf("expected %v, got %v", ErrImageTooLarge, err)
+	}
+	if err := newDoc(&Limits{MaxImagePixels: 400*300 - 1}, 1, logo).Validate(); err != ErrImageTooLarge {
+		t.Errorf("expected %v, got %v", ErrImageTooLarge, err)
+	}
+
+	if err := newDoc(&Limits{MaxOutputBytes: 1000}, 1, nil).Output(&bytes.Buffer{}); err != ErrOutputTooLarge {
+		t.Errorf("expected %v, got %v", ErrOutputTooLarge, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := newDoc(&Limits{MaxItems: 10, MaxPages: 2, MaxOutputBytes: 1 << 20}, 10, logo).Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buffer.Bytes(), []byte("%PDF-")) {
+		t.Errorf("expected PDF output")
+	}
+}
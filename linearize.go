@@ -0,0 +1,605 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	pdfReferenceRegexp = regexp.MustCompile(`(\d+) 0 R`)
+	pdfStreamRegexp    = regexp.MustCompile(`(?:>>|\s)stream\r?\n`)
+	pdfContentsRegexp  = regexp.MustCompile(`/Contents (\d+) 0 R`)
+	pdfXrefEntryRegexp = regexp.MustCompile(`^(\d{10}) (\d{5}) ([nf])`)
+)
+
+// OutputLinearized build the document and write it as a linearized PDF (fast web view)
+func (doc *Document) OutputLinearized(w io.Writer) error {
+	pdf, err := doc.Build()
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		return err
+	}
+
+	linearized, err := Linearize(buffer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if err := doc.checkOutputSize(len(linearized)); err != nil {
+		return err
+	}
+
+	_, err = w.Write(linearized)
+	return err
+}
+
+// pdfObjects return the object bodies of a PDF having a single cross-reference table,
+// with root and info object numbers
+func pdfObjects(pdf []byte) (map[int][]byte, int, int, error) {
+	if bytes.Count(pdf, []byte("startxref")) != 1 {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	startXref, err := pdfSubmatchInt(pdfStartXrefRegexp, pdf)
+	if err != nil || startXref >= len(pdf) {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	trailerIndex := bytes.Index(pdf[startXref:], []byte("trailer"))
+	if !bytes.HasPrefix(pdf[startXref:], []byte("xref")) || trailerIndex < 0 {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(pdf[startXref:startXref+trailerIndex])), "\n")
+	if len(lines) < 2 {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	subsection := strings.Fields(lines[1])
+	if len(subsection) != 2 {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+	first, _ := strconv.Atoi(subsection[0])
+	count, _ := strconv.Atoi(subsection[1])
+	if len(lines) != count+2 {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	offsets := map[int]int{}
+	starts := []int{}
+	for i, line := range lines[2:] {
+		entry := pdfXrefEntryRegexp.FindStringSubmatch(line)
+		if entry == nil {
+			return nil, 0, 0, ErrInvalidPDFStructure
+		}
+		if entry[3] == "f" {
+			continue
+		}
+
+		offset, _ := strconv.Atoi(entry[1])
+		if offset >= startXref {
+			return nil, 0, 0, ErrInvalidPDFStructure
+		}
+		offsets[first+i] = offset
+		starts = append(starts, offset)
+	}
+	sort.Ints(starts)
+
+	objects := map[int][]byte{}
+	for number, offset := range offsets {
+		// An object ends where the next one starts
+		end := startXref
+		if next := sort.SearchInts(starts, offset+1); next < len(starts) {
+			end = starts[next]
+		}
+
+		object := pdf[offset:end]
+		header := []byte(fmt.Sprintf("%d 0 obj", number))
+		endIndex := bytes.LastIndex(object, []byte("endobj"))
+		if !bytes.HasPrefix(object, header) || endIndex < 0 {
+			return nil, 0, 0, ErrInvalidPDFStructure
+		}
+
+		objects[number] = bytes.TrimSpace(object[len(header):endIndex])
+	}
+
+	trailer := pdf[startXref+trailerIndex:]
+	rootNumber, err := pdfSubmatchInt(pdfRootRegexp, trailer)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if _, ok := objects[rootNumber]; !ok {
+		return nil, 0, 0, ErrInvalidPDFStructure
+	}
+
+	// Info dictionary is optional
+	infoNumber, _ := pdfSubmatchInt(pdfInfoRegexp, trailer)
+
+	return objects, rootNumber, infoNumber, nil
+}
+
+// pdfDictionaryPart return the part of an object body before its stream data
+func pdfDictionaryPart(body []byte) []byte {
+	if location := pdfStreamRegexp.FindIndex(body); location != nil {
+		return body[:location[0]]
+	}
+
+	return body
+}
+
+// pdfReferences return object numbers referenced by object body, in order of appearance
+func pdfReferences(body []byte) []int {
+	references := []int{}
+	for _, match := range pdfReferenceRegexp.FindAllSubmatch(pdfDictionaryPart(body), -1) {
+		number, _ := strconv.Atoi(string(match[1]))
+		references = append(references, number)
+	}
+
+	return references
+}
+
+// pdfRenumber replace object references of body dictionary following numbers
+func pdfRenumber(body []byte, numbers map[int]int) []byte {
+	dictionary := pdfDictionaryPart(body)
+	renumbered := pdfReferenceRegexp.ReplaceAllFunc(dictionary, func(match []byte) []byte {
+		number, _ := strconv.Atoi(string(match[:bytes.IndexByte(match, ' ')]))
+		return []byte(fmt.Sprintf("%d 0 R", numbers[number]))
+	})
+
+	return append(renumbered, body[len(dictionary):]...)
+}
+
+// pdfBitWriter write hint table values using a fixed number of bits
+type pdfBitWriter struct {
+	buffer bytes.Buffer
+	byte   byte
+	bits   uint
+}
+
+// write append the nbits lowest bits of value
+func (w *pdfBitWriter) write(value int, nbits int) {
+	for i := nbits - 1; i >= 0; i-- {
+		w.byte = w.byte<<1 | byte(value>>uint(i)&1)
+		w.bits++
+		if w.bits == 8 {
+			w.buffer.WriteByte(w.byte)
+			w.byte, w.bits = 0, 0
+		}
+	}
+}
+
+// flush pad the last byte with zero bits
+func (w *pdfBitWriter) flush() {
+	if w.bits > 0 {
+		w.write(0, int(8-w.bits))
+	}
+}
+
+// pdfBitsFor return the number of bits needed to represent value
+func pdfBitsFor(value int) int {
+	bits := 0
+	for value > 0 {
+		bits++
+		value >>= 1
+	}
+
+	return bits
+}
+
+// pdfMinMax return the least and greatest values
+func pdfMinMax(values []int) (int, int) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	least, greatest := values[0], values[0]
+	for _, value := range values[1:] {
+		if value < least {
+			least = value
+		}
+		if value > greatest {
+			greatest = value
+		}
+	}
+
+	return least, greatest
+}
+
+// pdfLinearization define the objects order of a linearized PDF.
+// Object numbers are the new ones: main section objects (remaining pages then shared objects)
+// are numbered first, first page section objects (linearization dictionary, document level
+// objects, hint stream and first page objects) last, as required by the first page cross-reference table.
+type pdfLinearization struct {
+	version    string
+	objects    map[int][]byte
+	pages      [][]int       // Objects of each page section, page object first
+	shared     []int         // Objects shared by pages other than the first one
+	document   []int         // Catalog and objects not used by pages
+	references map[int][]int // Shared objects referenced by each page
+	contents   map[int]int   // Content stream of each page
+	linearized int
+	hint       int
+	root       int
+	info       int
+	mainSize   int
+}
+
+// pdfLinearizationMarks define offsets of a linearized PDF layout
+type pdfLinearizationMarks struct {
+	offsets        map[int]int
+	lengths        map[int]int
+	firstPageXref  int
+	hintOffset     int
+	hintLength     int
+	endOfFirstPage int
+	mainXref       int
+	length         int
+}
+
+// newPDFLinearization order and renumber objects of pdf for linearization
+func newPDFLinearization(pdf []byte) (*pdfLinearization, error) {
+	objects, rootNumber, infoNumber, err := pdfObjects(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	pagesNumber, err := pdfSubmatchInt(pdfPagesRegexp, objects[rootNumber])
+	if err != nil {
+		return nil, err
+	}
+
+	kids := pdfKidsRegexp.FindSubmatch(objects[pagesNumber])
+	if kids == nil {
+		return nil, ErrInvalidPDFStructure
+	}
+
+	pageNumbers := []int{}
+	isPage := map[int]bool{}
+	for _, match := range pdfReferenceRegexp.FindAllSubmatch(kids[1], -1) {
+		number, _ := strconv.Atoi(string(match[1]))
+		if _, ok := objects[number]; !ok {
+			return nil, ErrInvalidPDFStructure
+		}
+		pageNumbers = append(pageNumbers, number)
+		isPage[number] = true
+	}
+	if len(pageNumbers) == 0 {
+		return nil, ErrInvalidPDFStructure
+	}
+
+	// Objects used by each page, without following parent, catalog and links to other pages
+	closures := make([][]int, len(pageNumbers))
+	users := map[int]int{}
+	for i, pageNumber := range pageNumbers {
+		visited := map[int]bool{pageNumber: true}
+		stack := []int{pageNumber}
+		for len(stack) > 0 {
+			number := stack[0]
+			stack = stack[1:]
+			closures[i] = append(closures[i], number)
+			users[number]++
+
+			for _, reference := range pdfReferences(objects[number]) {
+				if _, ok := objects[reference]; !ok || visited[reference] || isPage[reference] ||
+					reference == pagesNumber || reference == rootNumber {
+					continue
+				}
+				visited[reference] = true
+				stack = append(stack, reference)
+			}
+		}
+	}
+
+	inFirstPage := map[int]bool{}
+	for _, number := range closures[0] {
+		inFirstPage[number] = true
+	}
+
+	placed := map[int]bool{}
+	oldPages := make([][]int, len(pageNumbers))
+	oldPages[0] = closures[0]
+	oldShared := []int{}
+	for i, closure := range closures[1:] {
+		for _, number := range closure {
+			if inFirstPage[number] || placed[number] {
+				continue
+			}
+			placed[number] = true
+
+			if users[number] > 1 {
+				oldShared = append(oldShared, number)
+			} else {
+				oldPages[i+1] = append(oldPages[i+1], number)
+			}
+		}
+	}
+
+	oldDocument := []int{rootNumber}
+	others := []int{}
+	for number := range objects {
+		if number != rootNumber && !inFirstPage[number] && !placed[number] {
+			others = append(others, number)
+		}
+	}
+	sort.Ints(others)
+	oldDocument = append(oldDocument, others...)
+
+	// Main section objects are numbered from 1
+	numbers := map[int]int{}
+	next := 1
+	assign := func(list []int) []int {
+		assigned := make([]int, len(list))
+		for i, number := range list {
+			numbers[number] = next
+			assigned[i] = next
+			next++
+		}
+		return assigned
+	}
+
+	l := &pdfLinearization{
+		version:    "1.4",
+		objects:    map[int][]byte{},
+		pages:      make([][]int, len(pageNumbers)),
+		references: map[int][]int{},
+		contents:   map[int]int{},
+	}
+	if header := regexp.MustCompile(`^%PDF-(\d\.\d)`).FindSubmatch(pdf); header != nil && string(header[1]) > l.version {
+		l.version = string(header[1])
+	}
+
+	for i := 1; i < len(oldPages); i++ {
+		l.pages[i] = assign(oldPages[i])
+	}
+	l.shared = assign(oldShared)
+	l.mainSize = next
+
+	l.linearized = next
+	next++
+	l.document = assign(oldDocument)
+	l.hint = next
+	next++
+	l.pages[0] = assign(oldPages[0])
+
+	for old, number := range numbers {
+		l.objects[number] = pdfRenumber(objects[old], numbers)
+	}
+	l.root = numbers[rootNumber]
+	l.info = numbers[infoNumber]
+
+	for i, closure := range closures {
+		page := numbers[pageNumbers[i]]
+		if contents := pdfContentsRegexp.FindSubmatch(pdfDictionaryPart(l.objects[page])); contents != nil {
+			number, _ := strconv.Atoi(string(contents[1]))
+			l.contents[i] = number
+		}
+
+		if i == 0 {
+			continue
+		}
+		for _, old := range closure {
+			if users[old] > 1 {
+				l.references[i] = append(l.references[i], numbers[old])
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// size return the number of entries of the first page cross-reference table, whole file /Size
+func (l *pdfLinearization) size() int {
+	return l.pages[0][len(l.pages[0])-1] + 1
+}
+
+// write lay out the linearized PDF, hint stream is omitted when nil.
+// Dictionaries and cross-reference tables have a fixed width, so offsets don't depend on marks values.
+func (l *pdfLinearization) write(hint []byte, sharedOffset int, marks *pdfLinearizationMarks) ([]byte, *pdfLinearizationMarks) {
+	buffer := &bytes.Buffer{}
+	layout := &pdfLinearizationMarks{offsets: map[int]int{}, lengths: map[int]int{}}
+	if marks == nil {
+		marks = layout
+	}
+
+	writeObject := func(number int, body []byte) {
+		layout.offsets[number] = buffer.Len()
+		fmt.Fprintf(buffer, "%d 0 obj\n%s\nendobj\n", number, body)
+		layout.lengths[number] = buffer.Len() - layout.offsets[number]
+	}
+
+	fmt.Fprintf(buffer, "%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", l.version)
+	writeObject(l.linearized, []byte(fmt.Sprintf(
+		"<< /Linearized 1 /L %010d /H [ %010d %010d ] /O %d /E %010d /N %d /T %010d >>",
+		marks.length, marks.hintOffset, marks.hintLength, l.pages[0][0], marks.endOfFirstPage, len(l.pages),
+		marks.mainXref+len(fmt.Sprintf("xref\n0 %d", l.mainSize)),
+	)))
+
+	// First page cross-reference table, followed by a dummy startxref
+	layout.firstPageXref = buffer.Len()
+	fmt.Fprintf(buffer, "xref\n%d %d\n", l.linearized, l.size()-l.linearized)
+	for number := l.linearized; number < l.size(); number++ {
+		fmt.Fprintf(buffer, "%010d 00000 n \n", marks.offsets[number])
+	}
+	fmt.Fprintf(buffer, "trailer\n<< /Size %d /Root %d 0 R", l.size(), l.root)
+	if l.info > 0 {
+		fmt.Fprintf(buffer, " /Info %d 0 R", l.info)
+	}
+	fmt.Fprintf(buffer, " /Prev %010d >>\nstartxref\n0\n%%%%EOF\n", marks.mainXref)
+
+	for _, number := range l.document {
+		writeObject(number, l.objects[number])
+	}
+
+	if hint != nil {
+		layout.hintOffset = buffer.Len()
+		writeObject(l.hint, append([]byte(fmt.Sprintf("<< /S %d /Length %d >>\nstream\n", sharedOffset, len(hint))),
+			append(hint, []byte("\nendstream")...)...))
+		layout.hintLength = buffer.Len() - layout.hintOffset
+	}
+
+	for _, page := range l.pages {
+		for _, number := range page {
+			writeObject(number, l.objects[number])
+		}
+		if layout.endOfFirstPage == 0 {
+			layout.endOfFirstPage = buffer.Len()
+		}
+	}
+	for _, number := range l.shared {
+		writeObject(number, l.objects[number])
+	}
+
+	// Main cross-reference table, startxref locate the first page one
+	layout.mainXref = buffer.Len()
+	fmt.Fprintf(buffer, "xref\n0 %d\n0000000000 65535 f \n", l.mainSize)
+	for number := 1; number < l.mainSize; number++ {
+		fmt.Fprintf(buffer, "%010d 00000 n \n", marks.offsets[number])
+	}
+	fmt.Fprintf(buffer, "trailer\n<< /Size %d >>\nstartxref\n%d\n%%%%EOF\n", l.mainSize, layout.firstPageXref)
+	layout.length = buffer.Len()
+
+	return buffer.Bytes(), layout
+}
+
+// hintStream return the page offset and shared object hint tables, with the shared one offset.
+// Hint tables offsets are computed as if the hint stream was absent.
+func (l *pdfLinearization) hintStream(layout *pdfLinearizationMarks) ([]byte, int) {
+	objectCounts := []int{}
+	pageLengths := []int{}
+	contentOffsets := []int{}
+	contentLengths := []int{}
+	sharedCounts := []int{}
+	for i, page := range l.pages {
+		start := layout.offsets[page[0]]
+		last := page[len(page)-1]
+
+		objectCounts = append(objectCounts, len(page))
+		pageLengths = append(pageLengths, layout.offsets[last]+layout.lengths[last]-start)
+		sharedCounts = append(sharedCounts, len(l.references[i]))
+
+		if contents, ok := l.contents[i]; ok {
+			contentOffsets = append(contentOffsets, layout.offsets[contents]-start)
+			contentLengths = append(contentLengths, layout.lengths[contents])
+		} else {
+			contentOffsets = append(contentOffsets, 0)
+			contentLengths = append(contentLengths, 0)
+		}
+	}
+
+	// Shared object groups: first page objects, then shared objects section, one object per group
+	groups := append(append([]int{}, l.pages[0]...), l.shared...)
+	identifiers := map[int]int{}
+	groupLengths := []int{}
+	for i, number := range groups {
+		identifiers[number] = i
+		groupLengths = append(groupLengths, layout.lengths[number])
+	}
+
+	leastObjects, greatestObjects := pdfMinMax(objectCounts)
+	leastLength, greatestLength := pdfMinMax(pageLengths)
+	leastContentOffset, greatestContentOffset := pdfMinMax(contentOffsets)
+	leastContentLength, greatestContentLength := pdfMinMax(contentLengths)
+	_, greatestShared := pdfMinMax(sharedCounts)
+	identifierBits := pdfBitsFor(len(groups) - 1)
+
+	w := &pdfBitWriter{}
+	w.write(leastObjects, 32)
+	w.write(layout.offsets[l.pages[0][0]], 32)
+	w.write(pdfBitsFor(greatestObjects-leastObjects), 16)
+	w.write(leastLength, 32)
+	w.write(pdfBitsFor(greatestLength-leastLength), 16)
+	w.write(leastContentOffset, 32)
+	w.write(pdfBitsFor(greatestContentOffset-leastContentOffset), 16)
+	w.write(leastContentLength, 32)
+	w.write(pdfBitsFor(greatestContentLength-leastContentLength), 16)
+	w.write(pdfBitsFor(greatestShared), 16)
+	w.write(identifierBits, 16)
+	w.write(0, 16) // Fractional position numerator bits
+	w.write(1, 16) // Fractional position denominator
+
+	// Per page entries, each item for all pages padded to byte boundary
+	columns := []struct {
+		values []int
+		least  int
+		bits   int
+	}{
+		{objectCounts, leastObjects, pdfBitsFor(greatestObjects - leastObjects)},
+		{pageLengths, leastLength, pdfBitsFor(greatestLength - leastLength)},
+		{sharedCounts, 0, pdfBitsFor(greatestShared)},
+	}
+	for _, column := range columns {
+		for _, value := range column.values {
+			w.write(value-column.least, column.bits)
+		}
+		w.flush()
+	}
+	for i := range l.pages {
+		for _, number := range l.references[i] {
+			w.write(identifiers[number], identifierBits)
+		}
+	}
+	w.flush()
+	for _, value := range contentOffsets {
+		w.write(value-leastContentOffset, pdfBitsFor(greatestContentOffset-leastContentOffset))
+	}
+	w.flush()
+	for _, value := range contentLengths {
+		w.write(value-leastContentLength, pdfBitsFor(greatestContentLength-leastContentLength))
+	}
+	w.flush()
+
+	sharedOffset := w.buffer.Len()
+
+	firstShared, firstSharedOffset := 0, 0
+	if len(l.shared) > 0 {
+		firstShared, firstSharedOffset = l.shared[0], layout.offsets[l.shared[0]]
+	}
+	leastGroup, greatestGroup := pdfMinMax(groupLengths)
+
+	w.write(firstShared, 32)
+	w.write(firstSharedOffset, 32)
+	w.write(len(l.pages[0]), 32)
+	w.write(len(groups), 32)
+	w.write(0, 16) // Objects per group bits, each group is a single object
+	w.write(leastGroup, 32)
+	w.write(pdfBitsFor(greatestGroup-leastGroup), 16)
+	for _, length := range groupLengths {
+		w.write(length-leastGroup, pdfBitsFor(greatestGroup-leastGroup))
+	}
+	w.flush()
+	for range groupLengths {
+		w.write(0, 1) // No MD5 signature
+	}
+	w.flush()
+
+	return w.buffer.Bytes(), sharedOffset
+}
+
+// Linearize rewrite a PDF generated by Build as a linearized PDF, so viewers can display
+// the first page before the whole file is downloaded. Linearize before signing:
+// incrementally updated PDFs return ErrInvalidPDFStructure.
+func Linearize(pdf []byte) ([]byte, error) {
+	l, err := newPDFLinearization(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hint tables use the layout without hint stream
+	_, withoutHint := l.write(nil, 0, nil)
+	hint, sharedOffset := l.hintStream(withoutHint)
+
+	// Lay out once to get offsets, then again with linearization values
+	_, marks := l.write(hint, sharedOffset, nil)
+	linearized, _ := l.write(hint, sharedOffset, marks)
+
+	return linearized, nil
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestLinearize(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	for i := 0; i < 60; i++ {
+		doc.AppendItem(&Item{Name: fmt.Sprintf("Item %d", i), UnitCost: "10", Quantity: "1"})
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := doc.OutputLinearized(buffer); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buffer.Bytes()
+
+	dictionary := regexp.MustCompile(`^%PDF-1\.\d\n%.{4}\n(\d+) 0 obj\n<< /Linearized 1 /L (\d+) /H \[ (\d+) (\d+) \] /O (\d+) /E (\d+) /N (\d+) /T (\d+) >>`).FindSubmatch(pdf)
+	if dictionary == nil {
+		t.Fatalf("expected linearization dictionary, got %q", pdf[:200])
+	}
+	values := make([]int, len(dictionary))
+	for i := 1; i < len(dictionary); i++ {
+		values[i], _ = strconv.Atoi(string(dictionary[i]))
+	}
+	length, hintOffset, firstPage, endOfFirstPage, pages, mainEntries := values[2], values[3], values[5], values[6], values[7], values[8]
+
+	if length != len(pdf) {
+		t.Errorf("expected /L %d, got %d", len(pdf), length)
+	}
+	if pages < 2 || pages != doc.pdf.PageCount() {
+		t.Errorf("expected /N %d, got %d", doc.pdf.PageCount(), pages)
+	}
+	if !regexp.MustCompile(`^\d+ 0 obj\n<< /S \d+ /Length \d+ >>\nstream\n`).Match(pdf[hintOffset:]) {
+		t.Errorf("expected hint stream at /H offset")
+	}
+	if !bytes.HasPrefix(pdf[mainEntries:], []byte("\n0000000000 65535 f")) {
+		t.Errorf("expected /T before main cross-reference table first entry")
+	}
+
+	// Every cross-reference entry locate its object
+	offsets := map[int]int{}
+	for _, table := range regexp.MustCompile(`xref\n(\d+) \d+\n((?:\d{10} \d{5} [nf] \n)+)`).FindAllSubmatch(pdf, -1) {
+		first, _ := strconv.Atoi(string(table[1]))
+		for i, entry := range bytes.Split(bytes.TrimSpace(table[2]), []byte("\n")) {
+			if bytes.HasSuffix(bytes.TrimSpace(entry), []byte("f")) {
+				continue
+			}
+			offsets[first+i], _ = strconv.Atoi(string(entry[:10]))
+		}
+	}
+	for number, offset := range offsets {
+		if !bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj", number))) {
+			t.Errorf("expected object %d at offset %d", number, offset)
+		}
+	}
+
+	if offsets[firstPage] == 0 || offsets[firstPage] > endOfFirstPage {
+		t.Errorf("expected first page object %d before /E", firstPage)
+	}
+	if !bytes.Contains(pdfDictionaryPart(pdf[offsets[firstPage]:]), []byte("/Type /Page")) {
+		t.Errorf("expected /O to be the first page object")
+	}
+
+	startXref, _ := pdfSubmatchInt(pdfStartXrefRegexp, pdf)
+	if !bytes.HasPrefix(pdf[startXref:], []byte(fmt.Sprintf("xref\n%d ", values[1]))) {
+		t.Errorf("expected last startxref to locate the first page cross-reference table")
+	}
+
+	// Signed documents are incrementally updated and can't be linearized
+	request, err := doc.PrepareForSigning(&Signature{Name: "Jane Doe", Size: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := request.EmbedSignature([]byte{0x30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Linearize(signed); err != ErrInvalidPDFStructure {
+		t.Errorf("expected %v, got %v", ErrInvalidPDFStructure, err)
+	}
+}
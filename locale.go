@@ -0,0 +1,382 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnknownLocale when Options.Locale has no registered locale
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locale bundle the number, currency and date conventions of a language and region, and its translations.
+// Options left empty are set from the locale, Options.Translations override locale translations.
+type Locale struct {
+	Code string // ex de-DE
+
+	Decimal        string
+	Thousand       string
+	CurrencyFormat string // Position of currency symbol (%s) and amount (%v), ex "%v %s" for 1.234,50 €
+	DateFormat     string
+	Numerals       string // NumeralsArabic or NumeralsPersian digits of amounts and dates, Western when empty
+
+	// Translations of texts by Options json key, ex text_type_invoice
+	Translations map[string]string
+}
+
+// locales registered by code
+var locales = map[string]*Locale{
+	"en-US": {
+		Code:       "en-US",
+		Decimal:    ".",
+		Thousand:   ",",
+		DateFormat: "01/02/2006",
+	},
+	"en-GB": {
+		Code:       "en-GB",
+		Decimal:    ".",
+		Thousand:   ",",
+		DateFormat: "02/01/2006",
+	},
+	"fr-FR": {
+		Code:           "fr-FR",
+		Decimal:        ",",
+		Thousand:       "\u00a0",
+		CurrencyFormat: "%v\u00a0%s",
+		DateFormat:     "02/01/2006",
+		Translations: map[string]string{
+			"text_type_invoice":          "FACTURE",
+			"text_type_quotation":        "DEVIS",
+			"text_type_delivery_note":    "BON DE LIVRAISON",
+			"text_type_tax_invoice":      "FACTURE",
+			"text_type_reminder":         "RELANCE DE PAIEMENT",
+			"text_type_donation_receipt": "REÇU DE DON",
+			"text_ref_title":             "Réf.",
+			"text_version_title":         "Version",
+			"text_date_title":            "Date",
+			"text_payment_term_title":    "Échéance",
+			"text_bank_details_title":    "Coordonnées bancaires",
+			"text_items_name_title":      "Désignation",
+			"text_items_unit_cost_title": "Prix unitaire",
+			"text_items_quantity_title":  "Qté",
+			"text_items_unit_title":      "Unité",
+			"text_items_total_ht_title":  "Total HT",
+			"text_items_tax_title":       "TVA",
+			"text_items_discount_title":  "Remise",
+			"text_items_total_ttc_title": "Total TTC",
+			"text_items_free_of_charge":  "Gratuit",
+			"text_chart_title":           "Répartition des dépenses",
+			"text_chart_other":           "Autres",
+			"text_total_subtotal":        "TOTAL HT",
+			"text_total_total":           "TOTAL",
+			"text_total_discounted":      "TOTAL REMISÉ",
+			"text_total_tax":             "TVA",
+			"text_total_with_tax":        "TOTAL TTC",
+		},
+	},
+	"de-DE": {
+		Code:           "de-DE",
+		Decimal:        ",",
+		Thousand:       ".",
+		CurrencyFormat: "%v %s",
+		DateFormat:     "02.01.2006",
+		Translations: map[string]string{
+			"text_type_invoice":          "RECHNUNG",
+			"text_type_quotation":        "ANGEBOT",
+			"text_type_delivery_note":    "LIEFERSCHEIN",
+			"text_type_tax_invoice":      "RECHNUNG",
+			"text_type_reminder":         "ZAHLUNGSERINNERUNG",
+			"text_type_donation_receipt": "SPENDENBESCHEINIGUNG",
+			"text_ref_title":             "Nr.",
+			"text_version_title":         "Version",
+			"text_date_title":            "Datum",
+			"text_payment_term_title":    "Zahlungsziel",
+			"text_bank_details_title":    "Bankverbindung",
+			"text_items_name_title":      "Bezeichnung",
+			"text_items_unit_cost_title": "Einzelpreis",
+			"text_items_quantity_title":  "Menge",
+			"text_items_unit_title":      "Einheit",
+			"text_items_total_ht_title":  "Netto",
+			"text_items_tax_title":       "MwSt.",
+			"text_items_discount_title":  "Rabatt",
+			"text_items_total_ttc_title": "Brutto",
+			"text_items_free_of_charge":  "Kostenlos",
+			"text_chart_title":           "Ausgabenverteilung",
+			"text_chart_other":           "Sonstiges",
+			"text_total_subtotal":        "NETTO",
+			"text_total_total":           "GESAMT",
+			"text_total_discounted":      "NETTO NACH RABATT",
+			"text_total_tax":             "MWST.",
+			"text_total_with_tax":        "GESAMT BRUTTO",
+		},
+	},
+	"es-ES": {
+		Code:           "es-ES",
+		Decimal:        ",",
+		Thousand:       ".",
+		CurrencyFormat: "%v %s",
+		DateFormat:     "02/01/2006",
+		Translations: map[string]string{
+			"text_type_invoice":          "FACTURA",
+			"text_type_quotation":        "PRESUPUESTO",
+			"text_type_delivery_note":    "ALBARÁN",
+			"text_type_tax_invoice":      "FACTURA",
+			"text_type_reminder":         "RECORDATORIO DE PAGO",
+			"text_type_donation_receipt": "CERTIFICADO DE DONACIÓN",
+			"text_ref_title":             "Ref.",
+			"text_version_title":         "Versión",
+			"text_date_title":            "Fecha",
+			"text_payment_term_title":    "Vencimiento",
+			"text_bank_details_title":    "Datos bancarios",
+			"text_items_name_title":      "Concepto",
+			"text_items_unit_cost_title": "Precio unitario",
+			"text_items_quantity_title":  "Cant.",
+			"text_items_unit_title":      "Unidad",
+			"text_items_total_ht_title":  "Base imponible",
+			"text_items_tax_title":       "IVA",
+			"text_items_discount_title":  "Descuento",
+			"text_items_total_ttc_title": "Total",
+			"text_items_free_of_charge":  "Gratuito",
+			"text_chart_title":           "Desglose de gastos",
+			"text_chart_other":           "Otros",
+			"text_total_subtotal":        "BASE IMPONIBLE",
+			"text_total_total":           "TOTAL",
+			"text_total_discounted":      "TOTAL CON DESCUENTO",
+			"text_total_tax":             "IVA",
+			"text_total_with_tax":        "TOTAL CON IVA",
+		},
+	},
+	"ar-SA": {
+		Code:           "ar-SA",
+		Decimal:        "\u066b",
+		Thousand:       "\u066c",
+		CurrencyFormat: "%v\u00a0%s",
+		DateFormat:     "02/01/2006",
+		Numerals:       NumeralsArabic,
+	},
+	"fa-IR": {
+		Code:           "fa-IR",
+		Decimal:        "\u066b",
+		Thousand:       "\u066c",
+		CurrencyFormat: "%v\u00a0%s",
+		DateFormat:     "2006/01/02",
+		Numerals:       NumeralsPersian,
+	},
+}
+
+// RegisterLocale register or replace the locale of locale.Code
+func RegisterLocale(locale *Locale) {
+	locales[locale.Code] = locale
+}
+
+// translatableTexts return the texts which can be translated, by Options json key
+func (o *Options) translatableTexts() map[string]*string {
+	return map[string]*string{
+		"text_type_invoice":              &o.TextTypeInvoice,
+		"text_type_quotation":            &o.TextTypeQuotation,
+		"text_type_delivery_note":        &o.TextTypeDeliveryNote,
+		"text_type_tax_invoice":          &o.TextTypeTaxInvoice,
+		"text_type_reminder":             &o.TextTypeReminder,
+		"text_type_donation_receipt":     &o.TextTypeDonationReceipt,
+		"text_type_settlement_statement": &o.TextTypeSettlementStatement,
+		"text_ref_title":                 &o.TextRefTitle,
+		"text_version_title":             &o.TextVersionTitle,
+		"text_date_title":                &o.TextDateTitle,
+		"text_payment_term_title":        &o.TextPaymentTermTitle,
+		"text_bank_details_title":        &o.TextBankDetailsTitle,
+		"text_items_name_title":          &o.TextItemsNameTitle,
+		"text_items_unit_cost_title":     &o.TextItemsUnitCostTitle,
+		"text_items_quantity_title":      &o.TextItemsQuantityTitle,
+		"text_items_unit_title":          &o.TextItemsUnitTitle,
+		"text_items_total_ht_title":      &o.TextItemsTotalHTTitle,
+		"text_items_tax_title":           &o.TextItemsTaxTitle,
+		"text_items_discount_title":      &o.TextItemsDiscountTitle,
+		"text_items_total_ttc_title":     &o.TextItemsTotalTTCTitle,
+		"text_items_free_of_charge":      &o.TextItemsFreeOfCharge,
+		"text_chart_title":               &o.TextChartTitle,
+		"text_chart_other":               &o.TextChartOther,
+		"text_total_subtotal":            &o.TextTotalSubtotal,
+		"text_total_total":               &o.TextTotalTotal,
+		"text_total_discounted":          &o.TextTotalDiscounted,
+		"text_total_tax":                 &o.TextTotalTax,
+		"text_total_with_tax":            &o.TextTotalWithTax,
+		"text_cover_letter_subject":      &o.TextCoverLetterSubject,
+		"text_cover_letter_greeting":     &o.TextCoverLetterGreeting,
+		"text_cover_letter_body":         &o.TextCoverLetterBody,
+		"text_cover_letter_closing":      &o.TextCoverLetterClosing,
+		"text_email_subject":             &o.TextEmailSubject,
+		"text_email_body":                &o.TextEmailBody,
+		"text_email_due":                 &o.TextEmailDue,
+		"text_portal_title":              &o.TextPortalTitle,
+		"text_payment_reference_title":   &o.TextPaymentReferenceTitle,
+	}
+}
+
+// setLocalized set value to localized when empty
+func setLocalized(value *string, localized string) {
+	if len(*value) == 0 {
+		*value = localized
+	}
+}
+
+// applyNumberLocale set empty separators, currency symbol and format from Options.NumberLocale, before Options.Locale
+func (o *Options) applyNumberLocale() error {
+	if len(o.NumberLocale) == 0 {
+		return nil
+	}
+
+	locale, ok := locales[o.NumberLocale]
+	if !ok {
+		return ErrUnknownLocale
+	}
+
+	o.applyNumberFormat(locale)
+
+	return nil
+}
+
+// applyNumberFormat set empty separators, currency symbol and format from locale
+func (o *Options) applyNumberFormat(locale *Locale) {
+	setLocalized(&o.CurrencyDecimal, locale.Decimal)
+	setLocalized(&o.CurrencyThousand, locale.Thousand)
+	setLocalized(&o.CurrencyFormat, locale.CurrencyFormat)
+
+	// Symbol of Options.CurrencyCode, default currency when empty
+	code := o.CurrencyCode
+	if len(code) == 0 {
+		code = "EUR"
+	}
+	if info, ok := accounting.LocaleInfo[code]; ok {
+		setLocalized(&o.CurrencySymbol, strings.TrimSpace(info.ComSymbol))
+	}
+}
+
+// applyLocale set empty separators, currency symbol and format, date format and texts from Options.Locale,
+// before defaults
+func (o *Options) applyLocale() error {
+	if err := o.applyNumberLocale(); err != nil {
+		return err
+	}
+
+	if len(o.Locale) == 0 {
+		return nil
+	}
+
+	locale, ok := locales[o.Locale]
+	if !ok {
+		return ErrUnknownLocale
+	}
+
+	o.applyNumberFormat(locale)
+	setLocalized(&o.DateFormat, locale.DateFormat)
+	if o.Numerals == nil && len(locale.Numerals) > 0 {
+		o.Numerals = &Numerals{System: locale.Numerals}
+	}
+
+	texts := o.translatableTexts()
+	for key, translation := range locale.Translations {
+		if text, ok := texts[key]; ok {
+			setLocalized(text, translation)
+		}
+	}
+
+	return nil
+}
+
+// applyTranslations override texts with Options.Translations, unknown keys are ignored
+func (o *Options) applyTranslations() {
+	if len(o.Translations) == 0 {
+		return
+	}
+
+	texts := o.translatableTexts()
+	for key, translation := range o.Translations {
+		if text, ok := texts[key]; ok {
+			*text = translation
+		}
+	}
+}
+
+// formatsAmounts return true when amounts are formatted with currency symbol and separators: with
+// Options.Locale, Options.NumberLocale or Options.FormatAmounts
+func (doc *Document) formatsAmounts() bool {
+	return len(doc.Options.Locale) > 0 || len(doc.Options.NumberLocale) > 0 || doc.Options.FormatAmounts
+}
+
+// formatAmount return amount with currency symbol and separators of Options.Locale,
+// as is when amounts aren't formatted or when it isn't a number, with the digits of Options.Numerals
+func (doc *Document) formatAmount(amount string) string {
+	if !doc.formatsAmounts() {
+		return doc.amountDigits(amount)
+	}
+
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return doc.amountDigits(amount)
+	}
+
+	// Keep unit prices more precise than the currency
+	ac := doc.ac
+	if precision := int(-value.Exponent()); precision > ac.Precision {
+		ac.Precision = precision
+	}
+
+	return doc.amountDigits(ac.FormatMoneyDecimal(value))
+}
+
+// totalText return the item total formatted with Options.Locale, computed when empty
+func (i *Item) totalText(doc *Document) string {
+	if doc.formatsAmounts() && len(i.Total) == 0 {
+		return doc.formatMoney(i.TotalWithoutTaxAndWithDiscount())
+	}
+
+	return doc.formatAmount(i.Total)
+}
+
+// formatPercent return percent followed by %, with the decimal separator of Options.Locale
+// and a non-breaking space
+func (doc *Document) formatPercent(percent string) string {
+	if doc.formatsAmounts() {
+		return doc.amountDigits(strings.Replace(percent, ".", doc.Options.CurrencyDecimal, 1)) + "\u00a0%"
+	}
+
+	return doc.amountDigits(percent) + " %"
+}
+
+// taxRateText return the percent rates of document taxes, ex 20 %, 5.5 %
+func (doc *Document) taxRateText() string {
+	rates := []string{}
+	for _, line := range doc.TaxLines() {
+		if line.Type == TaxTypePercent {
+			rates = append(rates, doc.formatPercent(line.Rate.String()))
+		}
+	}
+
+	return strings.Join(rates, ", ")
+}
+
+// totalsTexts return subtotal, tax rate, tax and total of totals block: Custom values when set,
+// computed and formatted with Options.Locale otherwise, or always for split bills
+func (doc *Document) totalsTexts() (string, string, string, string) {
+	subtotal, taxRate, tax, total := doc.CustomSubtotal, doc.CustomTaxRate, doc.CustomTax, doc.CustomTotal
+	if !doc.formatsAmounts() && !doc.splitBill {
+		return doc.amountDigits(subtotal), doc.amountDigits(taxRate), doc.amountDigits(tax), doc.amountDigits(total)
+	}
+
+	if len(subtotal) == 0 {
+		subtotal = doc.formatMoney(doc.TotalWithoutTax())
+	}
+	if len(taxRate) == 0 {
+		taxRate = doc.taxRateText()
+	}
+	if len(tax) == 0 {
+		tax = doc.formatMoney(doc.Tax())
+	}
+	if len(total) == 0 {
+		total = doc.formatMoney(doc.TotalWithTax())
+	}
+
+	return doc.amountDigits(subtotal), taxRate, doc.amountDigits(tax), doc.amountDigits(total)
+}
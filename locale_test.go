@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	doc, err := New(Invoice, &Options{
+		Locale:           "de-DE",
+		TextTypeInvoice:  "SCHLUSSRECHNUNG",
+		Translations:     map[string]string{"text_total_tax": "USt.", "text_unknown": "ignored"},
+		CurrencyThousand: "'",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := doc.Options
+	if options.TextTypeInvoice != "SCHLUSSRECHNUNG" || options.TextTotalTax != "USt." || options.TextDateTitle != "Datum" {
+		t.Errorf("unexpected texts %s %s %s", options.TextTypeInvoice, options.TextTotalTax, options.TextDateTitle)
+	}
+	if options.DateFormat != "02.01.2006" || options.CurrencySymbol != "€" || options.CurrencyDecimal != "," {
+		t.Errorf("unexpected formats %s %s %s", options.DateFormat, options.CurrencySymbol, options.CurrencyDecimal)
+	}
+
+	for amount, expected := range map[string]string{
+		"1234.5":  "1'234,50 €",
+		"10.333":  "10,333 €",
+		"-3":      "-3,00 €",
+		"unknown": "unknown",
+	} {
+		if formatted := doc.formatAmount(amount); formatted != expected {
+			t.Errorf("expected %s formatted as %q, got %q", amount, expected, formatted)
+		}
+	}
+
+	doc.SetRef("RE-1")
+	doc.SetDate("15.03.2024")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Hauptstraße"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Beratung", UnitCost: "1000", Quantity: "2", Tax: &Tax{Percent: "19"}})
+	doc.AppendItem(&Item{Name: "Bücher", UnitCost: "12.5", Quantity: "1", Tax: &Tax{Percent: "7.5"}})
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	subtotal, taxRate, tax, total := doc.totalsTexts()
+	if subtotal != "2'012,50 €" || taxRate != "19\u00a0%, 7,5\u00a0%" || tax != "380,94 €" || total != "2'393,44 €" {
+		t.Errorf("unexpected totals %s, %s, %s, %s", subtotal, taxRate, tax, total)
+	}
+	if text, _ := doc.Items[0].columnText(ItemColumnTotal, doc); text != "2'000,00 €" {
+		t.Errorf("expected localized item total, got %s", text)
+	}
+}
+
+func TestLocaleDefault(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	if doc.formatAmount("1234.5") != "1234.5" || doc.formatPercent("5.5") != "5.5 %" || doc.ac.FormatMoneyDecimal(doc.TotalWithTax()) != "€ 0.00" {
+		t.Error("expected amounts as is without locale")
+	}
+
+	if _, err := New(Invoice, &Options{Locale: "xx-XX"}); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("expected ErrUnknownLocale, got %v", err)
+	}
+
+	RegisterLocale(&Locale{Code: "de-CH", Decimal: ".", Thousand: "'", CurrencyFormat: "%s %v"})
+	doc, _ = New(Invoice, &Options{Locale: "de-CH", CurrencyCode: "CHF"})
+	if formatted := doc.formatAmount("1234.5"); formatted != "CHF 1'234.50" {
+		t.Errorf("expected registered locale, got %s", formatted)
+	}
+}
+
+func TestNumberLocale(t *testing.T) {
+	doc, err := New(Invoice, &Options{NumberLocale: "de-DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := doc.Options
+	if options.TextTypeInvoice != "INVOICE" || options.DateFormat != "02/01/2006" {
+		t.Errorf("expected english texts and default date format, got %s %s", options.TextTypeInvoice, options.DateFormat)
+	}
+	if formatted := doc.formatAmount("1234.5"); formatted != "1.234,50 €" {
+		t.Errorf("expected continental amount, got %s", formatted)
+	}
+	if percent := doc.formatPercent("7.5"); percent != "7,5\u00a0%" {
+		t.Errorf("expected continental percent, got %s", percent)
+	}
+
+	// Number locale wins over locale, explicit separators over both
+	doc, _ = New(Invoice, &Options{Locale: "en-GB", NumberLocale: "fr-FR", CurrencyThousand: "."})
+	if formatted := doc.formatAmount("1234.5"); formatted != "1.234,50 €" || doc.Options.DateFormat != "02/01/2006" {
+		t.Errorf("expected french amount with dot thousand separator, got %s", formatted)
+	}
+
+	doc, _ = New(Invoice, &Options{FormatAmounts: true, CurrencySymbol: "CHF", CurrencyThousand: "'", CurrencyFormat: "%s %v"})
+	if formatted := doc.formatAmount("1234.5"); formatted != "CHF 1'234.50" {
+		t.Errorf("expected amount formatted with currency options, got %s", formatted)
+	}
+
+	if _, err := New(Invoice, &Options{NumberLocale: "xx-XX"}); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("expected ErrUnknownLocale, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTimestampSize is the number of bytes reserved for a RFC 3161 timestamp token, see PrepareForTimestamp
+const DefaultTimestampSize int = 8192
+
+// pdfDSSRegexp match the document security store reference of a catalog
+var pdfDSSRegexp = regexp.MustCompile(`/DSS \d+ 0 R`)
+
+// DSS define the validation data of a signed document (PAdES LTV): DER encoded certificates of the chains,
+// OCSP responses and CRLs, fetched by the caller
+type DSS struct {
+	Certs [][]byte
+	OCSPs [][]byte
+	CRLs  [][]byte
+}
+
+// AddDSS add a document security store with dss validation data to a signed pdf, as an incremental update,
+// signatures stay valid. A previous store is replaced, dss must hold all validation data.
+func AddDSS(pdf []byte, dss *DSS) ([]byte, error) {
+	trailer, err := readPDFTrailer(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := pdfObject(pdf, trailer.root)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []pdfUpdateObject{}
+	number := trailer.size
+
+	// One stream per certificate, OCSP response and CRL
+	dictionary := "<</Type /DSS"
+	for _, entry := range []struct {
+		key    string
+		values [][]byte
+	}{
+		{"Certs", dss.Certs},
+		{"OCSPs", dss.OCSPs},
+		{"CRLs", dss.CRLs},
+	} {
+		if len(entry.values) == 0 {
+			continue
+		}
+
+		refs := []string{}
+		for _, value := range entry.values {
+			objects = append(objects, pdfUpdateObject{number, fmt.Sprintf("<</Length %d>>\nstream\n%s\nendstream", len(value), value)})
+			refs = append(refs, fmt.Sprintf("%d 0 R", number))
+			number++
+		}
+		dictionary += fmt.Sprintf(" /%s [%s]", entry.key, strings.Join(refs, " "))
+	}
+	dictionary += ">>"
+
+	dssRef := fmt.Sprintf("/DSS %d 0 R", number)
+	if pdfDSSRegexp.MatchString(catalog) {
+		catalog = pdfDSSRegexp.ReplaceAllLiteralString(catalog, dssRef)
+	} else {
+		catalog = pdfDictionaryAppend(catalog, dssRef)
+	}
+
+	objects = append(objects, pdfUpdateObject{number, dictionary}, pdfUpdateObject{trailer.root, catalog})
+
+	return appendPDFUpdate(pdf, trailer, objects, number+1), nil
+}
+
+// PrepareForTimestamp add a document timestamp field (PAdES LTA) to a signed pdf, with a placeholder of size
+// bytes, DefaultTimestampSize when 0, as an incremental update. The request digest must be timestamped by a
+// RFC 3161 timestamp authority, then the token embedded with EmbedSignature.
+func PrepareForTimestamp(pdf []byte, size int) (*SigningRequest, error) {
+	if size == 0 {
+		size = DefaultTimestampSize
+	}
+
+	dictionary := fmt.Sprintf(
+		"<</Type /DocTimeStamp /Filter /Adobe.PPKLite /SubFilter /%s\n%s\n/Contents <%s>>>",
+		SignatureSubFilterRFC3161,
+		byteRangePlaceholder,
+		strings.Repeat("0", size*2),
+	)
+
+	// Invisible field on first page
+	return prepareSignatureField(pdf, dictionary, size, 1, [4]float64{})
+}
+
+// Timestamp prepare pdf for a document timestamp, call timestamp with the request and embed the returned
+// RFC 3161 token. Validation data of signatures is added first with AddDSS for long term validation.
+func Timestamp(pdf []byte, size int, timestamp func(request *SigningRequest) ([]byte, error)) ([]byte, error) {
+	request, err := PrepareForTimestamp(pdf, size)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := timestamp(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request.EmbedSignature(token)
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestLongTermValidation(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	signed, err := doc.Sign(&Signature{Name: "Jane Doe", SubFilter: SignatureSubFilterCAdES, Size: 64}, func(request *SigningRequest) ([]byte, error) {
+		return []byte{0x30, 0x82, 0x01, 0x02}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Validation data
+	withDSS, err := AddDSS(signed, &DSS{
+		Certs: [][]byte{[]byte("signer certificate"), []byte("ca certificate")},
+		OCSPs: [][]byte{[]byte("ocsp response")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(withDSS, signed) {
+		t.Fatal("expected signed document kept as is")
+	}
+
+	trailer, err := readPDFTrailer(withDSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, _ := pdfObject(withDSS, trailer.root)
+	dssRef := fmt.Sprintf("/DSS %d 0 R", trailer.size-1)
+	if !bytes.Contains([]byte(catalog), []byte(dssRef)) {
+		t.Errorf("expected %s in catalog %s", dssRef, catalog)
+	}
+	dss, _ := pdfObject(withDSS, trailer.size-1)
+	expected := fmt.Sprintf("<</Type /DSS /Certs [%d 0 R %d 0 R] /OCSPs [%d 0 R]>>", trailer.size-4, trailer.size-3, trailer.size-2)
+	if dss != expected {
+		t.Errorf("unexpected DSS %s", dss)
+	}
+	if !bytes.Contains(withDSS, []byte("<</Length 14>>\nstream\nca certificate\nendstream")) {
+		t.Error("expected certificate stream")
+	}
+
+	// Replaced by a new store
+	updated, err := AddDSS(withDSS, &DSS{CRLs: [][]byte{[]byte("crl")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedTrailer, _ := readPDFTrailer(updated)
+	if catalog, _ := pdfObject(updated, updatedTrailer.root); bytes.Contains([]byte(catalog), []byte(dssRef)) ||
+		!bytes.Contains([]byte(catalog), []byte(fmt.Sprintf("/DSS %d 0 R", updatedTrailer.size-1))) {
+		t.Errorf("expected DSS replaced in catalog %s", catalog)
+	}
+
+	// Document timestamp
+	request, err := PrepareForTimestamp(withDSS, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(request.pdf, []byte("<</Type /DocTimeStamp /Filter /Adobe.PPKLite /SubFilter /ETSI.RFC3161\n")) {
+		t.Error("expected document timestamp dictionary")
+	}
+	digest := sha256.Sum256(request.SignedContent())
+	if !bytes.Equal(digest[:], request.Digest) || !bytes.HasPrefix(request.SignedContent(), withDSS) {
+		t.Error("expected digest of the document with its signature and validation data")
+	}
+
+	timestampTrailer, _ := readPDFTrailer(request.pdf)
+	catalog, _ = pdfObject(request.pdf, timestampTrailer.root)
+	fields := fmt.Sprintf("/AcroForm <</Fields [%d 0 R %d 0 R]", timestampTrailer.size-1, trailer.size-5)
+	if !bytes.Contains([]byte(catalog), []byte(fields)) || !bytes.Contains([]byte(catalog), []byte(dssRef)) {
+		t.Errorf("expected signature and timestamp fields %s and DSS in catalog %s", fields, catalog)
+	}
+
+	timestamped, err := Timestamp(withDSS, 32, func(request *SigningRequest) ([]byte, error) {
+		return []byte{0x30, 0x82, 0x03, 0x04}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(timestamped, []byte("/Contents <30820304")) {
+		t.Error("expected embedded timestamp token")
+	}
+}
@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrInvalidEnvelope when envelope format is unknown
+var ErrInvalidEnvelope = errors.New("invalid envelope format")
+
+// Envelope and label formats, see Document.BuildEnvelope
+const (
+	EnvelopeDL    string = "DL"    // 220 x 110 mm
+	EnvelopeC6    string = "C6"    // 162 x 114 mm
+	EnvelopeC5    string = "C5"    // 229 x 162 mm
+	EnvelopeC4    string = "C4"    // 324 x 229 mm
+	EnvelopeLabel string = "label" // 99.1 x 38.1 mm address label
+)
+
+// envelopeSizes in millimeters, width first
+var envelopeSizes = map[string]fpdf.SizeType{
+	EnvelopeDL:    {Wd: 220, Ht: 110},
+	EnvelopeC6:    {Wd: 162, Ht: 114},
+	EnvelopeC5:    {Wd: 229, Ht: 162},
+	EnvelopeC4:    {Wd: 324, Ht: 229},
+	EnvelopeLabel: {Wd: 99.1, Ht: 38.1},
+}
+
+// CoverLetter define the letter mailed with the document. Texts hold merge fields replaced with the
+// document ones, ex {ref} or {total}, see Document.MergeFields.
+type CoverLetter struct {
+	Subject   string `json:"subject,omitempty"`   // Options.TextCoverLetterSubject when empty
+	Greeting  string `json:"greeting,omitempty"`  // Options.TextCoverLetterGreeting when empty
+	Body      string `json:"body,omitempty"`      // Options.TextCoverLetterBody when empty
+	Closing   string `json:"closing,omitempty"`   // Options.TextCoverLetterClosing when empty
+	Signature string `json:"signature,omitempty"` // Company name when empty
+}
+
+// MergeFields return the document fields of cover letters texts and portal URLs, by name.
+// {number} is an alias of {ref}.
+func (doc *Document) MergeFields() map[string]string {
+	date := time.Now().Format(doc.Options.DateFormat)
+	if len(doc.Date) > 0 {
+		date = doc.Date
+	}
+
+	total := doc.CustomTotal
+	if len(total) == 0 {
+		total = doc.ac.FormatMoneyDecimal(doc.TotalWithTax())
+	}
+
+	fields := map[string]string{
+		"type":         doc.typeAsString(),
+		"ref":          doc.Ref,
+		"number":       doc.Ref,
+		"version":      doc.Version,
+		"date":         date,
+		"total":        total,
+		"payment_term": doc.PaymentTerm,
+	}
+	if reference, err := doc.PaymentReference(); err == nil {
+		fields["payment_reference"] = reference
+	}
+	if debit := doc.DirectDebit; debit != nil {
+		fields["mandate_ref"] = debit.MandateRef
+		fields["creditor_id"] = debit.CreditorID
+		fields["debit_date"] = debit.DebitDate
+	}
+	if doc.Company != nil {
+		fields["company"] = doc.Company.Name
+	}
+	if doc.Customer != nil {
+		fields["customer"] = doc.Customer.Name
+	}
+
+	return fields
+}
+
+// merge replace merge fields of text, unknown fields are kept as is
+func (doc *Document) merge(text string) string {
+	return mergeFields(text, doc.MergeFields())
+}
+
+// mergeFields replace fields of text by their value, unknown fields are kept as is
+func mergeFields(text string, fields map[string]string) string {
+	replacements := []string{}
+	for name, value := range fields {
+		replacements = append(replacements, fmt.Sprintf("{%s}", name), value)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+// mailingCopy return a copy of document drawn on a new pdf of size.
+// Custom fonts must be registered again on its pdf, like bills ones.
+func (doc *Document) mailingCopy(size fpdf.SizeType) *Document {
+	copied := *doc
+	copied.pdf = fpdf.NewCustom(&fpdf.InitType{OrientationStr: "P", UnitStr: "mm", Size: size})
+	copied.encoded = nil
+	copied.headerFunc = nil
+	copied.footerFunc = nil
+	copied.shifted = false
+
+	copied.pdf.SetCompression(!doc.Options.DisableCompression)
+	copied.applyBranding()
+	copied.pdf.SetAutoPageBreak(false, 0)
+	copied.pdf.SetTextColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+
+	return &copied
+}
+
+// BuildCoverLetter build the cover letter of document, contacts and references laid out like on the document
+// so that both fit the same windowed envelope and print layout
+func (doc *Document) BuildCoverLetter() (*fpdf.Fpdf, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	letter := doc.mailingCopy(fpdf.SizeType{Wd: 210, Ht: 297})
+	letter.pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
+
+	// Fold marks and gutter of print layout
+	letter.applyPrintLayout()
+	letter.pdf.AddPage()
+	letter.registerFallbackFonts()
+
+	letter.appendMetas()
+	letter.appendPartiesSection()
+
+	coverLetter := doc.CoverLetter
+	if coverLetter == nil {
+		coverLetter = &CoverLetter{}
+	}
+
+	text := func(value string, defaultValue string) string {
+		if len(value) == 0 {
+			value = defaultValue
+		}
+		return letter.encodeString(letter.wrapText(letter.merge(value), 190))
+	}
+
+	letter.pdf.SetXY(BaseMargin, letter.pdf.GetY()+15)
+	letter.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+	letter.pdf.MultiCell(190, 5, text(coverLetter.Subject, doc.Options.TextCoverLetterSubject), "0", "L", false)
+
+	// Greeting, body and closing paragraphs
+	letter.pdf.SetFont(doc.Options.Font, "", 10)
+	for _, paragraph := range []string{
+		text(coverLetter.Greeting, doc.Options.TextCoverLetterGreeting),
+		text(coverLetter.Body, doc.Options.TextCoverLetterBody),
+		text(coverLetter.Closing, doc.Options.TextCoverLetterClosing),
+	} {
+		letter.pdf.SetXY(BaseMargin, letter.pdf.GetY()+5)
+		letter.pdf.MultiCell(190, 5, paragraph, "0", "L", false)
+	}
+
+	letter.pdf.SetXY(BaseMargin, letter.pdf.GetY()+10)
+	letter.pdf.MultiCell(190, 5, text(coverLetter.Signature, doc.Company.Name), "0", "L", false)
+
+	letter.endPageShift()
+
+	return letter.pdf, letter.pdf.Error()
+}
+
+// BuildEnvelope build the envelope or address label of format addressed to document customer: company as
+// sender, document ref below it, postal barcode below the address and frank mark at the top right
+func (doc *Document) BuildEnvelope(format string) (*fpdf.Fpdf, error) {
+	size, ok := envelopeSizes[format]
+	if !ok {
+		return nil, ErrInvalidEnvelope
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	envelope := doc.mailingCopy(size)
+	envelope.pdf.SetMargins(0, 0, 0)
+	envelope.pdf.AddPage()
+	envelope.registerFallbackFonts()
+
+	// Labels only hold the sender line above the recipient
+	margin, addressX, addressY := 10.0, size.Wd*0.45, size.Ht*0.45
+	if format == EnvelopeLabel {
+		margin, addressX, addressY = 4, 8, 9
+	}
+
+	// Sender and references on a single line each
+	envelope.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	sender := doc.Company.Name
+	if doc.Company.Address != nil {
+		sender += ", " + strings.ReplaceAll(doc.Company.Address.ToString(), "\n", ", ")
+	}
+	envelope.pdf.SetXY(margin, margin)
+	envelope.pdf.CellFormat(size.Wd-2*margin, 3, envelope.encodeString(sender), "0", 0, "L", false, 0, "")
+	if format != EnvelopeLabel {
+		envelope.pdf.SetXY(margin, margin+3)
+		envelope.pdf.CellFormat(size.Wd-2*margin, 3, envelope.encodeString(fmt.Sprintf("%s: %s", doc.Options.TextRefTitle, doc.Ref)), "0", 0, "L", false, 0, "")
+	}
+
+	// Recipient
+	width := size.Wd - addressX - margin
+	envelope.pdf.SetXY(addressX, addressY)
+	envelope.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+	envelope.pdf.MultiCell(width, 5, envelope.encodeString(doc.Customer.Name), "0", "L", false)
+	if doc.Customer.Address != nil {
+		envelope.pdf.SetXY(addressX, envelope.pdf.GetY())
+		envelope.pdf.SetFont(doc.Options.Font, "", 10)
+		envelope.pdf.MultiCell(width, 5, envelope.encodeString(doc.Customer.addressString(doc)), "0", "L", false)
+	}
+
+	if postal := doc.PostalBarcode; postal != nil {
+		switch postal.Type {
+		case PostalBarcodeIMb:
+			// Validated on document validation
+			bars, _ := imbBars(postal.TrackingCode, postal.RoutingCode)
+			envelope.drawIMb(bars, addressX, envelope.pdf.GetY()+imbQuietZone, 3*imbTrackerHeight)
+		case PostalBarcodeDatamatrix:
+			envelope.drawFrankMarkPlaceholder(size.Wd-margin-frankMarkSize, margin)
+		}
+	}
+
+	return envelope.pdf, envelope.pdf.Error()
+}
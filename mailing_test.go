@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newMailingDocument(options *Options) *Document {
+	doc, _ := New(Invoice, options)
+	doc.SetRef("INV-1")
+	doc.SetDate("02/01/2024")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street", PostalCode: "10115", City: "Berlin"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "2 Side street", PostalCode: "20095", City: "Hamburg"}})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "2"})
+
+	return doc
+}
+
+func TestMergeFields(t *testing.T) {
+	doc := newMailingDocument(&Options{})
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	merged := doc.merge("{type} {ref} of {date} for {customer}: {total} {unknown}")
+	if expected := "INVOICE INV-1 of 02/01/2024 for Customer: € 20.00 {unknown}"; merged != expected {
+		t.Errorf("expected %q, got %q", expected, merged)
+	}
+}
+
+func TestBuildCoverLetter(t *testing.T) {
+	doc := newMailingDocument(&Options{DisableCompression: true, Print: NewLetterLayout(PrintFormB)})
+	doc.CoverLetter = &CoverLetter{Body: "Please pay {total} before {payment_term}."}
+	doc.SetPaymentTerm("02/02/2024")
+
+	pdf, err := doc.BuildCoverLetter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageCount() != 1 {
+		t.Errorf("expected a single page letter, got %d", pdf.PageCount())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(INVOICE INV-1)", "(Dear Customer,)", "(Please pay \x80 20.00 before 02/02/2024.)", "(Ref.: INV-1)", "(20095 Hamburg)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in output", expected)
+		}
+	}
+
+	// The document itself is left untouched
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildEnvelope(t *testing.T) {
+	doc := newMailingDocument(&Options{DisableCompression: true})
+	doc.PostalBarcode = &PostalBarcode{Type: PostalBarcodeDatamatrix}
+
+	for _, format := range []string{EnvelopeDL, EnvelopeC5, EnvelopeLabel} {
+		pdf, err := doc.BuildEnvelope(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		width, height := pdf.GetPageSize()
+		if size := envelopeSizes[format]; width != size.Wd || height != size.Ht {
+			t.Errorf("expected %s size %v, got %v x %v", format, size, width, height)
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := pdf.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{"(Customer)", "(Company, 1 Main street, 10115 Berlin)", "(FRANKIERUNG)"} {
+			if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+				t.Errorf("expected %q in %s output", expected, format)
+			}
+		}
+	}
+
+	if _, err := doc.BuildEnvelope("A4"); err != ErrInvalidEnvelope {
+		t.Errorf("expected %v, got %v", ErrInvalidEnvelope, err)
+	}
+}
@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnknownSeller when an item seller key matches no document seller
+var ErrUnknownSeller = errors.New("unknown seller")
+
+// ErrSellersDiscount when a document discount is set on an invoice issued on behalf of sellers
+var ErrSellersDiscount = errors.New("document discount can't be split between sellers")
+
+// Seller define a seller a platform issues the document on behalf of (disclosed agent), referenced by
+// items Seller key. Company is the issuing platform, items without seller are sold by the platform itself.
+type Seller struct {
+	Key     string   `json:"key" validate:"required,max=64"`
+	Contact *Contact `json:"contact" validate:"required"` // TaxID is the seller VAT number
+}
+
+// sellerGroup define the items sold by a seller, platform items having a nil seller
+type sellerGroup struct {
+	seller *Seller
+	items  []*Item
+}
+
+// name return seller name, platform name for platform items
+func (g *sellerGroup) name(doc *Document) string {
+	if g.seller == nil {
+		return doc.Company.Name
+	}
+
+	return g.seller.Contact.Name
+}
+
+// taxID return seller VAT number, platform VAT number for platform items
+func (g *sellerGroup) taxID(doc *Document) string {
+	if g.seller == nil {
+		return doc.Company.TaxID
+	}
+
+	return g.seller.Contact.TaxID
+}
+
+// validateSellers check items seller keys, document discount can't be attributed to a seller
+func (doc *Document) validateSellers() error {
+	if len(doc.Sellers) == 0 {
+		return nil
+	}
+
+	if doc.Discount != nil {
+		return ErrSellersDiscount
+	}
+
+	for _, item := range doc.Items {
+		if len(item.Seller) > 0 && doc.seller(item.Seller) == nil {
+			return ErrUnknownSeller
+		}
+	}
+
+	return nil
+}
+
+// seller return the seller of key, nil when unknown
+func (doc *Document) seller(key string) *Seller {
+	for _, seller := range doc.Sellers {
+		if seller.Key == key {
+			return seller
+		}
+	}
+
+	return nil
+}
+
+// sellerGroups return items grouped by seller in sellers order, platform items last, empty groups omitted
+func (doc *Document) sellerGroups() []*sellerGroup {
+	sellers := append([]*Seller{}, doc.Sellers...)
+	groups := []*sellerGroup{}
+	for _, seller := range append(sellers, nil) {
+		group := &sellerGroup{seller: seller}
+		for _, item := range doc.Items {
+			if (seller == nil && len(item.Seller) == 0) || (seller != nil && item.Seller == seller.Key) {
+				group.items = append(group.items, item)
+			}
+		}
+		if len(group.items) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// SellerTotals return the total without tax and the tax of items sold by seller key, platform items
+// when key is empty
+func (doc *Document) SellerTotals(key string) (decimal.Decimal, decimal.Decimal) {
+	total := decimal.Zero
+	tax := decimal.Zero
+	for _, item := range doc.Items {
+		if item.Seller != key {
+			continue
+		}
+		if item.Tax == nil {
+			item.Tax = doc.DefaultTax
+		}
+		total = total.Add(item.TotalWithoutTaxAndWithDiscount())
+		tax = tax.Add(item.TaxWithTotalDiscounted())
+	}
+
+	return total, tax
+}
+
+// appendIssuedOnBehalf append the disclosed agent mention: issued by the platform on behalf of sellers
+func (doc *Document) appendIssuedOnBehalf() {
+	if len(doc.Sellers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(doc.Sellers))
+	for _, seller := range doc.Sellers {
+		names = append(names, seller.Contact.Name)
+	}
+
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+5)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.MultiCell(
+		190,
+		4,
+		doc.encodeString(fmt.Sprintf(doc.Options.TextSellersIssuedOnBehalf, doc.Company.Name, strings.Join(names, ", "))),
+		"0",
+		"L",
+		false,
+	)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
+
+// appendSellerItems append one items table per seller with its VAT number and totals
+func (doc *Document) appendSellerItems() {
+	for i, group := range doc.sellerGroups() {
+		if i > 0 && doc.pdf.GetY()+30 > doc.pageBottom() {
+			doc.pdf.AddPage()
+			if doc.pageLimitReached() {
+				return
+			}
+		} else if i > 0 {
+			doc.appendSeparator(SeparatorGroups, doc.pdf.GetY()+2)
+		}
+
+		// Seller heading
+		heading := fmt.Sprintf("%s %s", doc.Options.TextSellersSoldBy, group.name(doc))
+		if taxID := group.taxID(doc); len(taxID) > 0 {
+			heading += fmt.Sprintf(" - %s: %s", doc.Options.TextSellersTaxIDTitle, taxID)
+		}
+		doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+4)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(190, 5, doc.encodeString(heading), "0", 2, "", false, 0, "")
+		doc.drawRule(BaseMargin, BaseMargin+190, doc.pdf.GetY())
+
+		doc.appendItemRows(group.items)
+		if doc.pageLimitReached() {
+			return
+		}
+
+		// Seller totals
+		key := ""
+		if group.seller != nil {
+			key = group.seller.Key
+		}
+		total, tax := doc.SellerTotals(key)
+		line := fmt.Sprintf(
+			"%s: %s   %s: %s   %s: %s",
+			doc.Options.TextTotalTotal,
+			doc.formatMoney(total),
+			doc.Options.TextTotalTax,
+			doc.formatMoney(tax),
+			doc.Options.TextTotalWithTax,
+			doc.formatMoney(total.Add(tax)),
+		)
+		doc.drawRule(BaseMargin, BaseMargin+190, doc.pdf.GetY())
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+		doc.pdf.CellFormat(190, 5, doc.encodeString(line), "0", 2, "R", false, 0, "")
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	}
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSellers(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("MP-001")
+	doc.SetDate("01/03/2024")
+	doc.SetCompany(&Contact{Name: "Platform", TaxID: "FR00000000001"})
+	doc.SetCustomer(&Contact{Name: "Buyer"})
+	doc.Sellers = []*Seller{
+		{Key: "a", Contact: &Contact{Name: "Shop A", TaxID: "DE123456789"}},
+		{Key: "b", Contact: &Contact{Name: "Shop B", TaxID: "IT12345678901"}},
+	}
+	doc.AppendItem(&Item{Name: "Lamp", Seller: "a", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "19"}})
+	doc.AppendItem(&Item{Name: "Vase", Seller: "b", UnitCost: "30", Quantity: "1", Tax: &Tax{Percent: "22"}})
+	doc.AppendItem(&Item{Name: "Shipping", UnitCost: "5", Quantity: "1", Tax: &Tax{Percent: "20"}})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, tax := doc.SellerTotals("a")
+	if total.StringFixed(2) != "100.00" || tax.StringFixed(2) != "19.00" {
+		t.Errorf("expected seller totals 100.00 and 19.00, got %s and %s", total.StringFixed(2), tax.StringFixed(2))
+	}
+	if groups := doc.sellerGroups(); len(groups) != 3 || groups[2].seller != nil {
+		t.Errorf("expected 2 sellers then platform items, got %d groups", len(groups))
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"(Issued by Platform in the name and on behalf of Shop A, Shop B)",
+		"(Sold by Shop A - VAT No: DE123456789)",
+		"(Sold by Platform - VAT No: FR00000000001)",
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	doc.Items[0].Seller = "c"
+	if err := doc.Validate(); err != ErrUnknownSeller {
+		t.Errorf("expected ErrUnknownSeller, got %v", err)
+	}
+
+	doc.Items[0].Seller = "a"
+	doc.SetDiscount(&Discount{Percent: "10"})
+	if err := doc.Validate(); err != ErrSellersDiscount {
+		t.Errorf("expected ErrSellersDiscount, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidMedicalSplit when insurer and patient amounts don't add up to document total
+var ErrInvalidMedicalSplit = errors.New("invalid medical co-pay split")
+
+// Medical define optional medical and insurance informations of a document
+type Medical struct {
+	PatientRef        string   `json:"patient_ref,omitempty" validate:"max=64"`
+	InsuranceProvider string   `json:"insurance_provider,omitempty" validate:"max=256"`
+	InsurancePolicy   string   `json:"insurance_policy,omitempty" validate:"max=64"`
+	TreatmentCodes    []string `json:"treatment_codes,omitempty"`
+
+	// Co-pay split, when only one amount is set the other one is deduced from document total
+	InsurerAmount string `json:"insurer_amount,omitempty"`
+	PatientAmount string `json:"patient_amount,omitempty"`
+
+	// Shares deduced by a previous Prepare, deduced again from the current total
+	_insurerDeduced bool
+	_patientDeduced bool
+}
+
+// Prepare check amounts and deduce missing co-pay share from total, insurer and patient amounts must add up to it
+func (m *Medical) Prepare(total string) error {
+	if m._insurerDeduced {
+		m.InsurerAmount, m._insurerDeduced = "", false
+	}
+	if m._patientDeduced {
+		m.PatientAmount, m._patientDeduced = "", false
+	}
+
+	if len(m.InsurerAmount) == 0 && len(m.PatientAmount) == 0 {
+		return nil
+	}
+
+	// Check provided amounts
+	amounts := []decimal.Decimal{}
+	for _, amount := range []string{m.InsurerAmount, m.PatientAmount} {
+		if len(amount) == 0 {
+			continue
+		}
+
+		parsed, err := decimal.NewFromString(amount)
+		if err != nil {
+			return err
+		}
+		amounts = append(amounts, parsed)
+	}
+
+	// Split needs a plain amount total
+	docTotal, err := decimal.NewFromString(total)
+	if err != nil {
+		return ErrInvalidMedicalSplit
+	}
+
+	if len(amounts) == 2 {
+		if !amounts[0].Add(amounts[1]).Equal(docTotal) {
+			return ErrInvalidMedicalSplit
+		}
+
+		return nil
+	}
+
+	// Deduce missing share
+	if len(m.PatientAmount) == 0 {
+		m.PatientAmount, m._patientDeduced = docTotal.Sub(amounts[0]).String(), true
+	} else {
+		m.InsurerAmount, m._insurerDeduced = docTotal.Sub(amounts[0]).String(), true
+	}
+
+	return nil
+}
+
+// hasSplit return true if a co-pay split must be displayed
+func (m *Medical) hasSplit() bool {
+	return len(m.InsurerAmount) > 0 || len(m.PatientAmount) > 0
+}
+
+// infosAsString return medical informations displayed under description
+func (m *Medical) infosAsString(options *Options) string {
+	infos := []string{}
+
+	if len(m.PatientRef) > 0 {
+		infos = append(infos, fmt.Sprintf("%s: %s", options.TextMedicalPatientRefTitle, m.PatientRef))
+	}
+
+	if len(m.InsuranceProvider) > 0 {
+		provider := m.InsuranceProvider
+		if len(m.InsurancePolicy) > 0 {
+			provider = fmt.Sprintf("%s (%s)", provider, m.InsurancePolicy)
+		}
+		infos = append(infos, fmt.Sprintf("%s: %s", options.TextMedicalInsuranceTitle, provider))
+	}
+
+	if len(m.TreatmentCodes) > 0 {
+		infos = append(infos, fmt.Sprintf(
+			"%s: %s",
+			options.TextMedicalTreatmentCodesTitle,
+			strings.Join(m.TreatmentCodes, ", "),
+		))
+	}
+
+	return strings.Join(infos, " - ")
+}
+
+// appendMedical append medical informations to document
+func (doc *Document) appendMedical() {
+	if doc.Medical == nil {
+		return
+	}
+
+	infos := doc.Medical.infosAsString(doc.Options)
+	if len(infos) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 5)
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.MultiCell(190, 4, doc.encodeString(infos), "0", "L", false)
+
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
+
+// appendMedicalSplit append insurer / patient shares under document total
+func (doc *Document) appendMedicalSplit() {
+	if doc.Medical == nil || !doc.Medical.hasSplit() {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 12)
+	doc.appendTotalLine(doc.Options.TextMedicalInsurerAmountTitle, doc.Medical.InsurerAmount)
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.appendTotalLine(doc.Options.TextMedicalPatientAmountTitle, doc.Medical.PatientAmount)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestMedicalSplit(t *testing.T) {
+	m := &Medical{InsurerAmount: "80"}
+	if err := m.Prepare("100"); err != nil {
+		t.Fatal(err)
+	}
+	if m.PatientAmount != "20" {
+		t.Errorf("expected patient share deduced, got %q", m.PatientAmount)
+	}
+
+	// Deduced share follows total changes
+	if err := m.Prepare("120"); err != nil {
+		t.Fatal(err)
+	}
+	if m.InsurerAmount != "80" || m.PatientAmount != "40" {
+		t.Errorf("expected patient share deduced again, got %q %q", m.InsurerAmount, m.PatientAmount)
+	}
+
+	m = &Medical{PatientAmount: "15.50"}
+	if err := m.Prepare("100"); err != nil || m.InsurerAmount != "84.5" {
+		t.Errorf("expected insurer share deduced, got %q %v", m.InsurerAmount, err)
+	}
+
+	if err := (&Medical{InsurerAmount: "70", PatientAmount: "30"}).Prepare("100.00"); err != nil {
+		t.Errorf("expected valid split, got %v", err)
+	}
+	if err := (&Medical{InsurerAmount: "70", PatientAmount: "20"}).Prepare("100"); err != ErrInvalidMedicalSplit {
+		t.Errorf("expected ErrInvalidMedicalSplit for mismatching shares, got %v", err)
+	}
+	if err := (&Medical{InsurerAmount: "70"}).Prepare(""); err != ErrInvalidMedicalSplit {
+		t.Errorf("expected ErrInvalidMedicalSplit without total, got %v", err)
+	}
+	if err := (&Medical{InsurerAmount: "seventy"}).Prepare("100"); err == nil {
+		t.Error("expected invalid amount error")
+	}
+	if err := (&Medical{PatientRef: "P-1"}).Prepare(""); err != nil {
+		t.Errorf("expected no split check without amounts, got %v", err)
+	}
+}
+
+func TestMedicalDocument(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Clinic"})
+	doc.SetCustomer(&Contact{Name: "Patient"})
+	doc.AppendItem(&Item{Name: "Consultation", UnitCost: "60", Quantity: "1"})
+	doc.Medical = &Medical{PatientRef: "P-1", InsurerAmount: "45"}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Medical.PatientAmount != "15" {
+		t.Errorf("expected patient share from document total, got %q", doc.Medical.PatientAmount)
+	}
+
+	doc.CustomTotal = "see agreement"
+	if _, err := doc.Build(); err != ErrInvalidMedicalSplit {
+		t.Errorf("expected ErrInvalidMedicalSplit with a text total, got %v", err)
+	}
+}
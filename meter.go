@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidMeterReading when meter index are invalid or an item reference an unknown meter
+var ErrInvalidMeterReading = errors.New("invalid meter reading")
+
+// MeterReading define a meter index reading (electricity, water, gas...)
+type MeterReading struct {
+	Meter        string `json:"meter,omitempty" validate:"required,min=1,max=64"` // Meter identifier
+	PreviousDate string `json:"previous_date,omitempty"`
+	CurrentDate  string `json:"current_date,omitempty"`
+	Previous     string `json:"previous,omitempty" validate:"required"` // Previous index ex 10452
+	Current      string `json:"current,omitempty" validate:"required"`  // Current index ex 10980
+	Unit         string `json:"unit,omitempty" validate:"max=16"`       // Consumption unit ex kWh
+	Factor       string `json:"factor,omitempty"`                       // Optional conversion factor applied to index difference
+
+	_consumption decimal.Decimal
+}
+
+// Prepare compute consumption from meter indexes
+func (m *MeterReading) Prepare() error {
+	previous, err := decimal.NewFromString(m.Previous)
+	if err != nil {
+		return err
+	}
+
+	current, err := decimal.NewFromString(m.Current)
+	if err != nil {
+		return err
+	}
+
+	if current.LessThan(previous) {
+		return ErrInvalidMeterReading
+	}
+
+	m._consumption = current.Sub(previous)
+
+	if len(m.Factor) > 0 {
+		factor, err := decimal.NewFromString(m.Factor)
+		if err != nil {
+			return err
+		}
+		m._consumption = m._consumption.Mul(factor)
+	}
+
+	return nil
+}
+
+// Consumption return the computed consumption, available after Prepare
+func (m *MeterReading) Consumption() decimal.Decimal {
+	return m._consumption
+}
+
+// meterReading return the document meter reading for given meter identifier
+func (doc *Document) meterReading(meter string) *MeterReading {
+	for _, reading := range doc.MeterReadings {
+		if reading.Meter == meter {
+			return reading
+		}
+	}
+
+	return nil
+}
+
+// prepareMeterReadings compute consumptions and set quantities of consumption based items
+func (doc *Document) prepareMeterReadings() error {
+	for _, reading := range doc.MeterReadings {
+		if err := reading.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range doc.Items {
+		if len(item.Meter) == 0 {
+			continue
+		}
+
+		reading := doc.meterReading(item.Meter)
+		if reading == nil {
+			return ErrInvalidMeterReading
+		}
+
+		// Recomputed on each build, readings may have changed
+		item.Quantity = reading._consumption.String()
+	}
+
+	return nil
+}
+
+// appendMeterReadings append meter readings table to document
+func (doc *Document) appendMeterReadings() {
+	if len(doc.MeterReadings) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 5)
+
+	// Titles
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(10, doc.pdf.GetY(), 190, 6, "F")
+
+	doc.pdf.SetX(10)
+	doc.pdf.CellFormat(50, 6, doc.encodeString(doc.Options.TextMeterTitle), "0", 0, "", false, 0, "")
+	doc.pdf.CellFormat(50, 6, doc.encodeString(doc.Options.TextMeterPeriodTitle), "0", 0, "", false, 0, "")
+	doc.pdf.CellFormat(30, 6, doc.encodeString(doc.Options.TextMeterPreviousTitle), "0", 0, "R", false, 0, "")
+	doc.pdf.CellFormat(30, 6, doc.encodeString(doc.Options.TextMeterCurrentTitle), "0", 0, "R", false, 0, "")
+	doc.pdf.CellFormat(30, 6, doc.encodeString(doc.Options.TextMeterConsumptionTitle), "0", 0, "R", false, 0, "")
+
+	// Readings
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	for _, reading := range doc.MeterReadings {
+		period := ""
+		if len(reading.PreviousDate) > 0 || len(reading.CurrentDate) > 0 {
+			period = fmt.Sprintf("%s - %s", reading.PreviousDate, reading.CurrentDate)
+		}
+
+		consumption := reading._consumption.String()
+		if len(reading.Unit) > 0 {
+			consumption = fmt.Sprintf("%s %s", consumption, reading.Unit)
+		}
+
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.pdf.SetX(10)
+		doc.pdf.CellFormat(50, 6, doc.encodeString(reading.Meter), "0", 0, "", false, 0, "")
+		doc.pdf.CellFormat(50, 6, doc.encodeString(period), "0", 0, "", false, 0, "")
+		doc.pdf.CellFormat(30, 6, doc.encodeString(reading.Previous), "0", 0, "R", false, 0, "")
+		doc.pdf.CellFormat(30, 6, doc.encodeString(reading.Current), "0", 0, "R", false, 0, "")
+		doc.pdf.CellFormat(30, 6, doc.encodeString(consumption), "0", 0, "R", false, 0, "")
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 6)
+}
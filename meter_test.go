@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMeterReadingPrepare(t *testing.T) {
+	reading := &MeterReading{Meter: "E1", Previous: "10452", Current: "10980", Factor: "1.5"}
+	if err := reading.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if reading.Consumption().String() != "792" {
+		t.Errorf("expected 792 consumption, got %s", reading.Consumption())
+	}
+
+	if err := (&MeterReading{Meter: "E1", Previous: "10980", Current: "10452"}).Prepare(); err != ErrInvalidMeterReading {
+		t.Errorf("expected ErrInvalidMeterReading for decreasing index, got %v", err)
+	}
+}
+
+func TestMeterReadingItems(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Utility"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.MeterReadings = []*MeterReading{{Meter: "E1", Previous: "100", Current: "350", Unit: "kWh"}}
+	doc.AppendItem(&Item{Name: "Electricity", UnitCost: "0.20", Meter: "E1"})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if item := doc.Items[0]; item.Quantity != "250" || !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromInt(50)) {
+		t.Errorf("unexpected consumption item %s = %s", item.Quantity, item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	// Quantity and total follow corrected readings
+	doc.MeterReadings[0].Current = "400"
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if item := doc.Items[0]; item.Quantity != "300" || !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromInt(60)) {
+		t.Errorf("expected consumption item recomputed, got %s = %s", item.Quantity, item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	// Item discount applies to consumption
+	doc.Items[0].Discount = &Discount{Percent: "10"}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if total := doc.TotalWithoutTax(); !total.Equal(decimal.NewFromInt(54)) {
+		t.Errorf("expected discounted 54 total, got %s", total)
+	}
+	doc.Items[0].Discount = nil
+
+	// Readings are validated
+	doc.MeterReadings[0].Previous = ""
+	if err := doc.Validate(); err == nil {
+		t.Error("expected missing previous index error")
+	}
+
+	doc.MeterReadings[0].Previous = "100"
+	doc.Items[0].Meter = "G1"
+	if err := doc.Validate(); err != ErrInvalidMeterReading {
+		t.Errorf("expected ErrInvalidMeterReading for unknown meter, got %v", err)
+	}
+}
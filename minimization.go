@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Data minimization modes
+const (
+	DataMinimizationOmit         string = "omit"
+	DataMinimizationPseudonymize string = "pseudonymize"
+)
+
+// Personal data patterns: emails, labelled or international phone numbers
+var (
+	personalEmailRegexp = regexp.MustCompile(`(?i)(?:e-?mail\s*:?\s*)?[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	personalPhoneRegexp = regexp.MustCompile(`(?i)(?:(?:tel|tél|phone|mobile|fax)\.?\s*:?\s*\+?|\+)[0-9][0-9 ().\-]{5,}[0-9]`)
+)
+
+// pseudonymRegexp match pseudonyms, kept as is when document is minimized again
+var pseudonymRegexp = regexp.MustCompile(`^(?:email|phone)-[0-9a-f]{8}$`)
+
+// pseudonym return a stable pseudonym of value, kind-<8 hex digits of salted SHA-256>
+func (doc *Document) pseudonym(kind string, value string) string {
+	hash := sha256.Sum256([]byte(doc.Options.DataMinimizationSalt + strings.ToLower(value)))
+	return fmt.Sprintf("%s-%x", kind, hash[:4])
+}
+
+// minimizeText omit or pseudonymize emails and phone numbers found in text
+func (doc *Document) minimizeText(text string) string {
+	for _, pattern := range []struct {
+		kind string
+		re   *regexp.Regexp
+	}{
+		{"email", personalEmailRegexp},
+		{"phone", personalPhoneRegexp},
+	} {
+		text = pattern.re.ReplaceAllStringFunc(text, func(match string) string {
+			if doc.Options.DataMinimization == DataMinimizationPseudonymize {
+				return doc.pseudonym(pattern.kind, match)
+			}
+			return ""
+		})
+	}
+
+	return strings.TrimSpace(text)
+}
+
+// minimizeContact omit or pseudonymize personal data of contact phone, email and additional informations
+func (doc *Document) minimizeContact(c *Contact) {
+	if c == nil {
+		return
+	}
+
+	for _, field := range []struct {
+		kind  string
+		value *string
+	}{
+		{"phone", &c.Phone},
+		{"email", &c.Email},
+	} {
+		if len(*field.value) == 0 || pseudonymRegexp.MatchString(*field.value) {
+			continue
+		}
+		if doc.Options.DataMinimization == DataMinimizationPseudonymize {
+			*field.value = doc.pseudonym(field.kind, *field.value)
+		} else {
+			*field.value = ""
+		}
+	}
+
+	if c.AddtionnalInfo == nil {
+		return
+	}
+
+	infos := []string{}
+	for _, info := range c.AddtionnalInfo {
+		// Omitted lines holding only personal data are removed
+		if minimized := doc.minimizeText(info); len(minimized) > 0 {
+			infos = append(infos, minimized)
+		}
+	}
+	c.AddtionnalInfo = infos
+}
+
+// MinimizePersonalData omit or pseudonymize emails and phone numbers of document
+// (contacts, description, notes, payment term and items), following
+// Options.DataMinimization. Document is updated in place so archived data is minimized too,
+// minimizing it again keeps its pseudonyms.
+func (doc *Document) MinimizePersonalData() {
+	if len(doc.Options.DataMinimization) == 0 {
+		return
+	}
+
+	doc.minimizeContact(doc.Company)
+	doc.minimizeContact(doc.Customer)
+
+	doc.Description = doc.minimizeText(doc.Description)
+	doc.Notes = doc.minimizeText(doc.Notes)
+	doc.PaymentTerm = doc.minimizeText(doc.PaymentTerm)
+	doc.BankDetails = doc.minimizeText(doc.BankDetails)
+
+	for _, item := range doc.Items {
+		item.Description = doc.minimizeText(item.Description)
+	}
+}
@@ -0,0 +1,54 @@
+package generator
+
+import "testing"
+
+func TestMinimizePersonalData(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DataMinimization: DataMinimizationOmit})
+	doc.SetRef("1")
+	doc.SetNotes("Questions? Email: billing@example.com. Total 1 000 000 due.")
+	doc.SetCompany(&Contact{Name: "Company", AddtionnalInfo: []string{"Tel: +33 1 23 45 67 89", "SIRET 123 456 789 00012"}})
+	doc.SetCustomer(&Contact{Name: "Customer", AddtionnalInfo: []string{"jane@example.com"}})
+
+	doc.MinimizePersonalData()
+
+	if doc.Notes != "Questions? . Total 1 000 000 due." {
+		t.Errorf("unexpected notes %q", doc.Notes)
+	}
+	if len(doc.Company.AddtionnalInfo) != 1 || doc.Company.AddtionnalInfo[0] != "SIRET 123 456 789 00012" {
+		t.Errorf("unexpected company infos %q", doc.Company.AddtionnalInfo)
+	}
+	if len(doc.Customer.AddtionnalInfo) != 0 {
+		t.Errorf("unexpected customer infos %q", doc.Customer.AddtionnalInfo)
+	}
+
+	// Pseudonyms are stable
+	doc.Options.DataMinimization = DataMinimizationPseudonymize
+	first := doc.minimizeText("jane@example.com")
+	if first != doc.minimizeText("Jane@Example.com") || first == "jane@example.com" {
+		t.Errorf("unexpected pseudonym %q", first)
+	}
+}
+
+func TestMinimizePersonalDataTwice(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DataMinimization: DataMinimizationPseudonymize})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer", Phone: "+33 1 23 45 67 89", Email: "jane@example.com"})
+	doc.SetNotes("Questions? Email: billing@example.com")
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	phone, email, notes := doc.Customer.Phone, doc.Customer.Email, doc.Notes
+	if phone != doc.pseudonym("phone", "+33 1 23 45 67 89") || email != doc.pseudonym("email", "jane@example.com") {
+		t.Fatalf("unexpected pseudonyms %q %q", phone, email)
+	}
+
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Customer.Phone != phone || doc.Customer.Email != email || doc.Notes != notes {
+		t.Errorf("expected stable pseudonyms, got %q %q %q", doc.Customer.Phone, doc.Customer.Email, doc.Notes)
+	}
+}
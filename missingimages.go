@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Missing images modes, see Options.MissingImages
+const (
+	MissingImagesStrict      string = "strict"
+	MissingImagesPlaceholder string = "placeholder"
+	MissingImagesOmit        string = "omit"
+)
+
+// Warning define a non fatal issue met while building a document, ex an image replaced by a placeholder
+type Warning struct {
+	Subject string `json:"subject"` // ex company logo
+	Message string `json:"message"`
+}
+
+// String return subject and message of warning
+func (w *Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Subject, w.Message)
+}
+
+// Warnings return the warnings of the last Build
+func (doc *Document) Warnings() []*Warning {
+	return doc.warnings
+}
+
+// warn append a warning about subject
+func (doc *Document) warn(subject string, err error) {
+	doc.warnings = append(doc.warnings, &Warning{Subject: subject, Message: err.Error()})
+}
+
+// strictImages return true when images which fail to load fail the document
+func (doc *Document) strictImages() bool {
+	return len(doc.Options.MissingImages) == 0 || doc.Options.MissingImages == MissingImagesStrict
+}
+
+// drawImage draw image data registered as name at x, y in millimeters, width or height computed from
+// image ratio when 0, and return true when something was drawn.
+// Images which fail to load follow Options.MissingImages: the pdf error is returned in strict mode,
+// a placeholder is drawn or the image is omitted with a warning otherwise. Nil data was already reported.
+func (doc *Document) drawImage(subject string, name string, data []byte, x float64, y float64, width float64, height float64) (bool, error) {
+	if doc.strictImages() {
+		if data == nil {
+			return false, nil
+		}
+
+		imageInfo, format := doc.registerImage(name, data)
+		if imageInfo == nil {
+			return false, doc.pdf.Error()
+		}
+
+		doc.pdf.ImageOptions(name, x, y, width, height, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+		return true, nil
+	}
+
+	if data != nil {
+		// Corrupted data would fail the whole pdf, check it first
+		if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+			doc.warn(subject, err)
+		} else if imageInfo, format := doc.registerImage(name, data); imageInfo == nil || doc.pdf.Err() {
+			doc.warn(subject, doc.pdf.Error())
+			doc.pdf.ClearError()
+		} else {
+			doc.pdf.ImageOptions(name, x, y, width, height, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+			return true, nil
+		}
+	}
+
+	if doc.Options.MissingImages == MissingImagesOmit {
+		return false, nil
+	}
+
+	doc.drawImagePlaceholder(x, y, width, height)
+	return true, nil
+}
+
+// drawImagePlaceholder draw Options.MissingImagePlaceholder, or a box with Options.TextMissingImage,
+// square when width or height is 0
+func (doc *Document) drawImagePlaceholder(x float64, y float64, width float64, height float64) {
+	if placeholder := doc.Options.MissingImagePlaceholder; placeholder != nil {
+		if _, _, err := image.DecodeConfig(bytes.NewReader(placeholder)); err == nil {
+			if imageInfo, format := doc.registerImage("missing-image-placeholder", placeholder); imageInfo != nil && !doc.pdf.Err() {
+				doc.pdf.ImageOptions("missing-image-placeholder", x, y, width, height, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+				return
+			}
+			doc.pdf.ClearError()
+		}
+	}
+
+	if width == 0 {
+		width = height
+	}
+	if height == 0 {
+		height = width
+	}
+
+	r, g, b := doc.pdf.GetFillColor()
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(x, y, width, height, "F")
+	doc.pdf.SetFillColor(r, g, b)
+
+	r, g, b = doc.pdf.GetTextColor()
+	doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.SetXY(x, y)
+	doc.pdf.CellFormat(width, height, doc.encodeString(doc.Options.TextMissingImage), "0", 0, "C", false, 0, "")
+	doc.pdf.SetTextColor(r, g, b)
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingFetcher fail every fetch
+type failingFetcher struct{}
+
+func (failingFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	return nil, ErrFetchNotAllowed
+}
+
+func newMissingImagesDocument(mode string) *Document {
+	doc, _ := New(Invoice, &Options{MissingImages: mode, Fetcher: failingFetcher{}, DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", LogoURL: "https://example.com/logo.png", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Logo: []byte("not an image")})
+	doc.AppendItem(&Item{Name: "Hosting", UnitCost: "100", Quantity: "1"})
+
+	return doc
+}
+
+func TestMissingImagesPlaceholder(t *testing.T) {
+	doc := newMissingImagesDocument(MissingImagesPlaceholder)
+	doc.AppendAnnotation(&Annotation{Page: 1, X: 180, Y: 5, Width: 20, Image: []byte("broken")})
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := doc.Warnings()
+	if len(warnings) != 3 || warnings[0].Subject != "Company logo" || warnings[0].Message != ErrFetchNotAllowed.Error() || warnings[1].Subject != "Customer logo" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	if count := bytes.Count(buffer.Bytes(), []byte("(Image unavailable)")); count != 3 {
+		t.Errorf("expected 3 placeholders, got %d", count)
+	}
+
+	// Custom placeholder image
+	doc = newMissingImagesDocument(MissingImagesPlaceholder)
+	doc.Options.MissingImagePlaceholder = testPhoto(10, 10)
+	if err := doc.Output(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Warnings()) != 2 {
+		t.Errorf("expected warnings of last build only, got %v", doc.Warnings())
+	}
+}
+
+func TestMissingImagesOmit(t *testing.T) {
+	doc := newMissingImagesDocument(MissingImagesOmit)
+	doc.Options.Limits = &Limits{MaxImagePixels: 1000}
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Warnings()) != 2 || bytes.Contains(buffer.Bytes(), []byte("(Image unavailable)")) {
+		t.Errorf("expected omitted images, got %v", doc.Warnings())
+	}
+}
+
+func TestMissingImagesStrict(t *testing.T) {
+	if err := newMissingImagesDocument("").Output(&bytes.Buffer{}); !errors.Is(err, ErrFetchNotAllowed) {
+		t.Errorf("expected fetch error, got %v", err)
+	}
+
+	doc := newMissingImagesDocument(MissingImagesStrict)
+	doc.Company.LogoURL = ""
+	if err := doc.Output(&bytes.Buffer{}); err == nil {
+		t.Error("expected corrupted logo error")
+	}
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// ErrCurrencyMismatch when adding, subtracting or comparing amounts of different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money define an amount in an ISO 4217 currency
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// NewMoney return amount in currency
+func NewMoney(amount string, currency string) (Money, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: value, Currency: currency}, nil
+}
+
+// check return ErrCurrencyMismatch when currencies differ
+func (m Money) check(other Money) error {
+	if m.Currency != other.Currency {
+		return ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+// Add return m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+// Sub return m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Cmp compare m and other, -1 when m < other, 0 when equal, 1 when m > other
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.check(other); err != nil {
+		return 0, err
+	}
+
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// Mul return m x factor
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Neg return -m
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// IsZero return true when amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Round return m rounded to the minor unit of its currency
+func (m Money) Round() Money {
+	return Money{Amount: m.Amount.Round(int32(m.accounting().Precision)), Currency: m.Currency}
+}
+
+// accounting return formatting conventions of the currency:
+// symbol, symbol position, separators and minor unit
+func (m Money) accounting() *accounting.Accounting {
+	locale, ok := accounting.LocaleInfo[m.Currency]
+	if !ok {
+		return &accounting.Accounting{Symbol: m.Currency, Precision: 2, Thousand: ",", Decimal: ".", Format: "%v %s"}
+	}
+
+	ac := &accounting.Accounting{
+		Symbol:    locale.ComSymbol,
+		Precision: locale.FractionLength,
+		Thousand:  locale.ThouSep,
+		Decimal:   locale.DecSep,
+		Format:    "%s" + locale.SpaceSep + "%v",
+	}
+	if !locale.Pre {
+		ac.Format = "%v" + locale.SpaceSep + "%s"
+	}
+	if len(ac.Decimal) == 0 {
+		ac.Decimal = "."
+	}
+
+	return ac
+}
+
+// Format return amount formatted following the conventions of its currency, ex €1.234,50 or $1,234.50
+func (m Money) Format() string {
+	return m.accounting().FormatMoneyDecimal(m.Amount)
+}
+
+// String return amount and currency code, ex 1234.5 EUR
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Amount, m.Currency)
+}
+
+// Totals define document totals in Options.CurrencyCode
+type Totals struct {
+	WithoutTax Money `json:"without_tax"`
+	Tax        Money `json:"tax"`
+	WithTax    Money `json:"with_tax"`
+
+	doc *Document // Computed document, see Explain
+}
+
+// Money return amount in document currency
+func (doc *Document) Money(amount decimal.Decimal) Money {
+	return Money{Amount: amount, Currency: doc.Options.CurrencyCode}
+}
+
+// Totals return document totals as money
+func (doc *Document) Totals() *Totals {
+	return &Totals{
+		WithoutTax: doc.Money(doc.TotalWithoutTax()),
+		Tax:        doc.Money(doc.Tax()),
+		WithTax:    doc.Money(doc.TotalWithTax()),
+		doc:        doc,
+	}
+}
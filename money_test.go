@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoney(t *testing.T) {
+	eur, _ := NewMoney("1234.5", "EUR")
+	usd, _ := NewMoney("1234.5", "USD")
+
+	if _, err := eur.Add(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("expected currency mismatch, got %v", err)
+	}
+	if _, err := eur.Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("expected currency mismatch, got %v", err)
+	}
+
+	sum, err := eur.Add(eur)
+	if err != nil || sum.String() != "2469 EUR" {
+		t.Errorf("expected 2469 EUR, got %s (%v)", sum, err)
+	}
+
+	formats := map[string]Money{
+		"€1.234,50":     eur,
+		"$1,234.50":     usd,
+		"¥1,235":        {Amount: decimal.RequireFromString("1234.5"), Currency: "JPY"},
+		"1,234.50 XYZ":  {Amount: decimal.RequireFromString("1234.5"), Currency: "XYZ"},
+		"-$1,234.50":    usd.Neg(),
+		"$3,703.50":     usd.Mul(decimal.NewFromInt(3)),
+		"$0.33":         {Amount: decimal.RequireFromString("0.333"), Currency: "USD"},
+		"€1.234.567,00": {Amount: decimal.NewFromInt(1234567), Currency: "EUR"},
+	}
+	for expected, money := range formats {
+		if formatted := money.Format(); formatted != expected {
+			t.Errorf("expected %s, got %s", expected, formatted)
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	doc, _ := New(Invoice, &Options{CurrencyCode: "USD"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "100", Quantity: "2", Tax: &Tax{Percent: "10"}})
+
+	totals := doc.Totals()
+	if totals.WithTax.Format() != "$220.00" || totals.Tax.Currency != "USD" {
+		t.Errorf("unexpected totals %+v", totals)
+	}
+}
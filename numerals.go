@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Numeral systems, see Numerals
+const (
+	NumeralsArabic  string = "arabic"  // Eastern Arabic digits ٠١٢٣٤٥٦٧٨٩
+	NumeralsPersian string = "persian" // Persian digits ۰۱۲۳۴۵۶۷۸۹
+)
+
+// numeralsZero map numeral systems to their zero, other digits follow it
+var numeralsZero = map[string]rune{
+	NumeralsArabic:  '٠',
+	NumeralsPersian: '۰',
+}
+
+// Numerals draw amounts and dates with Eastern Arabic or Persian digits, as locales writing right to left
+// require. Western flags keep Western digits for a field. Bank details and IBANs keep Western digits to stay
+// copyable and machine readable, unless ShapeBankDetails. Font must draw these digits, see FallbackFonts.
+type Numerals struct {
+	System string `json:"system" validate:"required,oneof=arabic persian"`
+
+	WesternAmounts   bool `json:"western_amounts,omitempty"`    // Prices, totals and percents
+	WesternDates     bool `json:"western_dates,omitempty"`      // Document, validity and debit dates
+	ShapeBankDetails bool `json:"shape_bank_details,omitempty"` // Bank details and direct debit IBAN
+}
+
+// shapeDigits return text with its Western digits replaced by the digits of system, digits of HTML
+// tags and entities kept when markup
+func shapeDigits(text string, system string, markup bool) string {
+	zero, ok := numeralsZero[system]
+	if !ok {
+		return text
+	}
+
+	shaped := strings.Builder{}
+	shaped.Grow(len(text))
+	tag, entity := false, false
+	for _, r := range text {
+		switch {
+		case markup && r == '<':
+			tag = true
+		case markup && r == '>':
+			tag = false
+		case markup && r == '&':
+			entity = true
+		case markup && r == ';':
+			entity = false
+		case r >= '0' && r <= '9' && !tag && !entity:
+			r = zero + r - '0'
+		}
+		shaped.WriteRune(r)
+	}
+
+	return shaped.String()
+}
+
+// numerals return the numeral system of fields not kept Western, empty when digits are Western
+func (doc *Document) numerals(western bool) string {
+	if doc.Options.Numerals == nil || western {
+		return ""
+	}
+
+	return doc.Options.Numerals.System
+}
+
+// amountDigits return amount with the digits of Options.Numerals
+func (doc *Document) amountDigits(amount string) string {
+	if doc.Options.Numerals == nil {
+		return amount
+	}
+
+	return shapeDigits(amount, doc.numerals(doc.Options.Numerals.WesternAmounts), false)
+}
+
+// dateDigits return date with the digits of Options.Numerals
+func (doc *Document) dateDigits(date string) string {
+	if doc.Options.Numerals == nil {
+		return date
+	}
+
+	return shapeDigits(date, doc.numerals(doc.Options.Numerals.WesternDates), false)
+}
+
+// bankDigits return bank details, HTML when markup, with the digits of Options.Numerals when
+// Options.Numerals.ShapeBankDetails, as is otherwise
+func (doc *Document) bankDigits(details string, markup bool) string {
+	if doc.Options.Numerals == nil {
+		return details
+	}
+
+	return shapeDigits(details, doc.numerals(!doc.Options.Numerals.ShapeBankDetails), markup)
+}
+
+// formatMoney return value formatted with the currency options and the digits of Options.Numerals
+func (doc *Document) formatMoney(value decimal.Decimal) string {
+	return doc.amountDigits(doc.ac.FormatMoneyDecimal(value))
+}
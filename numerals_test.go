@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestNumerals(t *testing.T) {
+	doc, err := New(Invoice, &Options{Locale: "ar-SA", CurrencySymbol: "SAR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Options.Numerals == nil || doc.Options.Numerals.System != NumeralsArabic {
+		t.Fatal("expected Arabic numerals from locale")
+	}
+
+	doc.SetRef("INV-1")
+	doc.SetDate("15/03/2024")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "1234.5", Quantity: "1", Tax: &Tax{Percent: "15"}})
+	doc.SetBankDetails("<b>IBAN</b> SA03 8000 0000 6080 1016 7519")
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if text, _ := doc.Items[0].columnText(ItemColumnTotal, doc); text != "١٬٢٣٤٫٥٠\u00a0SAR" {
+		t.Errorf("expected item total in Eastern Arabic digits, got %q", text)
+	}
+	if _, taxRate, _, total := doc.totalsTexts(); taxRate != "١٥\u00a0%" || total != "١٬٤١٩٫٦٨\u00a0SAR" {
+		t.Errorf("unexpected totals %q %q", taxRate, total)
+	}
+	if date := doc.dateDigits(doc.Date); date != "١٥/٠٣/٢٠٢٤" {
+		t.Errorf("expected date in Eastern Arabic digits, got %q", date)
+	}
+
+	// IBANs kept Western by default
+	if details := doc.bankDigits(doc.BankDetails, true); details != doc.BankDetails {
+		t.Errorf("expected Western bank details, got %q", details)
+	}
+
+	// Shaped bank details, tags and entities digits are kept, Persian digits
+	doc.Options.Numerals = &Numerals{System: NumeralsPersian, ShapeBankDetails: true}
+	if details := doc.bankDigits("<font size=\"9\">SA03&#160;80</font>", true); details != "<font size=\"9\">SA۰۳&#160;۸۰</font>" {
+		t.Errorf("unexpected bank details %q", details)
+	}
+	if amount := doc.formatAmount("12"); amount != "۱۲٫۰۰\u00a0SAR" {
+		t.Errorf("expected amount in Persian digits, got %q", amount)
+	}
+}
@@ -5,35 +5,407 @@ type UnicodeTranslateFunc func(string) string
 
 // Options for Document
 type Options struct {
-	AutoPrint bool `json:"auto_print,omitempty"`
+	AutoPrint bool   `json:"auto_print,omitempty"`
+	Layout    string `default:"default" json:"layout,omitempty"`
+
+	// Compliance select a country compliance profile, ex FR, see RegisterComplianceProfile
+	Compliance string `json:"compliance,omitempty"`
+
+	// DataMinimization omit or pseudonymize personal data (emails, phone numbers), see MinimizePersonalData
+	DataMinimization     string `json:"data_minimization,omitempty" validate:"omitempty,oneof=omit pseudonymize"`
+	DataMinimizationSalt string `json:"-"` // Salt of pseudonyms
+
+	// Sanitize strip or reject hostile texts and amounts, see Document.Sanitize
+	Sanitize          string `json:"sanitize,omitempty" validate:"omitempty,oneof=strip reject"`
+	SanitizeMaxLength int    `default:"4096" json:"sanitize_max_length,omitempty" validate:"min=0"`
+
+	// Producer and Creator of PDF metadata, Producer is also written to e-invoices XMP and rules snapshots
+	Producer string `default:"go-invoice-generator" json:"producer,omitempty"`
+	Creator  string `json:"creator,omitempty"` // Application the document was created with, ex a product name
+	// WhiteLabel remove Producer from all outputs, for products embedding the library
+	WhiteLabel bool `json:"white_label,omitempty"`
+
+	// Fetcher fetch logos given as URLs, NewHTTPFetcher when nil
+	Fetcher Fetcher `json:"-"`
+
+	// AllowNegativeLines allow negative quantities, unit costs and totals, for returns and corrections
+	AllowNegativeLines bool `json:"allow_negative_lines,omitempty"`
+
+	// Chart render a spend breakdown chart of items by group, pie or bar
+	Chart string `json:"chart,omitempty" validate:"omitempty,oneof=pie bar"`
+
+	// AnnotatePage is called on each page after layout, to add positioned texts or images, see Page
+	AnnotatePage func(page *Page) error `json:"-"`
+
+	// Density compact reduce items font sizes, spacing and descriptions to fit short documents on a single page,
+	// auto when items wouldn't fit on the first page
+	Density string `json:"density,omitempty" validate:"omitempty,oneof=compact auto"`
+
+	// ItemColumns items table columns, DefaultItemColumns when empty
+	ItemColumns []*ItemColumn `json:"item_columns,omitempty" validate:"omitempty,dive"`
+
+	// HideEmptyItemColumns hide tax, discount and Item.Fields columns without value on any item, their width
+	// given to the name column
+	HideEmptyItemColumns bool `json:"hide_empty_item_columns,omitempty"`
+
+	// MaxDescriptionLength truncate longer item descriptions, in characters, with TextDescriptionContinued,
+	// their full text drawn in the appendix section. No limit when 0 or without the appendix section.
+	MaxDescriptionLength int `json:"max_description_length,omitempty" validate:"min=0"`
+
+	// Sections order and select rendered sections, DefaultSections when empty
+	Sections []string `json:"sections,omitempty"`
+
+	// CheckInvariants check totals consistency on build, see CheckInvariants
+	CheckInvariants bool `json:"check_invariants,omitempty"`
+
+	// RulesSnapshot attach the tax rates, rounding and compliance rules used as JSON to the PDF, see Document.RulesSnapshot
+	RulesSnapshot bool `json:"rules_snapshot,omitempty"`
+
+	// ExpiredWatermark draw TextExpiredWatermark across quotes past their validity date, see Document.ValidUntil
+	ExpiredWatermark bool `json:"expired_watermark,omitempty"`
+
+	// Rules style horizontal rules and enable separators between groups, above totals and above the footer
+	Rules *Rules `json:"rules,omitempty"`
+
+	// Bands fill brand colored bands behind the title and totals
+	Bands *Bands `json:"bands,omitempty"`
+
+	// Card style card blocks: rounded corners, border and shadow, see Bands.TotalsCard and Page.Card
+	Card *Card `json:"card,omitempty"`
+
+	// Icons draw icons of the embedded set next to contact phone, email and website and bank details
+	Icons *Icons `json:"icons,omitempty"`
+
+	// PageBackground fill pages with a tint and a brand stripe along an edge, behind content
+	PageBackground *PageBackground `json:"page_background,omitempty"`
+
+	// Grid layout grid annotations and AnnotatePage hooks can position against, see Page.Grid
+	Grid *Grid `json:"grid,omitempty"`
+
+	// Limits protect rendering services from abusive payloads
+	Limits *Limits `json:"limits,omitempty"`
+
+	// DisableCompression write uncompressed PDF streams
+	DisableCompression bool `json:"disable_compression,omitempty"`
+
+	// ImageMaxSize downsample images wider or higher than this number of pixels
+	ImageMaxSize int `json:"image_max_size,omitempty" validate:"min=0"`
+	// ImageQuality JPEG quality of downsampled images
+	ImageQuality int `default:"85" json:"image_quality,omitempty" validate:"min=0,max=100"`
+	// MaxOutputSize targeted maximum PDF size in bytes, images are downsampled to fit it
+	MaxOutputSize int `json:"max_output_size,omitempty" validate:"min=0"`
+	// ImageCache share downsampled images between documents, see NewImageCache
+	ImageCache *ImageCache `json:"-"`
+
+	// MissingImages logos and images which fail to load: strict fail the document, placeholder draw
+	// MissingImagePlaceholder or a box, omit skip them, see Document.Warnings. Strict when empty.
+	MissingImages           string `json:"missing_images,omitempty" validate:"omitempty,oneof=strict placeholder omit"`
+	MissingImagePlaceholder []byte `json:"missing_image_placeholder,omitempty"`
+
+	// KeepTogether blocks moved to next page instead of being split, every blocks when nil
+	KeepTogether *KeepTogether `json:"keep_together,omitempty"`
+
+	// PostalAddresses format the customer address following the postal conventions of its country,
+	// see RegisterAddressFormat
+	PostalAddresses bool `json:"postal_addresses,omitempty"`
+
+	// Print add marks and margins for printed and mailed documents, see PrintLayout
+	Print *PrintLayout `json:"print,omitempty"`
+
+	// PaymentReference generate the payment reference of documents from their ref: rf ISO 11649 creditor
+	// reference, fi Finnish reference or kid Norwegian KID, see Document.PaymentReference
+	PaymentReference string `json:"payment_reference,omitempty" validate:"omitempty,oneof=rf fi kid"`
+
+	// Portal print a QR code linking to the online copy of documents, see Portal
+	Portal *Portal `json:"portal,omitempty"`
+
+	// EInvoice embed a Factur-X / ZUGFeRD XML in PDF written by Output, see EInvoice
+	EInvoice *EInvoice `json:"e_invoice,omitempty"`
+
+	// Locale format amounts, tax percentages and dates and translate texts, ex de-DE, see RegisterLocale
+	Locale string `json:"locale,omitempty"`
+	// Translations override texts by Options json key, ex {"text_type_invoice": "RECHNUNG"}
+	Translations map[string]string `json:"translations,omitempty"`
+	// NumberLocale format amounts and tax percentages with the separators and currency format of a locale,
+	// keeping texts and date format, ex de-DE for English documents with continental numbers. Locale when empty.
+	NumberLocale string `json:"number_locale,omitempty"`
+	// FormatAmounts format amounts with the Currency options below without any locale
+	FormatAmounts bool `json:"format_amounts,omitempty"`
+
+	// CurrencyCode ISO 4217 currency of document amounts, see Money
+	CurrencyCode string `default:"EUR" json:"currency_code,omitempty"`
 
 	CurrencySymbol    string `default:"€ " json:"currency_symbol,omitempty"`
 	CurrencyPrecision int    `default:"2" json:"currency_precision,omitempty"`
 	CurrencyDecimal   string `default:"." json:"currency_decimal,omitempty"`
 	CurrencyThousand  string `default:" " json:"currency_thousand,omitempty"`
+	CurrencyFormat    string `json:"currency_format,omitempty"` // Position of symbol (%s) and amount (%v), %s%v when empty
+
+	// DisplayPrecision set the decimals unit prices, totals and quantities are displayed with
+	DisplayPrecision *DisplayPrecision `json:"display_precision,omitempty"`
 
-	TextTypeInvoice      string `default:"INVOICE" json:"text_type_invoice,omitempty"`
-	TextTypeQuotation    string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
-	TextTypeDeliveryNote string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
+	DateFormat string `default:"02/01/2006" json:"date_format,omitempty"`
+
+	// Numerals draw amounts, dates and bank details with Eastern Arabic or Persian digits, set from
+	// Options.Locale when nil, see Numerals
+	Numerals *Numerals `json:"numerals,omitempty"`
+
+	TextTypeInvoice             string `default:"INVOICE" json:"text_type_invoice,omitempty"`
+	TextTypeQuotation           string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
+	TextTypeDeliveryNote        string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
+	TextTypeTaxInvoice          string `default:"TAX INVOICE" json:"text_type_tax_invoice,omitempty"`
+	TextTypeReminder            string `default:"PAYMENT REMINDER" json:"text_type_reminder,omitempty"`
+	TextTypeDonationReceipt     string `default:"DONATION RECEIPT" json:"text_type_donation_receipt,omitempty"`
+	TextTypeSettlementStatement string `default:"SETTLEMENT STATEMENT" json:"text_type_settlement_statement,omitempty"`
 
 	TextRefTitle         string `default:"Ref." json:"text_ref_title,omitempty"`
 	TextVersionTitle     string `default:"Version" json:"text_version_title,omitempty"`
 	TextDateTitle        string `default:"Date" json:"text_date_title,omitempty"`
 	TextPaymentTermTitle string `default:"Payment term" json:"text_payment_term_title,omitempty"`
+	TextBankDetailsTitle string `default:"Bank details" json:"text_bank_details_title,omitempty"`
 
 	TextItemsNameTitle     string `default:"Name" json:"text_items_name_title,omitempty"`
 	TextItemsUnitCostTitle string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
 	TextItemsQuantityTitle string `default:"Qty" json:"text_items_quantity_title,omitempty"`
+	TextItemsUnitTitle     string `default:"Unit" json:"text_items_unit_title,omitempty"`
 	TextItemsTotalHTTitle  string `default:"Total no tax" json:"text_items_total_ht_title,omitempty"`
 	TextItemsTaxTitle      string `default:"Tax" json:"text_items_tax_title,omitempty"`
 	TextItemsDiscountTitle string `default:"Discount" json:"text_items_discount_title,omitempty"`
 	TextItemsTotalTTCTitle string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
 
+	TextItemsFreeOfCharge string `default:"Free of charge" json:"text_items_free_of_charge,omitempty"`
+	// ShowFreeOfChargeValue show the commercial value of free of charge items struck through
+	ShowFreeOfChargeValue bool `json:"show_free_of_charge_value,omitempty"`
+
+	TextChartTitle string `default:"Spend breakdown" json:"text_chart_title,omitempty"`
+	TextChartOther string `default:"Other" json:"text_chart_other,omitempty"`
+
+	TextTotalSubtotal   string `default:"SUBTOTAL" json:"text_total_subtotal,omitempty"`
 	TextTotalTotal      string `default:"TOTAL" json:"text_total_total,omitempty"`
 	TextTotalDiscounted string `default:"TOTAL DISCOUNTED" json:"text_total_discounted,omitempty"`
 	TextTotalTax        string `default:"TAX" json:"text_total_tax,omitempty"`
 	TextTotalWithTax    string `default:"TOTAL WITH TAX" json:"text_total_with_tax,omitempty"`
 
+	TextRentalFromTitle   string `default:"From" json:"text_rental_from_title,omitempty"`
+	TextRentalToTitle     string `default:"to" json:"text_rental_to_title,omitempty"`
+	TextRentalPeriodDay   string `default:"day(s)" json:"text_rental_period_day,omitempty"`
+	TextRentalPeriodWeek  string `default:"week(s)" json:"text_rental_period_week,omitempty"`
+	TextRentalPeriodMonth string `default:"month(s)" json:"text_rental_period_month,omitempty"`
+
+	TextMedicalPatientRefTitle     string `default:"Patient ref." json:"text_medical_patient_ref_title,omitempty"`
+	TextMedicalInsuranceTitle      string `default:"Insurance" json:"text_medical_insurance_title,omitempty"`
+	TextMedicalTreatmentCodesTitle string `default:"Treatment codes" json:"text_medical_treatment_codes_title,omitempty"`
+	TextMedicalInsurerAmountTitle  string `default:"INSURER SHARE" json:"text_medical_insurer_amount_title,omitempty"`
+	TextMedicalPatientAmountTitle  string `default:"PATIENT SHARE" json:"text_medical_patient_amount_title,omitempty"`
+
+	TextPayersNameTitle   string `default:"Payer" json:"text_payers_name_title,omitempty"`
+	TextPayersRefTitle    string `default:"Payment ref." json:"text_payers_ref_title,omitempty"`
+	TextPayersShareTitle  string `default:"Share" json:"text_payers_share_title,omitempty"`
+	TextPayersAmountTitle string `default:"Amount due" json:"text_payers_amount_title,omitempty"`
+
+	TextMeterTitle            string `default:"Meter" json:"text_meter_title,omitempty"`
+	TextMeterPeriodTitle      string `default:"Period" json:"text_meter_period_title,omitempty"`
+	TextMeterPreviousTitle    string `default:"Previous" json:"text_meter_previous_title,omitempty"`
+	TextMeterCurrentTitle     string `default:"Current" json:"text_meter_current_title,omitempty"`
+	TextMeterConsumptionTitle string `default:"Consumption" json:"text_meter_consumption_title,omitempty"`
+
+	TextDonationPurposeTitle         string `default:"Purpose of the donation" json:"text_donation_purpose_title,omitempty"`
+	TextDonationGoodsOrServicesTitle string `default:"Goods or services provided in exchange" json:"text_donation_goods_or_services_title,omitempty"`
+	TextDonationNoGoodsOrServices    string `default:"No goods or services were provided in exchange for this contribution." json:"text_donation_no_goods_or_services,omitempty"`
+
+	TextFolioGuestTitle        string `default:"Guest" json:"text_folio_guest_title,omitempty"`
+	TextFolioRoomTitle         string `default:"Room" json:"text_folio_room_title,omitempty"`
+	TextFolioArrivalTitle      string `default:"Arrival" json:"text_folio_arrival_title,omitempty"`
+	TextFolioDepartureTitle    string `default:"Departure" json:"text_folio_departure_title,omitempty"`
+	TextFolioNightsTitle       string `default:"Nights" json:"text_folio_nights_title,omitempty"`
+	TextFolioGuestsTitle       string `default:"Guests" json:"text_folio_guests_title,omitempty"`
+	TextFolioConfirmationTitle string `default:"Confirmation" json:"text_folio_confirmation_title,omitempty"`
+	TextFolioCityTaxTitle      string `default:"City tax" json:"text_folio_city_tax_title,omitempty"`
+	TextFolioDaySubtotalTitle  string `default:"Day total" json:"text_folio_day_subtotal_title,omitempty"`
+	TextFolioUndatedTitle      string `default:"Other charges" json:"text_folio_undated_title,omitempty"`
+
+	TextSplitBillTitle string `default:"Bill" json:"text_split_bill_title,omitempty"`
+
+	TextLateInterestTitle            string `default:"Late payment interest" json:"text_late_interest_title,omitempty"`
+	TextLateInterestPrincipalTitle   string `default:"Amount overdue" json:"text_late_interest_principal_title,omitempty"`
+	TextLateInterestOverdueTitle     string `default:"Overdue since" json:"text_late_interest_overdue_title,omitempty"`
+	TextLateInterestDaysTitle        string `default:"days" json:"text_late_interest_days_title,omitempty"`
+	TextLateInterestRateTitle        string `default:"Rate (reference rate + statutory margin)" json:"text_late_interest_rate_title,omitempty"`
+	TextLateInterestInterestTitle    string `default:"Interest" json:"text_late_interest_interest_title,omitempty"`
+	TextLateInterestRecoveryFeeTitle string `default:"Fixed recovery costs compensation" json:"text_late_interest_recovery_fee_title,omitempty"`
+	TextLateInterestTotalDueTitle    string `default:"Total due" json:"text_late_interest_total_due_title,omitempty"`
+
+	TextStatementPreviousBalanceTitle  string `default:"Previous balance" json:"text_statement_previous_balance_title,omitempty"`
+	TextStatementPaymentsReceivedTitle string `default:"Payments received" json:"text_statement_payments_received_title,omitempty"`
+	TextStatementNewChargesTitle       string `default:"New charges" json:"text_statement_new_charges_title,omitempty"`
+	TextStatementTotalDueTitle         string `default:"Total due" json:"text_statement_total_due_title,omitempty"`
+
+	TextSettlementPeriodTitle       string `default:"Period" json:"text_settlement_period_title,omitempty"`
+	TextSettlementPayoutDateTitle   string `default:"Payout date" json:"text_settlement_payout_date_title,omitempty"`
+	TextSettlementGrossSalesTitle   string `default:"Gross sales" json:"text_settlement_gross_sales_title,omitempty"`
+	TextSettlementRefundsTitle      string `default:"Refunds" json:"text_settlement_refunds_title,omitempty"`
+	TextSettlementFeesTitle         string `default:"Platform fees" json:"text_settlement_fees_title,omitempty"`
+	TextSettlementNetPayoutTitle    string `default:"Net payout" json:"text_settlement_net_payout_title,omitempty"`
+	TextSettlementTransactionsTitle string `default:"Transactions" json:"text_settlement_transactions_title,omitempty"`
+	TextSettlementDateTitle         string `default:"Date" json:"text_settlement_date_title,omitempty"`
+	TextSettlementRefTitle          string `default:"Ref." json:"text_settlement_ref_title,omitempty"`
+	TextSettlementDescriptionTitle  string `default:"Description" json:"text_settlement_description_title,omitempty"`
+	TextSettlementAmountTitle       string `default:"Amount" json:"text_settlement_amount_title,omitempty"`
+	TextSettlementFeeTitle          string `default:"Fee" json:"text_settlement_fee_title,omitempty"`
+	TextSettlementNetTitle          string `default:"Net" json:"text_settlement_net_title,omitempty"`
+	TextSettlementRefundMention     string `default:"refund" json:"text_settlement_refund_mention,omitempty"`
+
+	TextRetainerTitle               string `default:"Retainer" json:"text_retainer_title,omitempty"`
+	TextRetainerOpeningBalanceTitle string `default:"Opening balance" json:"text_retainer_opening_balance_title,omitempty"`
+	TextRetainerConsumedTitle       string `default:"Consumed this period" json:"text_retainer_consumed_title,omitempty"`
+	TextRetainerRemainingTitle      string `default:"Remaining balance" json:"text_retainer_remaining_title,omitempty"`
+	TextRetainerHoursUnit           string `default:"h" json:"text_retainer_hours_unit,omitempty"`
+
+	TextRoyaltySubtotalTitle     string `default:"Title total" json:"text_royalty_subtotal_title,omitempty"`
+	TextRoyaltyUngroupedTitle    string `default:"Other" json:"text_royalty_ungrouped_title,omitempty"`
+	TextRoyaltySummaryTitle      string `default:"Rights holder summary" json:"text_royalty_summary_title,omitempty"`
+	TextRoyaltyRightsHolderTitle string `default:"Rights holder" json:"text_royalty_rights_holder_title,omitempty"`
+	TextRoyaltyPeriodTitle       string `default:"Period" json:"text_royalty_period_title,omitempty"`
+	TextRoyaltyTitleColumn       string `default:"Title / SKU" json:"text_royalty_title_column,omitempty"`
+	TextRoyaltyUnitsColumn       string `default:"Units" json:"text_royalty_units_column,omitempty"`
+	TextRoyaltyAmountColumn      string `default:"Royalties" json:"text_royalty_amount_column,omitempty"`
+	TextRoyaltyTotalTitle        string `default:"Total royalties" json:"text_royalty_total_title,omitempty"`
+	TextRoyaltyAdvanceTitle      string `default:"Unrecouped advance" json:"text_royalty_advance_title,omitempty"`
+	TextRoyaltyRecoupedTitle     string `default:"Advance recouped" json:"text_royalty_recouped_title,omitempty"`
+	TextRoyaltyPayableTitle      string `default:"Payable" json:"text_royalty_payable_title,omitempty"`
+
+	TextDescriptionContinued     string `default:"… (continued in appendix, note %d)" json:"text_description_continued,omitempty"` // Appendix note number
+	TextDescriptionAppendixTitle string `default:"Appendix: full descriptions" json:"text_description_appendix_title,omitempty"`
+
+	TextSellersIssuedOnBehalf string `default:"Issued by %s in the name and on behalf of %s" json:"text_sellers_issued_on_behalf,omitempty"`
+	TextSellersSoldBy         string `default:"Sold by" json:"text_sellers_sold_by,omitempty"`
+	TextSellersTaxIDTitle     string `default:"VAT No" json:"text_sellers_tax_id_title,omitempty"`
+
+	TextPortugueseCertification string `default:"Processado por programa certificado n.º" json:"text_portuguese_certification,omitempty"`
+	TextVerifactuTitle          string `default:"VERI*FACTU" json:"text_verifactu_title,omitempty"`
+	TextVerifactuMention        string `default:"Factura verificable en la sede electrónica de la AEAT" json:"text_verifactu_mention,omitempty"`
+
+	TextTurkishEFaturaTitle       string `default:"e-FATURA" json:"text_turkish_e_fatura_title,omitempty"`
+	TextTurkishEArsivTitle        string `default:"e-ARŞİV FATURA" json:"text_turkish_e_arsiv_title,omitempty"`
+	TextTurkishCustomizationTitle string `default:"Özelleştirme No" json:"text_turkish_customization_title,omitempty"`
+	TextTurkishScenarioTitle      string `default:"Senaryo" json:"text_turkish_scenario_title,omitempty"`
+	TextTurkishInvoiceTypeTitle   string `default:"Fatura Tipi" json:"text_turkish_invoice_type_title,omitempty"`
+	TextTurkishInvoiceNumberTitle string `default:"Fatura No" json:"text_turkish_invoice_number_title,omitempty"`
+	TextTurkishInvoiceDateTitle   string `default:"Fatura Tarihi" json:"text_turkish_invoice_date_title,omitempty"`
+	TextTurkishInvoiceTimeTitle   string `default:"Fatura Saati" json:"text_turkish_invoice_time_title,omitempty"`
+	TextTurkishTaxIDTitle         string `default:"VKN/TCKN" json:"text_turkish_tax_id_title,omitempty"`
+	TextTurkishEArsivAnnotation   string `default:"e-Arşiv İzni Kapsamında Elektronik Ortamda İletilmiştir." json:"text_turkish_e_arsiv_annotation,omitempty"`
+
+	TextABNTitle           string `default:"ABN" json:"text_abn_title,omitempty"`
+	TextGSTNumberTitle     string `default:"GST No" json:"text_gst_number_title,omitempty"`
+	TextNZBNTitle          string `default:"NZBN" json:"text_nzbn_title,omitempty"`
+	TextGSTIncludedMention string `default:"Total price includes GST" json:"text_gst_included_mention,omitempty"`
+
+	TextSAVATNumberTitle     string `default:"VAT No" json:"text_sa_vat_number_title,omitempty"`
+	TextSAVATIncludedMention string `default:"Total price includes VAT at" json:"text_sa_vat_included_mention,omitempty"`
+
+	TextCanadianGSTTitle          string `default:"GST" json:"text_canadian_gst_title,omitempty"`
+	TextCanadianHSTTitle          string `default:"HST" json:"text_canadian_hst_title,omitempty"`
+	TextCanadianQSTTitle          string `default:"QST" json:"text_canadian_qst_title,omitempty"`
+	TextCanadianRegistrationTitle string `default:"Reg. No" json:"text_canadian_registration_title,omitempty"`
+
+	TextBrazilianAccessKeyTitle        string `default:"CHAVE DE ACESSO" json:"text_brazilian_access_key_title,omitempty"`
+	TextBrazilianAccessKeyMention      string `default:"Consulta de autenticidade no portal nacional da NF-e www.nfe.fazenda.gov.br/portal ou no site da Sefaz Autorizadora" json:"text_brazilian_access_key_mention,omitempty"`
+	TextBrazilianProtocolTitle         string `default:"PROTOCOLO DE AUTORIZAÇÃO DE USO" json:"text_brazilian_protocol_title,omitempty"`
+	TextBrazilianNFSeNumberTitle       string `default:"Número da NFS-e" json:"text_brazilian_nfse_number_title,omitempty"`
+	TextBrazilianVerificationCodeTitle string `default:"Código de verificação" json:"text_brazilian_verification_code_title,omitempty"`
+
+	TextSignatureSignedByTitle string `default:"Digitally signed by" json:"text_signature_signed_by_title,omitempty"`
+	TextSignatureDateTitle     string `default:"Date" json:"text_signature_date_title,omitempty"`
+	TextSignatureReasonTitle   string `default:"Reason" json:"text_signature_reason_title,omitempty"`
+	TextSignatureLocationTitle string `default:"Location" json:"text_signature_location_title,omitempty"`
+
+	TextShareSafeCopyTitle string `default:"Shared copy, some informations have been redacted" json:"text_share_safe_copy_title,omitempty"`
+
+	TextMissingImage string `default:"Image unavailable" json:"text_missing_image,omitempty"`
+
+	TextPostalFrankMark string `default:"FRANKIERUNG" json:"text_postal_frank_mark,omitempty"`
+
+	TextCoverLetterSubject  string `default:"{type} {ref}" json:"text_cover_letter_subject,omitempty"`
+	TextCoverLetterGreeting string `default:"Dear {customer}," json:"text_cover_letter_greeting,omitempty"`
+	TextCoverLetterBody     string `default:"Please find enclosed our document {ref} of {date}, for a total of {total}." json:"text_cover_letter_body,omitempty"`
+	TextCoverLetterClosing  string `default:"Kind regards," json:"text_cover_letter_closing,omitempty"`
+
+	TextEmailSubject string `default:"{type_name} {ref} for {total}{due}" json:"text_email_subject,omitempty"`
+	TextEmailBody    string `default:"Please find attached our {type_name} {ref} of {date}, for a total of {total}{due}." json:"text_email_body,omitempty"`
+	TextEmailDue     string `default:" due %s" json:"text_email_due,omitempty"` // Due date, in EmailDateFormat
+	EmailDateFormat  string `default:"2 Jan" json:"email_date_format,omitempty"`
+
+	TextPortalTitle string `default:"View and pay online" json:"text_portal_title,omitempty"`
+
+	TextPaymentReferenceTitle string `default:"Payment reference" json:"text_payment_reference_title,omitempty"`
+
+	TextDirectDebitTitle         string `default:"SEPA Direct Debit" json:"text_direct_debit_title,omitempty"`
+	TextDirectDebitMandateTitle  string `default:"Mandate reference" json:"text_direct_debit_mandate_title,omitempty"`
+	TextDirectDebitCreditorTitle string `default:"Creditor identifier" json:"text_direct_debit_creditor_title,omitempty"`
+	TextDirectDebitDateTitle     string `default:"Debit date" json:"text_direct_debit_date_title,omitempty"`
+	TextDirectDebitIBANTitle     string `default:"Debited account" json:"text_direct_debit_iban_title,omitempty"`
+	TextDirectDebitMention       string `default:"The amount of {total} will be debited from your account on {debit_date} under mandate {mandate_ref}. Please ensure sufficient funds are available." json:"text_direct_debit_mention,omitempty"`
+
+	TextPaymentMethodsTitle   string `default:"Payment methods" json:"text_payment_methods_title,omitempty"`
+	TextPaymentMethodTransfer string `default:"Bank transfer" json:"text_payment_method_transfer,omitempty"`
+	TextPaymentMethodCard     string `default:"Credit card" json:"text_payment_method_card,omitempty"`
+	TextPaymentMethodPayPal   string `default:"PayPal" json:"text_payment_method_paypal,omitempty"`
+	TextPaymentMethodCheck    string `default:"Check" json:"text_payment_method_check,omitempty"`
+	TextPaymentMethodOther    string `default:"Other" json:"text_payment_method_other,omitempty"`
+
+	TextPaymentMethodSurchargeTitle string `default:"Surcharge" json:"text_payment_method_surcharge_title,omitempty"`
+	TextPaymentMethodTotalTitle     string `default:"Total with this payment method" json:"text_payment_method_total_title,omitempty"`
+
+	TextRemittanceStubTitle               string `default:"Remittance" json:"text_remittance_stub_title,omitempty"`
+	TextRemittanceStubDetach              string `default:"Please detach and return this portion with your payment" json:"text_remittance_stub_detach,omitempty"`
+	TextRemittanceStubCustomerTitle       string `default:"Customer" json:"text_remittance_stub_customer_title,omitempty"`
+	TextRemittanceStubAccountTitle        string `default:"Account number" json:"text_remittance_stub_account_title,omitempty"`
+	TextRemittanceStubPayToTitle          string `default:"Make checks payable and mail to" json:"text_remittance_stub_pay_to_title,omitempty"`
+	TextRemittanceStubDueDateTitle        string `default:"Due date" json:"text_remittance_stub_due_date_title,omitempty"`
+	TextRemittanceStubAmountDueTitle      string `default:"Amount due" json:"text_remittance_stub_amount_due_title,omitempty"`
+	TextRemittanceStubAmountEnclosedTitle string `default:"Amount enclosed" json:"text_remittance_stub_amount_enclosed_title,omitempty"`
+
+	TextBoletoTitle                string `default:"Autenticação mecânica - Ficha de Compensação" json:"text_boleto_title,omitempty"`
+	TextBoletoPaymentPlace         string `default:"Pagável em qualquer banco até o vencimento" json:"text_boleto_payment_place,omitempty"`
+	TextBoletoPaymentPlaceTitle    string `default:"Local de pagamento" json:"text_boleto_payment_place_title,omitempty"`
+	TextBoletoDueDateTitle         string `default:"Vencimento" json:"text_boleto_due_date_title,omitempty"`
+	TextBoletoBeneficiaryTitle     string `default:"Beneficiário" json:"text_boleto_beneficiary_title,omitempty"`
+	TextBoletoBeneficiaryCodeTitle string `default:"Agência / Código do beneficiário" json:"text_boleto_beneficiary_code_title,omitempty"`
+	TextBoletoDocumentNumberTitle  string `default:"Nº do documento" json:"text_boleto_document_number_title,omitempty"`
+	TextBoletoOurNumberTitle       string `default:"Nosso número" json:"text_boleto_our_number_title,omitempty"`
+	TextBoletoInstructionsTitle    string `default:"Instruções (texto de responsabilidade do beneficiário)" json:"text_boleto_instructions_title,omitempty"`
+	TextBoletoAmountTitle          string `default:"(=) Valor do documento" json:"text_boleto_amount_title,omitempty"`
+	TextBoletoDeductionsTitle      string `default:"(-) Desconto / Abatimento" json:"text_boleto_deductions_title,omitempty"`
+	TextBoletoAmountChargedTitle   string `default:"(=) Valor cobrado" json:"text_boleto_amount_charged_title,omitempty"`
+	TextBoletoPayerTitle           string `default:"Pagador" json:"text_boleto_payer_title,omitempty"`
+
+	TextGiroTitle          string `default:"Giro" json:"text_giro_title,omitempty"`
+	TextGiroAccountTitle   string `default:"Account" json:"text_giro_account_title,omitempty"`
+	TextGiroReferenceTitle string `default:"Reference" json:"text_giro_reference_title,omitempty"`
+	TextGiroAmountTitle    string `default:"Amount" json:"text_giro_amount_title,omitempty"`
+
+	TextValidUntilTitle  string `default:"Valid until" json:"text_valid_until_title,omitempty"`
+	TextValidForDays     string `default:"This quotation is valid for %d days, until %s." json:"text_valid_for_days,omitempty"` // Days and validity date
+	TextExpiredWatermark string `default:"EXPIRED" json:"text_expired_watermark,omitempty"`
+
+	TextDownPaymentItem            string `default:"Down payment" json:"text_down_payment_item,omitempty"`
+	TextDownPaymentOfTitle         string `default:"Down payment invoice on order" json:"text_down_payment_of_title,omitempty"`
+	TextDownPaymentsTitle          string `default:"Down payments" json:"text_down_payments_title,omitempty"`
+	TextDownPaymentsAmountDueTitle string `default:"Amount due" json:"text_down_payments_amount_due_title,omitempty"`
+
+	TextQuoteRefTitle            string `default:"Quotation ref." json:"text_quote_ref_title,omitempty"`
+	TextQuoteOptionTitle         string `default:"Option" json:"text_quote_option_title,omitempty"`
+	TextQuoteOptionSubtotalTitle string `default:"Subtotal option" json:"text_quote_option_subtotal_title,omitempty"`
+	TextQuoteOptionsNote         string `default:"Options are alternatives, only one of them can be chosen. They are given for information and are not included in the total above." json:"text_quote_options_note,omitempty"`
+
+	TextAcceptanceTitle          string `default:"Acceptance" json:"text_acceptance_title,omitempty"`
+	TextAcceptanceStatement      string `default:"Good for agreement, I accept this quotation and its terms." json:"text_acceptance_statement,omitempty"`
+	TextAcceptanceNameTitle      string `default:"Name" json:"text_acceptance_name_title,omitempty"`
+	TextAcceptanceDateTitle      string `default:"Date" json:"text_acceptance_date_title,omitempty"`
+	TextAcceptanceSignatureTitle string `default:"Signature" json:"text_acceptance_signature_title,omitempty"`
+	TextAcceptanceViaTitle       string `default:"Accepted via" json:"text_acceptance_via_title,omitempty"`
+
 	BaseTextColor []int `default:"[35,35,35]" json:"base_text_color,omitempty"`
 	GreyTextColor []int `default:"[82,82,82]" json:"grey_text_color,omitempty"`
 	GreyBgColor   []int `default:"[232,232,232]" json:"grey_bg_color,omitempty"`
@@ -42,5 +414,17 @@ type Options struct {
 	Font     string `default:"Helvetica"`
 	BoldFont string `default:"Helvetica"`
 
-	UnicodeTranslateFunc UnicodeTranslateFunc
+	// FallbackFonts draw item names characters Font can't encode, in order, see FallbackFont
+	FallbackFonts []*FallbackFont `json:"fallback_fonts,omitempty" validate:"omitempty,dive"`
+	// SymbolReplacements replace characters Font can't encode and no fallback font draws, ex {"✓": "OK"},
+	// before DefaultSymbolReplacements
+	SymbolReplacements map[string]string `json:"symbol_replacements,omitempty"`
+	// TextUnknownSymbol replace other characters Font can't encode, emoji are dropped
+	TextUnknownSymbol string `default:"?" json:"text_unknown_symbol,omitempty"`
+	// TextDirection of lines mixing right to left (Arabic, Hebrew) and left to right characters,
+	// TextDirectionLTR or TextDirectionRTL, from the first strong character of each line when empty.
+	// HTML texts (notes, bank details, headers and footers) are drawn as is.
+	TextDirection string `json:"text_direction,omitempty" validate:"omitempty,oneof=ltr rtl"`
+
+	UnicodeTranslateFunc UnicodeTranslateFunc `json:"-"`
 }
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidPayers when payers shares does not match document total
+var ErrInvalidPayers = errors.New("invalid payers split")
+
+// Payer define a party paying a share of the document total
+type Payer struct {
+	Name    string `json:"name,omitempty" validate:"required,min=1,max=256"`
+	Percent string `json:"percent,omitempty"` // Share in percent ex 40
+	Amount  string `json:"amount,omitempty"`  // Share in amount ex 123.40
+	Ref     string `json:"ref,omitempty"`     // Payment reference, generated from document ref when empty
+
+	_amount decimal.Decimal
+}
+
+// Due return the amount due by payer, available after document validation
+func (p *Payer) Due() decimal.Decimal {
+	return p._amount
+}
+
+// payerRef return payer payment reference, document ref followed by payer number when empty
+func (doc *Document) payerRef(i int) string {
+	if ref := doc.Payers[i].Ref; len(ref) > 0 {
+		return ref
+	}
+
+	return fmt.Sprintf("%s-%02d", doc.Ref, i+1)
+}
+
+// preparePayers compute each payer due amount
+func (doc *Document) preparePayers() error {
+	if len(doc.Payers) == 0 {
+		return nil
+	}
+
+	total, err := doc.totalAmount()
+	if err != nil {
+		return err
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	sum := decimal.Zero
+	var lastPercentPayer *Payer
+
+	for _, payer := range doc.Payers {
+		if len(payer.Percent) == 0 && len(payer.Amount) == 0 {
+			return ErrInvalidPayers
+		}
+
+		if len(payer.Amount) > 0 {
+			amount, err := decimal.NewFromString(payer.Amount)
+			if err != nil {
+				return err
+			}
+			payer._amount = amount
+		} else {
+			percent, err := decimal.NewFromString(payer.Percent)
+			if err != nil {
+				return err
+			}
+			payer._amount = total.Mul(percent).Div(decimal.NewFromInt(100)).Round(precision)
+			lastPercentPayer = payer
+		}
+
+		sum = sum.Add(payer._amount)
+	}
+
+	// Assign rounding difference to the last percent based payer
+	diff := total.Sub(sum)
+	if diff.IsZero() {
+		return nil
+	}
+
+	maxRoundingDiff := decimal.New(1, -precision).Mul(decimal.NewFromInt(int64(len(doc.Payers))))
+	if lastPercentPayer == nil || diff.Abs().GreaterThan(maxRoundingDiff) {
+		return ErrInvalidPayers
+	}
+
+	lastPercentPayer._amount = lastPercentPayer._amount.Add(diff)
+
+	return nil
+}
+
+// appendPayers append the per payer payable table to document
+func (doc *Document) appendPayers() {
+	if len(doc.Payers) == 0 {
+		return
+	}
+
+	y := doc.blockY(6 * float64(len(doc.Payers)+1))
+
+	// Titles
+	doc.pdf.SetY(y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(10, y, 190, 6, "F")
+
+	doc.pdf.SetX(10)
+	doc.pdf.CellFormat(80, 6, doc.encodeString(doc.Options.TextPayersNameTitle), "0", 0, "", false, 0, "")
+	doc.pdf.CellFormat(50, 6, doc.encodeString(doc.Options.TextPayersRefTitle), "0", 0, "", false, 0, "")
+	doc.pdf.CellFormat(25, 6, doc.encodeString(doc.Options.TextPayersShareTitle), "0", 0, "", false, 0, "")
+	doc.pdf.CellFormat(35, 6, doc.encodeString(doc.Options.TextPayersAmountTitle), "0", 0, "R", false, 0, "")
+
+	// Lines
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	for i, payer := range doc.Payers {
+		share := payer.Percent + " %"
+		if len(payer.Amount) > 0 {
+			share = ""
+		}
+
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.pdf.SetX(10)
+		doc.pdf.CellFormat(80, 6, doc.encodeString(payer.Name), "0", 0, "", false, 0, "")
+		doc.pdf.CellFormat(50, 6, doc.encodeString(doc.payerRef(i)), "0", 0, "", false, 0, "")
+		doc.pdf.CellFormat(25, 6, doc.encodeString(share), "0", 0, "", false, 0, "")
+		doc.pdf.CellFormat(35, 6, doc.encodeString(doc.formatMoney(payer._amount)), "0", 0, "R", false, 0, "")
+	}
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPreparePayers(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV1")
+	doc.CustomTotal = "100.00"
+
+	doc.AppendPayer(&Payer{Name: "A", Amount: "10"})
+	doc.AppendPayer(&Payer{Name: "B", Percent: "45"})
+	doc.AppendPayer(&Payer{Name: "C", Percent: "45"})
+
+	if err := doc.preparePayers(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if doc.Payers[2].Due().String() != "45" || doc.payerRef(2) != "INV1-03" {
+		t.Errorf("unexpected payer C: %s %s", doc.Payers[2].Due(), doc.payerRef(2))
+	}
+
+	// Generated references follow document ref and aren't stored
+	doc.SetRef("INV2")
+	if doc.Payers[2].Ref != "" || doc.payerRef(2) != "INV2-03" {
+		t.Errorf("unexpected payer C ref %q %q", doc.Payers[2].Ref, doc.payerRef(2))
+	}
+
+	// Payers fields are validated
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendPayer(&Payer{Percent: "0"})
+	if err := doc.Validate(); err == nil {
+		t.Error("expected payer without name error")
+	}
+	doc.Payers = doc.Payers[:3]
+
+	doc.Payers[0].Amount = "20"
+	if err := doc.preparePayers(); !errors.Is(err, ErrInvalidPayers) {
+		t.Errorf("expected ErrInvalidPayers, got %v", err)
+	}
+}
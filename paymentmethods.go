@@ -0,0 +1,267 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Payment methods types, see PaymentMethod.Type
+const (
+	PaymentMethodTransfer string = "transfer"
+	PaymentMethodCard     string = "card"
+	PaymentMethodPayPal   string = "paypal"
+	PaymentMethodCheck    string = "check"
+	PaymentMethodOther    string = "other"
+)
+
+// Payment methods layout in millimeters
+const (
+	paymentMethodIconSize float64 = 5
+	paymentMethodIndent   float64 = 7
+)
+
+// PaymentMethod define a payment method accepted for the document, drawn in the payment section
+type PaymentMethod struct {
+	Type    string `json:"type" validate:"required,oneof=transfer card paypal check other"`
+	Title   string `json:"title,omitempty" validate:"max=128"` // Options text of type when empty
+	Details string `json:"details,omitempty"`                  // You can use basic html here (bold, italic tags)
+	URL     string `json:"url,omitempty" validate:"max=2048"`  // Payment link, ex card checkout page
+	Icon    []byte `json:"icon,omitempty"`                     // Icon byte array, drawn before title
+	Order   int    `json:"order,omitempty"`                    // Methods are drawn by increasing order, then as given
+
+	// Amounts range of document total the method is offered for, unbounded when empty
+	MinAmount string `json:"min_amount,omitempty"`
+	MaxAmount string `json:"max_amount,omitempty"`
+
+	// Method fee charged on top of document total, ex 1.5 percent for card, rendered with the total paid by this method
+	SurchargePercent string `json:"surcharge_percent,omitempty"`
+	SurchargeAmount  string `json:"surcharge_amount,omitempty"` // Fixed fee added to the percent one
+
+	_minAmount        decimal.Decimal
+	_maxAmount        decimal.Decimal
+	_surchargePercent decimal.Decimal
+	_surchargeAmount  decimal.Decimal
+}
+
+// Prepare convert amounts range to decimal
+func (m *PaymentMethod) Prepare() error {
+	if len(m.MinAmount) > 0 {
+		amount, err := decimal.NewFromString(m.MinAmount)
+		if err != nil {
+			return err
+		}
+		m._minAmount = amount
+	}
+
+	if len(m.MaxAmount) > 0 {
+		amount, err := decimal.NewFromString(m.MaxAmount)
+		if err != nil {
+			return err
+		}
+		m._maxAmount = amount
+	}
+
+	if len(m.SurchargePercent) > 0 {
+		percent, err := decimal.NewFromString(m.SurchargePercent)
+		if err != nil {
+			return err
+		}
+		m._surchargePercent = percent
+	}
+
+	if len(m.SurchargeAmount) > 0 {
+		amount, err := decimal.NewFromString(m.SurchargeAmount)
+		if err != nil {
+			return err
+		}
+		m._surchargeAmount = amount
+	}
+
+	return nil
+}
+
+// surcharged return true when method has a fee
+func (m *PaymentMethod) surcharged() bool {
+	return !m._surchargePercent.IsZero() || !m._surchargeAmount.IsZero()
+}
+
+// Surcharge return method fee on total, rounded to precision
+func (m *PaymentMethod) Surcharge(total decimal.Decimal, precision int32) decimal.Decimal {
+	fee := total.Mul(m._surchargePercent).Div(decimal.NewFromInt(100)).Round(precision)
+	return fee.Add(m._surchargeAmount)
+}
+
+// TotalWithSurcharge return total paid with this method, fee included
+func (m *PaymentMethod) TotalWithSurcharge(total decimal.Decimal, precision int32) decimal.Decimal {
+	return total.Add(m.Surcharge(total, precision))
+}
+
+// offered return true when total is in method amounts range
+func (m *PaymentMethod) offered(total decimal.Decimal) bool {
+	if len(m.MinAmount) > 0 && total.LessThan(m._minAmount) {
+		return false
+	}
+
+	return len(m.MaxAmount) == 0 || !total.GreaterThan(m._maxAmount)
+}
+
+// title return method title, the type one when empty
+func (m *PaymentMethod) title(options *Options) string {
+	if len(m.Title) > 0 {
+		return m.Title
+	}
+
+	switch m.Type {
+	case PaymentMethodTransfer:
+		return options.TextPaymentMethodTransfer
+	case PaymentMethodCard:
+		return options.TextPaymentMethodCard
+	case PaymentMethodPayPal:
+		return options.TextPaymentMethodPayPal
+	case PaymentMethodCheck:
+		return options.TextPaymentMethodCheck
+	}
+
+	return options.TextPaymentMethodOther
+}
+
+// surchargeLines return method fee and total paid with it, ex Surcharge (1.5 % + €0.30): €1.80
+func (doc *Document) surchargeLines(method *PaymentMethod, total decimal.Decimal) []string {
+	precision := int32(doc.Options.CurrencyPrecision)
+
+	rates := []string{}
+	if !method._surchargePercent.IsZero() {
+		rates = append(rates, doc.formatPercent(method._surchargePercent.String()))
+	}
+	if !method._surchargeAmount.IsZero() {
+		rates = append(rates, doc.formatMoney(method._surchargeAmount))
+	}
+
+	return []string{
+		fmt.Sprintf(
+			"%s (%s): %s",
+			doc.Options.TextPaymentMethodSurchargeTitle,
+			strings.Join(rates, " + "),
+			doc.formatMoney(method.Surcharge(total, precision)),
+		),
+		fmt.Sprintf(
+			"%s: %s",
+			doc.Options.TextPaymentMethodTotalTitle,
+			doc.formatMoney(method.TotalWithSurcharge(total, precision)),
+		),
+	}
+}
+
+// documentTotal return the document total methods are offered and surcharged for, prior down payments deducted
+func (doc *Document) documentTotal() decimal.Decimal {
+	total, err := doc.totalAmount()
+	if err != nil {
+		total = doc.TotalWithTax()
+	}
+
+	return total.Sub(doc.DownPaymentsTotal())
+}
+
+// OfferedPaymentMethods return the payment methods offered for document total, in order
+func (doc *Document) OfferedPaymentMethods() []*PaymentMethod {
+	total := doc.documentTotal()
+
+	methods := []*PaymentMethod{}
+	for _, method := range doc.PaymentMethods {
+		if method.offered(total) {
+			methods = append(methods, method)
+		}
+	}
+
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methods[i].Order < methods[j].Order
+	})
+
+	return methods
+}
+
+// appendPaymentMethods append offered payment methods with their icon, details and link
+func (doc *Document) appendPaymentMethods() {
+	methods := doc.OfferedPaymentMethods()
+	if len(methods) == 0 {
+		return
+	}
+	total := doc.documentTotal()
+
+	y := doc.pdf.GetY() + 10
+	if doc.notesBottom > y {
+		y = doc.notesBottom + 5
+	}
+	doc.pdf.SetY(y)
+
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	_, lineHt := doc.pdf.GetFontSize()
+	x, width := BaseMargin+paymentMethodIndent, 190-paymentMethodIndent
+
+	doc.keepBlockTogether(BlockBankDetails, 6+doc.paymentMethodHeight(methods[0], width, lineHt))
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 9)
+	doc.pdf.CellFormat(190, 6, doc.encodeString(doc.Options.TextPaymentMethodsTitle), "0", 2, "", false, 0, "")
+
+	for i, method := range methods {
+		// Move each method to next page rather than splitting it
+		doc.keepBlockTogether(BlockBankDetails, doc.paymentMethodHeight(method, width, lineHt))
+		y := doc.pdf.GetY()
+
+		if method.Icon != nil {
+			_, _ = doc.drawImage(
+				fmt.Sprintf("%s payment method icon", method.title(doc.Options)),
+				fmt.Sprintf("payment-method-icon-%d", i), method.Icon,
+				BaseMargin, y, paymentMethodIconSize, paymentMethodIconSize,
+			)
+		}
+
+		doc.pdf.SetXY(x, y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", 9)
+		doc.pdf.CellFormat(width, paymentMethodIconSize, doc.encodeString(method.title(doc.Options)), "0", 2, "", false, 0, "")
+
+		doc.pdf.SetFont(doc.Options.Font, "", 9)
+		if len(method.Details) > 0 {
+			left, _, _, _ := doc.pdf.GetMargins()
+			doc.pdf.SetLeftMargin(x)
+			doc.pdf.SetX(x)
+			html := doc.pdf.HTMLBasicNew()
+			html.Write(lineHt, doc.encode(method.Details))
+			doc.pdf.Ln(lineHt)
+			doc.pdf.SetLeftMargin(left)
+		}
+
+		if len(method.URL) > 0 {
+			doc.pdf.SetX(x)
+			doc.pdf.CellFormat(width, lineHt, doc.encodeString(method.URL), "0", 2, "", false, 0, method.URL)
+		}
+
+		if method.surcharged() {
+			for _, line := range doc.surchargeLines(method, total) {
+				doc.pdf.SetX(x)
+				doc.pdf.CellFormat(width, lineHt, doc.encodeString(line), "0", 2, "", false, 0, "")
+			}
+		}
+
+		doc.pdf.SetY(doc.pdf.GetY() + 2)
+	}
+}
+
+// paymentMethodHeight return the height of method block drawn width wide
+func (doc *Document) paymentMethodHeight(method *PaymentMethod, width float64, lineHt float64) float64 {
+	height := paymentMethodIconSize + 2
+	if len(method.Details) > 0 {
+		height += doc.textHeight(method.Details, width, lineHt)
+	}
+	if len(method.URL) > 0 {
+		height += lineHt
+	}
+	if method.surcharged() {
+		height += 2 * lineHt
+	}
+
+	return height
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOfferedPaymentMethods(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "100", Quantity: "1"})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodTransfer, Details: "IBAN: FR76 3000 6000 0112 3456 7890 189", Order: 2})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodCard, URL: "https://pay.example.com/INV-1", Order: 1})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodCheck, MaxAmount: "50"})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodPayPal, Title: "PayPal account", MinAmount: "10", MaxAmount: "500"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methods := doc.OfferedPaymentMethods()
+	expected := []string{PaymentMethodPayPal, PaymentMethodCard, PaymentMethodTransfer}
+	if len(methods) != len(expected) {
+		t.Fatalf("expected %d offered methods, got %d", len(expected), len(methods))
+	}
+	for i, method := range methods {
+		if method.Type != expected[i] {
+			t.Errorf("expected method %d to be %s, got %s", i, expected[i], method.Type)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"(Payment methods)", "(Credit card)", "(PayPal account)", "(https://pay.example.com/INV-1)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(text)) {
+			t.Errorf("expected %q in output", text)
+		}
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("(Check)")) {
+		t.Error("expected check method above its max amount to be omitted")
+	}
+
+	doc.PaymentMethods[0].Type = "cash"
+	if _, err := doc.Build(); err == nil {
+		t.Error("expected unknown payment method type to fail validation")
+	}
+}
+
+func TestPaymentMethodSurcharge(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "100", Quantity: "1"})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodTransfer})
+	doc.AppendPaymentMethod(&PaymentMethod{Type: PaymentMethodCard, SurchargePercent: "1.5", SurchargeAmount: "0.25"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card := doc.PaymentMethods[1]
+	total := doc.TotalWithTax()
+	if surcharge := card.Surcharge(total, 2); surcharge.String() != "1.75" {
+		t.Errorf("expected 1.75 surcharge, got %s", surcharge)
+	}
+	if withSurcharge := card.TotalWithSurcharge(total, 2); withSurcharge.String() != "101.75" {
+		t.Errorf("expected 101.75 total with surcharge, got %s", withSurcharge)
+	}
+	if doc.PaymentMethods[0].surcharged() {
+		t.Error("expected transfer method without surcharge")
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"Surcharge \\(1.5 % + ", "Total with this payment method: "} {
+		if bytes.Count(buffer.Bytes(), []byte(text)) != 1 {
+			t.Errorf("expected %q once in output", text)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingPortalSecret when portal URL holds a token without secret to sign it
+var ErrMissingPortalSecret = errors.New("missing portal secret")
+
+// portalTokenSize bytes of HMAC kept in tokens, short enough to keep printed QR codes small
+const portalTokenSize int = 16
+
+// Portal define the online copy of documents, linked by a QR code printed for paper recipients
+type Portal struct {
+	// URL template, merge fields and {token} replaced, ex https://pay.example.com/{number}?token={token}.
+	// See Document.MergeFields.
+	URL string `json:"url" validate:"required,max=1024"`
+	// Secret signing tokens, see PortalToken
+	Secret []byte `json:"-"`
+}
+
+// PortalToken return the URL safe token signing ref with secret, checked by the portal with VerifyPortalToken
+func PortalToken(secret []byte, ref string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ref))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:portalTokenSize])
+}
+
+// VerifyPortalToken return true when token was signed with secret for ref
+func VerifyPortalToken(secret []byte, ref string, token string) bool {
+	return hmac.Equal([]byte(token), []byte(PortalToken(secret, ref)))
+}
+
+// Prepare check a token can be signed
+func (p *Portal) Prepare() error {
+	if strings.Contains(p.URL, "{token}") && len(p.Secret) == 0 {
+		return ErrMissingPortalSecret
+	}
+
+	return nil
+}
+
+// PortalURL return the URL of the online copy of document, empty without Options.Portal
+func (doc *Document) PortalURL() string {
+	portal := doc.Options.Portal
+	if portal == nil {
+		return ""
+	}
+
+	replacements := []string{"{token}", PortalToken(portal.Secret, doc.Ref)}
+	for name, value := range doc.MergeFields() {
+		// Escaped for both path and query
+		escaped := strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+		replacements = append(replacements, fmt.Sprintf("{%s}", name), escaped)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(portal.URL)
+}
+
+// appendPortal append the QR code of the document online copy, with its title and URL
+func (doc *Document) appendPortal() error {
+	portalURL := doc.PortalURL()
+	if len(portalURL) == 0 {
+		return nil
+	}
+
+	y := doc.blockY(25)
+
+	if err := doc.drawQRCode(portalURL, QRCodeECLevelM, BaseMargin, y, 25); err != nil {
+		return err
+	}
+
+	doc.pdf.SetXY(BaseMargin+30, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(160, 5, doc.encodeString(doc.Options.TextPortalTitle), "0", 2, "L", false, 0, "")
+
+	// URLs can't be wrapped on spaces
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.MultiCell(160, 3, doc.encodeString(doc.wrapText(portalURL, 160)), "0", "L", false)
+	doc.pdf.SetY(y + 25)
+
+	return nil
+}
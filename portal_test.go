@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPortalToken(t *testing.T) {
+	secret := []byte("secret")
+
+	token := PortalToken(secret, "INV-1")
+	if len(token) != 22 {
+		t.Errorf("expected 22 characters token, got %s", token)
+	}
+	if !VerifyPortalToken(secret, "INV-1", token) {
+		t.Error("expected token to be verified")
+	}
+	if VerifyPortalToken(secret, "INV-2", token) || VerifyPortalToken([]byte("other"), "INV-1", token) {
+		t.Error("expected token of another ref or secret to be rejected")
+	}
+}
+
+func TestPortalURL(t *testing.T) {
+	secret := []byte("secret")
+	doc, _ := New(Invoice, &Options{
+		DisableCompression: true,
+		Portal:             &Portal{URL: "https://pay.example.com/{ref}?token={token}", Secret: secret},
+	})
+	doc.SetRef("INV 1/2")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	expected := "https://pay.example.com/INV%201%2F2?token=" + PortalToken(secret, "INV 1/2")
+	if portalURL := doc.PortalURL(); portalURL != expected {
+		t.Errorf("expected %s, got %s", expected, portalURL)
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(View and pay online)")) {
+		t.Error("expected portal title in output")
+	}
+
+	// Number alias of ref
+	doc.Options.Portal.URL = "https://pay.example.com/{number}?token={token}"
+	if portalURL := doc.PortalURL(); portalURL != expected {
+		t.Errorf("expected %s, got %s", expected, portalURL)
+	}
+
+	doc.Options.Portal.Secret = nil
+	if _, err := doc.Build(); err != ErrMissingPortalSecret {
+		t.Errorf("expected %v, got %v", ErrMissingPortalSecret, err)
+	}
+}
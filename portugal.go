@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrMissingPortugueseFiscal when Portuguese fiscal informations are required but not set
+var ErrMissingPortugueseFiscal = errors.New("missing portuguese fiscal informations")
+
+// PortugueseConsumerTaxID is the tax id used in AT QR code when customer has no tax id
+const PortugueseConsumerTaxID string = "999999990"
+
+// PortugueseFiscal define Portuguese tax authority (AT) requirements of a document
+type PortugueseFiscal struct {
+	ValidationCode      string `json:"validation_code,omitempty" validate:"required,min=8,max=70"` // Series validation code given by AT
+	SequenceNumber      string `json:"sequence_number,omitempty" validate:"required,numeric"`      // Document sequential number in series
+	DocumentType        string `json:"document_type,omitempty" default:"FT"`                       // FT, FS, FR, NC, ND...
+	DocumentStatus      string `json:"document_status,omitempty" default:"N"`
+	SoftwareCertificate string `json:"software_certificate,omitempty" validate:"required"` // Certified software number ex 9999
+	SystemEntryDate     string `json:"system_entry_date,omitempty"`                        // Format 2006-01-02T15:04:05, defaults to document date
+
+	// Hash is the document signature provided by the certified signing process, see HashInput.
+	// A "0" placeholder is used while not signed.
+	Hash string `json:"hash,omitempty" default:"0"`
+}
+
+// ATCUD return the unique document code, "<validation code>-<sequence number>"
+func (p *PortugueseFiscal) ATCUD() string {
+	return fmt.Sprintf("%s-%s", p.ValidationCode, p.SequenceNumber)
+}
+
+// HashInput return the message to sign to chain this document after previousHash:
+// "InvoiceDate;SystemEntryDate;InvoiceNo;GrossTotal;PreviousHash"
+func (doc *Document) HashInput(previousHash string) (string, error) {
+	fiscal := doc.PortugueseFiscal
+	if fiscal == nil {
+		return "", ErrMissingPortugueseFiscal
+	}
+
+	if err := doc.Validate(); err != nil {
+		return "", err
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		date.Format("2006-01-02"),
+		fiscal.systemEntryDate(date),
+		doc.Ref,
+		doc.TotalWithTax().StringFixed(2),
+		previousHash,
+	}, ";"), nil
+}
+
+// systemEntryDate return the system entry date of document
+func (p *PortugueseFiscal) systemEntryDate(date time.Time) string {
+	if len(p.SystemEntryDate) > 0 {
+		return p.SystemEntryDate
+	}
+
+	return date.Format("2006-01-02T15:04:05")
+}
+
+// hashControl return the 4 hash characters printed on documents (positions 1, 11, 21 and 31)
+func (p *PortugueseFiscal) hashControl() string {
+	control := ""
+	for _, pos := range []int{0, 10, 20, 30} {
+		if pos < len(p.Hash) {
+			control += string(p.Hash[pos])
+		}
+	}
+
+	return control
+}
+
+// portugueseQRCodeContent return the AT QR code content (Portaria n.º 195/2020)
+func (doc *Document) portugueseQRCodeContent() (string, error) {
+	fiscal := doc.PortugueseFiscal
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return "", err
+	}
+
+	customerTaxID := doc.Customer.TaxID
+	customerCountry := "PT"
+	if len(customerTaxID) == 0 {
+		customerTaxID = PortugueseConsumerTaxID
+	}
+	if doc.Customer.Address != nil && len(doc.Customer.Address.Country) == 2 {
+		customerCountry = strings.ToUpper(doc.Customer.Address.Country)
+	}
+
+	fields := []string{
+		"A:" + doc.Company.TaxID,
+		"B:" + customerTaxID,
+		"C:" + customerCountry,
+		"D:" + fiscal.DocumentType,
+		"E:" + fiscal.DocumentStatus,
+		"F:" + date.Format("20060102"),
+		"G:" + doc.Ref,
+		"H:" + fiscal.ATCUD(),
+		"I1:PT",
+	}
+
+	// Taxable bases and VAT per rate kind: exempt (I2), reduced (I3, I4), intermediate (I5, I6), normal (I7, I8)
+	bases := map[string]decimal.Decimal{}
+	taxes := map[string]decimal.Decimal{}
+	for _, line := range doc.TaxLines() {
+		code := saftPTTaxCode(line.Rate)
+		bases[code] = bases[code].Add(line.Base)
+		taxes[code] = taxes[code].Add(line.Amount)
+	}
+
+	if base, ok := bases["ISE"]; ok {
+		fields = append(fields, "I2:"+base.StringFixed(2))
+	}
+	for _, code := range []struct{ key, base, tax string }{
+		{"RED", "I3", "I4"},
+		{"INT", "I5", "I6"},
+		{"NOR", "I7", "I8"},
+	} {
+		if base, ok := bases[code.key]; ok {
+			fields = append(fields, code.base+":"+base.StringFixed(2), code.tax+":"+taxes[code.key].StringFixed(2))
+		}
+	}
+
+	fields = append(
+		fields,
+		"N:"+doc.Tax().StringFixed(2),
+		"O:"+doc.TotalWithTax().StringFixed(2),
+		"Q:"+fiscal.hashControl(),
+		"R:"+fiscal.SoftwareCertificate,
+	)
+
+	return strings.Join(fields, "*"), nil
+}
+
+// appendPortugueseFiscal append ATCUD, AT QR code and certification mention to document
+func (doc *Document) appendPortugueseFiscal() error {
+	if doc.PortugueseFiscal == nil {
+		return nil
+	}
+
+	content, err := doc.portugueseQRCodeContent()
+	if err != nil {
+		return err
+	}
+
+	y := doc.blockY(40)
+	x := BaseMargin
+
+	// ATCUD immediately above QR code
+	doc.pdf.SetXY(x, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(60, 4, doc.encodeString("ATCUD: "+doc.PortugueseFiscal.ATCUD()), "0", 0, "L", false, 0, "")
+
+	// QR code, at least 30x30 mm
+	if err := doc.drawQRCode(content, QRCodeECLevelM, x, y+5, 30); err != nil {
+		return err
+	}
+
+	// Certification mention
+	mention := fmt.Sprintf(
+		"%s-%s %s/AT",
+		doc.PortugueseFiscal.hashControl(),
+		doc.Options.TextPortugueseCertification,
+		doc.PortugueseFiscal.SoftwareCertificate,
+	)
+	doc.pdf.SetXY(x+35, y+30)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(120, 4, doc.encodeString(mention), "0", 0, "L", false, 0, "")
+	doc.pdf.SetY(y + 35)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+// newPortugueseInvoice return an invoice with the amounts of the AT QR code specification example
+func newPortugueseInvoice() *Document {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("FT AB2019/0035")
+	doc.SetDate("31/12/2019")
+	doc.SetCompany(&Contact{Name: "Company", TaxID: "123456789"})
+	doc.SetCustomer(&Contact{Name: "Consumer"})
+	doc.AppendItem(&Item{Name: "Exempt", UnitCost: "12000", Quantity: "1", Tax: &Tax{Percent: "0"}})
+	doc.AppendItem(&Item{Name: "Reduced", UnitCost: "15000", Quantity: "1", Tax: &Tax{Percent: "6"}})
+	doc.AppendItem(&Item{Name: "Intermediate", UnitCost: "50000", Quantity: "1", Tax: &Tax{Percent: "13"}})
+	doc.AppendItem(&Item{Name: "Normal", UnitCost: "80000", Quantity: "1", Tax: &Tax{Percent: "23"}})
+	doc.PortugueseFiscal = &PortugueseFiscal{
+		ValidationCode:      "CSDF7T5H",
+		SequenceNumber:      "0035",
+		DocumentType:        "FT",
+		DocumentStatus:      "N",
+		SoftwareCertificate: "9999",
+		Hash:                "kAb1c2d3e4Lf5g6h7i8jpk9l0m1n2o0p4q5r",
+	}
+
+	return doc
+}
+
+func TestPortugueseATCUD(t *testing.T) {
+	if atcud := newPortugueseInvoice().PortugueseFiscal.ATCUD(); atcud != "CSDF7T5H-0035" {
+		t.Errorf("unexpected ATCUD %s", atcud)
+	}
+}
+
+func TestPortugueseHashInput(t *testing.T) {
+	// First document of a series, example of the AT signature specification
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("FAC 001/14")
+	doc.SetDate("18/05/2010")
+	doc.SetCompany(&Contact{Name: "Company", TaxID: "123456789"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "3.12", Quantity: "1"})
+	doc.PortugueseFiscal = &PortugueseFiscal{
+		ValidationCode:      "CSDF7T5H",
+		SequenceNumber:      "1",
+		SoftwareCertificate: "9999",
+		SystemEntryDate:     "2010-05-18T11:22:19",
+	}
+
+	input, err := doc.HashInput("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input != "2010-05-18;2010-05-18T11:22:19;FAC 001/14;3.12;" {
+		t.Errorf("unexpected hash input %q", input)
+	}
+
+	// Chained after the previous document signature
+	if input, _ := doc.HashInput("mYJEv4iGwLcnQbRD7dPs"); input != "2010-05-18;2010-05-18T11:22:19;FAC 001/14;3.12;mYJEv4iGwLcnQbRD7dPs" {
+		t.Errorf("unexpected chained hash input %q", input)
+	}
+
+	doc.PortugueseFiscal = nil
+	if _, err := doc.HashInput(""); err != ErrMissingPortugueseFiscal {
+		t.Errorf("expected ErrMissingPortugueseFiscal, got %v", err)
+	}
+}
+
+func TestPortugueseQRCode(t *testing.T) {
+	doc := newPortugueseInvoice()
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := doc.portugueseQRCodeContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "A:123456789*B:999999990*C:PT*D:FT*E:N*F:20191231*G:FT AB2019/0035*H:CSDF7T5H-0035*I1:PT" +
+		"*I2:12000.00*I3:15000.00*I4:900.00*I5:50000.00*I6:6500.00*I7:80000.00*I8:18400.00" +
+		"*N:25800.00*O:182800.00*Q:kLp0*R:9999"
+	if content != expected {
+		t.Errorf("unexpected AT QR code content\n%s\nexpected\n%s", content, expected)
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(ATCUD: CSDF7T5H-0035)")) {
+		t.Error("expected ATCUD in PDF")
+	}
+}
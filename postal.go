@@ -0,0 +1,292 @@
+package generator
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// ErrInvalidPostalBarcode when postal barcode tracking or routing code is invalid
+var ErrInvalidPostalBarcode = errors.New("invalid postal barcode tracking or routing code")
+
+// Postal barcodes types, see PostalBarcode.Type
+const (
+	PostalBarcodeIMb        string = "imb"        // USPS Intelligent Mail barcode
+	PostalBarcodeDatamatrix string = "datamatrix" // Deutsche Post Datamatrix frank mark, printed by the franking system
+)
+
+// Postal barcodes sizes in millimeters
+const (
+	imbBarPitch          float64 = 25.4 / 24 // 24 bars per inch
+	imbBarWidth          float64 = 0.5
+	imbTrackerHeight     float64 = 1.2
+	imbQuietZone         float64 = 3.2
+	frankMarkSize        float64 = 15
+	frankMarkEndorsement float64 = 17.7 // DIN 5008 additions and endorsements zone height
+)
+
+// PostalBarcode define the postal automation code printed with the customer address, for mailing houses
+type PostalBarcode struct {
+	Type string `json:"type" validate:"required,oneof=imb datamatrix"`
+
+	// TrackingCode 20 digits IMb barcode identifier, service type, mailer id and serial number
+	TrackingCode string `json:"tracking_code,omitempty"`
+	// RoutingCode 0, 5, 9 or 11 digits IMb delivery point ZIP code, customer postal code when empty
+	RoutingCode string `json:"routing_code,omitempty"`
+}
+
+// Prepare normalize and check IMb tracking and routing codes, routed to customer postal code by default
+func (p *PostalBarcode) Prepare(doc *Document) error {
+	if p.Type != PostalBarcodeIMb {
+		return nil
+	}
+
+	p.TrackingCode = strings.NewReplacer(" ", "", "-", "").Replace(p.TrackingCode)
+	p.RoutingCode = strings.NewReplacer(" ", "", "-", "").Replace(p.RoutingCode)
+	if len(p.RoutingCode) == 0 && doc.Customer != nil && doc.Customer.Address != nil {
+		p.RoutingCode = strings.NewReplacer(" ", "", "-", "").Replace(doc.Customer.Address.PostalCode)
+	}
+
+	if _, err := imbBars(p.TrackingCode, p.RoutingCode); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// imbCharacters5of13 and imbCharacters2of13 map IMb codewords to characters, see imbCharactersTable
+var (
+	imbCharacters5of13 = imbCharactersTable(5, 1287)
+	imbCharacters2of13 = imbCharactersTable(2, 78)
+)
+
+// imbBarsBits map characters bits to bars, descenders of bars 1 to 65 then their ascenders,
+// indexed by character (A to J) and bit
+var imbBarsBits = []int{
+	67, 6, 78, 16, 86, 95, 34, 40, 45, 113, 117, 121, 62,
+	87, 18, 104, 41, 76, 57, 119, 115, 72, 97, 2, 127, 26,
+	105, 35, 122, 52, 114, 7, 24, 82, 68, 63, 94, 44, 77,
+	112, 70, 100, 39, 30, 107, 15, 125, 85, 10, 65, 54, 88,
+	20, 106, 46, 66, 8, 116, 29, 61, 99, 80, 90, 37, 123,
+	51, 25, 84, 129, 56, 4, 109, 96, 28, 36, 47, 11, 71,
+	33, 102, 21, 9, 17, 49, 124, 79, 64, 91, 42, 69, 53,
+	60, 14, 1, 27, 103, 126, 75, 89, 50, 120, 19, 32, 110,
+	92, 111, 130, 59, 31, 12, 81, 43, 55, 5, 74, 22, 101,
+	128, 58, 118, 48, 108, 38, 98, 93, 23, 83, 13, 73, 3,
+}
+
+// imbCharactersTable return the 13 bits characters with n bits set, pairs of a character and its reverse
+// from the table start and symmetric characters from its end
+func imbCharactersTable(n int, length int) []int {
+	table := make([]int, length)
+	lower, upper := 0, length-1
+
+	for character := 0; character < 1<<13; character++ {
+		if bits.OnesCount(uint(character)) != n {
+			continue
+		}
+
+		reverse := int(bits.Reverse16(uint16(character)) >> 3)
+		switch {
+		case reverse < character:
+			continue
+		case reverse == character:
+			table[upper] = character
+			upper--
+		default:
+			table[lower], table[lower+1] = character, reverse
+			lower += 2
+		}
+	}
+
+	return table
+}
+
+// imbFrameCheckSequence return the 11 bits CRC of the 102 bits binary data
+func imbFrameCheckSequence(data []byte) int {
+	fcs := 0x07FF
+
+	for i, b := range data {
+		value, length := int(b)<<3, 8
+		// First byte 2 most significant bits are unused
+		if i == 0 {
+			value, length = int(b)<<5, 6
+		}
+
+		for bit := 0; bit < length; bit++ {
+			if (fcs^value)&0x400 != 0 {
+				fcs = (fcs << 1) ^ 0x0F35
+			} else {
+				fcs <<= 1
+			}
+			fcs &= 0x07FF
+			value <<= 1
+		}
+	}
+
+	return fcs
+}
+
+// imbBars return the 65 bars of the Intelligent Mail barcode of tracking and routing codes, following USPS-B-3200:
+// F full bar, A ascender, D descender and T tracker
+func imbBars(tracking string, routing string) (string, error) {
+	if len(tracking) != 20 || tracking[1] > '4' || !digits(tracking) || !digits(routing) {
+		return "", ErrInvalidPostalBarcode
+	}
+
+	// Binary data of routing then tracking code
+	value, _ := new(big.Int).SetString("0"+routing, 10)
+	switch len(routing) {
+	case 0:
+	case 5:
+		value.Add(value, big.NewInt(1))
+	case 9:
+		value.Add(value, big.NewInt(100000+1))
+	case 11:
+		value.Add(value, big.NewInt(1000000000+100000+1))
+	default:
+		return "", ErrInvalidPostalBarcode
+	}
+
+	value.Mul(value, big.NewInt(10)).Add(value, big.NewInt(int64(tracking[0]-'0')))
+	value.Mul(value, big.NewInt(5)).Add(value, big.NewInt(int64(tracking[1]-'0')))
+	for _, digit := range tracking[2:] {
+		value.Mul(value, big.NewInt(10)).Add(value, big.NewInt(int64(digit-'0')))
+	}
+
+	data := make([]byte, 13)
+	value.FillBytes(data)
+	fcs := imbFrameCheckSequence(data)
+
+	// Codewords, J then I to B in base 1365, A the remainder
+	codewords := make([]int, 10)
+	remainder := new(big.Int)
+	value.DivMod(value, big.NewInt(636), remainder)
+	codewords[9] = int(remainder.Int64()) * 2
+	for i := 8; i > 0; i-- {
+		value.DivMod(value, big.NewInt(1365), remainder)
+		codewords[i] = int(remainder.Int64())
+	}
+	codewords[0] = int(value.Int64())
+	if fcs&0x400 != 0 {
+		codewords[0] += 659
+	}
+
+	// Characters, negated following frame check sequence bits
+	characters := make([]int, 10)
+	for i, codeword := range codewords {
+		if codeword < len(imbCharacters5of13) {
+			characters[i] = imbCharacters5of13[codeword]
+		} else {
+			characters[i] = imbCharacters2of13[codeword-len(imbCharacters5of13)]
+		}
+
+		if fcs&(1<<uint(i)) != 0 {
+			characters[i] = ^characters[i] & 0x1FFF
+		}
+	}
+
+	extenders := make([]bool, 130)
+	for i, character := range characters {
+		for bit := 0; bit < 13; bit++ {
+			extenders[imbBarsBits[13*i+bit]-1] = character&(1<<uint(bit)) != 0
+		}
+	}
+
+	bars := strings.Builder{}
+	for i := 0; i < 65; i++ {
+		descender, ascender := extenders[i], extenders[65+i]
+		switch {
+		case descender && ascender:
+			bars.WriteByte('F')
+		case ascender:
+			bars.WriteByte('A')
+		case descender:
+			bars.WriteByte('D')
+		default:
+			bars.WriteByte('T')
+		}
+	}
+
+	return bars.String(), nil
+}
+
+// digits return true when s only holds ASCII digits
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// drawIMb draw the Intelligent Mail barcode of bars at x, y, height high
+func (doc *Document) drawIMb(bars string, x float64, y float64, height float64) {
+	extender := (height - imbTrackerHeight) / 2
+
+	doc.pdf.SetFillColor(0, 0, 0)
+	for i, bar := range bars {
+		top, bottom := y+extender, y+extender+imbTrackerHeight
+		if bar == 'F' || bar == 'A' {
+			top = y
+		}
+		if bar == 'F' || bar == 'D' {
+			bottom = y + height
+		}
+
+		doc.pdf.Rect(x+float64(i)*imbBarPitch, top, imbBarWidth, bottom-top, "F")
+	}
+}
+
+// drawFrankMarkPlaceholder draw the dashed frame of the space left to the Datamatrix frank mark at x, y
+func (doc *Document) drawFrankMarkPlaceholder(x float64, y float64) {
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDrawColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.SetDashPattern([]float64{1, 1}, 0)
+
+	doc.pdf.Rect(x, y, frankMarkSize, frankMarkSize, "D")
+
+	doc.pdf.SetDashPattern([]float64{}, 0)
+	doc.pdf.SetLineWidth(lineWidth)
+	doc.pdf.SetDrawColor(r, g, b)
+
+	r, g, b = doc.pdf.GetTextColor()
+	doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.SetXY(x, y)
+	doc.pdf.CellFormat(frankMarkSize, frankMarkSize, doc.encodeString(doc.Options.TextPostalFrankMark), "0", 0, "C", false, 0, "")
+	doc.pdf.SetTextColor(r, g, b)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
+
+// appendPostalBarcode draw the postal barcode of the customer address at x, below bottom, and return the
+// new bottom. The Datamatrix frank mark is left in the additions and endorsements zone of DIN 5008 windows.
+func (doc *Document) appendPostalBarcode(x float64, y float64, bottom float64) float64 {
+	postal := doc.PostalBarcode
+	if postal == nil {
+		return bottom
+	}
+
+	switch postal.Type {
+	case PostalBarcodeIMb:
+		// Validated on document validation
+		bars, _ := imbBars(postal.TrackingCode, postal.RoutingCode)
+		doc.drawIMb(bars, x, bottom+imbQuietZone, 3*imbTrackerHeight)
+		return bottom + imbQuietZone + 3*imbTrackerHeight
+	case PostalBarcodeDatamatrix:
+		if _, _, ok := doc.addressWindow(); ok && doc.Options.Print.Form != PrintFormNF {
+			doc.drawFrankMarkPlaceholder(x, y-frankMarkEndorsement+(frankMarkEndorsement-frankMarkSize)/2)
+			return bottom
+		}
+
+		doc.drawFrankMarkPlaceholder(x, bottom+2)
+		return bottom + 2 + frankMarkSize
+	}
+
+	return bottom
+}
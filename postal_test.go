@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIMbBars(t *testing.T) {
+	// USPS-B-3200 examples
+	for routing, expected := range map[string]string{
+		"":            "ATTFATTDTTADTAATTDTDTATTDAFDDFADFDFTFFFFFTATFAAAATDFFTDAADFTFDTDT",
+		"01234567891": "AADTFFDFTDADTAADAATFDTDDAAADDTDTTDAFADADDDTFFFDDTTTADFAAADFTDAADA",
+	} {
+		bars, err := imbBars("01234567094987654321", routing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bars != expected {
+			t.Errorf("expected routing %q bars %s, got %s", routing, expected, bars)
+		}
+	}
+
+	for tracking, routing := range map[string]string{
+		"0123456709498765432":  "",
+		"01234567094987654321": "1234",
+		"09234567094987654321": "01234",
+		"0123456709498765432a": "",
+	} {
+		if _, err := imbBars(tracking, routing); err != ErrInvalidPostalBarcode {
+			t.Errorf("expected %v for %s %s, got %v", ErrInvalidPostalBarcode, tracking, routing, err)
+		}
+	}
+}
+
+func TestPostalBarcode(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main St", City: "Springfield", Country: "US"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "2 Side St", City: "Springfield", PostalCode: "62701-1234", Country: "US"}})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.PostalBarcode = &PostalBarcode{Type: PostalBarcodeIMb, TrackingCode: "00 270 123456 200800001"}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.PostalBarcode.RoutingCode != "627011234" || doc.PostalBarcode.TrackingCode != "00270123456200800001" {
+		t.Errorf("unexpected normalized codes %s %s", doc.PostalBarcode.TrackingCode, doc.PostalBarcode.RoutingCode)
+	}
+
+	doc.PostalBarcode.TrackingCode = "1"
+	if _, err := doc.Build(); err != ErrInvalidPostalBarcode {
+		t.Errorf("expected %v, got %v", ErrInvalidPostalBarcode, err)
+	}
+
+	doc, _ = New(Invoice, &Options{DisableCompression: true, Print: NewLetterLayout(PrintFormB)})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "Hauptstraße 1", City: "Berlin"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "Nebenstraße 2", City: "Hamburg"}})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.PostalBarcode = &PostalBarcode{Type: PostalBarcodeDatamatrix}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(FRANKIERUNG)")) {
+		t.Error("expected frank mark placeholder in output")
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import "github.com/shopspring/decimal"
+
+// DisplayPrecision define the decimals amounts and quantities are displayed with, independently of
+// Options.CurrencyPrecision used to round computed amounts
+type DisplayPrecision struct {
+	UnitPrice          int  `json:"unit_price,omitempty" validate:"min=0"` // Unit prices decimals, ex 4, as given when 0
+	Totals             int  `json:"totals,omitempty" validate:"min=0"`     // Formatted totals decimals, Options.CurrencyPrecision when 0
+	Quantity           int  `json:"quantity,omitempty" validate:"min=0"`   // Quantities decimals, as given when 0
+	StripQuantityZeros bool `json:"strip_quantity_zeros,omitempty"`        // Remove quantities trailing zeros, ex 1.500 as 1.5
+}
+
+// totalsPrecision return the decimals of formatted totals
+func (o *Options) totalsPrecision() int {
+	if o.DisplayPrecision != nil && o.DisplayPrecision.Totals > 0 {
+		return o.DisplayPrecision.Totals
+	}
+
+	return o.CurrencyPrecision
+}
+
+// formatUnitPrice return unit price rounded to DisplayPrecision.UnitPrice decimals, then formatted like amounts
+func (doc *Document) formatUnitPrice(amount string) string {
+	display := doc.Options.DisplayPrecision
+	if display == nil || display.UnitPrice == 0 {
+		return doc.formatAmount(amount)
+	}
+
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return doc.amountDigits(amount)
+	}
+	if !doc.formatsAmounts() {
+		return doc.amountDigits(value.StringFixed(int32(display.UnitPrice)))
+	}
+
+	ac := doc.ac
+	ac.Precision = display.UnitPrice
+
+	return doc.amountDigits(ac.FormatMoneyDecimal(value))
+}
+
+// formatQuantity return quantity rounded to DisplayPrecision.Quantity decimals, trailing zeros removed
+// with DisplayPrecision.StripQuantityZeros, as is when it isn't a number
+func (doc *Document) formatQuantity(quantity string) string {
+	display := doc.Options.DisplayPrecision
+	if display == nil || (display.Quantity == 0 && !display.StripQuantityZeros) {
+		return quantity
+	}
+
+	value, err := decimal.NewFromString(quantity)
+	if err != nil {
+		return quantity
+	}
+
+	if display.Quantity > 0 {
+		value = value.Round(int32(display.Quantity))
+		if !display.StripQuantityZeros {
+			return value.StringFixed(int32(display.Quantity))
+		}
+	}
+
+	// Decimal strings have no trailing zeros
+	return value.String()
+}
@@ -0,0 +1,47 @@
+package generator
+
+import "testing"
+
+func TestDisplayPrecision(t *testing.T) {
+	doc, _ := New(Invoice, &Options{
+		Locale:           "de-DE",
+		DisplayPrecision: &DisplayPrecision{UnitPrice: 4, Totals: 2, StripQuantityZeros: true},
+	})
+	doc.SetRef("RE-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Screws", UnitCost: "0.0125", Quantity: "1000.000", Unit: "pcs"})
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	item := doc.Items[0]
+	for _, cell := range []struct {
+		key      string
+		expected string
+	}{
+		{ItemColumnUnitPrice, "0,0125 €"},
+		{ItemColumnQuantity, "1000 pcs"},
+		{ItemColumnTotal, "12,50 €"},
+	} {
+		if text, _ := item.columnText(cell.key, doc); text != cell.expected {
+			t.Errorf("expected %s column %q, got %q", cell.key, cell.expected, text)
+		}
+	}
+
+	doc, _ = New(Invoice, &Options{DisplayPrecision: &DisplayPrecision{UnitPrice: 3, Quantity: 2}})
+	if price := doc.formatUnitPrice("1.5"); price != "1.500" {
+		t.Errorf("expected unit price with 3 decimals, got %s", price)
+	}
+	if quantity := doc.formatQuantity("2.456"); quantity != "2.46" {
+		t.Errorf("expected quantity rounded to 2 decimals, got %s", quantity)
+	}
+	if quantity := doc.formatQuantity("n/a"); quantity != "n/a" {
+		t.Errorf("expected quantity as is, got %s", quantity)
+	}
+
+	doc, _ = New(Invoice, &Options{CurrencyPrecision: 4, DisplayPrecision: &DisplayPrecision{Totals: 2}})
+	if total := doc.ac.FormatMoneyDecimal(doc.TotalWithTax()); total != "€ 0.00" {
+		t.Errorf("expected totals displayed with 2 decimals, got %s", total)
+	}
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// Validation stages, see ValidationIssue
+const (
+	ValidationFields     string = "fields"     // Struct fields validation, Code is the failed rule ex required
+	ValidationAmounts    string = "amounts"    // Amounts, quantities and rates parsing, and document preparation
+	ValidationCompliance string = "compliance" // Compliance profile required fields and rules
+	ValidationLayout     string = "layout"     // Items table and pages fitting the page size and Limits
+)
+
+// estimatedItemsTop is the Y of the items table on the first page, below title, contacts and details,
+// for pages estimates
+const estimatedItemsTop float64 = 100
+
+// ValidationReport define the machine readable result of Validate
+type ValidationReport struct {
+	Valid  bool               `json:"valid"`
+	Pages  int                `json:"pages,omitempty"` // Estimated pages of document, without detail and appendix pages
+	Issues []*ValidationIssue `json:"issues"`
+}
+
+// ValidationIssue define a reason a document can't be built
+type ValidationIssue struct {
+	Stage   string `json:"stage"`
+	Field   string `json:"field,omitempty"` // ex Items[0].UnitCost
+	Code    string `json:"code"`            // ex required, invalid_amount, item_too_tall, too_many_pages
+	Message string `json:"message"`
+}
+
+// add append an issue to report
+func (r *ValidationReport) add(stage string, field string, code string, message string) {
+	r.Issues = append(r.Issues, &ValidationIssue{Stage: stage, Field: field, Code: code, Message: message})
+}
+
+// Validate check document without rendering it, for pre-flight checks of APIs: fields, amounts parsing, rules of
+// the compliance profile (Options.Compliance when empty) and layout feasibility. Every invalid field and amount is
+// reported, preparation stops at the first other issue. doc is left untouched, a copy of it is prepared.
+func Validate(doc *Document, profile string) *ValidationReport {
+	report := &ValidationReport{Issues: []*ValidationIssue{}}
+
+	options := *doc.Options
+	if len(profile) > 0 {
+		options.Compliance = profile
+	}
+
+	// Fields
+	checked := *doc
+	checked.Options = &options
+	if err := validator.New().Struct(&checked); err != nil {
+		var fields validator.ValidationErrors
+		if !errors.As(err, &fields) {
+			report.add(ValidationFields, "", "invalid", err.Error())
+		}
+		for _, field := range fields {
+			report.add(ValidationFields, strings.TrimPrefix(field.Namespace(), "Document."), field.Tag(), field.Error())
+		}
+	}
+
+	// Amounts and preparation update the document, a deep copy is prepared
+	copied, err := doc.clone()
+	if err != nil {
+		if len(report.Issues) == 0 {
+			report.add(ValidationFields, "", "invalid", err.Error())
+		}
+		return report
+	}
+	copied.Options = &options
+	copied.pdf = fpdf.New("P", "mm", "A4", "")
+
+	// Spanish records are checked without calling the signing service
+	if fiscal := copied.SpanishFiscal; fiscal != nil && doc.SpanishFiscal.Signer != nil && len(fiscal.Signature) == 0 {
+		fiscal.Signature = "preflight"
+	}
+
+	// Amounts of each item
+	for i, item := range copied.Items {
+		if item == nil {
+			continue
+		}
+		if item.Rental != nil {
+			if err := item.Rental.apply(item, copied.Options); err != nil {
+				report.add(ValidationAmounts, fmt.Sprintf("Items[%d].Rental", i), "invalid_amount", err.Error())
+				continue
+			}
+		}
+		if err := item.Prepare(); err != nil {
+			report.add(ValidationAmounts, fmt.Sprintf("Items[%d]", i), "invalid_amount", err.Error())
+		} else if _, err := decimal.NewFromString(item.Quantity); err != nil && len(item.Quantity) > 0 {
+			report.add(ValidationAmounts, fmt.Sprintf("Items[%d].Quantity", i), "invalid_amount", err.Error())
+		}
+	}
+
+	// Preparation needs valid fields and amounts
+	if len(report.Issues) > 0 {
+		return report
+	}
+
+	// Preparation and compliance profile
+	if err := copied.Validate(); err != nil {
+		switch {
+		case errors.Is(err, ErrComplianceRequiredField):
+			field := strings.TrimPrefix(err.Error(), ErrComplianceRequiredField.Error()+": ")
+			report.add(ValidationCompliance, field, "compliance", err.Error())
+		case errors.Is(err, ErrUnknownComplianceProfile):
+			report.add(ValidationCompliance, "", "compliance", err.Error())
+		case errors.Is(err, ErrUnknownSection):
+			report.add(ValidationFields, "Options.Sections", "unknown_section", err.Error())
+		case errors.Is(err, ErrInvalidItemColumns):
+			report.add(ValidationLayout, "Options.ItemColumns", "columns_too_wide", err.Error())
+		default:
+			report.add(ValidationAmounts, "", "invalid", err.Error())
+		}
+		return report
+	}
+
+	// Layout
+	copied.checkLayout(report)
+
+	report.Valid = len(report.Issues) == 0
+	return report
+}
+
+// checkLayout estimate the pages of the items table and totals and check each item row fits a page
+func (doc *Document) checkLayout(report *ValidationReport) {
+	doc.registerFallbackFonts()
+	doc.pdf.SetFont(doc.Options.Font, "", 12)
+	doc.emptyCols = doc.emptyItemColumns()
+
+	// Density of Build
+	doc.compact = doc.Options.Density == DensityCompact && doc.Options.Layout != LayoutFolio && doc.Options.Layout != LayoutRoyalty
+	if doc.Options.Density == DensityAuto && doc.Options.Layout != LayoutFolio && doc.Options.Layout != LayoutRoyalty {
+		doc.compact = estimatedItemsTop+doc.itemsHeight()+doc.totalsHeight() > doc.pageBottom()
+	}
+
+	// Rows taller than a page are split across pages
+	pageHeight := doc.pageBottom() - BaseMarginTop
+	if name := doc.itemColumn(ItemColumnName); name != nil && name.Formatter == nil {
+		for i, item := range doc.Items {
+			if doc.itemHeight(item, name.width) > pageHeight-itemsTitlesHeight {
+				report.add(ValidationLayout, fmt.Sprintf("Items[%d]", i), "item_too_tall", "item row is taller than a page")
+			}
+		}
+	}
+
+	// Pages after the first one
+	overflow := estimatedItemsTop + doc.itemsHeight() + doc.totalsHeight() - doc.pageBottom()
+	report.Pages = 1 + int(math.Ceil(math.Max(overflow, 0)/pageHeight))
+
+	if limits := doc.Options.Limits; limits != nil && limits.MaxPages > 0 && report.Pages > limits.MaxPages {
+		report.add(ValidationLayout, "Options.Limits.MaxPages", "too_many_pages", fmt.Sprintf("about %d pages, more than %d", report.Pages, limits.MaxPages))
+	}
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	report := Validate(newQuote(), "")
+	if !report.Valid || report.Pages != 1 || len(report.Issues) != 0 {
+		t.Errorf("expected valid single page document, got %+v", report)
+	}
+
+	// Every invalid field and amount
+	doc := newQuote()
+	doc.Ref = ""
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "ten", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Training", UnitCost: "10", Quantity: "two"})
+	report = Validate(doc, "")
+	if report.Valid || len(report.Issues) != 3 {
+		t.Fatalf("expected 3 issues, got %+v", report.Issues)
+	}
+	if issue := report.Issues[0]; issue.Stage != ValidationFields || issue.Field != "Ref" || issue.Code != "required" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue := report.Issues[1]; issue.Stage != ValidationAmounts || issue.Field != "Items[1]" || issue.Code != "invalid_amount" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue := report.Issues[2]; issue.Field != "Items[2].Quantity" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+
+	// Compliance profile given for the check only
+	doc = newQuote()
+	report = Validate(doc, "FR")
+	if report.Valid || report.Issues[0].Stage != ValidationCompliance || report.Issues[0].Field != "Company.TaxID" {
+		t.Errorf("expected compliance issue, got %+v", report.Issues)
+	}
+	if len(doc.Options.Compliance) != 0 {
+		t.Errorf("expected document options kept, got %s", doc.Options.Compliance)
+	}
+
+	// Pages and rows
+	doc = newQuote()
+	doc.Options.Limits = &Limits{MaxPages: 2}
+	for i := 0; i < 80; i++ {
+		doc.AppendItem(&Item{Name: "Support", UnitCost: "10", Quantity: "1"})
+	}
+	doc.AppendItem(&Item{Name: "Contract", UnitCost: "10", Quantity: "1", Description: strings.Repeat("Clause text\n", 200)})
+	report = Validate(doc, "")
+	if report.Valid || report.Pages <= 2 || len(report.Issues) != 2 {
+		t.Fatalf("expected layout issues, got %d pages and %+v", report.Pages, report.Issues)
+	}
+	if issue := report.Issues[0]; issue.Code != "item_too_tall" || issue.Field != "Items[81]" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue := report.Issues[1]; issue.Stage != ValidationLayout || issue.Code != "too_many_pages" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+}
+
+func TestValidateUntouched(t *testing.T) {
+	doc := newQuote()
+	doc.Options.DataMinimization = DataMinimizationPseudonymize
+	doc.Customer.Phone = "+33 1 23 45 67 89"
+	doc.AppendItem(&Item{Name: "Scaffolding", Rental: &Rental{Start: "01/03/2021", End: "14/03/2021", Period: RentalPeriodWeek, Rate: "120"}})
+	doc.MeterReadings = []*MeterReading{{Meter: "E1", Previous: "100", Current: "350"}}
+	doc.AppendItem(&Item{Name: "Electricity", UnitCost: "0.20", Meter: "E1"})
+
+	if report := Validate(doc, ""); !report.Valid {
+		t.Fatalf("expected valid document, got %+v", report.Issues)
+	}
+	if doc.Customer.Phone != "+33 1 23 45 67 89" {
+		t.Errorf("expected customer phone untouched, got %q", doc.Customer.Phone)
+	}
+	if rental, meter := doc.Items[1], doc.Items[2]; len(rental.Quantity) > 0 || len(rental.UnitCost) > 0 || len(meter.Quantity) > 0 {
+		t.Errorf("expected items untouched, got %s x %s and %s", rental.Quantity, rental.UnitCost, meter.Quantity)
+	}
+}
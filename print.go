@@ -0,0 +1,230 @@
+package generator
+
+// Letter forms, see PrintLayout.Form
+const (
+	PrintFormA  string = "A"  // DIN 5008 form A, address window higher
+	PrintFormB  string = "B"  // DIN 5008 form B
+	PrintFormNF string = "NF" // French NF Z 10-011, address window on the right
+)
+
+// Print marks positions and sizes in millimeters
+const (
+	printBleed          float64 = 3
+	printCropMarksGap   float64 = 0.5
+	printMarksX         float64 = 4
+	printFoldMarkLength float64 = 5
+	printPunchMarkY     float64 = 148.5
+	printPunchMarkLen   float64 = 8
+	printAddressWindowX float64 = 25
+	printInfoBlockX     float64 = 125
+)
+
+// PrintLayout define marks and margins of documents physically printed and mailed
+type PrintLayout struct {
+	// CropMarks draw marks at page corners, Bleed millimeters inside page edges, and set PDF trim box
+	CropMarks bool    `json:"crop_marks,omitempty"`
+	Bleed     float64 `json:"bleed,omitempty" validate:"min=0,max=10"` // 3 when 0
+
+	// Form select the letter form of fold marks, address window and info block, B when empty
+	Form string `json:"form,omitempty" validate:"omitempty,oneof=A B NF"`
+	// FoldMarks draw fold marks and the punch mark on the left edge of the first page, for windowed envelopes
+	FoldMarks bool `json:"fold_marks,omitempty"`
+	// AddressWindow place the customer address in the envelope window of the form, the company beside it
+	AddressWindow bool `json:"address_window,omitempty"`
+	// InfoBlock place references and date in the DIN 5008 info block, right of the address window
+	InfoBlock bool `json:"info_block,omitempty"`
+
+	// Duplex print recto verso: gutter mirrored on back pages and page count padded to even,
+	// documents of a batch starting on a new sheet
+	Duplex bool `json:"duplex,omitempty"`
+	// Gutter shift pages content away from the binding edge in millimeters, to the right of front pages
+	Gutter float64 `json:"gutter,omitempty" validate:"min=0,max=8"`
+}
+
+// NewLetterLayout return the preset print layout of letters of form, sent in windowed envelopes:
+// fold marks, customer address in the window and, for DIN 5008, references in the info block
+func NewLetterLayout(form string) *PrintLayout {
+	return &PrintLayout{
+		Form:          form,
+		FoldMarks:     true,
+		AddressWindow: true,
+		InfoBlock:     form != PrintFormNF,
+	}
+}
+
+// foldMarks return the fold marks positions from page top of the layout form
+func (l *PrintLayout) foldMarks() []float64 {
+	switch l.Form {
+	case PrintFormA:
+		return []float64{87, 192}
+	case PrintFormNF:
+		return []float64{99, 198}
+	}
+
+	return []float64{105, 210}
+}
+
+// addressWindow return the position of the address in the window of the layout form,
+// below the additions and endorsements zone of DIN 5008 forms
+func (l *PrintLayout) addressWindow() (float64, float64) {
+	switch l.Form {
+	case PrintFormA:
+		return printAddressWindowX, 27 + 17.7
+	case PrintFormNF:
+		return 110, 50
+	}
+
+	return printAddressWindowX, 45 + 17.7
+}
+
+// pageShift return the horizontal shift of page content, the gutter mirrored on back pages in duplex
+func (l *PrintLayout) pageShift(page int) float64 {
+	if l.Duplex && page%2 == 0 {
+		return -l.Gutter
+	}
+
+	return l.Gutter
+}
+
+// setHeaderFunc set the pdf header func, kept to be wrapped by print layout
+func (doc *Document) setHeaderFunc(fn func()) {
+	doc.headerFunc = fn
+	doc.pdf.SetHeaderFunc(fn)
+}
+
+// setFooterFunc set the pdf footer func, kept to be wrapped by print layout
+func (doc *Document) setFooterFunc(fn func()) {
+	doc.footerFunc = fn
+	doc.pdf.SetFooterFunc(fn)
+}
+
+// applyPrintLayout draw print marks at each page start and shift its content following Options.Print,
+// around header and footer funcs
+func (doc *Document) applyPrintLayout() {
+	if doc.Options.Print == nil {
+		return
+	}
+
+	header, footer := doc.headerFunc, doc.footerFunc
+	doc.pdf.SetHeaderFunc(func() {
+		doc.drawPageBackground()
+		doc.drawPrintMarks()
+		doc.beginPageShift()
+		if header != nil {
+			header()
+		}
+	})
+	doc.pdf.SetFooterFunc(func() {
+		// The last page footer is drawn on output, after layout ended its shift
+		doc.beginPageShift()
+		if footer != nil {
+			footer()
+		}
+		doc.endPageShift()
+	})
+}
+
+// beginPageShift shift content drawn on current page following Options.Print gutter, until endPageShift
+func (doc *Document) beginPageShift() {
+	if doc.shifted || doc.Options.Print == nil {
+		return
+	}
+
+	if shift := doc.Options.Print.pageShift(doc.pdf.PageNo()); shift != 0 {
+		doc.pdf.TransformBegin()
+		doc.pdf.TransformTranslateX(shift)
+		doc.shifted = true
+	}
+}
+
+// endPageShift end the shift of current page content, pdf transforms being closed before output
+func (doc *Document) endPageShift() {
+	if doc.shifted {
+		doc.pdf.TransformEnd()
+		doc.shifted = false
+	}
+}
+
+// drawPrintMarks draw crop marks and fold marks of Options.Print on current page
+func (doc *Document) drawPrintMarks() {
+	layout := doc.Options.Print
+	width, height := doc.pdf.GetPageSize()
+
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+	doc.pdf.SetLineWidth(0.1)
+	doc.pdf.SetDrawColor(0, 0, 0)
+
+	if layout.CropMarks {
+		bleed := layout.Bleed
+		if bleed == 0 {
+			bleed = printBleed
+		}
+		doc.pdf.SetPageBox("trim", bleed, bleed, width-2*bleed, height-2*bleed)
+
+		// Marks in the bleed along trim edges, clear of the trimmed area
+		mark := bleed - printCropMarksGap
+		for _, x := range []float64{bleed, width - bleed} {
+			doc.pdf.Line(x, 0, x, mark)
+			doc.pdf.Line(x, height-mark, x, height)
+		}
+		for _, y := range []float64{bleed, height - bleed} {
+			doc.pdf.Line(0, y, mark, y)
+			doc.pdf.Line(width-mark, y, width, y)
+		}
+	}
+
+	if layout.FoldMarks && doc.pdf.PageNo() == 1 {
+		for _, y := range layout.foldMarks() {
+			doc.pdf.Line(printMarksX, y, printMarksX+printFoldMarkLength, y)
+		}
+		doc.pdf.Line(printMarksX, printPunchMarkY, printMarksX+printPunchMarkLen, printPunchMarkY)
+	}
+
+	doc.pdf.SetLineWidth(lineWidth)
+	doc.pdf.SetDrawColor(r, g, b)
+}
+
+// addressWindow return the position of the customer address in the envelope window, false without one
+func (doc *Document) addressWindow() (float64, float64, bool) {
+	if doc.Options.Print == nil || !doc.Options.Print.AddressWindow {
+		return 0, 0, false
+	}
+
+	x, y := doc.Options.Print.addressWindow()
+	return x, y, true
+}
+
+// letterCompanyPosition return the position of the company contact beside the address window: on the right
+// of DIN 5008 windows, below the info block when drawn, at the top left of NF Z 10-011 letters
+func (doc *Document) letterCompanyPosition() (float64, float64) {
+	if doc.Options.Print.Form == PrintFormNF {
+		return BaseMargin, BaseMarginTop
+	}
+	if _, y, ok := doc.infoBlock(); ok {
+		return 130, y + 16
+	}
+
+	return 130, BaseMarginTop + 25
+}
+
+// infoBlock return the position of the DIN 5008 info block, false when metas are drawn below the title
+func (doc *Document) infoBlock() (float64, float64, bool) {
+	layout := doc.Options.Print
+	if layout == nil || !layout.InfoBlock || layout.Form == PrintFormNF {
+		return 0, 0, false
+	}
+
+	if layout.Form == PrintFormA {
+		return printInfoBlockX, 32, true
+	}
+
+	return printInfoBlockX, 50, true
+}
+
+// padDuplexPages add a blank back page to documents printed recto verso with an odd page count
+func (doc *Document) padDuplexPages() {
+	if doc.Options.Print != nil && doc.Options.Print.Duplex && doc.pdf.PageCount()%2 == 1 {
+		doc.pdf.AddPage()
+	}
+}
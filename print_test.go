@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintLayout(t *testing.T) {
+	doc, _ := New(Invoice, &Options{
+		DisableCompression: true,
+		Print: &PrintLayout{
+			CropMarks:     true,
+			FoldMarks:     true,
+			AddressWindow: true,
+			Duplex:        true,
+			Gutter:        5,
+		},
+	})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street", City: "Berlin"}})
+	doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "2 Side street", City: "Hamburg"}})
+	doc.SetHeader(&HeaderFooter{Text: "Header"})
+	doc.SetFooter(&HeaderFooter{Text: "Footer", Pagination: true})
+	for i := 0; i < 30; i++ {
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	}
+	doc.AppendAnnotation(&Annotation{X: 10, Y: 290, Text: "Scanned"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageCount()%2 != 0 {
+		t.Errorf("expected even page count, got %d", pdf.PageCount())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"/TrimBox [8.50 8.50 586.78 833.39]", "1.00000 14.17323 -0.00000 cm", "1.00000 -14.17323 -0.00000 cm", "(Footer)", "(Scanned)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %s in output", expected)
+		}
+	}
+}
+
+func TestPrintLayoutForms(t *testing.T) {
+	layout := &PrintLayout{Form: PrintFormA, Gutter: 4}
+	if marks := layout.foldMarks(); marks[0] != 87 || marks[1] != 192 {
+		t.Errorf("unexpected form A fold marks %v", marks)
+	}
+	if layout.pageShift(2) != 4 {
+		t.Error("expected gutter on every page without duplex")
+	}
+
+	layout = &PrintLayout{Duplex: true, Gutter: 4}
+	if marks := layout.foldMarks(); marks[0] != 105 || marks[1] != 210 {
+		t.Errorf("unexpected form B fold marks %v", marks)
+	}
+	if layout.pageShift(1) != 4 || layout.pageShift(2) != -4 {
+		t.Error("expected gutter mirrored on back pages")
+	}
+}
+
+func TestLetterLayouts(t *testing.T) {
+	for form, expected := range map[string][]string{
+		// Customer in the left window, references in the info block
+		PrintFormB: {"73.70 649.82 Td (Customer)", "357.17 692.09 Td (Ref.: 1)"},
+		// Customer in the right window, references below the title
+		PrintFormNF: {"314.65 685.82 Td (Customer)", "31.18 770.86 Td (Company)"},
+	} {
+		doc, _ := New(Invoice, &Options{DisableCompression: true, Print: NewLetterLayout(form)})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street", City: "Berlin"}})
+		doc.SetCustomer(&Contact{Name: "Customer", Address: &Address{Address: "2 Side street", City: "Hamburg"}})
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+		buffer := &bytes.Buffer{}
+		if err := doc.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		for _, text := range expected {
+			if !bytes.Contains(buffer.Bytes(), []byte(text)) {
+				t.Errorf("expected %s in form %s output", text, form)
+			}
+		}
+	}
+}
@@ -0,0 +1,558 @@
+package generator
+
+import (
+	"errors"
+)
+
+// ErrQRCodeTooLong when content does not fit in the largest QR code version
+var ErrQRCodeTooLong = errors.New("qr code content too long")
+
+// QR code error correction levels
+const (
+	QRCodeECLevelL string = "L" // Recovers 7% of data
+	QRCodeECLevelM string = "M" // Recovers 15% of data
+	QRCodeECLevelQ string = "Q" // Recovers 25% of data
+	QRCodeECLevelH string = "H" // Recovers 30% of data
+)
+
+// qrECLevelIndex map error correction level to tables index and format bits
+var qrECLevelIndex = map[string]struct{ index, formatBits int }{
+	QRCodeECLevelL: {0, 1},
+	QRCodeECLevelM: {1, 0},
+	QRCodeECLevelQ: {2, 3},
+	QRCodeECLevelH: {3, 2},
+}
+
+// qrECCCodewordsPerBlock indexed by error correction level and version
+var qrECCCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrNumErrorCorrectionBlocks indexed by error correction level and version
+var qrNumErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrCode define an encoded QR code symbol (byte mode)
+type qrCode struct {
+	version    int
+	size       int
+	ecl        int
+	formatBits int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// newQRCode encode content as a QR code using the smallest version fitting the error correction level
+func newQRCode(content string, ecLevel string) (*qrCode, error) {
+	level, ok := qrECLevelIndex[ecLevel]
+	if !ok {
+		level = qrECLevelIndex[QRCodeECLevelM]
+	}
+
+	data := []byte(content)
+
+	// Find smallest version
+	version := 1
+	for ; version <= 40; version++ {
+		countBits := 8
+		if version > 9 {
+			countBits = 16
+		}
+
+		if 4+countBits+len(data)*8 <= qrNumDataCodewords(version, level.index)*8 && len(data) < 1<<uint(countBits) {
+			break
+		}
+	}
+
+	if version > 40 {
+		return nil, ErrQRCodeTooLong
+	}
+
+	// Byte mode segment
+	bits := &qrBitBuffer{}
+	bits.append(0x4, 4)
+	if version > 9 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// Terminator and padding
+	capacity := qrNumDataCodewords(version, level.index) * 8
+	terminator := capacity - len(*bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(*bits)%8)%8)
+	for pad := 0xEC; len(*bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(*bits)/8)
+	for i, bit := range *bits {
+		if bit {
+			codewords[i>>3] |= 1 << uint(7-(i&7))
+		}
+	}
+
+	qr := &qrCode{
+		version:    version,
+		size:       version*4 + 17,
+		ecl:        level.index,
+		formatBits: level.formatBits,
+	}
+
+	qr.modules = make([][]bool, qr.size)
+	qr.isFunction = make([][]bool, qr.size)
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, qr.size)
+		qr.isFunction[i] = make([]bool, qr.size)
+	}
+
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(qr.addECCAndInterleave(codewords))
+
+	// Choose best mask
+	bestMask, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+
+		if penalty := qr.penaltyScore(); minPenalty < 0 || penalty < minPenalty {
+			bestMask, minPenalty = mask, penalty
+		}
+
+		// Undo mask (xor)
+		qr.applyMask(mask)
+	}
+
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+
+	return qr, nil
+}
+
+// qrBitBuffer is a sequence of bits
+type qrBitBuffer []bool
+
+// append the n lower bits of value, most significant first
+func (b *qrBitBuffer) append(value int, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 != 0)
+	}
+}
+
+// qrNumRawDataModules return the number of data modules of a version, including remainder bits
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+// qrNumDataCodewords return the number of data codewords of a version and error correction level
+func qrNumDataCodewords(version int, ecl int) int {
+	return qrNumRawDataModules(version)/8 - qrECCCodewordsPerBlock[ecl][version]*qrNumErrorCorrectionBlocks[ecl][version]
+}
+
+// setFunctionModule set a function pattern module
+func (qr *qrCode) setFunctionModule(x int, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+// alignmentPatternPositions return alignment patterns centers
+func (qr *qrCode) alignmentPatternPositions() []int {
+	if qr.version == 1 {
+		return []int{}
+	}
+
+	numAlign := qr.version/7 + 2
+	step := (qr.version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, qr.size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+
+	return result
+}
+
+// drawFunctionPatterns draw timing, finder and alignment patterns, reserve format and version areas
+func (qr *qrCode) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < qr.size; i++ {
+		qr.setFunctionModule(6, i, i%2 == 0)
+		qr.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns
+	for _, center := range [][2]int{{3, 3}, {qr.size - 4, 3}, {3, qr.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x < 0 || x >= qr.size || y < 0 || y >= qr.size {
+					continue
+				}
+
+				dist := qrMax(qrAbs(dx), qrAbs(dy))
+				qr.setFunctionModule(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	// Alignment patterns
+	positions := qr.alignmentPatternPositions()
+	numAlign := len(positions)
+	for i := 0; i < numAlign; i++ {
+		for j := 0; j < numAlign; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == numAlign-1) || (i == numAlign-1 && j == 0) {
+				continue
+			}
+
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.setFunctionModule(positions[i]+dx, positions[j]+dy, qrMax(qrAbs(dx), qrAbs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve format bits and draw version
+	qr.drawFormatBits(0)
+	qr.drawVersion()
+}
+
+// drawFormatBits draw both copies of format bits for given mask
+func (qr *qrCode) drawFormatBits(mask int) {
+	data := qr.formatBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	bit := func(i int) bool {
+		return (bits>>uint(i))&1 != 0
+	}
+
+	// First copy
+	for i := 0; i <= 5; i++ {
+		qr.setFunctionModule(8, i, bit(i))
+	}
+	qr.setFunctionModule(8, 7, bit(6))
+	qr.setFunctionModule(8, 8, bit(7))
+	qr.setFunctionModule(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunctionModule(14-i, 8, bit(i))
+	}
+
+	// Second copy
+	for i := 0; i < 8; i++ {
+		qr.setFunctionModule(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunctionModule(8, qr.size-15+i, bit(i))
+	}
+
+	// Dark module
+	qr.setFunctionModule(8, qr.size-8, true)
+}
+
+// drawVersion draw version informations (version 7 and above)
+func (qr *qrCode) drawVersion() {
+	if qr.version < 7 {
+		return
+	}
+
+	rem := qr.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := qr.version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a, b := qr.size-11+i%3, i/3
+		qr.setFunctionModule(a, b, dark)
+		qr.setFunctionModule(b, a, dark)
+	}
+}
+
+// addECCAndInterleave split data in blocks, append error correction codewords and interleave blocks
+func (qr *qrCode) addECCAndInterleave(data []byte) []byte {
+	numBlocks := qrNumErrorCorrectionBlocks[qr.ecl][qr.version]
+	blockECCLen := qrECCCodewordsPerBlock[qr.ecl][qr.version]
+	rawCodewords := qrNumRawDataModules(qr.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(blockECCLen)
+
+	blocks := make([][]byte, 0, numBlocks)
+	k := 0
+	for i := 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, data[k:k+dataLen]...)
+		k += dataLen
+
+		ecc := qrReedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+
+		blocks = append(blocks, append(block, ecc...))
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// drawCodewords place data codewords in zigzag order
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i>>3]>>uint(7-(i&7)))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask xor data modules with mask pattern
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.isFunction[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			default:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			qr.modules[y][x] = qr.modules[y][x] != invert
+		}
+	}
+}
+
+// penaltyScore compute the mask penalty of current modules
+func (qr *qrCode) penaltyScore() int {
+	result := 0
+	dark := 0
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+
+	module := func(x, y int, vertical bool) bool {
+		if vertical {
+			return qr.modules[x][y]
+		}
+		return qr.modules[y][x]
+	}
+
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < qr.size; y++ {
+			// Runs of same color
+			runLen := 1
+			for x := 1; x < qr.size; x++ {
+				if module(x, y, vertical) == module(x-1, y, vertical) {
+					runLen++
+					continue
+				}
+				if runLen >= 5 {
+					result += 3 + runLen - 5
+				}
+				runLen = 1
+			}
+			if runLen >= 5 {
+				result += 3 + runLen - 5
+			}
+
+			// Finder like patterns
+			for x := 0; x+11 <= qr.size; x++ {
+				matchA, matchB := true, true
+				for k := 0; k < 11; k++ {
+					m := module(x+k, y, vertical)
+					matchA = matchA && m == finderA[k]
+					matchB = matchB && m == finderB[k]
+				}
+				if matchA {
+					result += 40
+				}
+				if matchB {
+					result += 40
+				}
+			}
+		}
+	}
+
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.modules[y][x] {
+				dark++
+			}
+
+			// 2x2 blocks of same color
+			if x > 0 && y > 0 {
+				c := qr.modules[y][x]
+				if c == qr.modules[y][x-1] && c == qr.modules[y-1][x] && c == qr.modules[y-1][x-1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	// Dark / light balance
+	total := qr.size * qr.size
+	k := (qrAbs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+
+	return result
+}
+
+// qrReedSolomonDivisor return the Reed-Solomon generator polynomial of given degree
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = qrReedSolomonMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrReedSolomonMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+// qrReedSolomonRemainder return the error correction codewords of data
+func qrReedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+
+		for i := range result {
+			result[i] ^= qrReedSolomonMultiply(divisor[i], factor)
+		}
+	}
+
+	return result
+}
+
+// qrReedSolomonMultiply multiply two elements of GF(2^8/0x11D)
+func qrReedSolomonMultiply(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+// qrAbs return absolute value of integer
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// qrMax return the maximum of two integers
+func qrMax(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// drawQRCode encode content and draw it as a size x size square at x, y
+func (doc *Document) drawQRCode(content string, ecLevel string, x float64, y float64, size float64) error {
+	qr, err := newQRCode(content, ecLevel)
+	if err != nil {
+		return err
+	}
+
+	moduleSize := size / float64(qr.size)
+
+	doc.pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < qr.size; row++ {
+		for col := 0; col < qr.size; col++ {
+			if qr.modules[row][col] {
+				doc.pdf.Rect(x+float64(col)*moduleSize, y+float64(row)*moduleSize, moduleSize, moduleSize, "F")
+			}
+		}
+	}
+
+	return nil
+}
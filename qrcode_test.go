@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// decodeQRCode read back a symbol produced by newQRCode, checking error correction codewords
+func decodeQRCode(t *testing.T, qr *qrCode) string {
+	// Format bits (first copy)
+	bits := 0
+	get := func(x, y int) int {
+		if qr.modules[y][x] {
+			return 1
+		}
+		return 0
+	}
+	for i := 0; i <= 5; i++ {
+		bits |= get(8, i) << uint(i)
+	}
+	bits |= get(8, 7)<<6 | get(8, 8)<<7 | get(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		bits |= get(14-i, 8) << uint(i)
+	}
+	bits ^= 0x5412
+	mask := (bits >> 10) & 7
+
+	// Unmask a copy of the symbol
+	copied := &qrCode{version: qr.version, size: qr.size, ecl: qr.ecl, formatBits: qr.formatBits}
+	copied.modules = make([][]bool, qr.size)
+	copied.isFunction = make([][]bool, qr.size)
+	for i := range qr.modules {
+		copied.modules[i] = make([]bool, qr.size)
+		copied.isFunction[i] = make([]bool, qr.size)
+	}
+	copied.drawFunctionPatterns()
+	for y := range qr.modules {
+		copy(copied.modules[y], qr.modules[y])
+	}
+	copied.applyMask(mask)
+
+	// Read codewords in zigzag order
+	raw := make([]byte, qrNumRawDataModules(qr.version)/8)
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !copied.isFunction[y][x] && i < len(raw)*8 {
+					if copied.modules[y][x] {
+						raw[i>>3] |= 1 << uint(7-(i&7))
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// De-interleave blocks and check error correction
+	numBlocks := qrNumErrorCorrectionBlocks[qr.ecl][qr.version]
+	eccLen := qrECCCodewordsPerBlock[qr.ecl][qr.version]
+	numShortBlocks := numBlocks - len(raw)%numBlocks
+	shortBlockLen := len(raw) / numBlocks
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortBlockLen+1; i++ {
+		for j := 0; j < numBlocks; j++ {
+			if i == shortBlockLen-eccLen && j < numShortBlocks {
+				continue
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	divisor := qrReedSolomonDivisor(eccLen)
+	data := []byte{}
+	for _, block := range blocks {
+		dataLen := len(block) - eccLen
+		ecc := qrReedSolomonRemainder(block[:dataLen], divisor)
+		if string(ecc) != string(block[dataLen:]) {
+			t.Fatalf("invalid error correction codewords in version %d", qr.version)
+		}
+		data = append(data, block[:dataLen]...)
+	}
+
+	// Byte mode segment
+	readBits := func(pos, n int) int {
+		v := 0
+		for i := pos; i < pos+n; i++ {
+			v = v<<1 | int((data[i>>3]>>uint(7-(i&7)))&1)
+		}
+		return v
+	}
+	if readBits(0, 4) != 0x4 {
+		t.Fatalf("invalid mode")
+	}
+	countBits := 8
+	if qr.version > 9 {
+		countBits = 16
+	}
+	count := readBits(4, countBits)
+	content := make([]byte, count)
+	for i := range content {
+		content[i] = byte(readBits(4+countBits+i*8, 8))
+	}
+
+	return string(content)
+}
+
+func TestQRCode(t *testing.T) {
+	contents := []string{
+		"A:123456789*B:999999990*C:PT*D:FT*E:N*F:20210301*G:FT A/1*H:CSDF7T5H-1",
+		"https://pay.example.com/INV-0001",
+		strings.Repeat("0123456789", 60),
+	}
+
+	for _, content := range contents {
+		for _, level := range []string{QRCodeECLevelL, QRCodeECLevelM, QRCodeECLevelQ, QRCodeECLevelH} {
+			qr, err := newQRCode(content, level)
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+
+			if decoded := decodeQRCode(t, qr); decoded != content {
+				t.Errorf("expected %q, got %q", content, decoded)
+			}
+		}
+	}
+
+	if _, err := newQRCode(strings.Repeat("a", 3000), QRCodeECLevelL); err != ErrQRCodeTooLong {
+		t.Errorf("expected ErrQRCodeTooLong, got %v", err)
+	}
+}
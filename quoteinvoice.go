@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrNotQuotation when converting a document which isn't a quotation
+var ErrNotQuotation = errors.New("document is not a quotation")
+
+// ErrUnknownQuoteOption when an accepted option key matches no quote option
+var ErrUnknownQuoteOption = errors.New("unknown quote option")
+
+// ToInvoice return the invoice of an accepted quote: quote items followed by the items of accepted options,
+// in the given order, linked back to the quote with QuoteRef. Ref is left empty for the invoice numbering,
+// date is set to today and a payment term date is moved by the same number of days. Validity, options,
+// acceptance and version of the quote are cleared. Options are shared with the quote.
+func (doc *Document) ToInvoice(acceptedOptions ...string) (*Document, error) {
+	if doc.Type != Quotation {
+		return nil, ErrNotQuotation
+	}
+
+	invoice, err := doc.clone()
+	if err != nil {
+		return nil, err
+	}
+	invoice.Type = Invoice
+
+	for _, key := range acceptedOptions {
+		var accepted *QuoteOption
+		for _, option := range invoice.QuoteOptions {
+			if option.Key == key {
+				accepted = option
+				break
+			}
+		}
+		if accepted == nil {
+			return nil, ErrUnknownQuoteOption
+		}
+
+		invoice.Items = append(invoice.Items, accepted.Items...)
+	}
+
+	invoice.QuoteRef = doc.Ref
+	invoice.Ref = ""
+	invoice.Version = ""
+	invoice.QuoteOptions = nil
+	invoice.Acceptance = nil
+	invoice.ValidityDate = ""
+	invoice.ValidForDays = 0
+
+	// Payment term date keeps its delay from document date
+	if len(invoice.Date) > 0 {
+		if date, err := time.Parse(doc.Options.DateFormat, invoice.Date); err == nil {
+			now := time.Now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			days := int(today.Sub(date).Hours() / 24)
+			if term, err := time.Parse(doc.Options.DateFormat, invoice.PaymentTerm); err == nil {
+				invoice.PaymentTerm = term.AddDate(0, 0, days).Format(doc.Options.DateFormat)
+			}
+		}
+	}
+	invoice.Date = time.Now().Format(doc.Options.DateFormat)
+
+	return invoice, nil
+}
+
+// clone return a copy of document sharing its options, ex to convert it to another document
+func (doc *Document) clone() (*Document, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	cloned, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Options not carried by JSON (Fetcher, AnnotatePage...) are kept
+	cloned.Options = doc.Options
+
+	return cloned, nil
+}
+
+// appendQuoteRef append the ref of the quote the document was converted from
+func (doc *Document) appendQuoteRef() {
+	if len(doc.QuoteRef) == 0 {
+		return
+	}
+
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+5)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 4, doc.encodeString(doc.Options.TextQuoteRefTitle+": "+doc.QuoteRef), "0", 2, "L", false, 0, "")
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestToInvoice(t *testing.T) {
+	quote := newQuote()
+	quote.SetPaymentTerm("31/03/2024")
+	quote.ValidForDays = 30
+	quote.Acceptance = &Acceptance{SignerName: "Jane Buyer"}
+	quote.AppendQuoteOption(&QuoteOption{Key: "A", Items: []*Item{{Name: "Basic", UnitCost: "500", Quantity: "1"}}})
+	quote.AppendQuoteOption(&QuoteOption{Key: "B", Items: []*Item{{Name: "Premium", UnitCost: "900", Quantity: "1"}}})
+
+	invoice, err := quote.ToInvoice("B")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if invoice.Type != Invoice || invoice.QuoteRef != "Q-001" || len(invoice.Ref) != 0 {
+		t.Errorf("unexpected type %s, quote ref %s or ref %s", invoice.Type, invoice.QuoteRef, invoice.Ref)
+	}
+	if len(invoice.Items) != 2 || invoice.Items[1].Name != "Premium" {
+		t.Errorf("expected quote items then option B items, got %d items", len(invoice.Items))
+	}
+	if invoice.QuoteOptions != nil || invoice.Acceptance != nil || invoice.ValidForDays != 0 {
+		t.Error("expected quote options, acceptance and validity cleared")
+	}
+	today := time.Now().Format(quote.Options.DateFormat)
+	if invoice.Date != today {
+		t.Errorf("expected date %s, got %s", today, invoice.Date)
+	}
+	if term := time.Now().AddDate(0, 0, 30).Format(quote.Options.DateFormat); invoice.PaymentTerm != term {
+		t.Errorf("expected payment term %s, got %s", term, invoice.PaymentTerm)
+	}
+	if len(quote.Items) != 1 || quote.Ref != "Q-001" {
+		t.Error("expected quote left unchanged")
+	}
+
+	invoice.SetRef("INV-001")
+	pdf, err := invoice.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(Quotation ref.: Q-001)")) {
+		t.Error("expected quote ref in PDF")
+	}
+
+	if _, err := quote.ToInvoice("C"); err != ErrUnknownQuoteOption {
+		t.Errorf("expected ErrUnknownQuoteOption, got %v", err)
+	}
+	if _, err := invoice.ToInvoice(); err != ErrNotQuotation {
+		t.Errorf("expected ErrNotQuotation, got %v", err)
+	}
+}
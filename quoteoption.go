@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// QuoteOption define a mutually exclusive alternative of a quote, ex Option A: Basic, Option B: Premium.
+// Its items are priced with their own subtotal and are not included in document totals.
+type QuoteOption struct {
+	Key         string  `json:"key" validate:"required,max=8"` // ex A
+	Title       string  `json:"title,omitempty" validate:"max=128"`
+	Description string  `json:"description,omitempty" validate:"max=1024"`
+	Items       []*Item `json:"items" validate:"required,min=1,dive"`
+}
+
+// Prepare apply default tax and convert items strings to decimal
+func (o *QuoteOption) Prepare(doc *Document) error {
+	for _, item := range o.Items {
+		if item.Tax == nil {
+			item.Tax = doc.DefaultTax
+		}
+
+		if err := item.Prepare(); err != nil {
+			return err
+		}
+
+		if !doc.Options.AllowNegativeLines && item.negative() {
+			return ErrNegativeLine
+		}
+	}
+
+	return nil
+}
+
+// Subtotal return option items total without tax, discounts applied
+func (o *QuoteOption) Subtotal() decimal.Decimal {
+	total := decimal.NewFromInt(0)
+	for _, item := range o.Items {
+		total = total.Add(item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	return total
+}
+
+// TotalWithTax return option items total with tax, discounts applied
+func (o *QuoteOption) TotalWithTax() decimal.Decimal {
+	total := decimal.NewFromInt(0)
+	for _, item := range o.Items {
+		total = total.Add(item.TotalWithTaxAndDiscount())
+	}
+
+	return total
+}
+
+// title return option title, ex Option A: Basic
+func (o *QuoteOption) title(options *Options) string {
+	title := fmt.Sprintf("%s %s", options.TextQuoteOptionTitle, o.Key)
+	if len(o.Title) > 0 {
+		title += ": " + o.Title
+	}
+
+	return title
+}
+
+// appendQuoteOptions append each option of quotes with its items and subtotal, then the non-binding note
+func (doc *Document) appendQuoteOptions() {
+	if len(doc.QuoteOptions) == 0 || doc.Type != Quotation {
+		return
+	}
+
+	doc.pdf.SetY(doc.blockY(30) - 5)
+
+	for _, option := range doc.QuoteOptions {
+		// Keep option title with its first rows
+		if doc.pdf.GetY()+30 > doc.pageBottom() {
+			doc.pdf.AddPage()
+		}
+
+		doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+5)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+		doc.pdf.CellFormat(190, 6, doc.encodeString(option.title(doc.Options)), "0", 2, "L", false, 0, "")
+		if len(option.Description) > 0 {
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+			doc.pdf.MultiCell(190, 4, doc.encodeString(option.Description), "0", "L", false)
+		}
+
+		doc.appendItemRows(option.Items)
+
+		subtotal := fmt.Sprintf(
+			"%s %s: %s (%s: %s)",
+			doc.Options.TextQuoteOptionSubtotalTitle,
+			option.Key,
+			doc.formatMoney(option.Subtotal()),
+			doc.Options.TextTotalWithTax,
+			doc.formatMoney(option.TotalWithTax()),
+		)
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(190, 5, doc.encodeString(subtotal), "T", 2, "R", false, 0, "")
+	}
+
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+3)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.MultiCell(190, 3.5, doc.encodeString(doc.Options.TextQuoteOptionsNote), "0", "L", false)
+	doc.pdf.SetTextColor(doc.Options.BaseTextColor[0], doc.Options.BaseTextColor[1], doc.Options.BaseTextColor[2])
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestQuoteOptions(t *testing.T) {
+	doc := newQuote()
+	doc.SetDefaultTax(&Tax{Percent: "20"})
+	doc.AppendQuoteOption(&QuoteOption{Key: "A", Title: "Basic", Items: []*Item{{Name: "Paint walls", UnitCost: "500", Quantity: "1"}}})
+	doc.AppendQuoteOption(&QuoteOption{Key: "B", Title: "Premium", Description: "Walls and ceilings", Items: []*Item{
+		{Name: "Paint walls", UnitCost: "500", Quantity: "1"},
+		{Name: "Paint ceilings", UnitCost: "300", Quantity: "1", Discount: &Discount{Percent: "10"}},
+	}})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total := doc.TotalWithoutTax(); !total.Equal(doc.Items[0].TotalWithoutTaxAndWithDiscount()) {
+		t.Errorf("expected options excluded from totals, got %s", total)
+	}
+	if subtotal := doc.QuoteOptions[1].Subtotal().StringFixed(2); subtotal != "770.00" {
+		t.Errorf("expected option B subtotal 770.00, got %s", subtotal)
+	}
+	if total := doc.QuoteOptions[1].TotalWithTax().StringFixed(2); total != "924.00" {
+		t.Errorf("expected option B total with tax 924.00, got %s", total)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"(Option A: Basic)",
+		"(Option B: Premium)",
+		"(Walls and ceilings)",
+		"(Subtotal option B: ",
+		"(Options are alternatives, only one of them can be chosen.",
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	doc = newQuote()
+	doc.QuoteOptions = []*QuoteOption{{Key: "A"}}
+	if err := doc.Validate(); err == nil {
+		t.Error("expected an error for an option without items")
+	}
+}
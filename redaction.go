@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"regexp"
+
+	"github.com/creasty/defaults"
+	"github.com/go-pdf/fpdf"
+)
+
+// Bank details patterns masked in free texts (IBAN, BIC introduced by a label, account numbers)
+var redactionBankPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`),
+	regexp.MustCompile(`(?i)\b(BIC|SWIFT)(\s*:?\s*)[A-Z]{6}[A-Z0-9]{2}(?:[A-Z0-9]{3})?\b`),
+	regexp.MustCompile(`(?i)\b(account(?: number| no\.?)?|acct\.?|routing(?: number)?|sort code)(\s*:?\s*)[0-9][0-9 -]{3,}[0-9]\b`),
+}
+
+// Redaction define fields masked in a share safe copy of a document
+type Redaction struct {
+	BankDetails  bool     `json:"bank_details,omitempty"`  // IBAN, BIC and account numbers in texts and payment methods, debtor IBAN, giro slip
+	UnitPrices   bool     `json:"unit_prices,omitempty"`   // Items unit price column, totals are kept
+	InternalRefs bool     `json:"internal_refs,omitempty"` // Version, payers, patient and confirmation references
+	Patterns     []string `json:"patterns,omitempty"`      // Additional regular expressions masked in free texts
+	Mask         string   `json:"mask,omitempty" default:"****"`
+}
+
+// ShareSafeCopy return a copy of document with redaction fields masked, for forwarding to third parties.
+// Masked values are removed from the copy, they are not only hidden in the rendered PDF.
+// The copy has its own pdf, custom fonts must be registered again on copy.Pdf().
+func (doc *Document) ShareSafeCopy(redaction *Redaction) (*Document, error) {
+	if err := defaults.Set(redaction); err != nil {
+		return nil, err
+	}
+
+	patterns := []*regexp.Regexp{}
+	if redaction.BankDetails {
+		patterns = append(patterns, redactionBankPatterns...)
+	}
+	for _, pattern := range redaction.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, re)
+	}
+
+	mask := func(text string) string {
+		for _, re := range patterns {
+			text = re.ReplaceAllStringFunc(text, func(match string) string {
+				// Keep the label of labelled patterns
+				if groups := re.FindStringSubmatch(match); len(groups) == 3 {
+					return groups[1] + groups[2] + redaction.Mask
+				}
+				return redaction.Mask
+			})
+		}
+		return text
+	}
+
+	copied := *doc
+	copied.pdf = fpdf.New("P", "mm", "A4", "")
+	copied.redaction = redaction
+	copied.encoded = nil
+
+	copied.Description = mask(doc.Description)
+	if len(copied.Description) > 0 {
+		copied.Description += "\n"
+	}
+	copied.Description += doc.Options.TextShareSafeCopyTitle
+	copied.Notes = mask(doc.Notes)
+	copied.PaymentTerm = mask(doc.PaymentTerm)
+	copied.BankDetails = mask(doc.BankDetails)
+	copied.Company = redactContact(doc.Company, mask)
+	copied.Customer = redactContact(doc.Customer, mask)
+
+	if doc.PaymentMethods != nil {
+		copied.PaymentMethods = make([]*PaymentMethod, len(doc.PaymentMethods))
+		for i, method := range doc.PaymentMethods {
+			copiedMethod := *method
+			copiedMethod.Details = mask(method.Details)
+			copied.PaymentMethods[i] = &copiedMethod
+		}
+	}
+
+	if redaction.BankDetails {
+		if doc.DirectDebit != nil {
+			debit := *doc.DirectDebit
+			debit.IBAN = debit.maskedIBAN()
+			copied.DirectDebit = &debit
+		}
+
+		// Giro slips are only bank details
+		copied.Giro = nil
+	}
+
+	copied.Items = make([]*Item, len(doc.Items))
+	for i, item := range doc.Items {
+		copiedItem := *item
+		copiedItem.Description = mask(item.Description)
+		copied.Items[i] = &copiedItem
+	}
+
+	if redaction.InternalRefs {
+		copied.Version = ""
+
+		if doc.Payers != nil {
+			copied.Payers = make([]*Payer, len(doc.Payers))
+			for i, payer := range doc.Payers {
+				copiedPayer := *payer
+				copiedPayer.Ref = redaction.Mask
+				copied.Payers[i] = &copiedPayer
+			}
+		}
+
+		if doc.Medical != nil {
+			medical := *doc.Medical
+			if len(medical.PatientRef) > 0 {
+				medical.PatientRef = redaction.Mask
+			}
+			copied.Medical = &medical
+		}
+
+		if doc.Stay != nil {
+			stay := *doc.Stay
+			if len(stay.Confirmation) > 0 {
+				stay.Confirmation = redaction.Mask
+			}
+			copied.Stay = &stay
+		}
+	}
+
+	return &copied, nil
+}
+
+// redactContact return a copy of contact with additional informations masked
+func redactContact(c *Contact, mask func(string) string) *Contact {
+	if c == nil {
+		return nil
+	}
+
+	contact := *c
+	if c.AddtionnalInfo != nil {
+		contact.AddtionnalInfo = make([]string, len(c.AddtionnalInfo))
+		for i, info := range c.AddtionnalInfo {
+			contact.AddtionnalInfo[i] = mask(info)
+		}
+	}
+
+	return &contact
+}
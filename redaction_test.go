@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShareSafeCopy(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetVersion("internal-7")
+	doc.SetNotes("Pay to IBAN FR76 3000 6000 0112 3456 7890 189, BIC: AGRIFRPP882")
+	doc.SetCompany(&Contact{Name: "Company", AddtionnalInfo: []string{"Account number: 12345678"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	copied, err := doc.ShareSafeCopy(&Redaction{BankDetails: true, UnitPrices: true, InternalRefs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copied.Notes != "Pay to IBAN ****, BIC: ****" {
+		t.Errorf("unexpected notes %q", copied.Notes)
+	}
+	if copied.Company.AddtionnalInfo[0] != "Account number: ****" {
+		t.Errorf("unexpected company info %q", copied.Company.AddtionnalInfo[0])
+	}
+	if len(copied.Version) > 0 {
+		t.Errorf("expected version to be removed")
+	}
+	if !strings.Contains(doc.Notes, "FR76") || doc.Version != "internal-7" {
+		t.Errorf("expected source document to be unchanged")
+	}
+
+	if _, err := copied.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShareSafeCopyPayment(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.PaymentMethods = []*PaymentMethod{{Type: "transfer", Details: "IBAN FR76 3000 6000 0112 3456 7890 189, BIC: AGRIFRPP882"}}
+	doc.DirectDebit = &DirectDebit{MandateRef: "M-1", DebitDate: "15/03/2024", CreditorID: "DE98ZZZ09999999999", IBAN: "FR7630006000011234567890189"}
+	doc.Giro = &Giro{Type: GiroBankgiro, Account: "5555-5555"}
+
+	copied, err := doc.ShareSafeCopy(&Redaction{BankDetails: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if details := copied.PaymentMethods[0].Details; details != "IBAN ****, BIC: ****" {
+		t.Errorf("unexpected payment method details %q", details)
+	}
+	if copied.DirectDebit.IBAN != "FR** **** **** **** **** ***0 189" || copied.Giro != nil {
+		t.Errorf("expected debtor IBAN masked and giro removed, got %q %v", copied.DirectDebit.IBAN, copied.Giro)
+	}
+	if !strings.Contains(doc.PaymentMethods[0].Details, "FR76") || doc.DirectDebit.IBAN != "FR7630006000011234567890189" || doc.Giro == nil {
+		t.Errorf("expected source document to be unchanged")
+	}
+
+	if _, err := copied.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
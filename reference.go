@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPaymentReference when no payment reference can be generated from document ref
+var ErrInvalidPaymentReference = errors.New("invalid payment reference")
+
+// Payment references types, see Options.PaymentReference
+const (
+	PaymentReferenceRF  string = "rf"  // ISO 11649 creditor reference
+	PaymentReferenceFI  string = "fi"  // Finnish national reference (viitenumero)
+	PaymentReferenceKID string = "kid" // Norwegian KID, MOD10 check digit
+)
+
+// PaymentReference return the payment reference of Options.PaymentReference generated from document ref
+// and its check digits, in electronic format without spaces. Empty without Options.PaymentReference.
+func (doc *Document) PaymentReference() (string, error) {
+	switch doc.Options.PaymentReference {
+	case PaymentReferenceRF:
+		return CreditorReference(doc.Ref)
+	case PaymentReferenceFI:
+		return FinnishReference(doc.Ref)
+	case PaymentReferenceKID:
+		return KIDNumber(doc.Ref)
+	}
+
+	return "", nil
+}
+
+// CreditorReference return the ISO 11649 RF creditor reference of ref letters and digits, ex RF18539007547034
+func CreditorReference(ref string) (string, error) {
+	reference := strings.Builder{}
+	for _, r := range strings.ToUpper(ref) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			reference.WriteRune(r)
+		}
+	}
+	if reference.Len() == 0 || reference.Len() > 21 {
+		return "", ErrInvalidPaymentReference
+	}
+
+	return fmt.Sprintf("RF%02d%s", 98-mod97(reference.String()+"RF00"), reference.String()), nil
+}
+
+// FinnishReference return the Finnish reference of ref digits followed by its 7, 3, 1 weighted check digit
+func FinnishReference(ref string) (string, error) {
+	base := refDigits(ref)
+	if len(base) < 3 || len(base) > 19 {
+		return "", ErrInvalidPaymentReference
+	}
+
+	sum := 0
+	weights := []int{7, 3, 1}
+	for i := 0; i < len(base); i++ {
+		sum += int(base[len(base)-1-i]-'0') * weights[i%3]
+	}
+
+	return fmt.Sprintf("%s%d", base, (10-sum%10)%10), nil
+}
+
+// KIDNumber return the Norwegian KID of ref digits followed by its MOD10 (Luhn) check digit
+func KIDNumber(ref string) (string, error) {
+	base := refDigits(ref)
+	if len(base) < 1 || len(base) > 24 {
+		return "", ErrInvalidPaymentReference
+	}
+
+	return fmt.Sprintf("%s%d", base, luhnCheckDigit(base)), nil
+}
+
+// luhnCheckDigit return the MOD10 (Luhn) check digit of digits
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+
+	return (10 - sum%10) % 10
+}
+
+// refDigits return the digits of ref, leading zeros removed
+func refDigits(ref string) string {
+	digits := strings.Builder{}
+	for _, r := range ref {
+		if r >= '0' && r <= '9' && (digits.Len() > 0 || r != '0') {
+			digits.WriteRune(r)
+		}
+	}
+
+	return digits.String()
+}
+
+// paymentReferenceAsString return reference in print format, RF references in groups of 4 characters
+// from the left, national references in groups of 5 digits from the right
+func (doc *Document) paymentReferenceAsString(reference string) string {
+	groups := []string{}
+	if doc.Options.PaymentReference == PaymentReferenceRF {
+		for len(reference) > 4 {
+			groups = append(groups, reference[:4])
+			reference = reference[4:]
+		}
+		return strings.Join(append(groups, reference), " ")
+	}
+
+	for len(reference) > 5 {
+		groups = append([]string{reference[len(reference)-5:]}, groups...)
+		reference = reference[:len(reference)-5]
+	}
+
+	return strings.Join(append([]string{reference}, groups...), " ")
+}
+
+// appendPaymentReference append the payment reference below payment term
+func (doc *Document) appendPaymentReference() {
+	// Validated on document validation
+	reference, _ := doc.PaymentReference()
+	if len(reference) == 0 {
+		return
+	}
+
+	offset := 15.0
+	if len(doc.PaymentTerm) > 0 {
+		offset = 5
+	} else {
+		doc.keepBlockTogether(BlockPaymentTerm, 19)
+	}
+	doc.pdf.SetY(doc.pdf.GetY() + offset)
+
+	doc.pdf.SetX(120)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+	doc.pdf.CellFormat(80, 4, doc.encodeString(fmt.Sprintf(
+		"%s: %s",
+		doc.Options.TextPaymentReferenceTitle,
+		doc.paymentReferenceAsString(reference),
+	)), "0", 0, "R", false, 0, "")
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPaymentReferences(t *testing.T) {
+	references := map[string]string{}
+	references["RF18539007547034"], _ = CreditorReference("5390 0754 7034")
+	references["RF712348231"], _ = CreditorReference("2348231")
+	references["RF74INV20240042"], _ = CreditorReference("inv-2024-0042")
+	references["12345672"], _ = FinnishReference("INV-1234567")
+	references["12345674"], _ = KIDNumber("1234567")
+	references["202400420"], _ = KIDNumber("INV-2024-0042")
+	for expected, reference := range references {
+		if reference != expected {
+			t.Errorf("expected %s, got %s", expected, reference)
+		}
+	}
+
+	if _, err := FinnishReference("INV-01"); err != ErrInvalidPaymentReference {
+		t.Errorf("expected %v, got %v", ErrInvalidPaymentReference, err)
+	}
+	if _, err := CreditorReference("---"); err != ErrInvalidPaymentReference {
+		t.Errorf("expected %v, got %v", ErrInvalidPaymentReference, err)
+	}
+}
+
+func TestAppendPaymentReference(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, PaymentReference: PaymentReferenceRF})
+	doc.SetRef("539007547034")
+	doc.SetPaymentTerm("02/02/2024")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(Payment reference: RF18 5390 0754 7034)")) {
+		t.Error("expected payment reference in output")
+	}
+	if fields := doc.MergeFields(); fields["payment_reference"] != "RF18539007547034" {
+		t.Errorf("unexpected payment reference merge field %s", fields["payment_reference"])
+	}
+
+	doc.Options.PaymentReference = PaymentReferenceFI
+	if formatted := doc.paymentReferenceAsString("12345678901234567890"); formatted != "12345 67890 12345 67890" {
+		t.Errorf("unexpected formatted reference %s", formatted)
+	}
+	if formatted := doc.paymentReferenceAsString("12345672"); formatted != "123 45672" {
+		t.Errorf("unexpected formatted reference %s", formatted)
+	}
+}
@@ -0,0 +1,206 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Remittance stub layout in millimeters, the stub spans the bottom of page 1 down to the footer
+const (
+	remittanceStubTop       float64 = 190
+	remittanceStubGap       float64 = 5 // Space kept between page 1 content and perforation
+	remittanceStubBoxX      float64 = 120
+	remittanceStubBoxWidth  float64 = 80
+	remittanceStubBoxHeight float64 = 8
+)
+
+// RemittanceStub define the detachable stub returned with check payments, drawn at the bottom of page 1
+type RemittanceStub struct {
+	ReturnAddress *Address `json:"return_address,omitempty"` // Company address when empty
+	PayTo         string   `json:"pay_to,omitempty"`         // Company name when empty
+	AccountNumber string   `json:"account_number,omitempty"` // Customer account number
+	DueDate       string   `json:"due_date,omitempty"`
+	AmountDue     string   `json:"amount_due,omitempty"` // Document total when empty
+
+	_amountDue decimal.Decimal
+}
+
+// Prepare convert amount due to decimal
+func (s *RemittanceStub) Prepare() error {
+	if len(s.AmountDue) == 0 {
+		return nil
+	}
+
+	amount, err := decimal.NewFromString(s.AmountDue)
+	if err != nil {
+		return err
+	}
+	s._amountDue = amount
+
+	return nil
+}
+
+// hasRemittanceStub return true when the stub is drawn at the bottom of page 1
+func (doc *Document) hasRemittanceStub() bool {
+	return doc.RemittanceStub != nil && doc.hasSection(SectionRemittance)
+}
+
+// pageBottom return the Y content of current page must end before, above the remittance stub on page 1
+func (doc *Document) pageBottom() float64 {
+	if doc.hasRemittanceStub() && doc.pdf.PageNo() == 1 {
+		return remittanceStubTop - remittanceStubGap
+	}
+
+	return MaxPageHeight
+}
+
+// applyRemittanceStub break page 1 text above the remittance stub, other pages breaking as usual
+func (doc *Document) applyRemittanceStub() {
+	if !doc.hasRemittanceStub() {
+		return
+	}
+
+	_, margin := doc.pdf.GetAutoPageBreak()
+	_, height := doc.pdf.GetPageSize()
+	header := doc.headerFunc
+	doc.setHeaderFunc(func() {
+		if doc.pdf.PageNo() == 1 {
+			doc.pdf.SetAutoPageBreak(true, height-remittanceStubTop+remittanceStubGap)
+		} else {
+			doc.pdf.SetAutoPageBreak(true, margin)
+		}
+		if header != nil {
+			header()
+		}
+	})
+}
+
+// remittanceAmount return the amount due printed on stub, document total less down payments when not set
+func (doc *Document) remittanceAmount() string {
+	if len(doc.RemittanceStub.AmountDue) > 0 {
+		return doc.formatMoney(doc.RemittanceStub._amountDue)
+	}
+	if len(doc.CustomTotal) > 0 && len(doc.DownPayments) == 0 {
+		return doc.amountDigits(doc.CustomTotal)
+	}
+
+	return doc.formatMoney(doc.documentTotal())
+}
+
+// remittanceReturnAddress return the name and address lines the payment is mailed to
+func (doc *Document) remittanceReturnAddress() []string {
+	stub := doc.RemittanceStub
+
+	name, address := stub.PayTo, stub.ReturnAddress
+	if doc.Company != nil {
+		if len(name) == 0 {
+			name = doc.Company.Name
+		}
+		if address == nil {
+			address = doc.Company.Address
+		}
+	}
+
+	lines := []string{name}
+	if address != nil {
+		lines = append(lines, strings.Split(address.ToString(), "\n")...)
+	}
+
+	return lines
+}
+
+// drawRemittanceBox draw a labelled box of the stub at y, the value right aligned
+func (doc *Document) drawRemittanceBox(y float64, label string, value string, bold bool) {
+	doc.pdf.SetXY(remittanceStubBoxX, y)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(remittanceStubBoxWidth/2, remittanceStubBoxHeight, doc.encodeString(label), "1", 0, "L", false, 0, "")
+
+	if bold {
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	}
+	doc.pdf.CellFormat(remittanceStubBoxWidth/2, remittanceStubBoxHeight, doc.encodeString(value), "1", 0, "R", false, 0, "")
+}
+
+// appendRemittanceStub draw the perforation line, return address and amount boxes at the bottom of page 1
+func (doc *Document) appendRemittanceStub() {
+	if !doc.hasRemittanceStub() {
+		return
+	}
+	stub := doc.RemittanceStub
+
+	current := doc.pdf.PageNo()
+	x, y := doc.pdf.GetXY()
+	auto, margin := doc.pdf.GetAutoPageBreak()
+	doc.pdf.SetAutoPageBreak(false, margin)
+	defer func() {
+		doc.pdf.SetPage(current)
+		doc.pdf.SetAutoPageBreak(auto, margin)
+		doc.pdf.SetXY(x, y)
+	}()
+
+	doc.pdf.SetPage(1)
+	if current != 1 {
+		doc.beginPageShift()
+		defer doc.endPageShift()
+	}
+
+	// Perforation across the whole page width
+	width, _ := doc.pdf.GetPageSize()
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetDrawColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.SetDashPattern([]float64{2, 1}, 0)
+	doc.pdf.Line(0, remittanceStubTop, width, remittanceStubTop)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+	doc.pdf.SetDrawColor(r, g, b)
+
+	tr, tg, tb := doc.pdf.GetTextColor()
+	doc.pdf.SetTextColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.SetXY(BaseMargin, remittanceStubTop+1)
+	doc.pdf.CellFormat(190, 3, doc.encodeString(doc.Options.TextRemittanceStubDetach), "0", 0, "C", false, 0, "")
+	doc.pdf.SetTextColor(tr, tg, tb)
+
+	// Title and references on the left
+	top := remittanceStubTop + 8
+	doc.pdf.SetXY(BaseMargin, top)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(100, 6, doc.encodeString(doc.Options.TextRemittanceStubTitle), "0", 2, "L", false, 0, "")
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	lines := []string{fmt.Sprintf("%s: %s", doc.typeAsString(), doc.Ref)}
+	if doc.Customer != nil {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextRemittanceStubCustomerTitle, doc.Customer.Name))
+	}
+	if len(stub.AccountNumber) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextRemittanceStubAccountTitle, stub.AccountNumber))
+	}
+	for _, line := range lines {
+		doc.pdf.CellFormat(100, 4, doc.encodeString(line), "0", 2, "L", false, 0, "")
+	}
+
+	// Return address, placed for a #9 return envelope window
+	doc.pdf.SetXY(BaseMargin+10, top+35)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(90, 4, doc.encodeString(doc.Options.TextRemittanceStubPayToTitle), "0", 2, "L", false, 0, "")
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	for _, line := range doc.remittanceReturnAddress() {
+		doc.pdf.CellFormat(90, 4, doc.encodeString(line), "0", 2, "L", false, 0, "")
+	}
+
+	// Amount boxes on the right, amount enclosed left blank for the payer
+	doc.pdf.SetLineWidth(0.3)
+	boxY := top
+	if len(stub.DueDate) > 0 {
+		doc.drawRemittanceBox(boxY, doc.Options.TextRemittanceStubDueDateTitle, stub.DueDate, false)
+		boxY += remittanceStubBoxHeight + 2
+	}
+	doc.drawRemittanceBox(boxY, doc.Options.TextRemittanceStubAmountDueTitle, doc.remittanceAmount(), true)
+	doc.drawRemittanceBox(boxY+remittanceStubBoxHeight+2, doc.Options.TextRemittanceStubAmountEnclosedTitle, "", false)
+	doc.pdf.SetLineWidth(lineWidth)
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendRemittanceStub(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, CurrencySymbol: "$ "})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main Street", PostalCode: "10001", City: "New York"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.RemittanceStub = &RemittanceStub{ReturnAddress: &Address{Address: "PO Box 1234", PostalCode: "10008", City: "New York"}, AccountNumber: "C-0042", DueDate: "03/15/2024", AmountDue: "80"}
+	for i := 0; i < 40; i++ {
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdf.PageCount() < 2 {
+		t.Fatalf("expected items to continue on page 2, got %d pages", pdf.PageCount())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		"(Please detach and return this portion with your payment)",
+		"(Account number: C-0042)",
+		"(PO Box 1234)",
+		"(Amount enclosed)",
+		"($ 80.00)",
+	} {
+		if bytes.Count(buffer.Bytes(), []byte(text)) != 1 {
+			t.Errorf("expected %q once in output", text)
+		}
+	}
+
+	doc.Options.Sections = []string{SectionMeta, SectionItems, SectionRemittance}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Options.Sections = []string{SectionMeta, SectionItems}
+	if doc.pageBottom() != MaxPageHeight {
+		t.Error("expected full page height without remittance section")
+	}
+}
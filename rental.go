@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidRental when rental dates or period are invalid
+var ErrInvalidRental = errors.New("invalid rental")
+
+// Rental periods
+const (
+	RentalPeriodDay   string = "day"
+	RentalPeriodWeek  string = "week"
+	RentalPeriodMonth string = "month"
+)
+
+// Rental define a period based item (equipment hire, housing, subscriptions...).
+// Start and End are inclusive and use Options.DateFormat.
+type Rental struct {
+	Start  string `json:"start,omitempty" validate:"required"`
+	End    string `json:"end,omitempty" validate:"required"`
+	Period string `json:"period,omitempty" validate:"required,oneof=day week month"`
+	Rate   string `json:"rate,omitempty" validate:"required"` // Rate per period ex 45.00
+
+	_start time.Time
+	_end   time.Time
+	_rate  decimal.Decimal
+}
+
+// Prepare parse rental dates and rate using the provided date layout
+func (r *Rental) Prepare(dateLayout string) error {
+	start, err := time.Parse(dateLayout, r.Start)
+	if err != nil {
+		return err
+	}
+	r._start = start
+
+	end, err := time.Parse(dateLayout, r.End)
+	if err != nil {
+		return err
+	}
+	r._end = end
+
+	if r._end.Before(r._start) {
+		return ErrInvalidRental
+	}
+
+	if r.Period != RentalPeriodDay && r.Period != RentalPeriodWeek && r.Period != RentalPeriodMonth {
+		return ErrInvalidRental
+	}
+
+	rate, err := decimal.NewFromString(r.Rate)
+	if err != nil {
+		return err
+	}
+	r._rate = rate
+
+	return nil
+}
+
+// Quantity return the number of periods covered by the rental window.
+// Started weeks and months are billed as full periods.
+func (r *Rental) Quantity() int64 {
+	// End date is inclusive
+	end := r._end.AddDate(0, 0, 1)
+	days := int64(end.Sub(r._start).Hours() / 24)
+
+	switch r.Period {
+	case RentalPeriodWeek:
+		return (days + 6) / 7
+	case RentalPeriodMonth:
+		months := int64(end.Year()-r._start.Year())*12 + int64(end.Month()-r._start.Month())
+		if r._start.AddDate(0, int(months), 0).Before(end) {
+			months++
+		}
+		return months
+	default:
+		return days
+	}
+}
+
+// apply set item quantity and unit cost from rental informations, total is computed with item discount
+func (r *Rental) apply(i *Item, options *Options) error {
+	if err := r.Prepare(options.DateFormat); err != nil {
+		return err
+	}
+
+	// Recomputed on each build, rental dates or rate may have changed
+	i.Quantity = decimal.NewFromInt(r.Quantity()).String()
+	i.UnitCost = r.Rate
+
+	return nil
+}
+
+// windowAsString return the rental window as displayed under the item name
+func (r *Rental) windowAsString(options *Options) string {
+	unit := options.TextRentalPeriodDay
+	if r.Period == RentalPeriodWeek {
+		unit = options.TextRentalPeriodWeek
+	}
+	if r.Period == RentalPeriodMonth {
+		unit = options.TextRentalPeriodMonth
+	}
+
+	return fmt.Sprintf(
+		"%s %s %s %s (%d %s)",
+		options.TextRentalFromTitle,
+		r.Start,
+		options.TextRentalToTitle,
+		r.End,
+		r.Quantity(),
+		unit,
+	)
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRentalQuantity(t *testing.T) {
+	cases := []struct {
+		rental   Rental
+		expected int64
+	}{
+		{Rental{Start: "01/03/2021", End: "03/03/2021", Period: RentalPeriodDay, Rate: "10"}, 3},
+		{Rental{Start: "01/03/2021", End: "08/03/2021", Period: RentalPeriodWeek, Rate: "10"}, 2},
+		{Rental{Start: "01/03/2021", End: "31/03/2021", Period: RentalPeriodMonth, Rate: "10"}, 1},
+		{Rental{Start: "15/01/2021", End: "14/03/2021", Period: RentalPeriodMonth, Rate: "10"}, 2},
+		{Rental{Start: "15/01/2021", End: "15/03/2021", Period: RentalPeriodMonth, Rate: "10"}, 3},
+	}
+
+	for _, c := range cases {
+		if err := c.rental.Prepare("02/01/2006"); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		if q := c.rental.Quantity(); q != c.expected {
+			t.Errorf("%s - %s per %s: expected %d, got %d", c.rental.Start, c.rental.End, c.rental.Period, c.expected, q)
+		}
+	}
+}
+
+func TestRentalInvalidPeriod(t *testing.T) {
+	r := &Rental{Start: "03/03/2021", End: "01/03/2021", Period: RentalPeriodDay, Rate: "10"}
+	if err := r.Prepare("02/01/2006"); err != ErrInvalidRental {
+		t.Fatalf("expected ErrInvalidRental, got %v", err)
+	}
+}
+
+func TestRentalItem(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{
+		Name: "Scaffolding",
+		Rental: &Rental{
+			Start:  "01/03/2021",
+			End:    "14/03/2021",
+			Period: RentalPeriodWeek,
+			Rate:   "120.00",
+		},
+	})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if item := doc.Items[0]; item.Quantity != "2" || item.UnitCost != "120.00" || !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromInt(240)) {
+		t.Errorf("unexpected rental item %s x %s = %s", item.Quantity, item.UnitCost, item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	// Total follows an extended rental window
+	doc.Items[0].Rental.End = "21/03/2021"
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if item := doc.Items[0]; item.Quantity != "3" || !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromInt(360)) {
+		t.Errorf("expected total recomputed, got %s x %s = %s", item.Quantity, item.UnitCost, item.TotalWithoutTaxAndWithDiscount())
+	}
+}
+
+func TestRentalItemDiscount(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{
+		Name:     "Scaffolding",
+		Rental:   &Rental{Start: "01/03/2021", End: "14/03/2021", Period: RentalPeriodWeek, Rate: "100"},
+		Discount: &Discount{Percent: "50"},
+	})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if total := doc.TotalWithoutTax(); !total.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected discounted 100 total, got %s", total)
+	}
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Retainer define the retainer balance billed against by a document: opening balance, amount and hours
+// consumed this period and remaining balance
+type Retainer struct {
+	OpeningBalance string `json:"opening_balance"`          // Balance at the start of the period ex 5000
+	Consumed       string `json:"consumed,omitempty"`       // Amount consumed this period, document total without tax when empty
+	OpeningHours   string `json:"opening_hours,omitempty"`  // Hours balance at the start of the period, hours aren't rendered when empty
+	ConsumedHours  string `json:"consumed_hours,omitempty"` // Hours consumed this period ex 12.5
+
+	_openingBalance decimal.Decimal
+	_consumed       decimal.Decimal
+	_openingHours   decimal.Decimal
+	_consumedHours  decimal.Decimal
+}
+
+// Prepare convert strings to decimal, consumed is the document total without tax
+func (r *Retainer) Prepare(consumed decimal.Decimal) error {
+	r._consumed = consumed
+	r._consumedHours = decimal.Zero
+
+	for _, value := range []struct {
+		value  string
+		parsed *decimal.Decimal
+	}{
+		{r.OpeningBalance, &r._openingBalance},
+		{r.Consumed, &r._consumed},
+		{r.OpeningHours, &r._openingHours},
+		{r.ConsumedHours, &r._consumedHours},
+	} {
+		if len(value.value) == 0 {
+			continue
+		}
+
+		parsed, err := decimal.NewFromString(value.value)
+		if err != nil {
+			return err
+		}
+		*value.parsed = parsed
+	}
+
+	return nil
+}
+
+// Remaining return opening balance - consumed amount, negative when the retainer is overdrawn
+func (r *Retainer) Remaining() decimal.Decimal {
+	return r._openingBalance.Sub(r._consumed)
+}
+
+// RemainingHours return opening hours - consumed hours
+func (r *Retainer) RemainingHours() decimal.Decimal {
+	return r._openingHours.Sub(r._consumedHours)
+}
+
+// appendRetainer append the retainer balance block above items
+func (doc *Document) appendRetainer() {
+	retainer := doc.Retainer
+	if retainer == nil {
+		return
+	}
+
+	titles := []string{
+		doc.Options.TextRetainerOpeningBalanceTitle,
+		doc.Options.TextRetainerConsumedTitle,
+		doc.Options.TextRetainerRemainingTitle,
+	}
+	rows := [][]string{{
+		doc.formatMoney(retainer._openingBalance),
+		doc.formatMoney(retainer._consumed.Neg()),
+		doc.formatMoney(retainer.Remaining()),
+	}}
+	if len(retainer.OpeningHours) > 0 {
+		unit := " " + doc.Options.TextRetainerHoursUnit
+		rows = append(rows, []string{
+			doc.formatQuantity(retainer._openingHours.String()) + unit,
+			doc.formatQuantity(retainer._consumedHours.Neg().String()) + unit,
+			doc.formatQuantity(retainer.RemainingHours().String()) + unit,
+		})
+	}
+
+	y := doc.pdf.GetY() + 10
+
+	// Title then columns titles
+	doc.pdf.SetXY(BaseMargin, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.CellFormat(190/4, 6, doc.encodeString(doc.Options.TextRetainerTitle), "0", 0, "L", true, 0, "")
+	for _, title := range titles {
+		doc.pdf.CellFormat(190/4, 6, doc.encodeString(title), "0", 0, "C", true, 0, "")
+	}
+	y += 6
+
+	// Amounts then hours, remaining balance highlighted
+	for _, row := range rows {
+		doc.pdf.SetXY(BaseMargin+190/4, y)
+		for i, value := range row {
+			if i == len(row)-1 {
+				doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+			} else {
+				doc.pdf.SetFont(doc.Options.Font, "", 10)
+			}
+			doc.pdf.CellFormat(190/4, 6, doc.encodeString(value), "0", 0, "C", false, 0, "")
+		}
+		y += 6
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetXY(BaseMargin, y+2)
+}
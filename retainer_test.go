@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRetainer(t *testing.T) {
+	doc := newQuote()
+	doc.Type = Invoice
+	doc.Retainer = &Retainer{OpeningBalance: "5000", OpeningHours: "40", ConsumedHours: "12.5"}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := doc.Retainer.Remaining().StringFixed(2); remaining != "4800.00" {
+		t.Errorf("expected remaining balance 4800.00, got %s", remaining)
+	}
+	if hours := doc.Retainer.RemainingHours().String(); hours != "27.5" {
+		t.Errorf("expected 27.5 remaining hours, got %s", hours)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(Retainer)", "(Consumed this period)", "(Remaining balance)", "(27.5 h)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Consumed amount given by caller
+	doc.Retainer = &Retainer{OpeningBalance: "1000", Consumed: "1200"}
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := doc.Retainer.Remaining().String(); remaining != "-200" {
+		t.Errorf("expected overdrawn retainer -200, got %s", remaining)
+	}
+}
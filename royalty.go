@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Royalty summary table columns widths in millimeters
+var royaltySummaryColumns = []float64{110, 30, 50}
+
+// Royalty define the rights holder and unrecouped advance of royalty statements, see LayoutRoyalty
+type Royalty struct {
+	RightsHolder string `json:"rights_holder,omitempty" validate:"max=256"`
+	Period       string `json:"period,omitempty" validate:"max=64"` // Statement period ex 2024 H1
+	Advance      string `json:"advance,omitempty"`                  // Unrecouped advance at the start of the period, recouped from royalties
+
+	_advance decimal.Decimal
+}
+
+// Prepare convert advance to decimal
+func (r *Royalty) Prepare() error {
+	r._advance = decimal.Zero
+	if len(r.Advance) == 0 {
+		return nil
+	}
+
+	advance, err := decimal.NewFromString(r.Advance)
+	if err != nil {
+		return err
+	}
+	r._advance = advance
+
+	return nil
+}
+
+// royaltyTitle define the lines of a title or SKU, ordered by period
+type royaltyTitle struct {
+	name   string
+	items  []*Item
+	units  decimal.Decimal
+	amount decimal.Decimal
+}
+
+// royaltyTitles return items grouped by title (Item.Group) in order of first appearance,
+// each title lines sorted by period
+func (doc *Document) royaltyTitles() []*royaltyTitle {
+	titles := []*royaltyTitle{}
+	byName := map[string]*royaltyTitle{}
+
+	for _, item := range doc.Items {
+		title, ok := byName[item.Group]
+		if !ok {
+			title = &royaltyTitle{name: item.Group, units: decimal.Zero, amount: decimal.Zero}
+			byName[item.Group] = title
+			titles = append(titles, title)
+		}
+
+		title.items = append(title.items, item)
+		if quantity, err := decimal.NewFromString(item.Quantity); err == nil {
+			title.units = title.units.Add(quantity)
+		}
+		title.amount = title.amount.Add(item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	for _, title := range titles {
+		sort.SliceStable(title.items, func(a, b int) bool {
+			return title.items[a].Period < title.items[b].Period
+		})
+	}
+
+	return titles
+}
+
+// RoyaltyRecouped return the part of the unrecouped advance recouped from document total
+func (doc *Document) RoyaltyRecouped() decimal.Decimal {
+	if doc.Royalty == nil || !doc.Royalty._advance.IsPositive() {
+		return decimal.Zero
+	}
+
+	total := doc.documentTotal()
+	if total.LessThan(doc.Royalty._advance) {
+		return decimal.Max(total, decimal.Zero)
+	}
+
+	return doc.Royalty._advance
+}
+
+// RoyaltyPayable return document total less the recouped advance
+func (doc *Document) RoyaltyPayable() decimal.Decimal {
+	return doc.documentTotal().Sub(doc.RoyaltyRecouped())
+}
+
+// appendRoyaltyItems append items grouped by title and period with per title subtotals,
+// then the rights holder summary page
+func (doc *Document) appendRoyaltyItems() {
+	doc.drawsTableTitles()
+
+	doc.pdf.SetX(10)
+	doc.pdf.SetY(doc.pdf.GetY() + 8)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+	newPage := func() bool {
+		if doc.pdf.GetY() <= doc.pageBottom() {
+			return true
+		}
+
+		doc.pdf.AddPage()
+		if doc.pageLimitReached() {
+			return false
+		}
+		doc.drawsTableTitles()
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetY(doc.pdf.GetY() + 8)
+
+		return true
+	}
+
+	titles := doc.royaltyTitles()
+	for i, title := range titles {
+		name := title.name
+		if len(name) == 0 {
+			name = doc.Options.TextRoyaltyUngroupedTitle
+		}
+		doc.pdf.SetX(10)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(190, 5, doc.encodeString(name), "0", 0, "", false, 0, "")
+		doc.drawRule(10, 200, doc.pdf.GetY()+5)
+		doc.pdf.SetY(doc.pdf.GetY() + 7)
+
+		for i, item := range title.items {
+			// Period heading
+			if i == 0 || item.Period != title.items[i-1].Period {
+				doc.pdf.SetX(12)
+				doc.pdf.SetFont(doc.Options.Font, "I", SmallTextFontSize)
+				doc.pdf.CellFormat(188, 4, doc.encodeString(item.Period), "0", 0, "", false, 0, "")
+				doc.pdf.SetY(doc.pdf.GetY() + 5)
+			}
+
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+			if item.Tax == nil {
+				item.Tax = doc.DefaultTax
+			}
+			item.appendColTo(doc.Options, doc)
+
+			doc.pdf.SetX(10)
+			doc.pdf.SetY(doc.pdf.GetY() + 6)
+			if !newPage() {
+				return
+			}
+		}
+
+		// Title subtotal
+		doc.pdf.SetX(120)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(ItemColTotalHTOffset-120, 4, doc.encodeString(doc.Options.TextRoyaltySubtotalTitle), "0", 0, "R", false, 0, "")
+		doc.pdf.SetX(ItemColTotalHTOffset)
+		doc.pdf.CellFormat(25, 4, doc.encodeString(doc.formatMoney(title.amount)), "0", 0, "", false, 0, "")
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetY(doc.pdf.GetY() + 7)
+		if i < len(titles)-1 {
+			doc.appendSeparator(SeparatorGroups, doc.pdf.GetY()-1.5)
+		}
+		if !newPage() {
+			return
+		}
+	}
+
+	doc.appendRoyaltySummary(titles)
+}
+
+// appendRoyaltySummary append the rights holder summary page: units and royalties per title,
+// advance recoupment and payable amount
+func (doc *Document) appendRoyaltySummary(titles []*royaltyTitle) {
+	doc.pdf.AddPage()
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(190, 8, doc.encodeString(doc.Options.TextRoyaltySummaryTitle), "0", 2, "L", false, 0, "")
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	if doc.Royalty != nil {
+		for _, line := range [][]string{
+			{doc.Options.TextRoyaltyRightsHolderTitle, doc.Royalty.RightsHolder},
+			{doc.Options.TextRoyaltyPeriodTitle, doc.Royalty.Period},
+		} {
+			if len(line[1]) > 0 {
+				doc.pdf.CellFormat(190, 5, doc.encodeString(fmt.Sprintf("%s: %s", line[0], line[1])), "0", 2, "L", false, 0, "")
+			}
+		}
+	}
+	doc.pdf.SetY(doc.pdf.GetY() + 3)
+
+	// Per title table
+	row := func(values []string, style string, border string, fill bool) {
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.SetFont(doc.Options.BoldFont, style, BaseTextFontSize)
+		if style == "" {
+			doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		}
+		for i, value := range values {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			doc.pdf.CellFormat(royaltySummaryColumns[i], 6, doc.encodeString(value), border, 0, align, fill, 0, "")
+		}
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+	}
+
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	row([]string{doc.Options.TextRoyaltyTitleColumn, doc.Options.TextRoyaltyUnitsColumn, doc.Options.TextRoyaltyAmountColumn}, "B", "0", true)
+
+	units := decimal.Zero
+	amount := decimal.Zero
+	for _, title := range titles {
+		name := title.name
+		if len(name) == 0 {
+			name = doc.Options.TextRoyaltyUngroupedTitle
+		}
+		row([]string{name, doc.formatQuantity(title.units.String()), doc.formatMoney(title.amount)}, "", "B", false)
+		units = units.Add(title.units)
+		amount = amount.Add(title.amount)
+	}
+	row([]string{doc.Options.TextRoyaltyTotalTitle, doc.formatQuantity(units.String()), doc.formatMoney(amount)}, "B", "0", false)
+
+	// Advance recoupment
+	if doc.Royalty != nil && doc.Royalty._advance.IsPositive() {
+		doc.pdf.SetY(doc.pdf.GetY() + 3)
+		row([]string{doc.Options.TextRoyaltyAdvanceTitle, "", doc.formatMoney(doc.Royalty._advance)}, "", "0", false)
+		row([]string{doc.Options.TextRoyaltyRecoupedTitle, "", doc.formatMoney(doc.RoyaltyRecouped().Neg())}, "", "0", false)
+		row([]string{doc.Options.TextRoyaltyPayableTitle, "", doc.formatMoney(doc.RoyaltyPayable())}, "B", "T", false)
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoyaltyLayout(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, Layout: LayoutRoyalty})
+	doc.SetRef("RS-2024-1")
+	doc.SetDate("01/07/2024")
+	doc.SetCompany(&Contact{Name: "Publisher"})
+	doc.SetCustomer(&Contact{Name: "Author"})
+	doc.Royalty = &Royalty{RightsHolder: "Jane Writer", Period: "2024 H1", Advance: "500"}
+	doc.AppendItem(&Item{Name: "Paperback sales", Group: "The Novel", Period: "2024-Q2", UnitCost: "1.5", Quantity: "200"})
+	doc.AppendItem(&Item{Name: "Ebook sales", Group: "The Essay", Period: "2024-Q1", UnitCost: "2", Quantity: "50"})
+	doc.AppendItem(&Item{Name: "Paperback sales", Group: "The Novel", Period: "2024-Q1", UnitCost: "1.5", Quantity: "100"})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	titles := doc.royaltyTitles()
+	if len(titles) != 2 || titles[0].name != "The Novel" || titles[1].name != "The Essay" {
+		t.Fatalf("expected titles in order of first appearance, got %v", titles)
+	}
+	if titles[0].items[0].Period != "2024-Q1" {
+		t.Errorf("expected title lines sorted by period, got %s first", titles[0].items[0].Period)
+	}
+	if units, amount := titles[0].units.String(), titles[0].amount.StringFixed(2); units != "300" || amount != "450.00" {
+		t.Errorf("expected 300 units for 450.00, got %s for %s", units, amount)
+	}
+
+	// 550 of royalties, the whole advance is recouped
+	if recouped := doc.RoyaltyRecouped().String(); recouped != "500" {
+		t.Errorf("expected 500 recouped, got %s", recouped)
+	}
+	if payable := doc.RoyaltyPayable().StringFixed(2); payable != "50.00" {
+		t.Errorf("expected 50.00 payable, got %s", payable)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"(The Novel)", "(2024-Q1)", "(Title total)", "(Rights holder summary)",
+		"(Rights holder: Jane Writer)", "(Unrecouped advance)", "(Payable)",
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+	if pages := pdf.PageCount(); pages != 2 {
+		t.Errorf("expected items page and summary page, got %d pages", pages)
+	}
+
+	// Advance larger than royalties is partly recouped
+	doc.Royalty.Advance = "1000"
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if payable := doc.RoyaltyPayable().String(); payable != "0" {
+		t.Errorf("expected nothing payable, got %s", payable)
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"github.com/creasty/defaults"
+)
+
+// Separators drawn with Options.Rules style, see Rules.Separators
+const (
+	SeparatorGroups string = "groups" // Between items groups: folio days, royalty titles and sellers
+	SeparatorTotals string = "totals" // Above totals and notes
+	SeparatorFooter string = "footer" // Above the footer, on each page
+)
+
+// Rules define the style of horizontal rules, ex below the description and group headings, and the
+// separators drawn between document parts
+type Rules struct {
+	Width float64   `default:"0.2" json:"width,omitempty" validate:"min=0,max=5"` // Line width in millimeters
+	Color []int     `default:"[0,0,0]" json:"color,omitempty" validate:"omitempty,len=3,dive,min=0,max=255"`
+	Dash  []float64 `json:"dash,omitempty" validate:"omitempty,dive,gt=0"` // Dash and gap lengths in millimeters ex [1, 1], solid when empty
+
+	Separators []string `json:"separators,omitempty" validate:"omitempty,dive,oneof=groups totals footer"`
+}
+
+// rules return Options.Rules with defaults, solid black rules without separators when nil
+func (doc *Document) rules() *Rules {
+	rules := &Rules{}
+	if doc.Options.Rules != nil {
+		copied := *doc.Options.Rules
+		rules = &copied
+	}
+	_ = defaults.Set(rules)
+
+	return rules
+}
+
+// separator return true when separator is enabled in Options.Rules
+func (doc *Document) separator(separator string) bool {
+	if doc.Options.Rules == nil {
+		return false
+	}
+
+	for _, enabled := range doc.Options.Rules.Separators {
+		if enabled == separator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// drawRule draw an horizontal rule from x1 to x2 at y with Options.Rules style
+func (doc *Document) drawRule(x1 float64, x2 float64, y float64) {
+	rules := doc.rules()
+
+	lineWidth := doc.pdf.GetLineWidth()
+	r, g, b := doc.pdf.GetDrawColor()
+
+	doc.pdf.SetLineWidth(rules.Width)
+	doc.pdf.SetDrawColor(rules.Color[0], rules.Color[1], rules.Color[2])
+	if len(rules.Dash) > 0 {
+		doc.pdf.SetDashPattern(rules.Dash, 0)
+	}
+	doc.pdf.Line(x1, y, x2, y)
+	doc.pdf.SetDashPattern([]float64{}, 0)
+
+	doc.pdf.SetLineWidth(lineWidth)
+	doc.pdf.SetDrawColor(r, g, b)
+}
+
+// appendSeparator draw a full width rule at y when separator is enabled
+func (doc *Document) appendSeparator(separator string, y float64) {
+	if doc.separator(separator) {
+		doc.drawRule(BaseMargin, BaseMargin+190, y)
+	}
+}
@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	doc := newQuote()
+	doc.Description = "Website redesign"
+	doc.Footer = &HeaderFooter{Text: "Seller - 1 main street"}
+	doc.Options.Rules = &Rules{
+		Color:      []int{200, 0, 0},
+		Dash:       []float64{2, 1},
+		Separators: []string{SeparatorTotals, SeparatorFooter},
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if width := doc.rules().Width; width != 0.2 {
+		t.Errorf("expected default width 0.2, got %v", width)
+	}
+	if !doc.separator(SeparatorTotals) || doc.separator(SeparatorGroups) {
+		t.Error("expected only totals and footer separators")
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"0.784 0.000 0.000 RG", "[5.67 2.83] 0.00 d"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	doc.Options.Rules.Separators = []string{"columns"}
+	if err := doc.Validate(); err == nil {
+		t.Error("expected unknown separator error")
+	}
+}
@@ -0,0 +1,794 @@
+package generator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSAFTVariant when SAF-T variant is not supported
+var ErrInvalidSAFTVariant = errors.New("invalid SAF-T variant")
+
+// SAF-T variants
+const (
+	SAFTVariantPT string = "PT" // Portugal, SAF-T (PT) 1.04_01
+	SAFTVariantNO string = "NO" // Norway, SAF-T Financial 1.30
+	SAFTVariantPL string = "PL" // Poland, JPK_FA (4)
+)
+
+// SAFTHeader define audit file header informations not available on documents.
+// Company informations are taken from the first document, selection period defaults to documents dates.
+type SAFTHeader struct {
+	CompanyID                 string    `json:"company_id,omitempty"`
+	FiscalYear                int       `json:"fiscal_year,omitempty"`
+	StartDate                 time.Time `json:"start_date,omitempty"`
+	EndDate                   time.Time `json:"end_date,omitempty"`
+	CurrencyCode              string    `json:"currency_code,omitempty" default:"EUR"`
+	TaxOfficeCode             string    `json:"tax_office_code,omitempty"` // PL KodUrzedu
+	ProductID                 string    `json:"product_id,omitempty" default:"go-invoice-generator"`
+	ProductVersion            string    `json:"product_version,omitempty" default:"1.0"`
+	ProductCompanyTaxID       string    `json:"product_company_tax_id,omitempty"`
+	SoftwareCertificateNumber string    `json:"software_certificate_number,omitempty" default:"0"`
+}
+
+// saftLine define an item line with amounts used by every variants
+type saftLine struct {
+	number      int
+	item        *Item
+	net         decimal.Decimal
+	tax         decimal.Decimal
+	taxPercent  decimal.Decimal
+	productCode string
+}
+
+// saftInvoice define a document with amounts used by every variants
+type saftInvoice struct {
+	doc   *Document
+	date  time.Time
+	lines []*saftLine
+	net   decimal.Decimal
+	tax   decimal.Decimal
+	gross decimal.Decimal
+}
+
+// ExportSAFT write documents as a SAF-T audit file of the given variant
+func ExportSAFT(w io.Writer, variant string, header *SAFTHeader, docs ...*Document) error {
+	if len(docs) == 0 {
+		return ErrInvalidSAFTVariant
+	}
+
+	if err := defaults.Set(header); err != nil {
+		return err
+	}
+
+	invoices := make([]*saftInvoice, 0, len(docs))
+	for _, doc := range docs {
+		invoice, err := newSAFTInvoice(doc)
+		if err != nil {
+			return err
+		}
+		invoices = append(invoices, invoice)
+
+		// Default selection period to documents dates
+		if header.StartDate.IsZero() || invoice.date.Before(header.StartDate) {
+			header.StartDate = invoice.date
+		}
+		if invoice.date.After(header.EndDate) {
+			header.EndDate = invoice.date
+		}
+	}
+
+	if header.FiscalYear == 0 {
+		header.FiscalYear = header.StartDate.Year()
+	}
+
+	var file interface{}
+	switch variant {
+	case SAFTVariantPT:
+		file = newSAFTPT(header, invoices)
+	case SAFTVariantNO:
+		file = newSAFTNO(header, invoices)
+	case SAFTVariantPL:
+		file = newJPKFA(header, invoices)
+	default:
+		return ErrInvalidSAFTVariant
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(file)
+}
+
+// newSAFTInvoice validate document and compute its amounts
+func newSAFTInvoice(doc *Document) (*saftInvoice, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if len(doc.Date) > 0 {
+		parsed, err := time.Parse(doc.Options.DateFormat, doc.Date)
+		if err != nil {
+			return nil, err
+		}
+		date = parsed
+	}
+
+	invoice := &saftInvoice{
+		doc:   doc,
+		date:  date,
+		net:   doc.TotalWithoutTax(),
+		tax:   doc.Tax(),
+		gross: doc.TotalWithTax(),
+	}
+
+	// Document discount is prorated on lines like the tax breakdown
+	totalWithoutDocDiscount := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	applyDiscount := doc.Discount != nil && !totalWithoutDocDiscount.IsZero()
+
+	linesNet, linesTax := decimal.Zero, decimal.Zero
+	for i, item := range doc.Items {
+		line := &saftLine{
+			number:      i + 1,
+			item:        item,
+			net:         item.TotalWithoutTaxAndWithDiscount(),
+			tax:         item.TaxWithTotalDiscounted(),
+			productCode: fmt.Sprintf("%d", i+1),
+		}
+
+		percentTax := false
+		if item.Tax != nil {
+			if taxType, taxNumber := item.Tax.getTax(); taxType == TaxTypePercent {
+				line.taxPercent = taxNumber
+				percentTax = true
+			}
+		}
+
+		if applyDiscount {
+			line.net = line.net.Mul(invoice.net).Div(totalWithoutDocDiscount)
+			if percentTax {
+				line.tax = line.tax.Mul(invoice.net).Div(totalWithoutDocDiscount)
+			}
+		}
+
+		line.net, line.tax = line.net.Round(2), line.tax.Round(2)
+		linesNet, linesTax = linesNet.Add(line.net), linesTax.Add(line.tax)
+
+		invoice.lines = append(invoice.lines, line)
+	}
+
+	// Rounding difference on the last line, lines add up to totals
+	if last := len(invoice.lines) - 1; last >= 0 {
+		invoice.lines[last].net = invoice.lines[last].net.Add(invoice.net.Round(2).Sub(linesNet))
+		invoice.lines[last].tax = invoice.lines[last].tax.Add(invoice.tax.Round(2).Sub(linesTax))
+	}
+
+	return invoice, nil
+}
+
+// saftAmount format amount as required in audit files
+func saftAmount(amount decimal.Decimal) string {
+	return amount.StringFixed(2)
+}
+
+// saftDate format date as required in audit files
+func saftDate(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// saftContactID return contact identifier, tax id when set
+func saftContactID(c *Contact) string {
+	if len(c.TaxID) > 0 {
+		return c.TaxID
+	}
+
+	return c.Name
+}
+
+// SAF-T (PT)
+
+type saftPTAddress struct {
+	AddressDetail string `xml:"AddressDetail"`
+	City          string `xml:"City"`
+	PostalCode    string `xml:"PostalCode"`
+	Country       string `xml:"Country"`
+}
+
+type saftPTHeader struct {
+	AuditFileVersion          string        `xml:"AuditFileVersion"`
+	CompanyID                 string        `xml:"CompanyID"`
+	TaxRegistrationNumber     string        `xml:"TaxRegistrationNumber"`
+	TaxAccountingBasis        string        `xml:"TaxAccountingBasis"`
+	CompanyName               string        `xml:"CompanyName"`
+	CompanyAddress            saftPTAddress `xml:"CompanyAddress"`
+	FiscalYear                int           `xml:"FiscalYear"`
+	StartDate                 string        `xml:"StartDate"`
+	EndDate                   string        `xml:"EndDate"`
+	CurrencyCode              string        `xml:"CurrencyCode"`
+	DateCreated               string        `xml:"DateCreated"`
+	TaxEntity                 string        `xml:"TaxEntity"`
+	ProductCompanyTaxID       string        `xml:"ProductCompanyTaxID"`
+	SoftwareCertificateNumber string        `xml:"SoftwareCertificateNumber"`
+	ProductID                 string        `xml:"ProductID"`
+	ProductVersion            string        `xml:"ProductVersion"`
+}
+
+type saftPTCustomer struct {
+	CustomerID           string        `xml:"CustomerID"`
+	AccountID            string        `xml:"AccountID"`
+	CustomerTaxID        string        `xml:"CustomerTaxID"`
+	CompanyName          string        `xml:"CompanyName"`
+	BillingAddress       saftPTAddress `xml:"BillingAddress"`
+	SelfBillingIndicator int           `xml:"SelfBillingIndicator"`
+}
+
+type saftPTProduct struct {
+	ProductType        string `xml:"ProductType"`
+	ProductCode        string `xml:"ProductCode"`
+	ProductDescription string `xml:"ProductDescription"`
+	ProductNumberCode  string `xml:"ProductNumberCode"`
+}
+
+type saftPTTax struct {
+	TaxType          string `xml:"TaxType"`
+	TaxCountryRegion string `xml:"TaxCountryRegion"`
+	TaxCode          string `xml:"TaxCode"`
+	Description      string `xml:"Description,omitempty"`
+	TaxPercentage    string `xml:"TaxPercentage"`
+}
+
+type saftPTLine struct {
+	LineNumber         int       `xml:"LineNumber"`
+	ProductCode        string    `xml:"ProductCode"`
+	ProductDescription string    `xml:"ProductDescription"`
+	Quantity           string    `xml:"Quantity"`
+	UnitOfMeasure      string    `xml:"UnitOfMeasure"`
+	UnitPrice          string    `xml:"UnitPrice"`
+	TaxPointDate       string    `xml:"TaxPointDate"`
+	Description        string    `xml:"Description"`
+	CreditAmount       string    `xml:"CreditAmount"`
+	Tax                saftPTTax `xml:"Tax"`
+}
+
+type saftPTInvoice struct {
+	InvoiceNo      string `xml:"InvoiceNo"`
+	ATCUD          string `xml:"ATCUD"`
+	DocumentStatus struct {
+		InvoiceStatus     string `xml:"InvoiceStatus"`
+		InvoiceStatusDate string `xml:"InvoiceStatusDate"`
+		SourceID          string `xml:"SourceID"`
+		SourceBilling     string `xml:"SourceBilling"`
+	} `xml:"DocumentStatus"`
+	Hash            string       `xml:"Hash"`
+	InvoiceDate     string       `xml:"InvoiceDate"`
+	InvoiceType     string       `xml:"InvoiceType"`
+	SourceID        string       `xml:"SourceID"`
+	SystemEntryDate string       `xml:"SystemEntryDate"`
+	CustomerID      string       `xml:"CustomerID"`
+	Lines           []saftPTLine `xml:"Line"`
+	DocumentTotals  struct {
+		TaxPayable string `xml:"TaxPayable"`
+		NetTotal   string `xml:"NetTotal"`
+		GrossTotal string `xml:"GrossTotal"`
+	} `xml:"DocumentTotals"`
+}
+
+type saftPT struct {
+	XMLName     xml.Name     `xml:"AuditFile"`
+	Xmlns       string       `xml:"xmlns,attr"`
+	Header      saftPTHeader `xml:"Header"`
+	MasterFiles struct {
+		Customers []saftPTCustomer `xml:"Customer"`
+		Products  []saftPTProduct  `xml:"Product"`
+		TaxTable  struct {
+			Entries []saftPTTax `xml:"TaxTableEntry"`
+		} `xml:"TaxTable"`
+	} `xml:"MasterFiles"`
+	SourceDocuments struct {
+		SalesInvoices struct {
+			NumberOfEntries int             `xml:"NumberOfEntries"`
+			TotalDebit      string          `xml:"TotalDebit"`
+			TotalCredit     string          `xml:"TotalCredit"`
+			Invoices        []saftPTInvoice `xml:"Invoice"`
+		} `xml:"SalesInvoices"`
+	} `xml:"SourceDocuments"`
+}
+
+// saftPTAddressOf return SAF-T (PT) address of contact
+func saftPTAddressOf(c *Contact) saftPTAddress {
+	if c.Address == nil {
+		return saftPTAddress{AddressDetail: "Desconhecido", City: "Desconhecido", PostalCode: "Desconhecido", Country: "PT"}
+	}
+
+	return saftPTAddress{
+		AddressDetail: c.Address.Address,
+		City:          c.Address.City,
+		PostalCode:    c.Address.PostalCode,
+		Country:       c.Address.Country,
+	}
+}
+
+// saftPTTaxCode return SAF-T (PT) VAT rate code
+func saftPTTaxCode(percent decimal.Decimal) string {
+	switch {
+	case percent.IsZero():
+		return "ISE"
+	case percent.LessThan(decimal.NewFromInt(7)):
+		return "RED"
+	case percent.LessThan(decimal.NewFromInt(16)):
+		return "INT"
+	default:
+		return "NOR"
+	}
+}
+
+// newSAFTPT build SAF-T (PT) audit file
+func newSAFTPT(header *SAFTHeader, invoices []*saftInvoice) *saftPT {
+	company := invoices[0].doc.Company
+
+	file := &saftPT{Xmlns: "urn:OECD:StandardAuditFile-Tax:PT_1.04_01"}
+	file.Header = saftPTHeader{
+		AuditFileVersion:          "1.04_01",
+		CompanyID:                 header.CompanyID,
+		TaxRegistrationNumber:     company.TaxID,
+		TaxAccountingBasis:        "F",
+		CompanyName:               company.Name,
+		CompanyAddress:            saftPTAddressOf(company),
+		FiscalYear:                header.FiscalYear,
+		StartDate:                 saftDate(header.StartDate),
+		EndDate:                   saftDate(header.EndDate),
+		CurrencyCode:              header.CurrencyCode,
+		DateCreated:               saftDate(time.Now()),
+		TaxEntity:                 "Global",
+		ProductCompanyTaxID:       header.ProductCompanyTaxID,
+		SoftwareCertificateNumber: header.SoftwareCertificateNumber,
+		ProductID:                 header.ProductID,
+		ProductVersion:            header.ProductVersion,
+	}
+
+	customers := map[string]bool{}
+	taxes := map[string]bool{}
+	totalCredit := decimal.Zero
+
+	for _, invoice := range invoices {
+		doc := invoice.doc
+		customerID := saftContactID(doc.Customer)
+
+		if !customers[customerID] {
+			customers[customerID] = true
+			file.MasterFiles.Customers = append(file.MasterFiles.Customers, saftPTCustomer{
+				CustomerID:     customerID,
+				AccountID:      "Desconhecido",
+				CustomerTaxID:  doc.Customer.TaxID,
+				CompanyName:    doc.Customer.Name,
+				BillingAddress: saftPTAddressOf(doc.Customer),
+			})
+		}
+
+		ptInvoice := saftPTInvoice{
+			InvoiceNo:       doc.Ref,
+			Hash:            "0",
+			InvoiceDate:     saftDate(invoice.date),
+			InvoiceType:     "FT",
+			SourceID:        header.ProductID,
+			SystemEntryDate: invoice.date.Format("2006-01-02T15:04:05"),
+			CustomerID:      customerID,
+		}
+		ptInvoice.ATCUD = "0"
+		ptInvoice.DocumentStatus.InvoiceStatus = "N"
+		if fiscal := doc.PortugueseFiscal; fiscal != nil {
+			ptInvoice.ATCUD = fiscal.ATCUD()
+			ptInvoice.Hash = fiscal.Hash
+			ptInvoice.InvoiceType = fiscal.DocumentType
+			ptInvoice.SystemEntryDate = fiscal.systemEntryDate(invoice.date)
+			ptInvoice.DocumentStatus.InvoiceStatus = fiscal.DocumentStatus
+		}
+		ptInvoice.DocumentStatus.InvoiceStatusDate = ptInvoice.SystemEntryDate
+		ptInvoice.DocumentStatus.SourceID = header.ProductID
+		ptInvoice.DocumentStatus.SourceBilling = "P"
+
+		for _, line := range invoice.lines {
+			productCode := fmt.Sprintf("%s-%s", doc.Ref, line.productCode)
+			file.MasterFiles.Products = append(file.MasterFiles.Products, saftPTProduct{
+				ProductType:        "S",
+				ProductCode:        productCode,
+				ProductDescription: line.item.Name,
+				ProductNumberCode:  productCode,
+			})
+
+			tax := saftPTTax{
+				TaxType:          "IVA",
+				TaxCountryRegion: "PT",
+				TaxCode:          saftPTTaxCode(line.taxPercent),
+				TaxPercentage:    line.taxPercent.String(),
+			}
+
+			if !taxes[tax.TaxCode+tax.TaxPercentage] {
+				taxes[tax.TaxCode+tax.TaxPercentage] = true
+				file.MasterFiles.TaxTable.Entries = append(file.MasterFiles.TaxTable.Entries, tax)
+			}
+
+			ptInvoice.Lines = append(ptInvoice.Lines, saftPTLine{
+				LineNumber:         line.number,
+				ProductCode:        productCode,
+				ProductDescription: line.item.Name,
+				Quantity:           line.item.Quantity,
+				UnitOfMeasure:      "UN",
+				UnitPrice:          line.item.unitPrice(),
+				TaxPointDate:       saftDate(invoice.date),
+				Description:        line.item.Name,
+				CreditAmount:       saftAmount(line.net),
+				Tax:                tax,
+			})
+		}
+
+		ptInvoice.DocumentTotals.TaxPayable = saftAmount(invoice.tax)
+		ptInvoice.DocumentTotals.NetTotal = saftAmount(invoice.net)
+		ptInvoice.DocumentTotals.GrossTotal = saftAmount(invoice.gross)
+
+		totalCredit = totalCredit.Add(invoice.net)
+		file.SourceDocuments.SalesInvoices.Invoices = append(file.SourceDocuments.SalesInvoices.Invoices, ptInvoice)
+	}
+
+	file.SourceDocuments.SalesInvoices.NumberOfEntries = len(invoices)
+	file.SourceDocuments.SalesInvoices.TotalDebit = saftAmount(decimal.Zero)
+	file.SourceDocuments.SalesInvoices.TotalCredit = saftAmount(totalCredit)
+
+	return file
+}
+
+// SAF-T Financial (NO)
+
+type saftNOAddress struct {
+	StreetName string `xml:"StreetName"`
+	City       string `xml:"City"`
+	PostalCode string `xml:"PostalCode"`
+	Country    string `xml:"Country,omitempty"`
+}
+
+type saftNOParty struct {
+	RegistrationNumber string        `xml:"RegistrationNumber,omitempty"`
+	Name               string        `xml:"Name"`
+	Address            saftNOAddress `xml:"Address"`
+}
+
+type saftNOCustomer struct {
+	saftNOParty
+	CustomerID string `xml:"CustomerID"`
+}
+
+type saftNOAmount struct {
+	Amount string `xml:"Amount"`
+}
+
+type saftNOTaxInformation struct {
+	TaxType       string       `xml:"TaxType"`
+	TaxCode       string       `xml:"TaxCode"`
+	TaxPercentage string       `xml:"TaxPercentage"`
+	TaxBase       string       `xml:"TaxBase"`
+	TaxAmount     saftNOAmount `xml:"TaxAmount"`
+}
+
+type saftNOLine struct {
+	LineNumber           int                  `xml:"LineNumber"`
+	Quantity             string               `xml:"Quantity"`
+	UnitPrice            string               `xml:"UnitPrice"`
+	TaxPointDate         string               `xml:"TaxPointDate"`
+	Description          string               `xml:"Description"`
+	InvoiceLineAmount    saftNOAmount         `xml:"InvoiceLineAmount"`
+	DebitCreditIndicator string               `xml:"DebitCreditIndicator"`
+	TaxInformation       saftNOTaxInformation `xml:"TaxInformation"`
+}
+
+type saftNOInvoice struct {
+	InvoiceNo    string `xml:"InvoiceNo"`
+	CustomerInfo struct {
+		CustomerID string `xml:"CustomerID"`
+	} `xml:"CustomerInfo"`
+	InvoiceDate    string       `xml:"InvoiceDate"`
+	Lines          []saftNOLine `xml:"Line"`
+	DocumentTotals struct {
+		TaxPayable string `xml:"TaxPayable"`
+		NetTotal   string `xml:"NetTotal"`
+		GrossTotal string `xml:"GrossTotal"`
+	} `xml:"DocumentTotals"`
+}
+
+type saftNO struct {
+	XMLName xml.Name `xml:"AuditFile"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Header  struct {
+		AuditFileVersion     string      `xml:"AuditFileVersion"`
+		AuditFileCountry     string      `xml:"AuditFileCountry"`
+		AuditFileDateCreated string      `xml:"AuditFileDateCreated"`
+		SoftwareCompanyName  string      `xml:"SoftwareCompanyName"`
+		SoftwareID           string      `xml:"SoftwareID"`
+		SoftwareVersion      string      `xml:"SoftwareVersion"`
+		Company              saftNOParty `xml:"Company"`
+		DefaultCurrencyCode  string      `xml:"DefaultCurrencyCode"`
+		SelectionCriteria    struct {
+			SelectionStartDate string `xml:"SelectionStartDate"`
+			SelectionEndDate   string `xml:"SelectionEndDate"`
+		} `xml:"SelectionCriteria"`
+		TaxAccountingBasis string `xml:"TaxAccountingBasis"`
+	} `xml:"Header"`
+	MasterFiles struct {
+		Customers struct {
+			Customers []saftNOCustomer `xml:"Customer"`
+		} `xml:"Customers"`
+	} `xml:"MasterFiles"`
+	SourceDocuments struct {
+		SalesInvoices struct {
+			NumberOfEntries int             `xml:"NumberOfEntries"`
+			TotalDebit      string          `xml:"TotalDebit"`
+			TotalCredit     string          `xml:"TotalCredit"`
+			Invoices        []saftNOInvoice `xml:"Invoice"`
+		} `xml:"SalesInvoices"`
+	} `xml:"SourceDocuments"`
+}
+
+// saftNOPartyOf return SAF-T (NO) party of contact
+func saftNOPartyOf(c *Contact) saftNOParty {
+	party := saftNOParty{RegistrationNumber: c.TaxID, Name: c.Name}
+
+	if c.Address != nil {
+		party.Address = saftNOAddress{
+			StreetName: c.Address.Address,
+			City:       c.Address.City,
+			PostalCode: c.Address.PostalCode,
+			Country:    c.Address.Country,
+		}
+	}
+
+	return party
+}
+
+// saftNOTaxCode return SAF-T (NO) standard VAT code of outgoing sales
+func saftNOTaxCode(percent decimal.Decimal) string {
+	switch {
+	case percent.IsZero():
+		return "6"
+	case percent.LessThan(decimal.NewFromInt(13)):
+		return "33"
+	case percent.LessThan(decimal.NewFromInt(25)):
+		return "31"
+	default:
+		return "3"
+	}
+}
+
+// newSAFTNO build SAF-T Financial (NO) audit file
+func newSAFTNO(header *SAFTHeader, invoices []*saftInvoice) *saftNO {
+	file := &saftNO{Xmlns: "urn:StandardAuditFile-Taxation-Financial:NO"}
+	file.Header.AuditFileVersion = "1.30"
+	file.Header.AuditFileCountry = "NO"
+	file.Header.AuditFileDateCreated = saftDate(time.Now())
+	file.Header.SoftwareCompanyName = header.ProductID
+	file.Header.SoftwareID = header.ProductID
+	file.Header.SoftwareVersion = header.ProductVersion
+	file.Header.Company = saftNOPartyOf(invoices[0].doc.Company)
+	file.Header.DefaultCurrencyCode = header.CurrencyCode
+	file.Header.SelectionCriteria.SelectionStartDate = saftDate(header.StartDate)
+	file.Header.SelectionCriteria.SelectionEndDate = saftDate(header.EndDate)
+	file.Header.TaxAccountingBasis = "A"
+
+	customers := map[string]bool{}
+	totalCredit := decimal.Zero
+
+	for _, invoice := range invoices {
+		doc := invoice.doc
+		customerID := saftContactID(doc.Customer)
+
+		if !customers[customerID] {
+			customers[customerID] = true
+			file.MasterFiles.Customers.Customers = append(file.MasterFiles.Customers.Customers, saftNOCustomer{
+				saftNOParty: saftNOPartyOf(doc.Customer),
+				CustomerID:  customerID,
+			})
+		}
+
+		noInvoice := saftNOInvoice{
+			InvoiceNo:   doc.Ref,
+			InvoiceDate: saftDate(invoice.date),
+		}
+		noInvoice.CustomerInfo.CustomerID = customerID
+
+		for _, line := range invoice.lines {
+			noInvoice.Lines = append(noInvoice.Lines, saftNOLine{
+				LineNumber:           line.number,
+				Quantity:             line.item.Quantity,
+				UnitPrice:            line.item.unitPrice(),
+				TaxPointDate:         saftDate(invoice.date),
+				Description:          line.item.Name,
+				InvoiceLineAmount:    saftNOAmount{Amount: saftAmount(line.net)},
+				DebitCreditIndicator: "C",
+				TaxInformation: saftNOTaxInformation{
+					TaxType:       "MVA",
+					TaxCode:       saftNOTaxCode(line.taxPercent),
+					TaxPercentage: line.taxPercent.String(),
+					TaxBase:       saftAmount(line.net),
+					TaxAmount:     saftNOAmount{Amount: saftAmount(line.tax)},
+				},
+			})
+		}
+
+		noInvoice.DocumentTotals.TaxPayable = saftAmount(invoice.tax)
+		noInvoice.DocumentTotals.NetTotal = saftAmount(invoice.net)
+		noInvoice.DocumentTotals.GrossTotal = saftAmount(invoice.gross)
+
+		totalCredit = totalCredit.Add(invoice.net)
+		file.SourceDocuments.SalesInvoices.Invoices = append(file.SourceDocuments.SalesInvoices.Invoices, noInvoice)
+	}
+
+	file.SourceDocuments.SalesInvoices.NumberOfEntries = len(invoices)
+	file.SourceDocuments.SalesInvoices.TotalDebit = saftAmount(decimal.Zero)
+	file.SourceDocuments.SalesInvoices.TotalCredit = saftAmount(totalCredit)
+
+	return file
+}
+
+// JPK_FA (PL)
+
+type jpkFAInvoice struct {
+	KodWaluty string `xml:"tns:KodWaluty"`
+	P1        string `xml:"tns:P_1"`
+	P2A       string `xml:"tns:P_2A"`
+	P3A       string `xml:"tns:P_3A"`
+	P3B       string `xml:"tns:P_3B"`
+	P3C       string `xml:"tns:P_3C"`
+	P3D       string `xml:"tns:P_3D"`
+	P4B       string `xml:"tns:P_4B"`
+	P5B       string `xml:"tns:P_5B,omitempty"`
+	P131      string `xml:"tns:P_13_1,omitempty"`
+	P141      string `xml:"tns:P_14_1,omitempty"`
+	P132      string `xml:"tns:P_13_2,omitempty"`
+	P142      string `xml:"tns:P_14_2,omitempty"`
+	P133      string `xml:"tns:P_13_3,omitempty"`
+	P143      string `xml:"tns:P_14_3,omitempty"`
+	P136      string `xml:"tns:P_13_6,omitempty"`
+	P15       string `xml:"tns:P_15"`
+	Rodzaj    string `xml:"tns:RodzajFaktury"`
+	Typ       string `xml:"typ,attr"`
+}
+
+type jpkFALine struct {
+	P2B string `xml:"tns:P_2B"`
+	P7  string `xml:"tns:P_7"`
+	P8A string `xml:"tns:P_8A"`
+	P8B string `xml:"tns:P_8B"`
+	P9A string `xml:"tns:P_9A"`
+	P11 string `xml:"tns:P_11"`
+	P12 string `xml:"tns:P_12"`
+	Typ string `xml:"typ,attr"`
+}
+
+type jpkFA struct {
+	XMLName  xml.Name `xml:"tns:JPK"`
+	Xmlns    string   `xml:"xmlns:tns,attr"`
+	Naglowek struct {
+		KodFormularza struct {
+			Value        string `xml:",chardata"`
+			KodSystemowy string `xml:"kodSystemowy,attr"`
+			WersjaSchemy string `xml:"wersjaSchemy,attr"`
+		} `xml:"tns:KodFormularza"`
+		WariantFormularza  int    `xml:"tns:WariantFormularza"`
+		CelZlozenia        int    `xml:"tns:CelZlozenia"`
+		DataWytworzeniaJPK string `xml:"tns:DataWytworzeniaJPK"`
+		DataOd             string `xml:"tns:DataOd"`
+		DataDo             string `xml:"tns:DataDo"`
+		KodUrzedu          string `xml:"tns:KodUrzedu"`
+	} `xml:"tns:Naglowek"`
+	Podmiot1 struct {
+		NIP        string `xml:"tns:IdentyfikatorPodmiotu>tns:NIP"`
+		PelnaNazwa string `xml:"tns:IdentyfikatorPodmiotu>tns:PelnaNazwa"`
+	} `xml:"tns:Podmiot1"`
+	Faktury     []jpkFAInvoice `xml:"tns:Faktura"`
+	FakturaCtrl struct {
+		LiczbaFaktur  int    `xml:"tns:LiczbaFaktur"`
+		WartoscFaktur string `xml:"tns:WartoscFaktur"`
+	} `xml:"tns:FakturaCtrl"`
+	Wiersze           []jpkFALine `xml:"tns:FakturaWiersz"`
+	FakturaWierszCtrl struct {
+		LiczbaWierszyFaktur  int    `xml:"tns:LiczbaWierszyFaktur"`
+		WartoscWierszyFaktur string `xml:"tns:WartoscWierszyFaktur"`
+	} `xml:"tns:FakturaWierszCtrl"`
+}
+
+// jpkAddress return contact address on a single line
+func jpkAddress(c *Contact) string {
+	if c.Address == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s, %s %s", c.Address.Address, c.Address.PostalCode, c.Address.City)
+}
+
+// newJPKFA build JPK_FA (PL) audit file
+func newJPKFA(header *SAFTHeader, invoices []*saftInvoice) *jpkFA {
+	company := invoices[0].doc.Company
+
+	file := &jpkFA{Xmlns: "http://jpk.mf.gov.pl/wzor/2022/02/17/02171/"}
+	file.Naglowek.KodFormularza.Value = "JPK_FA"
+	file.Naglowek.KodFormularza.KodSystemowy = "JPK_FA (4)"
+	file.Naglowek.KodFormularza.WersjaSchemy = "1-0"
+	file.Naglowek.WariantFormularza = 4
+	file.Naglowek.CelZlozenia = 1
+	file.Naglowek.DataWytworzeniaJPK = time.Now().Format("2006-01-02T15:04:05")
+	file.Naglowek.DataOd = saftDate(header.StartDate)
+	file.Naglowek.DataDo = saftDate(header.EndDate)
+	file.Naglowek.KodUrzedu = header.TaxOfficeCode
+	file.Podmiot1.NIP = company.TaxID
+	file.Podmiot1.PelnaNazwa = company.Name
+
+	totalGross := decimal.Zero
+	totalLines := decimal.Zero
+
+	for _, invoice := range invoices {
+		doc := invoice.doc
+
+		faktura := jpkFAInvoice{
+			KodWaluty: header.CurrencyCode,
+			P1:        saftDate(invoice.date),
+			P2A:       doc.Ref,
+			P3A:       doc.Customer.Name,
+			P3B:       jpkAddress(doc.Customer),
+			P3C:       doc.Company.Name,
+			P3D:       jpkAddress(doc.Company),
+			P4B:       doc.Company.TaxID,
+			P5B:       doc.Customer.TaxID,
+			P15:       saftAmount(invoice.gross),
+			Rodzaj:    "VAT",
+			Typ:       "G",
+		}
+
+		// Net and tax amounts per VAT rate
+		for _, taxLine := range doc.TaxLines() {
+			base := saftAmount(taxLine.Base)
+			tax := saftAmount(taxLine.Amount)
+
+			switch {
+			case taxLine.Rate.IsZero():
+				faktura.P136 = base
+			case taxLine.Rate.LessThan(decimal.NewFromInt(7)):
+				faktura.P133, faktura.P143 = base, tax
+			case taxLine.Rate.LessThan(decimal.NewFromInt(22)):
+				faktura.P132, faktura.P142 = base, tax
+			default:
+				faktura.P131, faktura.P141 = base, tax
+			}
+		}
+
+		for _, line := range invoice.lines {
+			file.Wiersze = append(file.Wiersze, jpkFALine{
+				P2B: doc.Ref,
+				P7:  line.item.Name,
+				P8A: "szt.",
+				P8B: line.item.Quantity,
+				P9A: line.item.unitPrice(),
+				P11: saftAmount(line.net),
+				P12: line.taxPercent.String(),
+				Typ: "G",
+			})
+			totalLines = totalLines.Add(line.net)
+		}
+
+		totalGross = totalGross.Add(invoice.gross)
+		file.Faktury = append(file.Faktury, faktura)
+	}
+
+	file.FakturaCtrl.LiczbaFaktur = len(invoices)
+	file.FakturaCtrl.WartoscFaktur = saftAmount(totalGross)
+	file.FakturaWierszCtrl.LiczbaWierszyFaktur = len(file.Wiersze)
+	file.FakturaWierszCtrl.WartoscWierszyFaktur = saftAmount(totalLines)
+
+	return file
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// xmlValues return the texts of XML elements named name, or of their Amount element, in document order
+func xmlValues(t *testing.T, data []byte, name string) []string {
+	values := []string{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == name {
+			// Amount elements of SAF-T (NO)
+			value := struct {
+				Text   string `xml:",chardata"`
+				Amount string `xml:"Amount"`
+			}{}
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, strings.TrimSpace(value.Text)+value.Amount)
+		}
+	}
+
+	return values
+}
+
+// sumValues return the sum of amounts
+func sumValues(t *testing.T, values []string) decimal.Decimal {
+	sum := decimal.Zero
+	for _, value := range values {
+		amount, err := decimal.NewFromString(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum = sum.Add(amount)
+	}
+
+	return sum
+}
+
+// newSAFTDocument return an invoice with a document discount and lines of different tax rates
+func newSAFTDocument() *Document {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("FT 1/1")
+	doc.SetDate("15/03/2024")
+	doc.SetCompany(&Contact{Name: "Company", TaxID: "500000000"})
+	doc.SetCustomer(&Contact{Name: "Customer", TaxID: "999999990"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "23"}})
+	doc.AppendItem(&Item{Name: "Books", UnitCost: "33.33", Quantity: "1", Tax: &Tax{Percent: "6"}})
+	doc.AppendItem(&Item{Name: "Training", UnitCost: "20", Quantity: "1", Tax: &Tax{Percent: "13"}})
+	doc.SetDiscount(&Discount{Percent: "10"})
+
+	return doc
+}
+
+func TestExportSAFTDiscount(t *testing.T) {
+	for _, c := range []struct {
+		variant string
+		net     string
+		tax     string
+		total   string
+	}{
+		{SAFTVariantPT, "CreditAmount", "", "NetTotal"},
+		{SAFTVariantNO, "TaxBase", "TaxAmount", "NetTotal"},
+		{SAFTVariantPL, "P_11", "", "WartoscWierszyFaktur"},
+	} {
+		buf := &bytes.Buffer{}
+		if err := ExportSAFT(buf, c.variant, &SAFTHeader{}, newSAFTDocument()); err != nil {
+			t.Fatalf("%s: %v", c.variant, err)
+		}
+
+		lines := xmlValues(t, buf.Bytes(), c.net)
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %d", c.variant, len(lines))
+		}
+		if lines[0] != "90.00" {
+			t.Errorf("%s: expected discounted first line, got %s", c.variant, lines[0])
+		}
+
+		// 153.33 - 10 %
+		if sum := sumValues(t, lines); sum.StringFixed(2) != "138.00" {
+			t.Errorf("%s: expected lines adding up to 138.00, got %s", c.variant, sum.StringFixed(2))
+		}
+		if totals := xmlValues(t, buf.Bytes(), c.total); totals[0] != "138.00" {
+			t.Errorf("%s: expected 138.00 net total, got %s", c.variant, totals[0])
+		}
+
+		if len(c.tax) == 0 {
+			continue
+		}
+		taxes := xmlValues(t, buf.Bytes(), c.tax)
+		if sum, payable := sumValues(t, taxes), xmlValues(t, buf.Bytes(), "TaxPayable"); sum.StringFixed(2) != payable[0] {
+			t.Errorf("%s: expected lines tax adding up to %s, got %s", c.variant, payable[0], sum.StringFixed(2))
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package generator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sanitize policies
+const (
+	SanitizeStrip  string = "strip"
+	SanitizeReject string = "reject"
+)
+
+// ErrUnsafeInput when a text holds invalid UTF-8, control or bidi override characters,
+// or exceeds Options.SanitizeMaxLength, with SanitizeReject policy
+var ErrUnsafeInput = errors.New("unsafe input")
+
+// ErrInvalidAmount when an amount isn't a plain decimal number (exponents, NaN, Infinity...)
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// sanitizeAmountRegexp match plain decimal amounts
+var sanitizeAmountRegexp = regexp.MustCompile(`^[+-]?[0-9]{1,18}(\.[0-9]{1,12})?$`)
+
+// unsafeRune return true for control characters, except line breaks and tabs,
+// bidi overrides and isolates and byte order marks. Zero width joiners and non-joiners are kept,
+// Persian and Indic scripts need them.
+func unsafeRune(r rune) bool {
+	switch {
+	case r == '\n' || r == '\r' || r == '\t':
+		return false
+	case unicode.IsControl(r):
+		return true
+	case r == '\u061C' || r == '\u200E' || r == '\u200F':
+		return true
+	case r >= '\u202A' && r <= '\u202E', r >= '\u2066' && r <= '\u2069':
+		return true
+	case r == '\uFEFF':
+		return true
+	}
+
+	return false
+}
+
+// sanitizeText return text without unsafe characters, truncated to Options.SanitizeMaxLength runes
+func (doc *Document) sanitizeText(text string) (string, error) {
+	reject := doc.Options.Sanitize == SanitizeReject
+
+	if !utf8.ValidString(text) {
+		if reject {
+			return "", ErrUnsafeInput
+		}
+		text = strings.ToValidUTF8(text, "")
+	}
+
+	if strings.IndexFunc(text, unsafeRune) >= 0 {
+		if reject {
+			return "", ErrUnsafeInput
+		}
+		text = strings.Map(func(r rune) rune {
+			if unsafeRune(r) {
+				return -1
+			}
+			return r
+		}, text)
+	}
+
+	if limit := doc.Options.SanitizeMaxLength; limit > 0 && utf8.RuneCountInString(text) > limit {
+		if reject {
+			return "", ErrUnsafeInput
+		}
+		text = string([]rune(text)[:limit])
+	}
+
+	return text, nil
+}
+
+// sanitizeAmount check amount is empty or a plain decimal number
+func sanitizeAmount(amount string) error {
+	if len(amount) > 0 && !sanitizeAmountRegexp.MatchString(amount) {
+		return ErrInvalidAmount
+	}
+
+	return nil
+}
+
+// Sanitize strip or reject hostile texts (invalid UTF-8, control and bidi override characters,
+// overlong strings) and reject amounts which aren't plain decimal numbers, following Options.Sanitize.
+// Document is updated in place.
+func (doc *Document) Sanitize() error {
+	if len(doc.Options.Sanitize) == 0 {
+		return nil
+	}
+
+	texts := []*string{
+		&doc.Ref, &doc.Version, &doc.ClientRef, &doc.QuoteRef, &doc.Description, &doc.Notes, &doc.PaymentTerm, &doc.BankDetails,
+		&doc.Date, &doc.ValidityDate,
+		&doc.CustomTotal, &doc.CustomTax, &doc.CustomTaxRate, &doc.CustomSubtotal,
+	}
+	for i := range doc.Footnotes {
+		texts = append(texts, &doc.Footnotes[i])
+	}
+	if doc.CoverLetter != nil {
+		texts = append(texts,
+			&doc.CoverLetter.Subject, &doc.CoverLetter.Greeting, &doc.CoverLetter.Body,
+			&doc.CoverLetter.Closing, &doc.CoverLetter.Signature,
+		)
+	}
+	if doc.DirectDebit != nil {
+		texts = append(texts,
+			&doc.DirectDebit.MandateRef, &doc.DirectDebit.CreditorID, &doc.DirectDebit.DebitDate, &doc.DirectDebit.IBAN,
+		)
+	}
+	amounts := []string{}
+
+	contacts := []*Contact{doc.Company, doc.Customer}
+	for _, seller := range doc.Sellers {
+		texts = append(texts, &seller.Key)
+		contacts = append(contacts, seller.Contact)
+	}
+	for _, contact := range contacts {
+		if contact == nil {
+			continue
+		}
+
+		texts = append(texts, &contact.Name, &contact.TaxID, &contact.NZBN, &contact.Phone, &contact.Email, &contact.Website)
+		if contact.Address != nil {
+			texts = append(texts,
+				&contact.Address.Address, &contact.Address.Address2,
+				&contact.Address.PostalCode, &contact.Address.City, &contact.Address.State, &contact.Address.Country,
+			)
+		}
+		for i := range contact.AddtionnalInfo {
+			texts = append(texts, &contact.AddtionnalInfo[i])
+		}
+	}
+
+	if doc.DefaultTax != nil {
+		amounts = append(amounts, doc.DefaultTax.Percent, doc.DefaultTax.Amount)
+	}
+	if doc.Discount != nil {
+		amounts = append(amounts, doc.Discount.Percent, doc.Discount.Amount)
+	}
+	if doc.Statement != nil {
+		amounts = append(amounts, doc.Statement.PreviousBalance, doc.Statement.PaymentsReceived)
+	}
+
+	if doc.Settlement != nil {
+		texts = append(texts, &doc.Settlement.Period, &doc.Settlement.PayoutDate)
+		for _, transaction := range doc.Settlement.Transactions {
+			texts = append(texts, &transaction.Date, &transaction.Ref, &transaction.Description)
+			amounts = append(amounts, transaction.Amount, transaction.Fee)
+		}
+	}
+
+	if doc.Retainer != nil {
+		amounts = append(amounts, doc.Retainer.OpeningBalance, doc.Retainer.Consumed, doc.Retainer.OpeningHours, doc.Retainer.ConsumedHours)
+	}
+
+	if doc.Royalty != nil {
+		texts = append(texts, &doc.Royalty.RightsHolder, &doc.Royalty.Period)
+		amounts = append(amounts, doc.Royalty.Advance)
+	}
+
+	if doc.RemittanceStub != nil {
+		texts = append(texts, &doc.RemittanceStub.PayTo, &doc.RemittanceStub.AccountNumber, &doc.RemittanceStub.DueDate)
+		amounts = append(amounts, doc.RemittanceStub.AmountDue)
+	}
+
+	if doc.Boleto != nil {
+		texts = append(texts,
+			&doc.Boleto.BankName, &doc.Boleto.PaymentPlace, &doc.Boleto.DueDate, &doc.Boleto.Beneficiary,
+			&doc.Boleto.BeneficiaryCode, &doc.Boleto.OurNumber, &doc.Boleto.DocumentNumber, &doc.Boleto.Instructions,
+		)
+	}
+
+	if doc.Giro != nil {
+		texts = append(texts, &doc.Giro.Account, &doc.Giro.Reference)
+	}
+
+	for _, headerFooter := range []*HeaderFooter{doc.Header, doc.Footer} {
+		if headerFooter != nil {
+			texts = append(texts, &headerFooter.Text)
+		}
+	}
+
+	for _, annotation := range doc.Annotations {
+		texts = append(texts, &annotation.Text)
+	}
+
+	for _, payer := range doc.Payers {
+		texts = append(texts, &payer.Name, &payer.Ref)
+		amounts = append(amounts, payer.Percent, payer.Amount)
+	}
+
+	if doc.Medical != nil {
+		texts = append(texts, &doc.Medical.PatientRef, &doc.Medical.InsuranceProvider, &doc.Medical.InsurancePolicy)
+		for i := range doc.Medical.TreatmentCodes {
+			texts = append(texts, &doc.Medical.TreatmentCodes[i])
+		}
+		amounts = append(amounts, doc.Medical.InsurerAmount, doc.Medical.PatientAmount)
+	}
+
+	if doc.Stay != nil {
+		texts = append(texts, &doc.Stay.Guest, &doc.Stay.Room, &doc.Stay.Arrival, &doc.Stay.Departure, &doc.Stay.Confirmation)
+		amounts = append(amounts, doc.Stay.CityTaxRate)
+	}
+
+	if doc.Donation != nil {
+		texts = append(texts, &doc.Donation.Purpose, &doc.Donation.GoodsOrServices)
+		for i := range doc.Donation.Statements {
+			texts = append(texts, &doc.Donation.Statements[i])
+		}
+	}
+
+	for _, reading := range doc.MeterReadings {
+		texts = append(texts, &reading.Meter, &reading.PreviousDate, &reading.CurrentDate, &reading.Unit)
+		amounts = append(amounts, reading.Previous, reading.Current, reading.Factor)
+	}
+
+	texts = append(texts, &doc.DownPaymentOf)
+	for _, downPayment := range doc.DownPayments {
+		texts = append(texts, &downPayment.Ref, &downPayment.Date)
+		amounts = append(amounts, downPayment.Amount, downPayment.Tax)
+	}
+
+	if doc.Acceptance != nil {
+		texts = append(texts, &doc.Acceptance.Statement, &doc.Acceptance.AcceptedVia, &doc.Acceptance.SignerName, &doc.Acceptance.Date)
+	}
+
+	if doc.ExpectedTotals != nil {
+		amounts = append(amounts,
+			doc.ExpectedTotals.WithoutTax, doc.ExpectedTotals.Tax, doc.ExpectedTotals.WithTax, doc.ExpectedTotals.Tolerance,
+		)
+	}
+
+	for _, method := range doc.PaymentMethods {
+		texts = append(texts, &method.Title, &method.Details, &method.URL)
+		amounts = append(amounts, method.MinAmount, method.MaxAmount, method.SurchargePercent, method.SurchargeAmount)
+	}
+
+	items := append([]*Item{}, doc.Items...)
+	for _, option := range doc.QuoteOptions {
+		texts = append(texts, &option.Key, &option.Title, &option.Description)
+		items = append(items, option.Items...)
+	}
+
+	for _, item := range items {
+		texts = append(texts, &item.Name, &item.Description, &item.Unit, &item.Date, &item.Assignee, &item.Account, &item.CostCenter, &item.Group, &item.Period, &item.Seller)
+		amounts = append(amounts, item.UnitCost, item.Quantity, item.PriceBasis, item.Total)
+		if item.Tax != nil {
+			amounts = append(amounts, item.Tax.Percent, item.Tax.Amount)
+		}
+		if item.Discount != nil {
+			amounts = append(amounts, item.Discount.Percent, item.Discount.Amount)
+		}
+	}
+
+	for _, text := range texts {
+		sanitized, err := doc.sanitizeText(*text)
+		if err != nil {
+			return err
+		}
+		*text = sanitized
+	}
+
+	for _, item := range items {
+		for key, value := range item.Fields {
+			sanitized, err := doc.sanitizeText(value)
+			if err != nil {
+				return err
+			}
+			item.Fields[key] = sanitized
+		}
+	}
+
+	for _, amount := range amounts {
+		if err := sanitizeAmount(amount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	newDoc := func(policy string, name string, unitCost string) *Document {
+		doc, _ := New(Invoice, &Options{Sanitize: policy, SanitizeMaxLength: 64})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: name, UnitCost: unitCost, Quantity: "1"})
+		return doc
+	}
+
+	doc := newDoc(SanitizeStrip, "Ite\u202em\x00 \xff"+strings.Repeat("x", 100), "10")
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if name := doc.Items[0].Name; name != "Item "+strings.Repeat("x", 59) {
+		t.Errorf("unexpected sanitized name %q", name)
+	}
+
+	if err := newDoc(SanitizeReject, "Ite\u202em", "10").Validate(); err != ErrUnsafeInput {
+		t.Errorf("expected %v, got %v", ErrUnsafeInput, err)
+	}
+	if err := newDoc(SanitizeReject, strings.Repeat("x", 65), "10").Validate(); err != ErrUnsafeInput {
+		t.Errorf("expected %v, got %v", ErrUnsafeInput, err)
+	}
+
+	for _, amount := range []string{"NaN", "Infinity", "1e999999999", "0x10", "1.5.2"} {
+		if err := newDoc(SanitizeStrip, "Item", amount).Validate(); err != ErrInvalidAmount {
+			t.Errorf("expected %v for %s, got %v", ErrInvalidAmount, amount, err)
+		}
+	}
+
+	// Zero width joiners and non-joiners are kept
+	doc = newDoc(SanitizeReject, "می\u200cخواهم क्\u200dष", "10")
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if name := doc.Items[0].Name; name != "می\u200cخواهم क्\u200dष" {
+		t.Errorf("expected joiners kept, got %q", name)
+	}
+}
+
+func TestSanitizeSections(t *testing.T) {
+	unsafe := "Text\u202e"
+
+	doc, _ := New(Invoice, &Options{Sanitize: SanitizeStrip})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+	doc.SetHeader(&HeaderFooter{Text: unsafe})
+	doc.SetFooter(&HeaderFooter{Text: unsafe})
+	doc.Annotations = []*Annotation{{Page: 1, Text: unsafe}}
+	doc.Payers = []*Payer{{Name: unsafe, Ref: unsafe, Percent: "100"}}
+	doc.Medical = &Medical{PatientRef: unsafe, InsuranceProvider: unsafe, TreatmentCodes: []string{unsafe}}
+	doc.Stay = &Stay{Guest: unsafe, Room: unsafe, Arrival: "01/03/2024", Departure: "03/03/2024"}
+	doc.Donation = &Donation{Purpose: unsafe, Statements: []string{unsafe}}
+	doc.MeterReadings = []*MeterReading{{Meter: unsafe, Unit: unsafe, Previous: "10", Current: "20"}}
+
+	if err := doc.Sanitize(); err != nil {
+		t.Fatal(err)
+	}
+	texts := []string{
+		doc.Header.Text, doc.Footer.Text, doc.Annotations[0].Text, doc.Payers[0].Name, doc.Payers[0].Ref,
+		doc.Medical.PatientRef, doc.Medical.InsuranceProvider, doc.Medical.TreatmentCodes[0],
+		doc.Stay.Guest, doc.Stay.Room, doc.Donation.Purpose, doc.Donation.Statements[0],
+		doc.MeterReadings[0].Meter, doc.MeterReadings[0].Unit,
+	}
+	for i, text := range texts {
+		if text != "Text" {
+			t.Errorf("expected text %d sanitized, got %q", i, text)
+		}
+	}
+
+	for name, set := range map[string]func(amount string){
+		"payer":  func(amount string) { doc.Payers[0].Amount = amount },
+		"co-pay": func(amount string) { doc.Medical.InsurerAmount = amount },
+		"city":   func(amount string) { doc.Stay.CityTaxRate = amount },
+		"meter":  func(amount string) { doc.MeterReadings[0].Factor = amount },
+	} {
+		set("NaN")
+		if err := doc.Sanitize(); err != ErrInvalidAmount {
+			t.Errorf("expected %v for %s amount, got %v", ErrInvalidAmount, name, err)
+		}
+		set("")
+	}
+}
+
+func FuzzBuild(f *testing.F) {
+	f.Add("Item", "Description", "10.50", "2")
+	f.Add("\u202egnp.exe", "\x00\x1b[31m", "1e999999999", "NaN")
+	f.Add(strings.Repeat("W", 5000), "\xff\xfe", "-0.0000000001", "999999999999999999")
+
+	f.Fuzz(func(t *testing.T, name string, description string, unitCost string, quantity string) {
+		doc, _ := New(Invoice, &Options{Sanitize: SanitizeStrip})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer", AddtionnalInfo: []string{description}})
+		doc.AppendItem(&Item{Name: name, Description: description, UnitCost: unitCost, Quantity: quantity})
+
+		pdf, err := doc.Build()
+		if err != nil {
+			return
+		}
+
+		buffer := &bytes.Buffer{}
+		if err := pdf.Output(buffer); err != nil {
+			return
+		}
+		if _, _, _, err := pdfObjects(buffer.Bytes()); err != nil {
+			t.Errorf("expected well formed PDF, got %v", err)
+		}
+	})
+}
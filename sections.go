@@ -0,0 +1,298 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownSection when Options.Sections has a section without Section constant
+var ErrUnknownSection = errors.New("unknown section")
+
+// Document sections, rendered in Options.Sections order
+const (
+	SectionHeader  string = "header"  // Page header, see Document.Header
+	SectionFooter  string = "footer"  // Page footer, see Document.Footer
+	SectionMeta    string = "meta"    // Title, reference, version and dates
+	SectionParties string = "parties" // Company and customer contacts
+	SectionDetails string = "details" // Statement, retainer, fiscal references, description, medical, meter, donation and stay informations
+	SectionItems   string = "items"
+	SectionNotes   string = "notes"
+	SectionTotals  string = "totals"  // Totals and tax mentions
+	SectionPayment string = "payment" // Payment term, payers and late interest
+	SectionOptions string = "options" // Quote alternatives with their subtotal, see Document.QuoteOptions
+	SectionChart   string = "chart"   // Spend breakdown chart, see Options.Chart
+	SectionLegal   string = "legal"   // Fiscal QR codes and compliance mentions
+
+	SectionAcceptance string = "acceptance" // Quote acceptance name, date and signature, see Document.Acceptance
+
+	SectionBoleto     string = "boleto"     // Bank payment slip at the bottom of the last page, see Document.Boleto
+	SectionGiro       string = "giro"       // Nordic giro OCR line at the bottom of the last page, see Document.Giro
+	SectionRemittance string = "remittance" // Check remittance stub at the bottom of page 1, see Document.RemittanceStub
+
+	SectionTransactions string = "transactions" // Settlement statements transactions detail pages, see Document.Settlement
+	SectionAppendix     string = "appendix"     // Full text of truncated item descriptions, see Options.MaxDescriptionLength
+)
+
+// DefaultSections define the default sections order
+var DefaultSections = []string{
+	SectionHeader,
+	SectionFooter,
+	SectionMeta,
+	SectionParties,
+	SectionDetails,
+	SectionItems,
+	SectionNotes,
+	SectionTotals,
+	SectionPayment,
+	SectionOptions,
+	SectionChart,
+	SectionLegal,
+	SectionAcceptance,
+	SectionBoleto,
+	SectionGiro,
+	SectionRemittance,
+	SectionTransactions,
+	SectionAppendix,
+}
+
+// sections return Options.Sections, DefaultSections when empty
+func (doc *Document) sections() []string {
+	if len(doc.Options.Sections) == 0 {
+		return DefaultSections
+	}
+
+	return doc.Options.Sections
+}
+
+// checkSections check Options.Sections are known sections, DefaultSections listing every one of them
+func (doc *Document) checkSections() error {
+	for _, section := range doc.Options.Sections {
+		known := false
+		for _, s := range DefaultSections {
+			known = known || s == section
+		}
+
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownSection, section)
+		}
+	}
+
+	return nil
+}
+
+// hasSection return true when section is rendered
+func (doc *Document) hasSection(section string) bool {
+	for _, s := range doc.sections() {
+		if s == section {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendMetaSection append title and metas
+func (doc *Document) appendMetaSection() {
+	// Appenf document title
+	doc.appendTitle()
+
+	// Appenf document metas (ref & version)
+	doc.appendMetas()
+}
+
+// appendPartiesSection append company and customer contacts, Y is set below the highest
+func (doc *Document) appendPartiesSection() {
+	var companyBottom, customerBottom float64
+
+	if x, y, ok := doc.addressWindow(); ok {
+		// Customer address in the envelope window, company beside it
+		companyX, companyY := doc.letterCompanyPosition()
+		companyBottom = doc.Company.appendContactTODoc(companyX, companyY, true, "R", doc)
+		customerBottom = doc.Customer.appendContactTODoc(x, y, false, "L", doc)
+		customerBottom = doc.appendPostalBarcode(x, y, customerBottom)
+	} else {
+		// Append company contact to doc
+		companyBottom = doc.Company.appendCompanyContactToDoc(doc)
+
+		// Append customer contact to doc
+		customerBottom = doc.Customer.appendCustomerContactToDoc(doc)
+		customerBottom = doc.appendPostalBarcode(130, BaseMarginTop+25, customerBottom)
+	}
+
+	if customerBottom > companyBottom {
+		doc.pdf.SetXY(10, customerBottom)
+	} else {
+		doc.pdf.SetXY(10, companyBottom)
+	}
+}
+
+// appendDetailsSection append informations displayed above items
+func (doc *Document) appendDetailsSection() error {
+	// Append previous balance mini-statement
+	doc.appendStatement()
+
+	// Append retainer balance
+	doc.appendRetainer()
+
+	// Append settlement payout summary
+	doc.appendSettlementSummary()
+
+	// Append GIB e-Fatura / e-Arşiv informations
+	if err := doc.appendTurkishFiscal(); err != nil {
+		return err
+	}
+
+	// Append NF-e access key and NFS-e verification code
+	if err := doc.appendBrazilianFiscal(); err != nil {
+		return err
+	}
+
+	// Append issued on behalf of sellers mention
+	doc.appendIssuedOnBehalf()
+
+	// Append converted quote ref
+	doc.appendQuoteRef()
+
+	// Append order of down payment invoices
+	doc.appendDownPaymentOf()
+
+	// Append description
+	doc.appendDescription()
+
+	// Append medical informations
+	doc.appendMedical()
+
+	// Append meter readings
+	doc.appendMeterReadings()
+
+	// Append donation purpose and mentions
+	doc.appendDonation()
+
+	// Append guest and stay informations
+	doc.appendStay()
+
+	return nil
+}
+
+// appendItemsSection append items table, following layout
+func (doc *Document) appendItemsSection() {
+	// Switch to compact density when items and totals don't fit in page
+	if doc.Options.Density == DensityAuto && doc.Options.Layout != LayoutFolio && doc.Options.Layout != LayoutRoyalty {
+		doc.compact = doc.pdf.GetY()+doc.itemsHeight()+doc.totalsHeight() > doc.pageBottom()
+	}
+
+	if doc.Options.Layout == LayoutFolio {
+		doc.appendFolioItems()
+	} else if doc.Options.Layout == LayoutRoyalty {
+		doc.appendRoyaltyItems()
+	} else if len(doc.Sellers) > 0 {
+		doc.appendSellerItems()
+	} else if (doc.Type != DonationReceipt && doc.Type != SettlementStatement) || len(doc.Items) > 0 {
+		doc.appendItems()
+	}
+}
+
+// totalsHeight return the height of totals block
+func (doc *Document) totalsHeight() float64 {
+	// Total bloc height = 30, 45 when doc discount
+	height := 30.0
+	if doc.Discount != nil {
+		height += 15
+	}
+	if doc.Medical != nil && doc.Medical.hasSplit() {
+		height += 22
+	}
+	height += doc.downPaymentsHeight()
+
+	return height
+}
+
+// checkTotalsHeight add a page when totals don't fit in current page, then draw the totals separator
+func (doc *Document) checkTotalsHeight() {
+	if doc.pdf.GetY()+doc.totalsHeight() > doc.pageBottom() {
+		doc.pdf.AddPage()
+	}
+
+	doc.appendSeparator(SeparatorTotals, doc.pdf.GetY()+5)
+}
+
+// appendTotalsSection append totals and tax mentions
+func (doc *Document) appendTotalsSection() {
+	// Settlement statements without invoiced fees only have their payout summary
+	if doc.Type == SettlementStatement && len(doc.Items) == 0 {
+		return
+	}
+
+	// Append total
+	doc.appendTotal()
+
+	// Append GST included statement
+	doc.appendGSTMention()
+
+	// Append south african VAT included statement
+	doc.appendSAVATMention()
+
+	// Append canadian GST/HST and QST lines
+	doc.appendCanadianTaxLines()
+
+	// Append insurer / patient split
+	doc.appendMedicalSplit()
+
+	// Append prior down payments deduction
+	doc.appendDownPayments()
+}
+
+// appendPaymentSection append payment term and reference, bank details, portal QR code, payers and late interest
+func (doc *Document) appendPaymentSection() error {
+	// Append payment term
+	doc.appendPaymentTerm()
+
+	// Append quote validity
+	doc.appendValidity()
+
+	// Append RF or national payment reference
+	doc.appendPaymentReference()
+
+	// Append bank details
+	doc.appendBankDetails()
+
+	// Append accepted payment methods
+	doc.appendPaymentMethods()
+
+	// Append online copy QR code
+	if err := doc.appendPortal(); err != nil {
+		return err
+	}
+
+	// Append per payer payable table
+	doc.appendPayers()
+
+	// Append late interest calculation
+	doc.appendLateInterest()
+
+	return nil
+}
+
+// appendLegalSection append fiscal QR codes and compliance mentions
+func (doc *Document) appendLegalSection() error {
+	// Append Portuguese ATCUD and QR code
+	if err := doc.appendPortugueseFiscal(); err != nil {
+		return err
+	}
+
+	// Append TicketBAI / Verifactu QR code
+	if err := doc.appendSpanishFiscal(); err != nil {
+		return err
+	}
+
+	// Append e-Arşiv annotation
+	doc.appendTurkishAnnotation()
+
+	// Append footnotes
+	doc.appendFootnotes()
+
+	// Append compliance profile mentions
+	doc.appendComplianceMentions()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	output := func(sections []string) []byte {
+		doc, _ := New(Invoice, &Options{DisableCompression: true, Sections: sections})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.SetNotes("Thank you")
+		doc.SetPaymentTerm("30 days")
+		doc.AppendItem(&Item{Name: "Consulting", UnitCost: "150", Quantity: "2"})
+
+		pdf, err := doc.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		buffer := &bytes.Buffer{}
+		if err := pdf.Output(buffer); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	// Totals above items
+	summaryFirst := output([]string{SectionMeta, SectionParties, SectionTotals, SectionItems, SectionPayment})
+	if total, item := bytes.Index(summaryFirst, []byte("(SUBTOTAL)")), bytes.Index(summaryFirst, []byte("(Consulting)")); total < 0 || item < total {
+		t.Errorf("expected totals before items")
+	}
+	if bytes.Contains(summaryFirst, []byte("(Thank you)")) {
+		t.Errorf("expected notes to be omitted")
+	}
+
+	defaultOrder := output(nil)
+	if total, item := bytes.Index(defaultOrder, []byte("(SUBTOTAL)")), bytes.Index(defaultOrder, []byte("(Consulting)")); item < 0 || total < item {
+		t.Errorf("expected items before totals")
+	}
+
+	doc, _ := New(Invoice, &Options{Sections: []string{"sidebar"}})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	if err := doc.Validate(); !errors.Is(err, ErrUnknownSection) {
+		t.Errorf("expected ErrUnknownSection, got %v", err)
+	}
+
+	// Every section is accepted
+	doc.Options.Sections = DefaultSections
+	if err := doc.checkSections(); err != nil {
+		t.Error(err)
+	}
+}
@@ -66,6 +66,12 @@ func (d *Document) SetDate(date string) *Document {
 	return d
 }
 
+// SetBankDetails of document
+func (d *Document) SetBankDetails(bankDetails string) *Document {
+	d.BankDetails = bankDetails
+	return d
+}
+
 // SetPaymentTerm of document
 func (d *Document) SetPaymentTerm(term string) *Document {
 	d.PaymentTerm = term
@@ -83,3 +89,105 @@ func (d *Document) SetDiscount(discount *Discount) *Document {
 	d.Discount = discount
 	return d
 }
+
+// SetMedical set medical and insurance informations of document
+func (d *Document) SetMedical(medical *Medical) *Document {
+	d.Medical = medical
+	return d
+}
+
+// AppendPayer to document payers
+func (d *Document) AppendPayer(payer *Payer) *Document {
+	d.Payers = append(d.Payers, payer)
+	return d
+}
+
+// AppendPaymentMethod to document accepted payment methods
+func (d *Document) AppendPaymentMethod(method *PaymentMethod) *Document {
+	d.PaymentMethods = append(d.PaymentMethods, method)
+	return d
+}
+
+// AppendQuoteOption to quote alternatives
+func (d *Document) AppendQuoteOption(option *QuoteOption) *Document {
+	d.QuoteOptions = append(d.QuoteOptions, option)
+	return d
+}
+
+// AppendMeterReading to document meter readings
+func (d *Document) AppendMeterReading(reading *MeterReading) *Document {
+	d.MeterReadings = append(d.MeterReadings, reading)
+	return d
+}
+
+// SetDonation set donation receipt informations of document
+func (d *Document) SetDonation(donation *Donation) *Document {
+	d.Donation = donation
+	return d
+}
+
+// SetStay set hotel stay informations of document
+func (d *Document) SetStay(stay *Stay) *Document {
+	d.Stay = stay
+	return d
+}
+
+// SetLateInterest set late payment interest computation of document
+func (d *Document) SetLateInterest(lateInterest *LateInterest) *Document {
+	d.LateInterest = lateInterest
+	return d
+}
+
+// AppendAnnotation add a positioned text or image annotation to document pages
+func (d *Document) AppendAnnotation(annotation *Annotation) *Document {
+	d.Annotations = append(d.Annotations, annotation)
+	return d
+}
+
+// SetStatement set previous balance mini-statement of document
+func (d *Document) SetStatement(statement *Statement) *Document {
+	d.Statement = statement
+	return d
+}
+
+// SetPortugueseFiscal set Portuguese tax authority informations of document
+func (d *Document) SetPortugueseFiscal(fiscal *PortugueseFiscal) *Document {
+	d.PortugueseFiscal = fiscal
+	return d
+}
+
+// SetSpanishFiscal set TicketBAI or Verifactu informations of document
+func (d *Document) SetSpanishFiscal(fiscal *SpanishFiscal) *Document {
+	d.SpanishFiscal = fiscal
+	return d
+}
+
+// SetTurkishFiscal set GIB e-Fatura or e-Arşiv informations of document
+func (d *Document) SetTurkishFiscal(fiscal *TurkishFiscal) *Document {
+	d.TurkishFiscal = fiscal
+	return d
+}
+
+// SetGST set Australian or New Zealand tax invoice mode of document
+func (d *Document) SetGST(gst *GST) *Document {
+	d.GST = gst
+	return d
+}
+
+// SetCanadianTax set Canadian sales taxes registration numbers of document
+func (d *Document) SetCanadianTax(tax *CanadianTax) *Document {
+	d.CanadianTax = tax
+	return d
+}
+
+// SetBrazilianFiscal set NF-e and NFS-e references of document
+func (d *Document) SetBrazilianFiscal(fiscal *BrazilianFiscal) *Document {
+	d.BrazilianFiscal = fiscal
+	return d
+}
+
+// SetSouthAfricanVAT set South African tax invoice mode of document
+func (d *Document) SetSouthAfricanVAT(vat *SouthAfricanVAT) *Document {
+	d.SouthAfricanVAT = vat
+	return d
+}
@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Settlement transactions table columns widths in millimeters
+var settlementColumns = []float64{22, 30, 71, 22, 22, 23}
+
+// Settlement define the payout of a settlement statement: sales and refunds of the period with their
+// platform fees. Items of the statement are platform fees invoiced on top of transactions fees, ex a
+// subscription, and are deducted with their tax.
+type Settlement struct {
+	Period       string                   `json:"period,omitempty"`      // ex March 2024
+	PayoutDate   string                   `json:"payout_date,omitempty"` // Date the net payout is transferred
+	Transactions []*SettlementTransaction `json:"transactions,omitempty" validate:"dive"`
+
+	_grossSales decimal.Decimal
+	_refunds    decimal.Decimal
+	_fees       decimal.Decimal
+}
+
+// SettlementTransaction define a sale or refund of a settlement statement
+type SettlementTransaction struct {
+	Date        string `json:"date,omitempty"`
+	Ref         string `json:"ref,omitempty"` // Order or transaction ref
+	Description string `json:"description,omitempty"`
+	Refund      bool   `json:"refund,omitempty"`           // Refunded to the buyer, amount is deducted
+	Amount      string `json:"amount" validate:"required"` // Amount paid or refunded to the buyer ex 49.90
+	Fee         string `json:"fee,omitempty"`              // Platform fee of the transaction ex 4.99, negative when returned on refunds
+
+	_amount decimal.Decimal
+	_fee    decimal.Decimal
+}
+
+// Net return transaction amount less its fee, negative for refunds
+func (t *SettlementTransaction) Net() decimal.Decimal {
+	if t.Refund {
+		return t._amount.Neg().Sub(t._fee)
+	}
+
+	return t._amount.Sub(t._fee)
+}
+
+// Prepare convert transactions strings to decimal and sum them, invoicedFees is the statement total with tax
+func (s *Settlement) Prepare(invoicedFees decimal.Decimal) error {
+	s._grossSales = decimal.Zero
+	s._refunds = decimal.Zero
+	s._fees = invoicedFees
+
+	for _, transaction := range s.Transactions {
+		amount, err := decimal.NewFromString(transaction.Amount)
+		if err != nil {
+			return err
+		}
+		transaction._amount = amount
+
+		transaction._fee = decimal.Zero
+		if len(transaction.Fee) > 0 {
+			fee, err := decimal.NewFromString(transaction.Fee)
+			if err != nil {
+				return err
+			}
+			transaction._fee = fee
+		}
+
+		if transaction.Refund {
+			s._refunds = s._refunds.Add(amount)
+		} else {
+			s._grossSales = s._grossSales.Add(amount)
+		}
+		s._fees = s._fees.Add(transaction._fee)
+	}
+
+	return nil
+}
+
+// GrossSales return the sales amount of the period
+func (s *Settlement) GrossSales() decimal.Decimal {
+	return s._grossSales
+}
+
+// Refunds return the refunded amount of the period
+func (s *Settlement) Refunds() decimal.Decimal {
+	return s._refunds
+}
+
+// Fees return transactions fees plus statement items total with tax
+func (s *Settlement) Fees() decimal.Decimal {
+	return s._fees
+}
+
+// NetPayout return gross sales - refunds - fees
+func (s *Settlement) NetPayout() decimal.Decimal {
+	return s._grossSales.Sub(s._refunds).Sub(s._fees)
+}
+
+// appendSettlementSummary append period, gross sales, refunds, fees and net payout of settlement statements
+func (doc *Document) appendSettlementSummary() {
+	settlement := doc.Settlement
+	if settlement == nil || doc.Type != SettlementStatement {
+		return
+	}
+
+	titles := []string{
+		doc.Options.TextSettlementGrossSalesTitle,
+		doc.Options.TextSettlementRefundsTitle,
+		doc.Options.TextSettlementFeesTitle,
+		doc.Options.TextSettlementNetPayoutTitle,
+	}
+	amounts := []string{
+		doc.formatMoney(settlement.GrossSales()),
+		doc.formatMoney(settlement.Refunds().Neg()),
+		doc.formatMoney(settlement.Fees().Neg()),
+		doc.formatMoney(settlement.NetPayout()),
+	}
+
+	y := doc.pdf.GetY() + 10
+
+	// Period and payout date
+	details := []string{}
+	if len(settlement.Period) > 0 {
+		details = append(details, fmt.Sprintf("%s: %s", doc.Options.TextSettlementPeriodTitle, settlement.Period))
+	}
+	if len(settlement.PayoutDate) > 0 {
+		details = append(details, fmt.Sprintf("%s: %s", doc.Options.TextSettlementPayoutDateTitle, settlement.PayoutDate))
+	}
+	if len(details) > 0 {
+		doc.pdf.SetXY(BaseMargin, y)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		for _, detail := range details {
+			doc.pdf.CellFormat(95, 4, doc.encodeString(detail), "0", 0, "L", false, 0, "")
+		}
+		y += 6
+	}
+
+	// Titles
+	doc.pdf.SetXY(BaseMargin, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	for _, title := range titles {
+		doc.pdf.CellFormat(47.5, 6, doc.encodeString(title), "0", 0, "C", true, 0, "")
+	}
+
+	// Amounts, net payout highlighted
+	doc.pdf.SetXY(BaseMargin, y+6)
+	doc.pdf.SetFont(doc.Options.Font, "", 10)
+	for i, amount := range amounts {
+		if i == len(amounts)-1 {
+			doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+		}
+		doc.pdf.CellFormat(47.5, 8, doc.encodeString(amount), "0", 0, "C", false, 0, "")
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetXY(BaseMargin, y+14)
+}
+
+// drawSettlementTitles draw the transactions table titles at current Y
+func (doc *Document) drawSettlementTitles() {
+	titles := []string{
+		doc.Options.TextSettlementDateTitle,
+		doc.Options.TextSettlementRefTitle,
+		doc.Options.TextSettlementDescriptionTitle,
+		doc.Options.TextSettlementAmountTitle,
+		doc.Options.TextSettlementFeeTitle,
+		doc.Options.TextSettlementNetTitle,
+	}
+
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	for i, title := range titles {
+		align := "L"
+		if i > 2 {
+			align = "R"
+		}
+		doc.pdf.CellFormat(settlementColumns[i], 6, doc.encodeString(title), "0", 0, align, true, 0, "")
+	}
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+7)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+}
+
+// appendSettlementTransactions append the per transaction detail pages of settlement statements
+func (doc *Document) appendSettlementTransactions() {
+	settlement := doc.Settlement
+	if settlement == nil || doc.Type != SettlementStatement || len(settlement.Transactions) == 0 {
+		return
+	}
+
+	doc.pdf.AddPage()
+	doc.pdf.SetXY(BaseMargin, doc.pdf.GetY())
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(190, 8, doc.encodeString(doc.Options.TextSettlementTransactionsTitle), "0", 2, "L", false, 0, "")
+	doc.drawSettlementTitles()
+
+	for _, transaction := range settlement.Transactions {
+		if doc.pdf.GetY()+5 > doc.pageBottom() {
+			doc.pdf.AddPage()
+			if doc.pageLimitReached() {
+				return
+			}
+			doc.drawSettlementTitles()
+		}
+
+		description := transaction.Description
+		if transaction.Refund {
+			description = fmt.Sprintf("%s (%s)", description, doc.Options.TextSettlementRefundMention)
+		}
+		amount := transaction._amount
+		if transaction.Refund {
+			amount = amount.Neg()
+		}
+
+		values := []string{
+			transaction.Date,
+			transaction.Ref,
+			description,
+			doc.formatMoney(amount),
+			doc.formatMoney(transaction._fee.Neg()),
+			doc.formatMoney(transaction.Net()),
+		}
+		for i, value := range values {
+			align := "L"
+			if i > 2 {
+				align = "R"
+			}
+			doc.pdf.CellFormat(settlementColumns[i], 5, doc.encodeString(value), "B", 0, align, false, 0, "")
+		}
+		doc.pdf.SetXY(BaseMargin, doc.pdf.GetY()+5)
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+}
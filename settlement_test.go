@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestSettlementStatement(t *testing.T) {
+	doc, _ := New(SettlementStatement, &Options{DisableCompression: true})
+	doc.SetRef("PAYOUT-2024-03")
+	doc.SetCompany(&Contact{Name: "Marketplace"})
+	doc.SetCustomer(&Contact{Name: "Seller"})
+	doc.AppendItem(&Item{Name: "Pro subscription", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.Settlement = &Settlement{Period: "March 2024", PayoutDate: "05/04/2024"}
+	for i := 0; i < 80; i++ {
+		doc.Settlement.Transactions = append(doc.Settlement.Transactions, &SettlementTransaction{
+			Date: "10/03/2024", Ref: fmt.Sprintf("ORD-%03d", i), Description: "Handmade mug", Amount: "25", Fee: "2.50",
+		})
+	}
+	doc.Settlement.Transactions = append(doc.Settlement.Transactions, &SettlementTransaction{
+		Ref: "ORD-001", Description: "Handmade mug", Refund: true, Amount: "25", Fee: "-2.50",
+	})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settlement := doc.Settlement
+	for _, amount := range []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{"gross sales", settlement.GrossSales().StringFixed(2), "2000.00"},
+		{"refunds", settlement.Refunds().StringFixed(2), "25.00"},
+		{"fees", settlement.Fees().StringFixed(2), "209.50"},
+		{"net payout", settlement.NetPayout().StringFixed(2), "1765.50"},
+		{"refund net", settlement.Transactions[80].Net().StringFixed(2), "-22.50"},
+	} {
+		if amount.actual != amount.expected {
+			t.Errorf("expected %s %s, got %s", amount.name, amount.expected, amount.actual)
+		}
+	}
+
+	if pdf.PageCount() < 3 {
+		t.Errorf("expected transactions detail pages, got %d pages", pdf.PageCount())
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(SETTLEMENT STATEMENT)", "(Net payout)", "(Transactions)", "(ORD-079)", "(Handmade mug \\(refund\\))"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/creasty/defaults"
+	"github.com/go-pdf/fpdf"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrInvalidPDFStructure when generated PDF can't be updated for signing
+var ErrInvalidPDFStructure = errors.New("invalid pdf structure")
+
+// ErrInvalidSignaturePage when signature appearance page doesn't exist
+var ErrInvalidSignaturePage = errors.New("invalid signature page")
+
+// ErrSignatureTooLarge when signature doesn't fit the reserved contents size
+var ErrSignatureTooLarge = errors.New("signature exceeds reserved size")
+
+// Signature sub filters
+const (
+	SignatureSubFilterPKCS7   string = "adbe.pkcs7.detached"
+	SignatureSubFilterCAdES   string = "ETSI.CAdES.detached"
+	SignatureSubFilterRFC3161 string = "ETSI.RFC3161" // Document timestamps, see PrepareForTimestamp
+)
+
+// byteRangePlaceholder is replaced by the signed byte ranges once offsets are known
+const byteRangePlaceholder string = "/ByteRange [0 0000000000 0000000000 0000000000]"
+
+// Signature define the signature dictionary of a signed document
+type Signature struct {
+	Name        string    `json:"name,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	ContactInfo string    `json:"contact_info,omitempty"`
+	Date        time.Time `json:"date,omitempty"` // Signing time, defaults to now
+
+	SubFilter string `json:"sub_filter,omitempty" default:"adbe.pkcs7.detached" validate:"oneof=adbe.pkcs7.detached ETSI.CAdES.detached"`
+	Size      int    `json:"size,omitempty" default:"16384"` // Bytes reserved for the DER encoded CMS signature
+
+	// Appearance render a visible signature box, the signature is invisible when nil
+	Appearance *SignatureAppearance `json:"appearance,omitempty"`
+}
+
+// SignatureAppearance define the visible signature box, positions in mm from top left of page
+type SignatureAppearance struct {
+	Page   int     `json:"page,omitempty" default:"1"` // -1 for last page
+	X      float64 `json:"x,omitempty" default:"130"`
+	Y      float64 `json:"y,omitempty" default:"250"`
+	Width  float64 `json:"width,omitempty" default:"70"`
+	Height float64 `json:"height,omitempty" default:"22"`
+	Logo   []byte  `json:"logo,omitempty"` // Logo byte array, drawn on the left of the box
+}
+
+// SigningRequest define a document prepared for signing: the PDF with a signature
+// placeholder and the digest to sign with an external service (HSM, KMS, remote signing).
+type SigningRequest struct {
+	Digest    []byte // SHA-256 of the signed byte ranges
+	ByteRange [4]int
+
+	pdf []byte
+}
+
+// SignedContent return the bytes covered by the signature, for signers hashing themselves
+func (r *SigningRequest) SignedContent() []byte {
+	content := make([]byte, 0, r.ByteRange[1]+r.ByteRange[3])
+	content = append(content, r.pdf[r.ByteRange[0]:r.ByteRange[0]+r.ByteRange[1]]...)
+	return append(content, r.pdf[r.ByteRange[2]:r.ByteRange[2]+r.ByteRange[3]]...)
+}
+
+// EmbedSignature write the DER encoded detached CMS signature in the prepared document
+// and return the signed PDF
+func (r *SigningRequest) EmbedSignature(signature []byte) ([]byte, error) {
+	encoded := hex.EncodeToString(signature)
+
+	// Contents placeholder is between the two byte ranges, "<" and ">" excluded
+	start := r.ByteRange[1] + 1
+	size := r.ByteRange[2] - 1 - start
+	if len(encoded) > size {
+		return nil, ErrSignatureTooLarge
+	}
+
+	signed := make([]byte, len(r.pdf))
+	copy(signed, r.pdf)
+	copy(signed[start:], encoded)
+
+	return signed, nil
+}
+
+// PrepareForSigning build the document and add a signature field with a placeholder
+// as an incremental update. The returned request digest must be signed externally,
+// then the signature embedded with EmbedSignature.
+func (doc *Document) PrepareForSigning(signature *Signature) (*SigningRequest, error) {
+	if err := defaults.Set(signature); err != nil {
+		return nil, err
+	}
+
+	if err := validator.New().Struct(signature); err != nil {
+		return nil, err
+	}
+
+	if signature.Date.IsZero() {
+		signature.Date = time.Now()
+	}
+
+	pdf, err := doc.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Signer contact is personal data
+	if len(doc.Options.DataMinimization) > 0 {
+		signature.ContactInfo = doc.minimizeText(signature.ContactInfo)
+	}
+
+	// Visible signature box
+	page := 1
+	rect := [4]float64{}
+	if signature.Appearance != nil {
+		if page, rect, err = doc.appendSignatureAppearance(signature); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		return nil, err
+	}
+
+	if err := doc.checkOutputSize(buffer.Len() + signature.Size*2); err != nil {
+		return nil, err
+	}
+
+	return prepareForSigning(buffer.Bytes(), signature, page, rect)
+}
+
+// appendSignatureAppearance draw the visible signature box and return its page and
+// rectangle in PDF user space (points, from bottom left)
+func (doc *Document) appendSignatureAppearance(signature *Signature) (int, [4]float64, error) {
+	appearance := signature.Appearance
+	if err := defaults.Set(appearance); err != nil {
+		return 0, [4]float64{}, err
+	}
+
+	page := appearance.Page
+	if page == -1 {
+		page = doc.pdf.PageCount()
+	}
+	if page < 1 || page > doc.pdf.PageCount() {
+		return 0, [4]float64{}, ErrInvalidSignaturePage
+	}
+
+	currentPage := doc.pdf.PageNo()
+	doc.pdf.SetPage(page)
+	defer doc.pdf.SetPage(currentPage)
+
+	x, y := appearance.X, appearance.Y
+	width, height := appearance.Width, appearance.Height
+
+	// Box
+	doc.pdf.SetDrawColor(doc.Options.GreyTextColor[0], doc.Options.GreyTextColor[1], doc.Options.GreyTextColor[2])
+	doc.pdf.Rect(x, y, width, height, "D")
+
+	// Logo
+	textX := x + 2
+	if appearance.Logo != nil {
+		if err := doc.checkImageLimits(appearance.Logo); err != nil {
+			return 0, [4]float64{}, err
+		}
+
+		imageInfo, format := doc.registerImage("signature-logo", appearance.Logo)
+
+		if imageInfo != nil {
+			doc.pdf.ImageOptions("signature-logo", x+2, y+2, 0, height-4, false, fpdf.ImageOptions{ImageType: format}, 0, "")
+			textX += imageInfo.Width() * (height - 4) / imageInfo.Height()
+			textX += 2
+		}
+	}
+
+	// Signer, date, reason and location
+	lines := []string{}
+	if len(signature.Name) > 0 {
+		lines = append(lines, fmt.Sprintf("%s %s", doc.Options.TextSignatureSignedByTitle, signature.Name))
+	}
+	lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextSignatureDateTitle, signature.Date.Format("2006-01-02 15:04:05 -07:00")))
+	if len(signature.Reason) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextSignatureReasonTitle, signature.Reason))
+	}
+	if len(signature.Location) > 0 {
+		lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextSignatureLocationTitle, signature.Location))
+	}
+
+	doc.pdf.SetXY(textX, y+2)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.MultiCell(x+width-textX-2, 3.5, doc.encodeString(strings.Join(lines, "\n")), "0", "L", false)
+
+	// Rectangle in PDF user space
+	k := doc.pdf.GetConversionRatio()
+	_, pageHeight := doc.pdf.GetPageSize()
+
+	return page, [4]float64{x * k, (pageHeight - y - height) * k, (x + width) * k, (pageHeight - y) * k}, nil
+}
+
+// Sign prepare the document for signing, call sign with the request and embed the returned signature
+func (doc *Document) Sign(signature *Signature, sign func(request *SigningRequest) ([]byte, error)) ([]byte, error) {
+	request, err := doc.PrepareForSigning(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	cms, err := sign(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request.EmbedSignature(cms)
+}
+
+var (
+	pdfRootRegexp      = regexp.MustCompile(`/Root (\d+) 0 R`)
+	pdfInfoRegexp      = regexp.MustCompile(`/Info (\d+) 0 R`)
+	pdfSizeRegexp      = regexp.MustCompile(`/Size (\d+)`)
+	pdfStartXrefRegexp = regexp.MustCompile(`startxref\s+(\d+)`)
+	pdfPagesRegexp     = regexp.MustCompile(`/Pages (\d+) 0 R`)
+	pdfKidsRegexp      = regexp.MustCompile(`/Kids \[([0-9 R]*)\]`)
+)
+
+// pdfObject return the dictionary of the last definition of object number
+func pdfObject(pdf []byte, number int) (string, error) {
+	start := bytes.LastIndex(pdf, []byte(fmt.Sprintf("\n%d 0 obj", number)))
+	if start < 0 {
+		return "", ErrInvalidPDFStructure
+	}
+
+	end := bytes.Index(pdf[start:], []byte("endobj"))
+	if end < 0 {
+		return "", ErrInvalidPDFStructure
+	}
+
+	object := string(pdf[start : start+end])
+	object = strings.TrimSpace(object[strings.Index(object, "obj")+3:])
+	if !strings.HasPrefix(object, "<<") || !strings.HasSuffix(object, ">>") {
+		return "", ErrInvalidPDFStructure
+	}
+
+	return object, nil
+}
+
+// pdfSubmatchInt return the last integer captured by regexp in content
+func pdfSubmatchInt(re *regexp.Regexp, content []byte) (int, error) {
+	matches := re.FindAllSubmatch(content, -1)
+	if len(matches) == 0 {
+		return 0, ErrInvalidPDFStructure
+	}
+
+	return strconv.Atoi(string(matches[len(matches)-1][1]))
+}
+
+// pdfPageObject return the object number of page (starting at 1)
+func pdfPageObject(pdf []byte, catalog string, page int) (int, error) {
+	pagesNumber, err := pdfSubmatchInt(pdfPagesRegexp, []byte(catalog))
+	if err != nil {
+		return 0, err
+	}
+
+	pages, err := pdfObject(pdf, pagesNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	kids := pdfKidsRegexp.FindStringSubmatch(pages)
+	if kids == nil {
+		return 0, ErrInvalidPDFStructure
+	}
+
+	refs := strings.Fields(kids[1])
+	if page < 1 || len(refs) < page*3 {
+		return 0, ErrInvalidPDFStructure
+	}
+
+	return strconv.Atoi(refs[(page-1)*3])
+}
+
+// pdfTextString encode a PDF text string, UTF-16BE for non ASCII texts
+func pdfTextString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 126 {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`)
+		return "(" + replacer.Replace(s) + ")"
+	}
+
+	encoded := "<FEFF"
+	for _, unit := range utf16.Encode([]rune(s)) {
+		encoded += fmt.Sprintf("%04X", unit)
+	}
+
+	return encoded + ">"
+}
+
+// pdfDate format date as a PDF date string
+func pdfDate(date time.Time) string {
+	zone := date.Format("-07'00'")
+	if zone == "+00'00'" {
+		zone = "Z"
+	}
+
+	return fmt.Sprintf("(D:%s%s)", date.Format("20060102150405"), zone)
+}
+
+// pdfDictionaryAppend append entries at the end of a dictionary
+func pdfDictionaryAppend(dictionary string, entries string) string {
+	return strings.TrimSuffix(dictionary, ">>") + "\n" + entries + ">>"
+}
+
+// pdfTrailer define the last trailer of a pdf, updated by incremental updates
+type pdfTrailer struct {
+	size      int
+	root      int
+	info      int
+	startXref int
+}
+
+// readPDFTrailer return the last trailer of pdf
+func readPDFTrailer(pdf []byte) (*pdfTrailer, error) {
+	size, err := pdfSubmatchInt(pdfSizeRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	root, err := pdfSubmatchInt(pdfRootRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	startXref, err := pdfSubmatchInt(pdfStartXrefRegexp, pdf)
+	if err != nil {
+		return nil, err
+	}
+	info, _ := pdfSubmatchInt(pdfInfoRegexp, pdf)
+
+	return &pdfTrailer{size: size, root: root, info: info, startXref: startXref}, nil
+}
+
+// pdfUpdateObject define an object written by an incremental update, new or replacing an existing one
+type pdfUpdateObject struct {
+	number int
+	body   string
+}
+
+// appendPDFUpdate write objects after pdf as an incremental update, size being the new trailer size
+func appendPDFUpdate(pdf []byte, trailer *pdfTrailer, objects []pdfUpdateObject, size int) []byte {
+	update := &bytes.Buffer{}
+	update.Write(pdf)
+	if !bytes.HasSuffix(pdf, []byte("\n")) {
+		update.WriteString("\n")
+	}
+
+	offsets := map[int]int{}
+	for _, object := range objects {
+		offsets[object.number] = update.Len()
+		fmt.Fprintf(update, "%d 0 obj\n%s\nendobj\n", object.number, object.body)
+	}
+
+	xref := update.Len()
+	update.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for _, object := range objects {
+		fmt.Fprintf(update, "%d 1\n%010d 00000 n \n", object.number, offsets[object.number])
+	}
+
+	updated := fmt.Sprintf("trailer\n<<\n/Size %d\n/Root %d 0 R\n", size, trailer.root)
+	if trailer.info > 0 {
+		updated += fmt.Sprintf("/Info %d 0 R\n", trailer.info)
+	}
+	updated += fmt.Sprintf("/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n", trailer.startXref, xref)
+	update.WriteString(updated)
+
+	return update.Bytes()
+}
+
+// pdfAddField add the field widgetRef to the catalog form, creating it when there is none
+func pdfAddField(catalog string, widgetRef string) (string, error) {
+	if !strings.Contains(catalog, "/AcroForm") {
+		return pdfDictionaryAppend(catalog, fmt.Sprintf("/AcroForm <</Fields [%s] /SigFlags 3>>", widgetRef)), nil
+	}
+
+	// Form of a previous signature
+	if !strings.Contains(catalog, "/AcroForm <</Fields [") {
+		return "", ErrInvalidPDFStructure
+	}
+
+	return strings.Replace(catalog, "/AcroForm <</Fields [", "/AcroForm <</Fields ["+widgetRef+" ", 1), nil
+}
+
+// prepareForSigning add a signature field on page referencing a signature dictionary
+// with ByteRange and Contents placeholders, as an incremental update of pdf
+func prepareForSigning(pdf []byte, signature *Signature, page int, rect [4]float64) (*SigningRequest, error) {
+	// Signature dictionary
+	dictionary := fmt.Sprintf(
+		"<</Type /Sig /Filter /Adobe.PPKLite /SubFilter /%s\n%s\n/Contents <%s>\n/M %s",
+		signature.SubFilter,
+		byteRangePlaceholder,
+		strings.Repeat("0", signature.Size*2),
+		pdfDate(signature.Date),
+	)
+	for _, entry := range []struct{ key, value string }{
+		{"Name", signature.Name},
+		{"Reason", signature.Reason},
+		{"Location", signature.Location},
+		{"ContactInfo", signature.ContactInfo},
+	} {
+		if len(entry.value) > 0 {
+			dictionary += fmt.Sprintf("\n/%s %s", entry.key, pdfTextString(entry.value))
+		}
+	}
+	dictionary += ">>"
+
+	return prepareSignatureField(pdf, dictionary, signature.Size, page, rect)
+}
+
+// prepareSignatureField add a signature field on page referencing dictionary, holding ByteRange and Contents
+// placeholders of size bytes, as an incremental update of pdf and return its signing request
+func prepareSignatureField(pdf []byte, dictionary string, size int, page int, rect [4]float64) (*SigningRequest, error) {
+	trailer, err := readPDFTrailer(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := pdfObject(pdf, trailer.root)
+	if err != nil {
+		return nil, err
+	}
+
+	pageNumber, err := pdfPageObject(pdf, catalog, page)
+	if err != nil {
+		return nil, err
+	}
+	pageObject, err := pdfObject(pdf, pageNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureNumber := trailer.size
+	widgetNumber := trailer.size + 1
+
+	// Signature field and widget annotation
+	widget := fmt.Sprintf(
+		"<</Type /Annot /Subtype /Widget /FT /Sig /T (Signature%d) /V %d 0 R /F 132 /Rect [%.2f %.2f %.2f %.2f] /P %d 0 R>>",
+		signatureNumber,
+		signatureNumber,
+		rect[0],
+		rect[1],
+		rect[2],
+		rect[3],
+		pageNumber,
+	)
+
+	// Page annotations
+	widgetRef := fmt.Sprintf("%d 0 R", widgetNumber)
+	if strings.Contains(pageObject, "/Annots [") {
+		pageObject = strings.Replace(pageObject, "/Annots [", "/Annots ["+widgetRef+" ", 1)
+	} else {
+		pageObject = pdfDictionaryAppend(pageObject, "/Annots ["+widgetRef+"]")
+	}
+
+	if catalog, err = pdfAddField(catalog, widgetRef); err != nil {
+		return nil, err
+	}
+
+	// Incremental update
+	prepared := appendPDFUpdate(pdf, trailer, []pdfUpdateObject{
+		{pageNumber, pageObject},
+		{trailer.root, catalog},
+		{signatureNumber, dictionary},
+		{widgetNumber, widget},
+	}, widgetNumber+1)
+
+	// Compute byte ranges around contents placeholder
+	placeholder := bytes.LastIndex(prepared, []byte(byteRangePlaceholder))
+	contentsStart := bytes.Index(prepared[placeholder:], []byte("/Contents <")) + placeholder + len("/Contents ")
+	contentsEnd := contentsStart + size*2 + 2
+
+	request := &SigningRequest{
+		ByteRange: [4]int{0, contentsStart, contentsEnd, len(prepared) - contentsEnd},
+		pdf:       prepared,
+	}
+
+	byteRange := fmt.Sprintf(
+		"/ByteRange [0 %010d %010d %010d]",
+		request.ByteRange[1],
+		request.ByteRange[2],
+		request.ByteRange[3],
+	)
+	copy(prepared[placeholder:], byteRange)
+
+	digest := sha256.Sum256(request.SignedContent())
+	request.Digest = digest[:]
+
+	return request, nil
+}
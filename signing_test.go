@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestPrepareForSigning(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+
+	request, err := doc.PrepareForSigning(&Signature{Name: "Jane Doe", Reason: "Invoice (approved)", Size: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(request.SignedContent())
+	if !bytes.Equal(digest[:], request.Digest) {
+		t.Errorf("expected digest of signed content")
+	}
+
+	if _, err := request.EmbedSignature(make([]byte, 65)); err != ErrSignatureTooLarge {
+		t.Errorf("expected %v, got %v", ErrSignatureTooLarge, err)
+	}
+
+	cms := []byte{0x30, 0x82, 0x01, 0x02}
+	signed, err := request.EmbedSignature(cms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signed bytes are unchanged, contents hold the signature
+	contents := signed[request.ByteRange[1]:request.ByteRange[2]]
+	if !bytes.HasPrefix(contents, []byte("<"+hex.EncodeToString(cms)+"00")) || !bytes.HasSuffix(contents, []byte(">")) {
+		t.Errorf("unexpected contents %s", contents)
+	}
+
+	byteRange := fmt.Sprintf("/ByteRange [0 %010d %010d %010d]", request.ByteRange[1], request.ByteRange[2], request.ByteRange[3])
+	if !bytes.Contains(signed, []byte(byteRange)) {
+		t.Errorf("expected %s in signed document", byteRange)
+	}
+
+	if request.ByteRange[2]+request.ByteRange[3] != len(signed) {
+		t.Errorf("expected byte range to cover the end of document")
+	}
+}
+
+func TestSignatureAppearance(t *testing.T) {
+	newDoc := func() *Document {
+		doc, _ := New(Invoice, &Options{})
+		doc.SetRef("1")
+		doc.SetCompany(&Contact{Name: "Company"})
+		doc.SetCustomer(&Contact{Name: "Customer"})
+		doc.AppendItem(&Item{Name: "Item", UnitCost: "10", Quantity: "1"})
+		return doc
+	}
+
+	request, err := newDoc().PrepareForSigning(&Signature{
+		Name:       "Jane Doe",
+		Size:       64,
+		Appearance: &SignatureAppearance{X: 10, Y: 10, Width: 50, Height: 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(request.pdf, []byte("/Rect [28.35 756.85 170.08 813.54]")) {
+		t.Errorf("expected visible signature rectangle")
+	}
+
+	_, err = newDoc().PrepareForSigning(&Signature{Appearance: &SignatureAppearance{Page: 3}})
+	if err != ErrInvalidSignaturePage {
+		t.Errorf("expected %v, got %v", ErrInvalidSignaturePage, err)
+	}
+}
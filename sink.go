@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidSinkName when a document name is empty or escapes the sink
+var ErrInvalidSinkName = errors.New("invalid sink name")
+
+// Sink store rendered documents, see DirSink and MemorySink.
+// Implement it to stream documents to object storages (S3, GCS...).
+type Sink interface {
+	WriteDocument(name string, r io.Reader) error
+}
+
+// Save stream the PDF of document to sink under name
+func (doc *Document) Save(sink Sink, name string) error {
+	reader, writer := io.Pipe()
+
+	rendered := make(chan error, 1)
+	go func() {
+		err := doc.Output(writer)
+		writer.CloseWithError(err)
+		rendered <- err
+	}()
+
+	sinkErr := sink.WriteDocument(name, reader)
+
+	// Unblock rendering when sink stopped reading
+	reader.Close()
+	err := <-rendered
+	if sinkErr != nil {
+		return sinkErr
+	}
+
+	return err
+}
+
+// checkSinkName check name is a relative slash separated path without parent directories
+func checkSinkName(name string) error {
+	if len(name) == 0 || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return ErrInvalidSinkName
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if len(part) == 0 || part == "." || part == ".." {
+			return ErrInvalidSinkName
+		}
+	}
+
+	return nil
+}
+
+// DirSink write documents as files of a directory, names may contain sub directories
+type DirSink struct {
+	Dir string
+}
+
+// NewDirSink return a sink writing documents in dir
+func NewDirSink(dir string) *DirSink {
+	return &DirSink{Dir: dir}
+}
+
+// WriteDocument write r to file name of sink directory. The file is written aside and renamed,
+// readers never see partial documents.
+func (s *DirSink) WriteDocument(name string, r io.Reader) error {
+	if err := checkSinkName(name); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// MemorySink keep documents in memory, for tests and small batches
+type MemorySink struct {
+	documents map[string][]byte
+	mutex     sync.Mutex
+}
+
+// NewMemorySink return an empty in-memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{documents: map[string][]byte{}}
+}
+
+// WriteDocument read r and keep it under name, replacing previous document of name
+func (s *MemorySink) WriteDocument(name string, r io.Reader) error {
+	if err := checkSinkName(name); err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+	if _, err := io.Copy(buffer, r); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.documents[name] = buffer.Bytes()
+
+	return nil
+}
+
+// Get return the document of name, nil when unknown
+func (s *MemorySink) Get(name string) []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.documents[name]
+}
+
+// Names return sorted names of written documents
+func (s *MemorySink) Names() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	names := make([]string, 0, len(s.documents))
+	for name := range s.documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
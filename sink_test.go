@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingSink fail after reading a few bytes
+type failingSink struct{}
+
+func (failingSink) WriteDocument(name string, r io.Reader) error {
+	_, _ = r.Read(make([]byte, 8))
+	return errors.New("upload failed")
+}
+
+func newSinkDocument() *Document {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Hosting", UnitCost: "100", Quantity: "1"})
+
+	return doc
+}
+
+func TestSave(t *testing.T) {
+	sink := NewMemorySink()
+	if err := newSinkDocument().Save(sink, "2024/INV-1.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if names := sink.Names(); len(names) != 1 || !bytes.HasPrefix(sink.Get("2024/INV-1.pdf"), []byte("%PDF-")) {
+		t.Errorf("unexpected documents %v", names)
+	}
+
+	if err := newSinkDocument().Save(failingSink{}, "INV-1.pdf"); err == nil || err.Error() != "upload failed" {
+		t.Errorf("expected sink error, got %v", err)
+	}
+
+	doc := newSinkDocument()
+	doc.Ref = ""
+	if err := doc.Save(sink, "invalid.pdf"); err == nil || sink.Get("invalid.pdf") != nil {
+		t.Error("expected render error")
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewDirSink(dir)
+
+	if err := sink.WriteDocument("2024/03/INV-1.pdf", strings.NewReader("pdf")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "2024", "03", "INV-1.pdf")); err != nil || string(data) != "pdf" {
+		t.Errorf("unexpected file %q %v", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "2024", "03")); len(entries) != 1 {
+		t.Errorf("expected temporary files removed, got %d files", len(entries))
+	}
+
+	for _, name := range []string{"", "../INV-1.pdf", "/etc/INV-1.pdf", "2024//INV-1.pdf", `2024\INV-1.pdf`} {
+		if err := sink.WriteDocument(name, strings.NewReader("pdf")); !errors.Is(err, ErrInvalidSinkName) {
+			t.Errorf("expected ErrInvalidSinkName for %q, got %v", name, err)
+		}
+	}
+}
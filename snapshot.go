@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// RulesSnapshotFileName name of the rules snapshot attached to PDF documents
+const RulesSnapshotFileName = "rules-snapshot.json"
+
+// RulesSnapshotVersion version of the rules snapshot format
+const RulesSnapshotVersion = 1
+
+// RulesSnapshot define the tax rates, rounding and compliance rules a document was computed with,
+// attached to its PDF to audit it years later
+type RulesSnapshot struct {
+	Version     int                   `json:"version"`
+	Generator   string                `json:"generator"`
+	GeneratedAt string                `json:"generated_at"` // RFC 3339
+	Type        string                `json:"type"`
+	Ref         string                `json:"ref"`
+	Date        string                `json:"date,omitempty"`
+	Currency    string                `json:"currency"`
+	Locale      string                `json:"locale,omitempty"`
+	Rounding    *RoundingSnapshot     `json:"rounding"`
+	Taxes       []*TaxRateSnapshot    `json:"taxes"`
+	Compliance  *ComplianceSnapshot   `json:"compliance,omitempty"`
+	Totals      *Totals               `json:"totals"`
+	Expected    *ExpectedTotals       `json:"expected_totals,omitempty"`
+	Payments    []*PaymentSnapshot    `json:"payment_methods,omitempty"`
+	Discount    *Discount             `json:"discount,omitempty"`
+	Options     *RulesSnapshotOptions `json:"options"`
+}
+
+// RoundingSnapshot define how amounts were rounded
+type RoundingSnapshot struct {
+	Mode      string `json:"mode"`      // Always half_away_from_zero
+	Scope     string `json:"scope"`     // Always document, lines and taxes are computed with full precision
+	Precision int    `json:"precision"` // Options.CurrencyPrecision
+}
+
+// TaxRateSnapshot define a tax breakdown line
+type TaxRateSnapshot struct {
+	Type   string `json:"type"`
+	Rate   string `json:"rate,omitempty"`
+	Base   string `json:"base"`
+	Amount string `json:"amount"`
+}
+
+// ComplianceSnapshot define the compliance profile rules of a document
+type ComplianceSnapshot struct {
+	Country              string   `json:"country"`
+	RequireCompanyTaxID  bool     `json:"require_company_tax_id,omitempty"`
+	RequireCustomerTaxID bool     `json:"require_customer_tax_id,omitempty"`
+	RequireDate          bool     `json:"require_date,omitempty"`
+	RequirePaymentTerm   bool     `json:"require_payment_term,omitempty"`
+	Mentions             []string `json:"mentions,omitempty"`
+}
+
+// PaymentSnapshot define the surcharge rules of a payment method
+type PaymentSnapshot struct {
+	Type             string `json:"type"`
+	SurchargePercent string `json:"surcharge_percent,omitempty"`
+	SurchargeAmount  string `json:"surcharge_amount,omitempty"`
+}
+
+// RulesSnapshotOptions define the options changing computed or displayed amounts
+type RulesSnapshotOptions struct {
+	NumberLocale       string `json:"number_locale,omitempty"`
+	AllowNegativeLines bool   `json:"allow_negative_lines,omitempty"`
+	CheckInvariants    bool   `json:"check_invariants,omitempty"`
+	TotalsPrecision    int    `json:"totals_precision"`
+}
+
+// RulesSnapshot return the rules document totals are computed with, document must be validated
+func (doc *Document) RulesSnapshot() (*RulesSnapshot, error) {
+	precision := int32(doc.Options.CurrencyPrecision)
+
+	snapshot := &RulesSnapshot{
+		Version:     RulesSnapshotVersion,
+		Generator:   doc.producer(),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Type:        doc.Type,
+		Ref:         doc.Ref,
+		Date:        doc.Date,
+		Currency:    doc.Options.CurrencyCode,
+		Locale:      doc.Options.Locale,
+		Rounding: &RoundingSnapshot{
+			Mode:      "half_away_from_zero",
+			Scope:     "document",
+			Precision: doc.Options.CurrencyPrecision,
+		},
+		Taxes:    []*TaxRateSnapshot{},
+		Totals:   doc.Totals(),
+		Expected: doc.ExpectedTotals,
+		Discount: doc.Discount,
+		Options: &RulesSnapshotOptions{
+			NumberLocale:       doc.Options.NumberLocale,
+			AllowNegativeLines: doc.Options.AllowNegativeLines,
+			CheckInvariants:    doc.Options.CheckInvariants,
+			TotalsPrecision:    doc.Options.totalsPrecision(),
+		},
+	}
+
+	for _, line := range doc.TaxLines() {
+		rate := ""
+		if line.Type == TaxTypePercent {
+			rate = line.Rate.String()
+		}
+		snapshot.Taxes = append(snapshot.Taxes, &TaxRateSnapshot{
+			Type:   line.Type,
+			Rate:   rate,
+			Base:   line.Base.StringFixed(precision),
+			Amount: line.Amount.StringFixed(precision),
+		})
+	}
+
+	profile, err := doc.complianceProfile()
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		snapshot.Compliance = &ComplianceSnapshot{
+			Country:              profile.Country,
+			RequireCompanyTaxID:  profile.RequireCompanyTaxID,
+			RequireCustomerTaxID: profile.RequireCustomerTaxID,
+			RequireDate:          profile.RequireDate,
+			RequirePaymentTerm:   profile.RequirePaymentTerm,
+			Mentions:             profile.Mentions,
+		}
+	}
+
+	for _, method := range doc.PaymentMethods {
+		snapshot.Payments = append(snapshot.Payments, &PaymentSnapshot{
+			Type:             method.Type,
+			SurchargePercent: method.SurchargePercent,
+			SurchargeAmount:  method.SurchargeAmount,
+		})
+	}
+
+	return snapshot, nil
+}
+
+// attachRulesSnapshot attach the rules snapshot JSON to document pdf when Options.RulesSnapshot is set
+func (doc *Document) attachRulesSnapshot() error {
+	if !doc.Options.RulesSnapshot {
+		return nil
+	}
+
+	snapshot, err := doc.RulesSnapshot()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	doc.pdf.SetAttachments([]fpdf.Attachment{{
+		Content:     content,
+		Filename:    RulesSnapshotFileName,
+		Description: "Tax rates, rounding and compliance rules",
+	}})
+
+	return nil
+}
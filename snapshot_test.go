@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRulesSnapshot(t *testing.T) {
+	doc := newEInvoice(EInvoiceProfileBasic)
+	doc.Options.Compliance = "DE"
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := doc.RulesSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Currency != "EUR" || snapshot.Rounding.Precision != 2 || snapshot.Rounding.Mode != "half_away_from_zero" {
+		t.Errorf("unexpected currency or rounding %+v %+v", snapshot.Currency, snapshot.Rounding)
+	}
+	if snapshot.Compliance == nil || snapshot.Compliance.Country != "DE" || !snapshot.Compliance.RequireCompanyTaxID {
+		t.Errorf("expected DE compliance profile, got %+v", snapshot.Compliance)
+	}
+	if len(snapshot.Taxes) == 0 || snapshot.Taxes[0].Type != TaxTypePercent || len(snapshot.Taxes[0].Rate) == 0 {
+		t.Errorf("expected tax rates, got %+v", snapshot.Taxes)
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(content, []byte(`"compliance":{"country":"DE"`)) {
+		t.Errorf("unexpected JSON %s", content)
+	}
+
+	doc.Options.Compliance = "QQ"
+	if _, err := doc.RulesSnapshot(); err != ErrUnknownComplianceProfile {
+		t.Errorf("expected ErrUnknownComplianceProfile, got %v", err)
+	}
+}
+
+func TestOutputRulesSnapshot(t *testing.T) {
+	doc := newEInvoice(EInvoiceProfileBasic)
+	doc.Options.RulesSnapshot = true
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buffer.Bytes()
+
+	for _, expected := range []string{
+		"/Type /Filespec /F () /UF ",
+		"/EmbeddedFiles << /Names [(Attachement1) ",
+		"(factur-x.xml) ",
+	} {
+		if !bytes.Contains(pdf, []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSAVATNumber when a South African VAT number is missing or invalid
+var ErrInvalidSAVATNumber = errors.New("invalid south african vat number")
+
+// ErrMissingSerialNumber when document ref is not a serialized number
+var ErrMissingSerialNumber = errors.New("document ref must contain a serial number")
+
+// ErrMissingSARecipient when recipient informations are required for a full tax invoice
+var ErrMissingSARecipient = errors.New("missing vat recipient informations")
+
+var (
+	saVATNumberRegexp  = regexp.MustCompile(`^4[0-9]{9}$`)
+	serialNumberRegexp = regexp.MustCompile(`[0-9]+$`)
+)
+
+// SouthAfricanVAT define SARS tax invoice requirements.
+// Company TaxID holds the supplier VAT number, Customer TaxID the recipient one.
+type SouthAfricanVAT struct {
+	Rate             string `json:"rate,omitempty"`               // Defaults to 15
+	PricesIncludeVAT bool   `json:"prices_include_vat,omitempty"` // Print "VAT included" statement instead of the VAT amount
+
+	// Full tax invoice threshold, VAT included
+	FullInvoiceThreshold string `json:"full_invoice_threshold,omitempty"` // Defaults to 5000
+
+	_total decimal.Decimal
+}
+
+// Prepare check VAT numbers, serial number and full tax invoice rules of document
+func (s *SouthAfricanVAT) Prepare(doc *Document) error {
+	if len(s.Rate) == 0 {
+		s.Rate = "15"
+	}
+	if len(s.FullInvoiceThreshold) == 0 {
+		s.FullInvoiceThreshold = "5000"
+	}
+
+	doc.Company.TaxID = strings.ReplaceAll(doc.Company.TaxID, " ", "")
+	if !saVATNumberRegexp.MatchString(doc.Company.TaxID) {
+		return ErrInvalidSAVATNumber
+	}
+
+	if !serialNumberRegexp.MatchString(doc.Ref) {
+		return ErrMissingSerialNumber
+	}
+
+	total, err := doc.totalAmount()
+	if err != nil {
+		return err
+	}
+	s._total = total
+
+	if s.FullInvoiceRequired() {
+		// Recipient name, address and VAT number (when registered)
+		if doc.Customer == nil || doc.Customer.Address == nil {
+			return ErrMissingSARecipient
+		}
+
+		if len(doc.Customer.TaxID) > 0 {
+			doc.Customer.TaxID = strings.ReplaceAll(doc.Customer.TaxID, " ", "")
+			if !saVATNumberRegexp.MatchString(doc.Customer.TaxID) {
+				return ErrInvalidSAVATNumber
+			}
+		}
+	}
+
+	return nil
+}
+
+// FullInvoiceRequired return true when document total exceeds the abridged tax invoice threshold
+func (s *SouthAfricanVAT) FullInvoiceRequired() bool {
+	threshold, err := decimal.NewFromString(s.FullInvoiceThreshold)
+	if err != nil {
+		return true
+	}
+
+	return s._total.GreaterThan(threshold)
+}
+
+// registrationLines return VAT number line of contact.
+// Recipient VAT number is only displayed on full tax invoices.
+func (s *SouthAfricanVAT) registrationLines(c *Contact, company bool, options *Options) []string {
+	if len(c.TaxID) == 0 || (!company && !s.FullInvoiceRequired()) {
+		return []string{}
+	}
+
+	return []string{fmt.Sprintf("%s: %s", options.TextSAVATNumberTitle, c.TaxID)}
+}
+
+// appendSAVATMention append the VAT included statement under totals
+func (doc *Document) appendSAVATMention() {
+	if doc.SouthAfricanVAT == nil || !doc.SouthAfricanVAT.PricesIncludeVAT {
+		return
+	}
+
+	// Current Y is the top of the total line
+	y := doc.pdf.GetY()
+	doc.pdf.SetXY(120, y+10.5)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(
+		80,
+		4,
+		doc.encodeString(fmt.Sprintf("%s %s%%", doc.Options.TextSAVATIncludedMention, doc.SouthAfricanVAT.Rate)),
+		"0",
+		0,
+		"R",
+		false,
+		0,
+		"",
+	)
+	doc.pdf.SetY(y + 4)
+}
@@ -0,0 +1,31 @@
+package generator
+
+import "testing"
+
+func TestSouthAfricanVATPrepare(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-0001")
+	doc.SetCompany(&Contact{Name: "Supplier", TaxID: "4123456789"})
+	doc.SetCustomer(&Contact{Name: "Recipient"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "6000", Quantity: "1", Tax: &Tax{Percent: "15"}})
+	doc.SetSouthAfricanVAT(&SouthAfricanVAT{})
+
+	// Full tax invoice requires recipient address
+	if err := doc.Validate(); err != ErrMissingSARecipient {
+		t.Errorf("expected %v, got %v", ErrMissingSARecipient, err)
+	}
+
+	doc.Customer.Address = &Address{Address: "1 Long Street", PostalCode: "8001", City: "Cape Town"}
+	doc.Customer.TaxID = "123"
+	if err := doc.Validate(); err != ErrInvalidSAVATNumber {
+		t.Errorf("expected %v, got %v", ErrInvalidSAVATNumber, err)
+	}
+
+	doc.Customer.TaxID = "4987654321"
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.typeAsString() != doc.Options.TextTypeTaxInvoice {
+		t.Errorf("expected tax invoice title, got %s", doc.typeAsString())
+	}
+}
@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/shopspring/decimal"
+)
+
+// ErrMissingSpanishSignature when a TicketBAI document has no signature nor signer
+var ErrMissingSpanishSignature = errors.New("missing spanish record signature")
+
+// Spanish invoicing systems
+const (
+	SpanishSystemTicketBAI string = "ticketbai"
+	SpanishSystemVerifactu string = "verifactu"
+)
+
+// TicketBAI territories QR code base urls
+var ticketBAIQRCodeURLs = map[string]string{
+	"araba":    "https://ticketbai.araba.eus/tbai/qrtbai/",
+	"bizkaia":  "https://batuz.eus/QRTBAI/",
+	"gipuzkoa": "https://tbai.egoitza.gipuzkoa.eus/qr/",
+}
+
+// verifactuQRCodeURL is the AEAT invoice check url
+const verifactuQRCodeURL string = "https://www2.agenciatributaria.gob.es/wlpl/TIKE-CONT/ValidarQR"
+
+// SpanishSigner define a signing service supplying the chained signature of a record.
+// For TicketBAI it must return the XAdES signature value of the record, for Verifactu
+// the record hash (huella).
+type SpanishSigner interface {
+	Sign(record *SpanishRecord) (string, error)
+}
+
+// SpanishRecord define the hash chain inputs of a document
+type SpanishRecord struct {
+	IssuerTaxID  string
+	SeriesNumber string
+	Date         time.Time
+	InvoiceType  string
+	TaxTotal     decimal.Decimal
+	Total        decimal.Decimal
+	PreviousHash string
+	GeneratedAt  string
+}
+
+// Input return the Verifactu registration record string to hash
+func (r *SpanishRecord) Input() string {
+	return strings.Join([]string{
+		"IDEmisorFactura=" + r.IssuerTaxID,
+		"NumSerieFactura=" + r.SeriesNumber,
+		"FechaExpedicionFactura=" + r.Date.Format("02-01-2006"),
+		"TipoFactura=" + r.InvoiceType,
+		"CuotaTotal=" + r.TaxTotal.StringFixed(2),
+		"ImporteTotal=" + r.Total.StringFixed(2),
+		"Huella=" + r.PreviousHash,
+		"FechaHoraHusoGenRegistro=" + r.GeneratedAt,
+	}, "&")
+}
+
+// Fingerprint return the Verifactu record hash, uppercase hex SHA-256 of Input
+func (r *SpanishRecord) Fingerprint() string {
+	return strings.ToUpper(fmt.Sprintf("%x", sha256.Sum256([]byte(r.Input()))))
+}
+
+// SpanishFiscal define TicketBAI and Verifactu requirements of a document
+type SpanishFiscal struct {
+	System       string `json:"system,omitempty" validate:"required,oneof=ticketbai verifactu"`
+	Territory    string `json:"territory,omitempty" validate:"required_if=System ticketbai,omitempty,oneof=araba bizkaia gipuzkoa"` // TicketBAI only
+	Series       string `json:"series,omitempty" validate:"max=20"`
+	Number       string `json:"number,omitempty" validate:"required,max=20"`
+	InvoiceType  string `json:"invoice_type,omitempty" default:"F1"` // F1, F2, R1...
+	PreviousHash string `json:"previous_hash,omitempty"`             // Signature or hash of previous record, empty for the first one
+	GeneratedAt  string `json:"generated_at,omitempty"`              // RFC 3339 with time zone, defaults to now
+
+	// Signature of the record, supplied by Signer when empty and again on builds after the record changed.
+	// Verifactu records fallback to Fingerprint.
+	Signature string `json:"signature,omitempty"`
+
+	Signer SpanishSigner `json:"-"`
+
+	_signed string // Record input of the signature supplied by Signer or Fingerprint
+}
+
+// seriesNumber return series and number as a single identifier
+func (s *SpanishFiscal) seriesNumber() string {
+	return s.Series + s.Number
+}
+
+// SpanishRecord return the hash chain inputs of document
+func (doc *Document) SpanishRecord() (*SpanishRecord, error) {
+	fiscal := doc.SpanishFiscal
+	if fiscal == nil {
+		return nil, ErrMissingSpanishSignature
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpanishRecord{
+		IssuerTaxID:  doc.Company.TaxID,
+		SeriesNumber: fiscal.seriesNumber(),
+		Date:         date,
+		InvoiceType:  fiscal.InvoiceType,
+		TaxTotal:     doc.Tax(),
+		Total:        doc.TotalWithTax(),
+		PreviousHash: fiscal.PreviousHash,
+		GeneratedAt:  fiscal.GeneratedAt,
+	}, nil
+}
+
+// Prepare set defaults and generation time of record
+func (s *SpanishFiscal) Prepare() error {
+	if err := defaults.Set(s); err != nil {
+		return err
+	}
+
+	if len(s.GeneratedAt) == 0 {
+		s.GeneratedAt = time.Now().Format(time.RFC3339)
+	}
+
+	return nil
+}
+
+// signSpanishRecord set the record signature from signer or fingerprint. Signatures it set are computed again
+// when the record changed since, a caller supplied signature is kept as is.
+func (doc *Document) signSpanishRecord() error {
+	fiscal := doc.SpanishFiscal
+	if len(fiscal.Signature) > 0 && len(fiscal._signed) == 0 {
+		return nil
+	}
+
+	record, err := doc.SpanishRecord()
+	if err != nil {
+		return err
+	}
+
+	if len(fiscal.Signature) > 0 && record.Input() == fiscal._signed {
+		return nil
+	}
+
+	if fiscal.Signer != nil {
+		signature, err := fiscal.Signer.Sign(record)
+		if err != nil {
+			return err
+		}
+		fiscal.Signature, fiscal._signed = signature, record.Input()
+		return nil
+	}
+
+	if fiscal.System == SpanishSystemVerifactu {
+		fiscal.Signature, fiscal._signed = record.Fingerprint(), record.Input()
+		return nil
+	}
+
+	return ErrMissingSpanishSignature
+}
+
+// crc8 compute the TicketBAI CRC-8 (polynomial 0x07, initial value 0)
+func crc8(value string) string {
+	var crc byte
+	for i := 0; i < len(value); i++ {
+		crc ^= value[i]
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return fmt.Sprintf("%03d", crc)
+}
+
+// TicketBAIIdentifier return the TicketBAI identifier of document,
+// "TBAI-<tax id>-<DDMMYY>-<13 first signature characters>-<CRC>"
+func (doc *Document) TicketBAIIdentifier() (string, error) {
+	if err := doc.signSpanishRecord(); err != nil {
+		return "", err
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return "", err
+	}
+
+	signature := doc.SpanishFiscal.Signature
+	if len(signature) > 13 {
+		signature = signature[:13]
+	}
+
+	id := fmt.Sprintf("TBAI-%s-%s-%s-", doc.Company.TaxID, date.Format("020106"), signature)
+
+	return id + crc8(id), nil
+}
+
+// spanishQRCodeContent return the TicketBAI or Verifactu QR code url
+func (doc *Document) spanishQRCodeContent() (string, error) {
+	fiscal := doc.SpanishFiscal
+	total := doc.TotalWithTax().StringFixed(2)
+
+	if fiscal.System == SpanishSystemVerifactu {
+		date, err := doc.documentDate()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf(
+			"%s?nif=%s&numserie=%s&fecha=%s&importe=%s",
+			verifactuQRCodeURL,
+			url.QueryEscape(doc.Company.TaxID),
+			url.QueryEscape(fiscal.seriesNumber()),
+			date.Format("02-01-2006"),
+			total,
+		), nil
+	}
+
+	id, err := doc.TicketBAIIdentifier()
+	if err != nil {
+		return "", err
+	}
+
+	content := fmt.Sprintf(
+		"%s?id=%s&s=%s&nf=%s&i=%s",
+		ticketBAIQRCodeURLs[fiscal.Territory],
+		url.QueryEscape(id),
+		url.QueryEscape(fiscal.Series),
+		url.QueryEscape(fiscal.Number),
+		total,
+	)
+
+	return content + "&cr=" + crc8(content), nil
+}
+
+// appendSpanishFiscal append TicketBAI identifier or Verifactu mention and QR code to document
+func (doc *Document) appendSpanishFiscal() error {
+	if doc.SpanishFiscal == nil {
+		return nil
+	}
+
+	if err := doc.signSpanishRecord(); err != nil {
+		return err
+	}
+
+	content, err := doc.spanishQRCodeContent()
+	if err != nil {
+		return err
+	}
+
+	y := doc.blockY(45)
+	x := BaseMargin
+
+	// Verifactu label above QR code
+	if doc.SpanishFiscal.System == SpanishSystemVerifactu {
+		doc.pdf.SetXY(x, y)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(30, 4, doc.encodeString(doc.Options.TextVerifactuTitle), "0", 0, "C", false, 0, "")
+	}
+
+	if err := doc.drawQRCode(content, QRCodeECLevelM, x, y+5, 30); err != nil {
+		return err
+	}
+
+	// TicketBAI identifier or Verifactu mention under QR code
+	label := doc.Options.TextVerifactuMention
+	if doc.SpanishFiscal.System == SpanishSystemTicketBAI {
+		if label, err = doc.TicketBAIIdentifier(); err != nil {
+			return err
+		}
+	}
+
+	doc.pdf.SetXY(x, y+36)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.CellFormat(120, 4, doc.encodeString(label), "0", 0, "L", false, 0, "")
+	doc.pdf.SetY(y + 41)
+
+	return nil
+}
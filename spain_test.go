@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSpanishRecordFingerprint(t *testing.T) {
+	record := &SpanishRecord{
+		IssuerTaxID:  "89890001K",
+		SeriesNumber: "12345678/G33",
+		Date:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		InvoiceType:  "F1",
+		TaxTotal:     decimal.RequireFromString("12.35"),
+		Total:        decimal.RequireFromString("123.45"),
+		GeneratedAt:  "2024-01-01T19:20:30+01:00",
+	}
+
+	expected := "3C464DAF61ACB827C65FDA19F352A4E3BDC2C640E9E9FC4CC058073F38F12F60"
+	if fingerprint := record.Fingerprint(); fingerprint != expected {
+		t.Errorf("expected fingerprint %s, got %s", expected, fingerprint)
+	}
+}
+
+// countingSigner sign records with their fingerprint and count calls
+type countingSigner struct {
+	calls int
+}
+
+func (s *countingSigner) Sign(record *SpanishRecord) (string, error) {
+	s.calls++
+	return record.Fingerprint(), nil
+}
+
+func TestSpanishRecordSignature(t *testing.T) {
+	signer := &countingSigner{}
+
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("A-1")
+	doc.SetDate("01/01/2024")
+	doc.SetCompany(&Contact{Name: "Company", TaxID: "89890001K"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "Item", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "21"}})
+	doc.SpanishFiscal = &SpanishFiscal{
+		System:      SpanishSystemTicketBAI,
+		Territory:   "bizkaia",
+		Number:      "1",
+		GeneratedAt: "2024-01-01T19:20:30+01:00",
+		Signer:      signer,
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	signature := doc.SpanishFiscal.Signature
+
+	// Unchanged record isn't signed again
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if signer.calls != 1 || doc.SpanishFiscal.Signature != signature {
+		t.Errorf("expected a single signature, got %d calls", signer.calls)
+	}
+
+	// Edited record is signed again
+	doc.Items[0].UnitCost = "200"
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	record, _ := doc.SpanishRecord()
+	if signer.calls != 2 || doc.SpanishFiscal.Signature != record.Fingerprint() {
+		t.Errorf("expected signature of edited record, got %d calls", signer.calls)
+	}
+
+	// Caller supplied signatures are kept
+	doc.SpanishFiscal = &SpanishFiscal{System: SpanishSystemVerifactu, Number: "1", Signature: "SUPPLIED"}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SpanishFiscal.Signature != "SUPPLIED" {
+		t.Errorf("expected supplied signature, got %s", doc.SpanishFiscal.Signature)
+	}
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidSplit when a document cannot be split into bills
+var ErrInvalidSplit = errors.New("invalid split")
+
+// Split split document into n bills sharing every item equally.
+// Each bill has its own pdf, custom fonts must be registered again on bill.Pdf().
+func (doc *Document) Split(n int) ([]*Document, error) {
+	if n < 1 {
+		return nil, ErrInvalidSplit
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	bills := make([]*Document, n)
+	for i := range bills {
+		bills[i] = doc.newBill(i, n, "")
+	}
+
+	for _, item := range doc.Items {
+		doc.shareItem(item, bills)
+	}
+
+	return bills, doc.finalizeBills(bills)
+}
+
+// SplitByAssignee split document into one bill per item assignee.
+// Items without assignee are shared equally between all bills.
+func (doc *Document) SplitByAssignee() ([]*Document, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Collect assignees in order of appearance
+	assignees := []string{}
+	seen := map[string]bool{}
+	for _, item := range doc.Items {
+		if len(item.Assignee) > 0 && !seen[item.Assignee] {
+			seen[item.Assignee] = true
+			assignees = append(assignees, item.Assignee)
+		}
+	}
+
+	if len(assignees) == 0 {
+		return nil, ErrInvalidSplit
+	}
+
+	bills := make([]*Document, len(assignees))
+	billsByAssignee := map[string]*Document{}
+	for i, assignee := range assignees {
+		bills[i] = doc.newBill(i, len(assignees), assignee)
+		billsByAssignee[assignee] = bills[i]
+	}
+
+	for _, item := range doc.Items {
+		if len(item.Assignee) > 0 {
+			bill := billsByAssignee[item.Assignee]
+			bill.Items = append(bill.Items, item.share(decimal.NewFromInt(1), item.TotalWithoutTaxAndWithDiscount(), ""))
+			continue
+		}
+
+		doc.shareItem(item, bills)
+	}
+
+	return bills, doc.finalizeBills(bills)
+}
+
+// BuildBills build bills one after another in document pdf, each bill starting on a new page
+func (doc *Document) BuildBills(bills []*Document) (*fpdf.Fpdf, error) {
+	for _, bill := range bills {
+		bill.pdf = doc.pdf
+
+		if _, err := bill.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc.pdf, nil
+}
+
+// newBill return an empty copy of document for bill i of n
+func (doc *Document) newBill(i int, n int, assignee string) *Document {
+	bill := *doc
+
+	bill.pdf = fpdf.New("P", "mm", "A4", "")
+	bill.Items = []*Item{}
+	bill.Payers = nil
+	bill.Ref = fmt.Sprintf("%s-%d", doc.Ref, i+1)
+
+	// Totals of bills are computed from their items
+	bill.CustomSubtotal = ""
+	bill.CustomTax = ""
+	bill.CustomTotal = ""
+	bill.splitBill = true
+
+	// City tax items are shared as any other item
+	if doc.Stay != nil {
+		stay := *doc.Stay
+		stay.CityTaxRate = ""
+		bill.Stay = &stay
+	}
+
+	// Co-pay amounts apply to the whole document
+	if doc.Medical != nil {
+		medical := *doc.Medical
+		medical.InsurerAmount = ""
+		medical.PatientAmount = ""
+		bill.Medical = &medical
+	}
+
+	title := fmt.Sprintf("%s %d/%d", doc.Options.TextSplitBillTitle, i+1, n)
+	if len(assignee) > 0 {
+		title = fmt.Sprintf("%s (%s)", title, assignee)
+	}
+
+	if len(bill.Description) > 0 {
+		bill.Description = fmt.Sprintf("%s\n%s", bill.Description, title)
+	} else {
+		bill.Description = title
+	}
+
+	return &bill
+}
+
+// shareItem share item equally between bills, last bill absorb rounding difference
+func (doc *Document) shareItem(item *Item, bills []*Document) {
+	n := decimal.NewFromInt(int64(len(bills)))
+	ratio := decimal.NewFromInt(1).Div(n)
+	total := item.TotalWithoutTaxAndWithDiscount()
+	part := total.Div(n).Round(int32(doc.Options.CurrencyPrecision))
+	label := fmt.Sprintf("1/%d", len(bills))
+
+	for i, bill := range bills {
+		if i == len(bills)-1 {
+			part = total.Sub(part.Mul(n.Sub(decimal.NewFromInt(1))))
+		}
+
+		bill.Items = append(bill.Items, item.share(ratio, part, label))
+	}
+}
+
+// share return a copy of item for a bill, amount based tax is scaled by ratio
+func (i *Item) share(ratio decimal.Decimal, total decimal.Decimal, label string) *Item {
+	shared := *i
+	shared.Total = total.String()
+	shared.Rental = nil
+	shared.Meter = ""
+	shared.cityTax = false
+
+	if len(label) > 0 {
+		shared.Name = fmt.Sprintf("%s (%s)", i.Name, label)
+	}
+
+	if i.Tax != nil {
+		tax := *i.Tax
+		if taxType, taxNumber := tax.getTax(); taxType == TaxTypeAmount {
+			tax.Amount = taxNumber.Mul(ratio).String()
+		}
+		shared.Tax = &tax
+	}
+
+	// Discount is already applied to shared total
+	shared.Discount = nil
+
+	return &shared
+}
+
+// finalizeBills share document discount and validate bills
+func (doc *Document) finalizeBills(bills []*Document) error {
+	subtotal := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+
+	for _, bill := range bills {
+		if doc.Discount != nil {
+			discount := *doc.Discount
+			if discountType, discountNumber := discount.getDiscount(); discountType == DiscountTypeAmount && !subtotal.IsZero() {
+				ratio := bill.TotalWithoutTaxAndWithoutDocumentDiscount().Div(subtotal)
+				discount.Amount = discountNumber.Mul(ratio).Round(int32(doc.Options.CurrencyPrecision)).String()
+			}
+			bill.Discount = &discount
+		}
+
+		if err := bill.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSplit(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("T4")
+	doc.SetCompany(&Contact{Name: "Restaurant"})
+	doc.SetCustomer(&Contact{Name: "Table 4"})
+	doc.SetDefaultTax(&Tax{Percent: "10"})
+	doc.SetDiscount(&Discount{Amount: "6"})
+	doc.AppendItem(&Item{Name: "Wine", UnitCost: "30", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "Steak", UnitCost: "20", Quantity: "1", Assignee: "Alice"})
+	doc.AppendItem(&Item{Name: "Fish", UnitCost: "10.01", Quantity: "1", Assignee: "Bob"})
+
+	for _, split := range []func() ([]*Document, error){
+		func() ([]*Document, error) { return doc.Split(3) },
+		doc.SplitByAssignee,
+	} {
+		bills, err := split()
+		if err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		subtotal := decimal.Zero
+		for _, bill := range bills {
+			subtotal = subtotal.Add(bill.TotalWithoutTax())
+		}
+
+		if !subtotal.Equal(doc.TotalWithoutTax()) {
+			t.Errorf("expected bills subtotal %s, got %s", doc.TotalWithoutTax(), subtotal)
+		}
+	}
+
+	if _, err := doc.Split(0); err != ErrInvalidSplit {
+		t.Errorf("expected ErrInvalidSplit, got %v", err)
+	}
+}
+
+func TestBuildBillsStatement(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("T5")
+	doc.SetCompany(&Contact{Name: "Restaurant"})
+	doc.SetCustomer(&Contact{Name: "Table 5"})
+	doc.SetStatement(&Statement{PreviousBalance: "20", PaymentsReceived: "10"})
+	doc.AppendItem(&Item{Name: "Menu", UnitCost: "15", Quantity: "2"})
+
+	bills, err := doc.Split(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bill := range bills {
+		if total, err := bill.totalAmount(); err != nil || !total.Equal(decimal.NewFromInt(15)) {
+			t.Errorf("expected bill total 15, got %s %v", total, err)
+		}
+		if _, _, _, total := bill.totalsTexts(); total != "€ 15.00" {
+			t.Errorf("expected displayed bill total, got %q", total)
+		}
+	}
+
+	if _, err := doc.BuildBills(bills); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Statement define the account mini-statement of a document: previous balance,
+// payments received since, new charges (document total) and total due
+type Statement struct {
+	PreviousBalance  string `json:"previous_balance,omitempty"`  // Balance of the previous statement ex 120.50
+	PaymentsReceived string `json:"payments_received,omitempty"` // Payments received since the previous statement ex 120.50
+
+	_previousBalance  decimal.Decimal
+	_paymentsReceived decimal.Decimal
+	_newCharges       decimal.Decimal
+}
+
+// Prepare convert strings to decimal, newCharges is the document total
+func (s *Statement) Prepare(newCharges decimal.Decimal) error {
+	s._previousBalance = decimal.Zero
+	if len(s.PreviousBalance) > 0 {
+		previousBalance, err := decimal.NewFromString(s.PreviousBalance)
+		if err != nil {
+			return err
+		}
+		s._previousBalance = previousBalance
+	}
+
+	s._paymentsReceived = decimal.Zero
+	if len(s.PaymentsReceived) > 0 {
+		paymentsReceived, err := decimal.NewFromString(s.PaymentsReceived)
+		if err != nil {
+			return err
+		}
+		s._paymentsReceived = paymentsReceived
+	}
+
+	s._newCharges = newCharges
+
+	return nil
+}
+
+// NewCharges return the document total
+func (s *Statement) NewCharges() decimal.Decimal {
+	return s._newCharges
+}
+
+// TotalDue return previous balance - payments received + new charges
+func (s *Statement) TotalDue() decimal.Decimal {
+	return s._previousBalance.Sub(s._paymentsReceived).Add(s._newCharges)
+}
+
+// appendStatement append the mini-statement block to document
+func (doc *Document) appendStatement() {
+	if doc.Statement == nil {
+		return
+	}
+
+	titles := []string{
+		doc.Options.TextStatementPreviousBalanceTitle,
+		doc.Options.TextStatementPaymentsReceivedTitle,
+		doc.Options.TextStatementNewChargesTitle,
+		doc.Options.TextStatementTotalDueTitle,
+	}
+	amounts := []string{
+		doc.formatMoney(doc.Statement._previousBalance),
+		doc.formatMoney(doc.Statement._paymentsReceived.Neg()),
+		doc.formatMoney(doc.Statement._newCharges),
+		doc.formatMoney(doc.Statement.TotalDue()),
+	}
+
+	y := doc.pdf.GetY() + 10
+
+	// Titles
+	doc.pdf.SetXY(BaseMargin, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	for _, title := range titles {
+		doc.pdf.CellFormat(47.5, 6, doc.encodeString(title), "0", 0, "C", true, 0, "")
+	}
+
+	// Amounts, total due highlighted
+	doc.pdf.SetXY(BaseMargin, y+6)
+	doc.pdf.SetFont(doc.Options.Font, "", 10)
+	for i, amount := range amounts {
+		if i == len(amounts)-1 {
+			doc.pdf.SetFont(doc.Options.BoldFont, "B", 10)
+		}
+		doc.pdf.CellFormat(47.5, 8, doc.encodeString(amount), "0", 0, "C", false, 0, "")
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetXY(BaseMargin, y+14)
+}
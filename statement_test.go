@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStatement(t *testing.T) {
+	doc, _ := New(Invoice, &Options{DisableCompression: true})
+	doc.SetRef("1")
+	doc.SetCompany(&Contact{Name: "Telco"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.SetStatement(&Statement{PreviousBalance: "59.90", PaymentsReceived: "50"})
+	doc.AppendItem(&Item{Name: "Monthly plan", UnitCost: "39.90", Quantity: "1", Tax: &Tax{Percent: "20"}})
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due := doc.Statement.TotalDue().String(); due != "57.78" {
+		t.Errorf("expected total due 57.78, got %s", due)
+	}
+
+	summary, _ := doc.Summary()
+	if summary.Statement == nil || summary.Statement.NewCharges != "47.88" || summary.Statement.TotalDue != "57.78" {
+		t.Errorf("unexpected statement summary %+v", summary.Statement)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("(Previous balance)")) {
+		t.Errorf("expected statement block")
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+import "github.com/shopspring/decimal"
+
+// InvoiceSummary define the computed amounts of a document, decoupled from rendering,
+// to be serialized to JSON by APIs. Amounts are decimal strings rounded to
+// Options.CurrencyPrecision ex "1234.50", computed by the same code as the PDF.
+type InvoiceSummary struct {
+	Type            string            `json:"type"`                // Document type ex INVOICE
+	Ref             string            `json:"ref"`                 // Document reference
+	Date            string            `json:"date,omitempty"`      // Document date as written in the document
+	Currency        string            `json:"currency"`            // ISO 4217 currency code, Options.CurrencyCode
+	Lines           []*SummaryLine    `json:"lines"`               // Items in document order
+	Taxes           []*SummaryTax     `json:"taxes"`               // Tax breakdown
+	Subtotal        string            `json:"subtotal"`            // Sum of lines net amounts, before document discount
+	Discount        string            `json:"discount"`            // Document discount amount
+	TotalWithoutTax string            `json:"total_without_tax"`   // Taxable base, document discount applied
+	Tax             string            `json:"tax"`                 // Total tax
+	TotalWithTax    string            `json:"total_with_tax"`      // Amount due
+	Statement       *SummaryStatement `json:"statement,omitempty"` // Previous balance mini-statement
+	Hash            string            `json:"hash"`                // Document.Hash of the summarized content
+}
+
+// SummaryLine define the computed amounts of an item
+type SummaryLine struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`                  // ItemKind constant
+	Quantity   string `json:"quantity"`              // Quantity as written in the item
+	Unit       string `json:"unit,omitempty"`        // Unit of measure
+	UnitPrice  string `json:"unit_price"`            // Price of a single unit, not rounded
+	Discount   string `json:"discount"`              // Item discount amount
+	Net        string `json:"net"`                   // Line total without tax, item discount applied
+	Tax        string `json:"tax"`                   // Line tax, before document discount
+	Gross      string `json:"gross"`                 // Line total with tax
+	Free       bool   `json:"free,omitempty"`        // Free of charge
+	Account    string `json:"account,omitempty"`     // Ledger account
+	CostCenter string `json:"cost_center,omitempty"` // Cost center
+}
+
+// SummaryStatement define the previous balance mini-statement amounts
+type SummaryStatement struct {
+	PreviousBalance  string `json:"previous_balance"`
+	PaymentsReceived string `json:"payments_received"`
+	NewCharges       string `json:"new_charges"`
+	TotalDue         string `json:"total_due"`
+}
+
+// SummaryTax define a tax breakdown line
+type SummaryTax struct {
+	Type   string `json:"type"` // TaxTypePercent or TaxTypeAmount
+	Rate   string `json:"rate"` // Tax percent, "0" for amount taxes
+	Base   string `json:"base"`
+	Amount string `json:"amount"`
+}
+
+// Summary validate document and return its computed amounts
+func (doc *Document) Summary() (*InvoiceSummary, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	precision := int32(doc.Options.CurrencyPrecision)
+	amount := func(value decimal.Decimal) string {
+		return value.StringFixed(precision)
+	}
+
+	subtotal := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	totalWithoutTax := doc.TotalWithoutTax()
+
+	summary := &InvoiceSummary{
+		Type:            doc.Type,
+		Ref:             doc.Ref,
+		Date:            doc.Date,
+		Currency:        doc.Options.CurrencyCode,
+		Lines:           []*SummaryLine{},
+		Taxes:           []*SummaryTax{},
+		Subtotal:        amount(subtotal),
+		Discount:        amount(subtotal.Sub(totalWithoutTax)),
+		TotalWithoutTax: amount(totalWithoutTax),
+		Tax:             amount(doc.Tax()),
+		TotalWithTax:    amount(doc.TotalWithTax()),
+		Hash:            doc.Hash(),
+	}
+
+	if doc.Statement != nil {
+		summary.Statement = &SummaryStatement{
+			PreviousBalance:  amount(doc.Statement._previousBalance),
+			PaymentsReceived: amount(doc.Statement._paymentsReceived),
+			NewCharges:       amount(doc.Statement.NewCharges()),
+			TotalDue:         amount(doc.Statement.TotalDue()),
+		}
+	}
+
+	for _, item := range doc.Items {
+		kind := item.Kind
+		if len(kind) == 0 {
+			kind = ItemKindService
+		}
+
+		net := item.TotalWithoutTaxAndWithDiscount()
+		discount := decimal.Zero
+		if len(item.Total) == 0 && !item.Free {
+			discount = item.TotalWithoutTaxAndWithoutDiscount().Sub(net)
+		}
+
+		summary.Lines = append(summary.Lines, &SummaryLine{
+			Name:       item.Name,
+			Kind:       kind,
+			Quantity:   item.Quantity,
+			Unit:       item.Unit,
+			UnitPrice:  item.unitPrice(),
+			Discount:   amount(discount),
+			Net:        amount(net),
+			Tax:        amount(item.TaxWithTotalDiscounted()),
+			Gross:      amount(item.TotalWithTaxAndDiscount()),
+			Free:       item.freeOfCharge(),
+			Account:    item.Account,
+			CostCenter: item.CostCenter,
+		})
+	}
+
+	for _, line := range doc.TaxLines() {
+		summary.Taxes = append(summary.Taxes, &SummaryTax{
+			Type:   line.Type,
+			Rate:   line.Rate.String(),
+			Base:   amount(line.Base),
+			Amount: amount(line.Amount),
+		})
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.SetDiscount(&Discount{Percent: "10"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "150", Quantity: "12", Unit: "h", Tax: &Tax{Percent: "20"}, Discount: &Discount{Amount: "100"}})
+	doc.AppendItem(&Item{Name: "Books", UnitCost: "20", Quantity: "3", Tax: &Tax{Percent: "5.5"}})
+
+	summary, err := doc.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Subtotal != "1760.00" || summary.Discount != "176.00" || summary.TotalWithoutTax != "1584.00" {
+		t.Errorf("unexpected totals %+v", summary)
+	}
+	if summary.TotalWithTax != doc.TotalWithTax().StringFixed(2) || summary.Tax != doc.Tax().StringFixed(2) {
+		t.Errorf("expected summary totals to match document totals")
+	}
+	if len(summary.Taxes) != 2 || summary.Taxes[1].Rate != "5.5" || summary.Lines[0].Discount != "100.00" {
+		t.Errorf("unexpected lines %+v %+v", summary.Lines[0], summary.Taxes)
+	}
+
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), `"total_with_tax":"1892.97"`) || !strings.Contains(string(encoded), `"currency":"EUR"`) {
+		t.Errorf("unexpected json %s", encoded)
+	}
+}
@@ -69,3 +69,28 @@ func (t *Tax) getTax() (string, decimal.Decimal) {
 
 	return taxType, decVal
 }
+
+// TaxLine define a tax of the document tax breakdown
+type TaxLine struct {
+	Type   string          `json:"type"`   // TaxTypePercent or TaxTypeAmount
+	Rate   decimal.Decimal `json:"rate"`   // Tax percent, zero for amount taxes
+	Base   decimal.Decimal `json:"base"`   // Taxable base, document discount applied
+	Amount decimal.Decimal `json:"amount"` // Tax amount
+	Tax    *Tax            `json:"-"`      // First tax of the breakdown line
+
+	item *Item // First item of the breakdown line
+}
+
+// taxLineKey identify the tax line of an item tax
+func taxLineKey(tax *Tax) string {
+	if tax == nil {
+		return "none"
+	}
+
+	taxType, taxNumber := tax.getTax()
+	if taxType == TaxTypeAmount {
+		return TaxTypeAmount
+	}
+
+	return taxNumber.String()
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Override modify the next document built from a previous one, see FromDocumentJSON
+type Override func(doc *Document) error
+
+// NextRef return ref with its trailing number incremented, zero padding kept ex INV-2024-009 to INV-2024-010.
+// A ref without trailing number is considered the first one ex INV to INV-2.
+func NextRef(ref string) string {
+	digits := len(ref)
+	for digits > 0 && ref[digits-1] >= '0' && ref[digits-1] <= '9' {
+		digits--
+	}
+
+	prefix, number := ref[:digits], ref[digits:]
+	if len(number) == 0 {
+		return ref + "-2"
+	}
+
+	incremented := []byte(number)
+	for i := len(incremented) - 1; i >= 0; i-- {
+		if incremented[i] < '9' {
+			incremented[i]++
+			return prefix + string(incremented)
+		}
+		incremented[i] = '0'
+	}
+
+	return prefix + "1" + string(incremented)
+}
+
+// FromDocumentJSON load a previous document and return the next one: ref incremented with NextRef,
+// date set to today, validity date, payment term and items dates moved by the same number of days.
+// Overrides are applied in order afterwards.
+//
+// Fiscal chaining data (Portuguese hash, Spanish signature...) of the previous document is kept as is
+// and must be updated by overrides.
+func FromDocumentJSON(prev []byte, overrides ...Override) (*Document, error) {
+	doc, err := decodeDocument(prev)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Ref = NextRef(doc.Ref)
+
+	if len(doc.Date) > 0 {
+		if err := WithDate(time.Now())(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, override := range overrides {
+		if err := override(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// decodeDocument return the document of JSON data, ready to build with its options and defaults
+func decodeDocument(data []byte) (*Document, error) {
+	decoded := &Document{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return nil, err
+	}
+	if decoded.Options == nil {
+		decoded.Options = &Options{}
+	}
+
+	doc, err := New(decoded.Type, decoded.Options)
+	if err != nil {
+		return nil, err
+	}
+	decoded.pdf = doc.pdf
+	decoded.ac = doc.ac
+
+	return decoded, nil
+}
+
+// WithRef set the ref of next document
+func WithRef(ref string) Override {
+	return func(doc *Document) error {
+		doc.Ref = ref
+		return nil
+	}
+}
+
+// WithDate set the date of next document, validity date, payment term and items dates
+// are moved by the same number of days
+func WithDate(date time.Time) Override {
+	return func(doc *Document) error {
+		current, err := time.Parse(doc.Options.DateFormat, doc.Date)
+		if err != nil {
+			return err
+		}
+
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(date.Sub(current).Hours() / 24)
+
+		return ShiftDates(0, 0, days)(doc)
+	}
+}
+
+// ShiftDates move date, validity date, payment term and items dates of next document,
+// ex ShiftDates(0, 1, 0) for a monthly invoice. Payment terms and items dates which aren't dates are left as is.
+func ShiftDates(years int, months int, days int) Override {
+	return func(doc *Document) error {
+		shift := func(value *string, required bool) error {
+			if len(*value) == 0 {
+				return nil
+			}
+
+			date, err := time.Parse(doc.Options.DateFormat, *value)
+			if err != nil {
+				if required {
+					return err
+				}
+				return nil
+			}
+
+			*value = date.AddDate(years, months, days).Format(doc.Options.DateFormat)
+			return nil
+		}
+
+		for _, value := range []*string{&doc.Date, &doc.ValidityDate} {
+			if err := shift(value, true); err != nil {
+				return err
+			}
+		}
+		if err := shift(&doc.PaymentTerm, false); err != nil {
+			return err
+		}
+
+		for _, item := range doc.Items {
+			if err := shift(&item.Date, false); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// ClearItems remove items of next document, ex to bill new usage with the same parties and terms
+func ClearItems() Override {
+	return func(doc *Document) error {
+		doc.Items = nil
+		return nil
+	}
+}
+
+// WithItems replace items of next document
+func WithItems(items ...*Item) Override {
+	return func(doc *Document) error {
+		doc.Items = items
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNextRef(t *testing.T) {
+	for ref, expected := range map[string]string{
+		"INV-2024-009": "INV-2024-010",
+		"F99":          "F100",
+		"2024/0001":    "2024/0002",
+		"QUOTE":        "QUOTE-2",
+		"":             "-2",
+	} {
+		if next := NextRef(ref); next != expected {
+			t.Errorf("expected next ref of %q to be %q, got %q", ref, expected, next)
+		}
+	}
+}
+
+func TestFromDocumentJSON(t *testing.T) {
+	prev, _ := New(Invoice, &Options{TextTypeInvoice: "FACTURE"})
+	prev.SetRef("INV-2024-009")
+	prev.SetDate("01/03/2024")
+	prev.ValidityDate = "31/03/2024"
+	prev.SetPaymentTerm("15/03/2024")
+	prev.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	prev.SetCustomer(&Contact{Name: "Customer"})
+	prev.AppendItem(&Item{Name: "Hosting", UnitCost: "100", Quantity: "1"})
+
+	source, err := json.Marshal(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := FromDocumentJSON(source, ShiftDates(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now()
+	if next.Ref != "INV-2024-010" || next.Options.TextTypeInvoice != "FACTURE" || len(next.Items) != 1 {
+		t.Errorf("unexpected next document %s %s %d items", next.Ref, next.Options.TextTypeInvoice, len(next.Items))
+	}
+	if expected := today.AddDate(0, 1, 0).Format("02/01/2006"); next.Date != expected {
+		t.Errorf("expected date %s, got %s", expected, next.Date)
+	}
+	if expected := today.AddDate(0, 1, 14).Format("02/01/2006"); next.PaymentTerm != expected {
+		t.Errorf("expected payment term kept 14 days after date %s, got %s", expected, next.PaymentTerm)
+	}
+
+	if _, err := next.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fixed date, items cleared
+	next, err = FromDocumentJSON(source, WithDate(time.Date(2024, 4, 1, 10, 0, 0, 0, time.Local)), ClearItems(), WithRef("INV-2024-100"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Date != "01/04/2024" || next.ValidityDate != "01/05/2024" || len(next.Items) != 0 || next.Ref != "INV-2024-100" {
+		t.Errorf("unexpected next document %s %s %s %d items", next.Ref, next.Date, next.ValidityDate, len(next.Items))
+	}
+
+	if _, err := FromDocumentJSON([]byte(`{"type":"RECEIPT"}`)); err != ErrInvalidDocumentType {
+		t.Errorf("expected ErrInvalidDocumentType, got %v", err)
+	}
+}
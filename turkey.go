@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/creasty/defaults"
+)
+
+// ErrInvalidETTN when ETTN is not an UUID
+var ErrInvalidETTN = errors.New("invalid ettn")
+
+// ettnRegexp match UUIDs, upper or lower case
+var ettnRegexp = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Turkish electronic invoice profiles
+const (
+	TurkishProfileEFatura string = "e-fatura"
+	TurkishProfileEArsiv  string = "e-arsiv"
+)
+
+// TurkishFiscal define GIB (Turkish Revenue Administration) requirements of a document.
+// Turkish characters require a cp1254 unicode translator or an UTF-8 font.
+type TurkishFiscal struct {
+	Profile         string `json:"profile,omitempty" validate:"required,oneof=e-fatura e-arsiv"`
+	ETTN            string `json:"ettn,omitempty"`                             // Invoice UUID, generated if empty
+	CustomizationID string `json:"customization_id,omitempty" default:"TR1.2"` // UBL-TR customization
+	Scenario        string `json:"scenario,omitempty" validate:"omitempty,oneof=TEMELFATURA TICARIFATURA EARSIVFATURA IHRACAT"`
+	InvoiceType     string `json:"invoice_type,omitempty" default:"SATIS" validate:"omitempty,oneof=SATIS IADE TEVKIFAT ISTISNA OZELMATRAH IHRACKAYITLI"`
+	IssueTime       string `json:"issue_time,omitempty"`     // Format 15:04:05, defaults to now
+	PaperDelivery   bool   `json:"paper_delivery,omitempty"` // e-Arşiv delivered on paper instead of electronic means
+	Logo            []byte `json:"logo,omitempty"`           // GIB logo byte array
+}
+
+// Prepare set defaults, scenario and ETTN of document
+func (t *TurkishFiscal) Prepare() error {
+	if err := defaults.Set(t); err != nil {
+		return err
+	}
+
+	if len(t.Scenario) == 0 {
+		t.Scenario = "TEMELFATURA"
+		if t.Profile == TurkishProfileEArsiv {
+			t.Scenario = "EARSIVFATURA"
+		}
+	}
+
+	if len(t.IssueTime) == 0 {
+		t.IssueTime = time.Now().Format("15:04:05")
+	}
+
+	if len(t.ETTN) == 0 {
+		ettn, err := newUUID()
+		if err != nil {
+			return err
+		}
+		t.ETTN = ettn
+	}
+
+	if !ettnRegexp.MatchString(t.ETTN) {
+		return ErrInvalidETTN
+	}
+
+	return nil
+}
+
+// newUUID return a random (version 4) UUID
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), nil
+}
+
+// karekodContent return the GIB karekod QR code content, a JSON object of seller and buyer tax ids,
+// invoice informations and amounts with taxable base and tax per VAT rate
+func (doc *Document) karekodContent() (string, error) {
+	fiscal := doc.TurkishFiscal
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return "", err
+	}
+
+	customerTaxID := ""
+	if doc.Customer != nil {
+		customerTaxID = doc.Customer.TaxID
+	}
+
+	fields := [][2]string{
+		{"vkntckn", doc.Company.TaxID},
+		{"avkntckn", customerTaxID},
+		{"senaryo", fiscal.Scenario},
+		{"tip", fiscal.InvoiceType},
+		{"tarih", date.Format("2006-01-02")},
+		{"no", doc.Ref},
+		{"ettn", fiscal.ETTN},
+		{"parabirimi", doc.Options.CurrencyCode},
+		{"malhizmettoplam", doc.TotalWithoutTaxAndWithoutDocumentDiscount().StringFixed(2)},
+	}
+
+	for _, line := range doc.TaxLines() {
+		if line.Type != TaxTypePercent {
+			continue
+		}
+		fields = append(
+			fields,
+			[2]string{fmt.Sprintf("kdvmatrah(%s)", line.Rate), line.Base.StringFixed(2)},
+			[2]string{fmt.Sprintf("hesaplanankdv(%s)", line.Rate), line.Amount.StringFixed(2)},
+		)
+	}
+
+	total, err := doc.totalAmount()
+	if err != nil {
+		return "", err
+	}
+	fields = append(
+		fields,
+		[2]string{"vergidahil", doc.TotalWithTax().StringFixed(2)},
+		[2]string{"odenecek", total.StringFixed(2)},
+	)
+
+	// Keys in specification order
+	members := make([]string, 0, len(fields))
+	for _, field := range fields {
+		key, _ := json.Marshal(field[0])
+		value, _ := json.Marshal(field[1])
+		members = append(members, string(key)+":"+string(value))
+	}
+
+	return "{" + strings.Join(members, ",") + "}", nil
+}
+
+// appendTurkishFiscal append karekod, GIB logo, profile title and invoice informations table
+func (doc *Document) appendTurkishFiscal() error {
+	fiscal := doc.TurkishFiscal
+	if fiscal == nil {
+		return nil
+	}
+
+	y := doc.pdf.GetY() + 5
+
+	// Karekod on the left of title
+	content, err := doc.karekodContent()
+	if err != nil {
+		return err
+	}
+	if err := doc.drawQRCode(content, QRCodeECLevelM, BaseMargin, y, 25); err != nil {
+		return err
+	}
+
+	// GIB logo and profile title
+	title := doc.Options.TextTurkishEFaturaTitle
+	if fiscal.Profile == TurkishProfileEArsiv {
+		title = doc.Options.TextTurkishEArsivTitle
+	}
+
+	titleY := y
+	if fiscal.Logo != nil {
+		if drawn, _ := doc.drawImage("GIB logo", "gib-logo", fiscal.Logo, 45, y, 0, 20); drawn {
+			titleY = y + 21
+		}
+	}
+
+	doc.pdf.SetXY(BaseMargin+30, titleY)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.CellFormat(75, 8, doc.encodeString(title), "0", 0, "C", false, 0, "")
+
+	// Invoice informations table
+	date := time.Now().Format(doc.Options.DateFormat)
+	if len(doc.Date) > 0 {
+		date = doc.Date
+	}
+
+	rows := [][2]string{
+		{doc.Options.TextTurkishCustomizationTitle, fiscal.CustomizationID},
+		{doc.Options.TextTurkishScenarioTitle, fiscal.Scenario},
+		{doc.Options.TextTurkishInvoiceTypeTitle, fiscal.InvoiceType},
+		{doc.Options.TextTurkishInvoiceNumberTitle, doc.Ref},
+		{doc.Options.TextTurkishInvoiceDateTitle, date},
+		{doc.Options.TextTurkishInvoiceTimeTitle, fiscal.IssueTime},
+	}
+	if doc.Customer != nil && len(doc.Customer.TaxID) > 0 {
+		rows = append(rows, [2]string{doc.Options.TextTurkishTaxIDTitle, doc.Customer.TaxID})
+	}
+
+	rowY := y
+	for _, row := range rows {
+		doc.pdf.SetXY(120, rowY)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+		doc.pdf.CellFormat(30, 4, doc.encodeString(row[0]), "1", 0, "L", false, 0, "")
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.CellFormat(50, 4, doc.encodeString(row[1]), "1", 0, "L", false, 0, "")
+		rowY += 4
+	}
+
+	// ETTN on its own line, under karekod, title and table
+	bottom := math.Max(rowY, math.Max(titleY+8, y+25))
+
+	doc.pdf.SetXY(BaseMargin, bottom+2)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", SmallTextFontSize)
+	doc.pdf.CellFormat(190, 4, doc.encodeString(fmt.Sprintf("ETTN: %s", fiscal.ETTN)), "0", 0, "L", false, 0, "")
+	doc.pdf.SetXY(BaseMargin, bottom+6)
+
+	return nil
+}
+
+// appendTurkishAnnotation append the e-Arşiv electronic delivery annotation
+func (doc *Document) appendTurkishAnnotation() {
+	fiscal := doc.TurkishFiscal
+	if fiscal == nil || fiscal.Profile != TurkishProfileEArsiv || fiscal.PaperDelivery {
+		return
+	}
+
+	y := doc.blockY(6)
+	doc.pdf.SetXY(BaseMargin, y)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.CellFormat(190, 4, doc.encodeString(doc.Options.TextTurkishEArsivAnnotation), "0", 0, "C", false, 0, "")
+	doc.pdf.SetY(y + 5)
+}
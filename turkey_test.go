@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+// newTurkishInvoice return an e-Arşiv invoice with 20 % and 10 % VAT items
+func newTurkishInvoice() *Document {
+	doc, _ := New(Invoice, &Options{DisableCompression: true, CurrencyCode: "TRY"})
+	doc.SetRef("ABC2024000000001")
+	doc.SetDate("15/03/2024")
+	doc.SetCompany(&Contact{Name: "Satıcı", TaxID: "1234567890"})
+	doc.SetCustomer(&Contact{Name: "Alıcı", TaxID: "11111111111"})
+	doc.AppendItem(&Item{Name: "Hizmet", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "Kitap", UnitCost: "50", Quantity: "1", Tax: &Tax{Percent: "10"}})
+	doc.TurkishFiscal = &TurkishFiscal{
+		Profile:   TurkishProfileEArsiv,
+		ETTN:      "F47AC10B-58CC-4372-A567-0E02B2C3D479",
+		IssueTime: "10:30:00",
+	}
+
+	return doc
+}
+
+func TestTurkishFiscalPrepare(t *testing.T) {
+	fiscal := &TurkishFiscal{Profile: TurkishProfileEArsiv}
+	if err := fiscal.Prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if fiscal.Scenario != "EARSIVFATURA" || fiscal.InvoiceType != "SATIS" || fiscal.CustomizationID != "TR1.2" {
+		t.Errorf("unexpected defaults %s %s %s", fiscal.Scenario, fiscal.InvoiceType, fiscal.CustomizationID)
+	}
+	if !regexp.MustCompile(`^[0-9A-F]{8}-[0-9A-F]{4}-4[0-9A-F]{3}-[89AB][0-9A-F]{3}-[0-9A-F]{12}$`).MatchString(fiscal.ETTN) {
+		t.Errorf("expected version 4 UUID ETTN, got %s", fiscal.ETTN)
+	}
+
+	fiscal = &TurkishFiscal{Profile: TurkishProfileEFatura}
+	if err := fiscal.Prepare(); err != nil || fiscal.Scenario != "TEMELFATURA" {
+		t.Errorf("expected TEMELFATURA scenario, got %s %v", fiscal.Scenario, err)
+	}
+
+	fiscal = &TurkishFiscal{Profile: TurkishProfileEFatura, ETTN: "ABC2024000000001"}
+	if err := fiscal.Prepare(); err != ErrInvalidETTN {
+		t.Errorf("expected ErrInvalidETTN, got %v", err)
+	}
+}
+
+func TestKarekod(t *testing.T) {
+	doc := newTurkishInvoice()
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := doc.karekodContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"vkntckn":"1234567890","avkntckn":"11111111111","senaryo":"EARSIVFATURA","tip":"SATIS",` +
+		`"tarih":"2024-03-15","no":"ABC2024000000001","ettn":"F47AC10B-58CC-4372-A567-0E02B2C3D479",` +
+		`"parabirimi":"TRY","malhizmettoplam":"150.00","kdvmatrah(20)":"100.00","hesaplanankdv(20)":"20.00",` +
+		`"kdvmatrah(10)":"50.00","hesaplanankdv(10)":"5.00","vergidahil":"175.00","odenecek":"175.00"}`
+	if content != expected {
+		t.Errorf("unexpected karekod content\n%s\nexpected\n%s", content, expected)
+	}
+}
+
+func TestTurkishFiscalBuild(t *testing.T) {
+	doc := newTurkishInvoice()
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"(ETTN: F47AC10B-58CC-4372-A567-0E02B2C3D479)", "(EARSIVFATURA)", "(ABC2024000000001)"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Generated ETTN is kept by later builds
+	doc.TurkishFiscal.ETTN = ""
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.TurkishFiscal.Profile = "e-irsaliye"
+	if err := doc.Validate(); err == nil {
+		t.Error("expected unknown profile error")
+	}
+}
@@ -0,0 +1,252 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Scripts of text runs, written in item names, titles and footnotes as <sup>...</sup> and <sub>...</sub>
+const (
+	ScriptSuper string = "sup"
+	ScriptSub   string = "sub"
+)
+
+// Superscripts and subscripts font size and baseline shift, relative to the font size
+const (
+	scriptFontRatio  float64 = 0.65
+	superscriptShift float64 = 0.45
+	subscriptShift   float64 = -0.15
+)
+
+// scriptTags start or end scripts
+var scriptTags = map[string]string{
+	"<sup>":  ScriptSuper,
+	"</sup>": "",
+	"<sub>":  ScriptSub,
+	"</sub>": "",
+}
+
+// scriptTagsReplacer remove scripts markup
+var scriptTagsReplacer = strings.NewReplacer("<sup>", "", "</sup>", "", "<sub>", "", "</sub>", "")
+
+// textRun define a part of text drawn with a single font and script,
+// the document font when family is empty
+type textRun struct {
+	family string
+	script string
+	text   string
+}
+
+// stripScripts return text without scripts markup, ex to measure it
+func stripScripts(text string) string {
+	return scriptTagsReplacer.Replace(text)
+}
+
+// textRuns split text in runs of scripts and fallback fonts, in visual order,
+// nil when the document font draws the whole text as is
+func (doc *Document) textRuns(text string) []*textRun {
+	runes := []rune{}
+	scripts := []string{}
+	special := false
+	script := ""
+
+	for len(text) > 0 {
+		// Scripts markup
+		tagged := false
+		if text[0] == '<' {
+			for tag, tagScript := range scriptTags {
+				if strings.HasPrefix(text, tag) {
+					script, text, tagged = tagScript, text[len(tag):], true
+					break
+				}
+			}
+		}
+		if tagged {
+			special = true
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		runes = append(runes, r)
+		scripts = append(scripts, script)
+	}
+
+	// Lines mixing right to left and left to right characters
+	if rtl(string(runes)) {
+		special = true
+		runes, scripts = doc.visualRunes(runes, scripts)
+	}
+
+	runs := []*textRun{}
+	for i, r := range runes {
+		family := ""
+		if !doc.encodable(r) {
+			family = doc.fallbackFont(r)
+			if len(family) > 0 {
+				doc.substitute(r, GlyphFallback, family, "")
+			}
+		}
+		special = special || len(family) > 0
+
+		if last := len(runs) - 1; last >= 0 && runs[last].family == family && runs[last].script == scripts[i] {
+			runs[last].text += string(r)
+		} else {
+			runs = append(runs, &textRun{family: family, script: scripts[i], text: string(r)})
+		}
+	}
+
+	if !special {
+		return nil
+	}
+
+	return runs
+}
+
+// writeRuns write runs in a width wide column at x, wrapped like MultiCell,
+// with the current font size and family, style font
+func (doc *Document) writeRuns(runs []*textRun, x float64, width float64, lineHeight float64, family string, style string) {
+	left, _, right, _ := doc.pdf.GetMargins()
+	pageWidth, _ := doc.pdf.GetPageSize()
+
+	doc.pdf.SetLeftMargin(x)
+	doc.pdf.SetRightMargin(pageWidth - x - width)
+	doc.pdf.SetX(x)
+
+	doc.writeRunsInline(runs, lineHeight, family, style)
+	doc.pdf.Ln(lineHeight)
+
+	doc.pdf.SetLeftMargin(left)
+	doc.pdf.SetRightMargin(right)
+}
+
+// writeRunsInline write runs from current position, lineHeight high
+func (doc *Document) writeRunsInline(runs []*textRun, lineHeight float64, family string, style string) {
+	size, _ := doc.pdf.GetFontSize()
+
+	for _, run := range runs {
+		text := doc.encode(run.text)
+		if len(run.family) > 0 {
+			doc.pdf.SetFont(run.family, "", 0)
+			text = run.text
+		}
+
+		switch run.script {
+		case ScriptSuper:
+			doc.pdf.SubWrite(lineHeight, text, size*scriptFontRatio, size*superscriptShift, 0, "")
+		case ScriptSub:
+			doc.pdf.SubWrite(lineHeight, text, size*scriptFontRatio, size*subscriptShift, 0, "")
+		default:
+			doc.pdf.Write(lineHeight, text)
+		}
+
+		if len(run.family) > 0 {
+			doc.pdf.SetFont(family, style, 0)
+		}
+	}
+}
+
+// runsWidth return the width of runs with the current font size and family, style font
+func (doc *Document) runsWidth(runs []*textRun, family string, style string) float64 {
+	size, _ := doc.pdf.GetFontSize()
+
+	width := 0.0
+	for _, run := range runs {
+		text := doc.encode(run.text)
+		if len(run.family) > 0 {
+			doc.pdf.SetFont(run.family, "", 0)
+			text = run.text
+		}
+		if len(run.script) > 0 {
+			doc.pdf.SetFontSize(size * scriptFontRatio)
+		}
+
+		width += doc.pdf.GetStringWidth(text)
+
+		if len(run.script) > 0 {
+			doc.pdf.SetFontSize(size)
+		}
+		if len(run.family) > 0 {
+			doc.pdf.SetFont(family, style, 0)
+		}
+	}
+
+	return width
+}
+
+// cellFormat draw a single line cell of text like CellFormat without border nor fill, with scripts and
+// fallback fonts, the current font being family, style
+func (doc *Document) cellFormat(width float64, height float64, text string, align string, family string, style string) {
+	runs := doc.textRuns(text)
+	if runs == nil {
+		doc.pdf.CellFormat(width, height, doc.encodeString(text), "0", 0, align, false, 0, "")
+		return
+	}
+
+	// Written runs are shifted by the cell margin, like left aligned cells
+	x, y := doc.pdf.GetXY()
+	textX := x
+	switch align {
+	case "R":
+		textX = x + width - 2*doc.pdf.GetCellMargin() - doc.runsWidth(runs, family, style)
+	case "C":
+		textX = x + (width-doc.runsWidth(runs, family, style))/2 - doc.pdf.GetCellMargin()
+	}
+
+	// Never wrap
+	_, _, right, _ := doc.pdf.GetMargins()
+	doc.pdf.SetRightMargin(0)
+	doc.pdf.SetXY(textX, y)
+	doc.writeRunsInline(runs, height, family, style)
+	doc.pdf.SetRightMargin(right)
+
+	doc.pdf.SetXY(x+width, y)
+}
+
+// Ordinal return n as an ordinal number of locale, its suffix superscript, ex 1<sup>er</sup> for fr-FR
+// or 2<sup>nd</sup> for en-US, English when locale isn't French, German nor Spanish
+func Ordinal(n int, locale string) string {
+	switch {
+	case strings.HasPrefix(locale, "fr"):
+		if n == 1 {
+			return "1<sup>er</sup>"
+		}
+		return fmt.Sprintf("%d<sup>e</sup>", n)
+	case strings.HasPrefix(locale, "de"):
+		return fmt.Sprintf("%d.", n)
+	case strings.HasPrefix(locale, "es"):
+		return fmt.Sprintf("%d.º", n)
+	}
+
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+
+	return fmt.Sprintf("%d<sup>%s</sup>", n, suffix)
+}
+
+// appendFootnotes append document footnotes numbered with superscript markers,
+// referenced in texts as <sup>1</sup>
+func (doc *Document) appendFootnotes() {
+	if len(doc.Footnotes) == 0 {
+		return
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	y := doc.blockY(float64(len(doc.Footnotes)) * 3)
+	doc.pdf.SetY(y)
+
+	for i, footnote := range doc.Footnotes {
+		runs := doc.textRuns(fmt.Sprintf("<sup>%d</sup> %s", i+1, footnote))
+		doc.writeRuns(runs, BaseMargin, 190, 3, doc.Options.Font, "")
+	}
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrdinal(t *testing.T) {
+	for expected, ordinal := range map[string]string{
+		"1<sup>er</sup>":  Ordinal(1, "fr-FR"),
+		"2<sup>e</sup>":   Ordinal(2, "fr-FR"),
+		"1<sup>st</sup>":  Ordinal(1, "en-US"),
+		"2<sup>nd</sup>":  Ordinal(2, "en-GB"),
+		"3<sup>rd</sup>":  Ordinal(3, ""),
+		"11<sup>th</sup>": Ordinal(11, "en-US"),
+		"22<sup>nd</sup>": Ordinal(22, "en-US"),
+		"3.":              Ordinal(3, "de-DE"),
+		"4.º":             Ordinal(4, "es-ES"),
+	} {
+		if ordinal != expected {
+			t.Errorf("expected %q, got %q", expected, ordinal)
+		}
+	}
+}
+
+func TestTextRuns(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+
+	runs := doc.textRuns("Rent of the 1<sup>st</sup> floor, H<sub>2</sub>O")
+	if len(runs) != 5 || runs[1].script != ScriptSuper || runs[1].text != "st" || runs[3].script != ScriptSub || runs[4].script != "" {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+	if doc.textRuns("No markup <b>here</b>") != nil {
+		t.Error("expected no runs without scripts")
+	}
+	if stripped := stripScripts("1<sup>er</sup> étage"); stripped != "1er étage" {
+		t.Errorf("unexpected stripped text %q", stripped)
+	}
+}
+
+func TestNonBreakingSpaces(t *testing.T) {
+	doc, _ := New(Invoice, &Options{Locale: "fr-FR"})
+
+	if amount := doc.formatAmount("1234.56"); amount != "1 234,56 €" {
+		t.Errorf("unexpected amount %q", amount)
+	}
+
+	// Narrow no-break spaces can't be encoded, they are drawn as no-break spaces
+	if encoded := doc.encodeString("1 234,56 €"); encoded != "1\xa0234,56\xa0\x80" {
+		t.Errorf("unexpected encoded string %q", encoded)
+	}
+}
+
+func TestFootnotes(t *testing.T) {
+	doc, _ := New(Invoice, &Options{Locale: "fr-FR", DisableCompression: true})
+
+	doc.SetRef("FA-1")
+	doc.SetCompany(&Contact{Name: "Société", Address: &Address{Address: "1 rue de la Paix"}})
+	doc.SetCustomer(&Contact{Name: "Client"})
+	doc.AppendItem(&Item{Name: "Location du " + Ordinal(1, "fr-FR") + " étage<sup>1</sup>", UnitCost: "1234.56", Quantity: "1"})
+	doc.Footnotes = []string{"Charges comprises"}
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("( Charges comprises)")) {
+		t.Error("expected footnote")
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("<sup>")) {
+		t.Error("expected scripts markup removed")
+	}
+}
@@ -1,21 +1,100 @@
 package generator
 
 import (
+	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 )
 
 // Validate document fields
 func (d *Document) Validate() error {
+	// Apply country compliance profile modes
+	if err := d.applyCompliance(); err != nil {
+		return err
+	}
+
+	// Omit or pseudonymize personal data
+	d.MinimizePersonalData()
+
+	// Strip or reject hostile inputs
+	if err := d.Sanitize(); err != nil {
+		return err
+	}
+
 	validate := validator.New()
 	if err := validate.Struct(d); err != nil {
 		return err
 	}
 
+	// Check sections order
+	if err := d.checkSections(); err != nil {
+		return err
+	}
+
+	// Check items table columns
+	if err := d.checkItemColumns(); err != nil {
+		return err
+	}
+
+	// Fetch logos given as URLs
+	if err := d.fetchLogos(); err != nil {
+		return err
+	}
+
+	// Check items and images limits
+	if err := d.checkLimits(); err != nil {
+		return err
+	}
+
+	// Prepare stay and city tax items
+	if err := d.prepareStay(); err != nil {
+		return err
+	}
+
+	// Prepare meter readings and consumption based items
+	if err := d.prepareMeterReadings(); err != nil {
+		return err
+	}
+
 	// Prepare items
 	for _, item := range d.Items {
+		// Compute rental quantity and totals
+		if item.Rental != nil {
+			if err := validate.Struct(item.Rental); err != nil {
+				return err
+			}
+
+			if err := item.Rental.apply(item, d.Options); err != nil {
+				return err
+			}
+		}
+
+		// Check item tax
+		if item.Tax == nil {
+			item.Tax = d.DefaultTax
+		}
+
 		if err := item.Prepare(); err != nil {
 			return err
 		}
+
+		// Check returns and corrections are allowed
+		if !d.Options.AllowNegativeLines && item.negative() {
+			return ErrNegativeLine
+		}
+	}
+
+	// Prepare prior down payments
+	for _, downPayment := range d.DownPayments {
+		if err := downPayment.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare quote options items
+	for _, option := range d.QuoteOptions {
+		if err := option.Prepare(d); err != nil {
+			return err
+		}
 	}
 
 	// Prepare document discount
@@ -25,5 +104,192 @@ func (d *Document) Validate() error {
 		}
 	}
 
+	// Prepare medical co-pay split
+	if d.Medical != nil {
+		total := ""
+		if amount, err := d.totalAmount(); err == nil {
+			total = amount.Round(int32(d.Options.CurrencyPrecision)).String()
+		}
+
+		if err := d.Medical.Prepare(total); err != nil {
+			return err
+		}
+	}
+
+	// Prepare late interest
+	if d.LateInterest != nil {
+		total, err := d.totalAmount()
+		if err != nil && len(d.LateInterest.Principal) == 0 {
+			return err
+		}
+
+		if err := d.LateInterest.Prepare(d.Options.DateFormat, total); err != nil {
+			return err
+		}
+	}
+
+	// Prepare previous balance mini-statement
+	if d.Statement != nil {
+		total, err := d.totalAmount()
+		if err != nil {
+			return err
+		}
+
+		if err := d.Statement.Prepare(total); err != nil {
+			return err
+		}
+	}
+
+	// Prepare retainer balance, consumed by document total
+	if d.Retainer != nil {
+		if err := d.Retainer.Prepare(d.TotalWithoutTax()); err != nil {
+			return err
+		}
+	}
+
+	// Prepare settlement sums, statement items being invoiced fees
+	if d.Settlement != nil {
+		if err := d.Settlement.Prepare(d.TotalWithTax()); err != nil {
+			return err
+		}
+	}
+
+	// Prepare royalty advance
+	if d.Royalty != nil {
+		if err := d.Royalty.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check australian / new zealand tax invoice rules
+	if d.GST != nil {
+		if err := d.GST.Prepare(d); err != nil {
+			return err
+		}
+	}
+
+	// Check south african tax invoice rules
+	if d.SouthAfricanVAT != nil {
+		if err := d.SouthAfricanVAT.Prepare(d); err != nil {
+			return err
+		}
+	}
+
+	// Check canadian registration numbers
+	if d.CanadianTax != nil {
+		if err := d.CanadianTax.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Set Portuguese fiscal defaults
+	if d.PortugueseFiscal != nil {
+		if err := defaults.Set(d.PortugueseFiscal); err != nil {
+			return err
+		}
+	}
+
+	// Prepare Spanish record
+	if d.SpanishFiscal != nil {
+		if err := d.SpanishFiscal.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare Turkish ETTN and defaults
+	if d.TurkishFiscal != nil {
+		if err := d.TurkishFiscal.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check NF-e access key
+	if d.BrazilianFiscal != nil {
+		if err := d.BrazilianFiscal.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check IMb tracking and routing codes
+	if d.PostalBarcode != nil {
+		if err := d.PostalBarcode.Prepare(d); err != nil {
+			return err
+		}
+	}
+
+	// Check SEPA creditor identifier
+	if d.DirectDebit != nil {
+		if err := d.DirectDebit.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare payment methods amounts range
+	for _, method := range d.PaymentMethods {
+		if err := method.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check boleto barcode and digitable line
+	if d.Boleto != nil {
+		if err := d.Boleto.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check giro account and OCR reference
+	if d.Giro != nil {
+		if err := d.Giro.Prepare(d.Ref); err != nil {
+			return err
+		}
+	}
+
+	// Check remittance stub amount due
+	if d.RemittanceStub != nil {
+		if err := d.RemittanceStub.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare totals expected by caller
+	if d.ExpectedTotals != nil {
+		if err := d.ExpectedTotals.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Check quote validity date
+	if err := d.validateValidity(); err != nil {
+		return err
+	}
+
+	// Check items sellers
+	if err := d.validateSellers(); err != nil {
+		return err
+	}
+
+	// Check the payment reference can be generated from ref
+	if _, err := d.PaymentReference(); err != nil {
+		return err
+	}
+
+	// Check portal token secret
+	if d.Options.Portal != nil {
+		if err := d.Options.Portal.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare payers split
+	if err := d.preparePayers(); err != nil {
+		return err
+	}
+
+	// Check country compliance profile rules
+	if err := d.validateCompliance(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidValidity when the validity date is before the document date
+var ErrInvalidValidity = errors.New("validity date before document date")
+
+// Expired watermark style
+const (
+	expiredWatermarkSize  float64 = 96
+	expiredWatermarkAlpha float64 = 0.15
+	expiredWatermarkAngle float64 = 45
+)
+
+// ValidUntil return the last day quote is valid: ValidityDate, or document date plus ValidForDays.
+// Zero when none is set.
+func (doc *Document) ValidUntil() (time.Time, error) {
+	if len(doc.ValidityDate) > 0 {
+		return time.Parse(doc.Options.DateFormat, doc.ValidityDate)
+	}
+
+	if doc.ValidForDays == 0 {
+		return time.Time{}, nil
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return date.AddDate(0, 0, doc.ValidForDays), nil
+}
+
+// Expired return true when quote validity ended before now
+func (doc *Document) Expired(now time.Time) bool {
+	until, err := doc.ValidUntil()
+	if err != nil || until.IsZero() {
+		return false
+	}
+
+	// Valid until the end of the day
+	return now.After(until.AddDate(0, 0, 1))
+}
+
+// validateValidity check validity date can be parsed and doesn't precede document date
+func (doc *Document) validateValidity() error {
+	until, err := doc.ValidUntil()
+	if err != nil || until.IsZero() || len(doc.Date) == 0 {
+		return err
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return err
+	}
+	if until.Before(date) {
+		return ErrInvalidValidity
+	}
+
+	return nil
+}
+
+// validityDays return the number of days quote is valid, from document date
+func (doc *Document) validityDays(until time.Time) int {
+	if doc.ValidForDays > 0 {
+		return doc.ValidForDays
+	}
+
+	date, err := doc.documentDate()
+	if err != nil {
+		return 0
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, until.Location())
+
+	return int(math.Round(until.Sub(date).Hours() / 24))
+}
+
+// appendValidity append the quote validity sentence below payment term
+func (doc *Document) appendValidity() {
+	if doc.Type != Quotation {
+		return
+	}
+
+	until, err := doc.ValidUntil()
+	if err != nil || until.IsZero() {
+		return
+	}
+
+	date := doc.dateDigits(until.Format(doc.Options.DateFormat))
+	sentence := fmt.Sprintf("%s: %s", doc.Options.TextValidUntilTitle, date)
+	if days := doc.validityDays(until); days > 0 {
+		sentence = fmt.Sprintf(doc.Options.TextValidForDays, days, date)
+	}
+
+	y := doc.pdf.GetY() + 15
+	if len(doc.PaymentTerm) > 0 {
+		y = doc.pdf.GetY() + 5
+	}
+	doc.pdf.SetY(y)
+	doc.keepBlockTogether(BlockPaymentTerm, 4)
+
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", 9)
+	doc.pdf.CellFormat(190, 4, doc.encodeString(sentence), "0", 0, "R", false, 0, "")
+}
+
+// appendExpiredWatermark draw Options.TextExpiredWatermark across every page of quotes past their
+// validity date, when Options.ExpiredWatermark is set
+func (doc *Document) appendExpiredWatermark() {
+	if !doc.Options.ExpiredWatermark || doc.Type != Quotation || !doc.Expired(time.Now()) {
+		return
+	}
+
+	width, height := doc.pdf.GetPageSize()
+	count := doc.pdf.PageCount()
+	current := doc.pdf.PageNo()
+	defer doc.pdf.SetPage(current)
+
+	text := doc.encodeString(doc.Options.TextExpiredWatermark)
+	for number := 1; number <= count; number++ {
+		doc.pdf.SetPage(number)
+
+		// Content of the current page is still shifted by print layout
+		if number != current {
+			doc.beginPageShift()
+		}
+
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", expiredWatermarkSize)
+		doc.pdf.SetTextColor(200, 0, 0)
+		doc.pdf.SetAlpha(expiredWatermarkAlpha, "Normal")
+		doc.pdf.TransformBegin()
+		doc.pdf.TransformRotate(expiredWatermarkAngle, width/2, height/2)
+		_, fontHt := doc.pdf.GetFontSize()
+		doc.pdf.Text(width/2-doc.pdf.GetStringWidth(text)/2, height/2+fontHt*0.35, text)
+		doc.pdf.TransformEnd()
+		doc.pdf.SetAlpha(1, "Normal")
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+
+		if number != current {
+			doc.endPageShift()
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func newQuote() *Document {
+	doc, _ := New(Quotation, &Options{DisableCompression: true})
+	doc.SetRef("Q-001")
+	doc.SetDate("01/03/2024")
+	doc.SetCompany(&Contact{Name: "Seller"})
+	doc.SetCustomer(&Contact{Name: "Buyer"})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "100", Quantity: "2"})
+
+	return doc
+}
+
+func TestValidUntil(t *testing.T) {
+	doc := newQuote()
+	if until, err := doc.ValidUntil(); err != nil || !until.IsZero() {
+		t.Errorf("expected no validity, got %v %v", until, err)
+	}
+
+	doc.ValidForDays = 30
+	until, err := doc.ValidUntil()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if until.Format(doc.Options.DateFormat) != "31/03/2024" {
+		t.Errorf("expected 31/03/2024, got %s", until.Format(doc.Options.DateFormat))
+	}
+	if doc.Expired(time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)) {
+		t.Error("expected quote valid on its last day")
+	}
+	if !doc.Expired(time.Date(2024, 4, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Error("expected quote expired the day after")
+	}
+
+	doc.ValidityDate = "15/03/2024"
+	if until, _ := doc.ValidUntil(); until.Day() != 15 {
+		t.Errorf("expected validity date to win, got %v", until)
+	}
+
+	doc.ValidityDate = "15/02/2024"
+	if err := doc.Validate(); err != ErrInvalidValidity {
+		t.Errorf("expected ErrInvalidValidity, got %v", err)
+	}
+}
+
+func TestExpiredWatermark(t *testing.T) {
+	doc := newQuote()
+	doc.ValidForDays = 30
+	doc.Options.ExpiredWatermark = true
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"This quotation is valid for 30 days, until 31/03/2024.", "(EXPIRED) Tj"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("expected %q in PDF", expected)
+		}
+	}
+
+	// Quotes still valid have no watermark
+	doc = newQuote()
+	doc.SetDate(time.Now().Format(doc.Options.DateFormat))
+	doc.ValidForDays = 30
+	doc.Options.ExpiredWatermark = true
+	pdf, err = doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := pdf.Output(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buffer.Bytes(), []byte("(EXPIRED) Tj")) {
+		t.Error("expected no watermark on valid quote")
+	}
+}
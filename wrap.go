@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// wordBreaks are characters after which words too long for a line (URLs, SKUs, paths) are wrapped
+const wordBreaks = "/-_.,:;?&=+|\\"
+
+// wrapText break lines of text where they would be wrapped in width. Words too long for a line are broken
+// after their last slash, hyphen, underscore... fitting, anywhere without one, and lines holding right to left
+// characters are then reordered one by one. Other texts are returned as is, wrapped by MultiCell.
+func (doc *Document) wrapText(text string, width float64) string {
+	width -= 2 * doc.pdf.GetCellMargin()
+	if !rtl(text) && !doc.longWords(text, width) {
+		return text
+	}
+
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			for len(word) > 0 && doc.textWidth(joinWords(line, word)) > width {
+				// Word fits on next line
+				if len(line) > 0 && doc.textWidth(word) <= width {
+					lines = append(lines, line)
+					line = ""
+					break
+				}
+
+				head := doc.wordHead(line, word, width)
+				if len(head) == 0 {
+					lines = append(lines, line)
+					line = ""
+					continue
+				}
+				lines = append(lines, joinWords(line, head))
+				line, word = "", word[len(head):]
+			}
+			line = joinWords(line, word)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// longWords return true when a word of text is wider than width
+func (doc *Document) longWords(text string, width float64) bool {
+	for _, word := range strings.Fields(text) {
+		if doc.textWidth(word) > width {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wordHead return the longest head of word fitting in width after line, ended by a word break character,
+// anywhere when line is empty and word has no break fitting, or empty to wrap word on next line
+func (doc *Document) wordHead(line string, word string, width float64) string {
+	fit, wordBreak := 0, 0
+	tag := false
+
+	for i, r := range word {
+		end := i + utf8.RuneLen(r)
+		if doc.textWidth(joinWords(line, word[:end])) > width {
+			break
+		}
+
+		// Never break scripts markup
+		switch r {
+		case '<':
+			tag = true
+		case '>':
+			tag = false
+			continue
+		}
+		if tag {
+			continue
+		}
+
+		fit = end
+		if next, _ := utf8.DecodeRuneInString(word[end:]); strings.ContainsRune(wordBreaks, r) && end < len(word) && !strings.ContainsRune(wordBreaks, next) {
+			wordBreak = end
+		}
+	}
+
+	if wordBreak > 0 {
+		return word[:wordBreak]
+	}
+	if len(line) > 0 {
+		return ""
+	}
+
+	// At least one character per line
+	if fit == 0 {
+		_, size := utf8.DecodeRuneInString(word)
+		return word[:size]
+	}
+
+	return word[:fit]
+}
+
+// textWidth return the width of text with current font, without scripts markup
+func (doc *Document) textWidth(text string) float64 {
+	return doc.pdf.GetStringWidth(doc.encodeString(stripScripts(text)))
+}
+
+// joinWords return line followed by word, separated by a space
+func joinWords(line string, word string) string {
+	if len(line) == 0 {
+		return word
+	}
+
+	return line + " " + word
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+	doc.pdf.SetFont(doc.Options.Font, "", 8)
+
+	// Texts MultiCell wraps at spaces are kept as is
+	text := "Consulting services for the migration of the billing platform"
+	if wrapped := doc.wrapText(text, 40); wrapped != text {
+		t.Errorf("unexpected wrapped text %q", wrapped)
+	}
+
+	for text, expected := range map[string][]string{
+		"Hosting https://example.com/accounts/billing/invoices/2024": {"Hosting https://example.com/", "accounts/billing/invoices/2024"},
+		"SKU ACME-WIDGET-PRO-MAX-2000-BLUE-XL":                       {"SKU ACME-WIDGET-PRO-", "MAX-2000-BLUE-XL"},
+		"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA":       {"AAAAAAAAAAAAAAAAAAAA", "AAAAAAAAAAAAAAAAAAAA", "AAAAAAAAAAAA"},
+	} {
+		lines := strings.Split(doc.wrapText(text, 40), "\n")
+		if strings.Join(lines, "|") != strings.Join(expected, "|") {
+			t.Errorf("expected %q wrapped as %q, got %q", text, expected, lines)
+		}
+		for _, line := range lines {
+			if doc.textWidth(line) > 40-2*doc.pdf.GetCellMargin() {
+				t.Errorf("line %q overflows", line)
+			}
+		}
+	}
+}
+
+func TestWrapTextItems(t *testing.T) {
+	doc, _ := New(Invoice, &Options{})
+
+	doc.SetRef("INV-1")
+	doc.SetCompany(&Contact{Name: "Company", Address: &Address{Address: "1 Main street"}})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+	doc.AppendItem(&Item{Name: "https://storage.example.com/buckets/customer-exports/2024/archive.tar.gz", UnitCost: "100", Quantity: "1"})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}